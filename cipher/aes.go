@@ -46,12 +46,13 @@ func (a *AES) Encrypt(ctx context.Context, message []byte) (string, error) {
 		return "", errors.WithStack(herodot.ErrMisconfiguration().WithReason("Unable to encrypt message because no cipher secrets were configured."))
 	}
 
-	gcm, err := aesGCM(&a.c.SecretsCipher(ctx)[0])
+	secret := a.c.SecretsCipher(ctx)[0]
+	gcm, err := aesGCM(&secret)
 	if err != nil {
 		return "", errors.WithStack(herodot.ErrForbidden().WithWrap(err))
 	}
 
-	return hex.EncodeToString(gcm.Seal(nil, nil, message, nil)), nil
+	return KeyID(secret) + keyIDSeparator + hex.EncodeToString(gcm.Seal(nil, nil, message, nil)), nil
 }
 
 // Decrypt returns the decrypted AES data.
@@ -65,7 +66,13 @@ func (a *AES) Decrypt(ctx context.Context, ciphertext string) ([]byte, error) {
 		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReason("Unable to decipher the encrypted message because no AES secrets were configured."))
 	}
 
-	decode, err := hex.DecodeString(ciphertext)
+	keyID, payload := splitCiphertext(ciphertext)
+	secrets, err := decryptionSecrets(secrets, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	decode, err := hex.DecodeString(payload)
 	if err != nil {
 		return nil, errors.WithStack(herodot.ErrBadRequest().WithWrap(err))
 	}
@@ -82,3 +89,8 @@ func (a *AES) Decrypt(ctx context.Context, ciphertext string) ([]byte, error) {
 
 	return nil, errors.WithStack(herodot.ErrForbidden().WithReason("Unable to decipher the encrypted message."))
 }
+
+// NeedsRotation implements Rotator.
+func (a *AES) NeedsRotation(ctx context.Context, ciphertext string) bool {
+	return needsRotation(a.c.SecretsCipher(ctx), ciphertext)
+}
@@ -34,7 +34,8 @@ func (c *XChaCha20Poly1305) Encrypt(ctx context.Context, message []byte) (string
 		return "", errors.WithStack(herodot.ErrMisconfiguration().WithReason("Unable to encrypt message because no cipher secrets were configured."))
 	}
 
-	aead, err := chacha20poly1305.NewX(c.c.SecretsCipher(ctx)[0][:])
+	secret := c.c.SecretsCipher(ctx)[0]
+	aead, err := chacha20poly1305.NewX(secret[:])
 	if err != nil {
 		return "", herodot.ErrInternalServerError().WithWrap(err).WithReason("Unable to generate key")
 	}
@@ -51,7 +52,7 @@ func (c *XChaCha20Poly1305) Encrypt(ctx context.Context, message []byte) (string
 	}
 
 	encryptedMsg := aead.Seal(nonce, nonce, message, nil)
-	return hex.EncodeToString(encryptedMsg), nil
+	return KeyID(secret) + keyIDSeparator + hex.EncodeToString(encryptedMsg), nil
 }
 
 // Decrypt decrypts data using 256 bit key
@@ -65,7 +66,13 @@ func (c *XChaCha20Poly1305) Decrypt(ctx context.Context, ciphertext string) ([]b
 		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReason("Unable to decipher the encrypted message because no cipher secrets were configured."))
 	}
 
-	rawCiphertext, err := hex.DecodeString(ciphertext)
+	keyID, payload := splitCiphertext(ciphertext)
+	secrets, err := decryptionSecrets(secrets, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	rawCiphertext, err := hex.DecodeString(payload)
 	if err != nil {
 		return nil, errors.WithStack(herodot.ErrBadRequest().WithWrap(err).WithReason("Unable to decode hex encrypted string"))
 	}
@@ -92,3 +99,8 @@ func (c *XChaCha20Poly1305) Decrypt(ctx context.Context, ciphertext string) ([]b
 
 	return nil, errors.WithStack(herodot.ErrForbidden().WithReason("Unable to decrypt string"))
 }
+
+// NeedsRotation implements Rotator.
+func (c *XChaCha20Poly1305) NeedsRotation(ctx context.Context, ciphertext string) bool {
+	return needsRotation(c.c.SecretsCipher(ctx), ciphertext)
+}
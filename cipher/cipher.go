@@ -3,12 +3,22 @@
 
 package cipher
 
-import "context"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+)
 
 // Cipher provides methods for encrypt and decrypt string
 type Cipher interface {
 	// Encrypt encrypts the (binary) message and returns a hex-encoded binary ciphertext
-	// or an error if the encryption failed.
+	// or an error if the encryption failed. Ciphers implementing Rotator prefix the
+	// ciphertext with the ID of the secret used.
 	//
 	// If the message is empty, the ciphertext is also empty and no error is returned.
 	Encrypt(ctx context.Context, message []byte) (string, error)
@@ -27,3 +37,75 @@ type Provider interface {
 type SecretsProvider interface {
 	SecretsCipher(ctx context.Context) [][32]byte
 }
+
+// Rotator is implemented by ciphers which prefix ciphertexts with the ID of
+// the secret they were encrypted with.
+type Rotator interface {
+	// NeedsRotation reports whether the ciphertext was encrypted with a
+	// secret other than the primary one.
+	NeedsRotation(ctx context.Context, ciphertext string) bool
+}
+
+// keyIDSeparator separates the key ID from the hex-encoded ciphertext.
+// Ciphertexts written before key IDs were introduced are plain hex and never
+// contain it.
+const keyIDSeparator = "."
+
+// KeyID returns the ID of the secret which is prefixed to ciphertexts. It is
+// derived from the secret and does not reveal it.
+func KeyID(secret [32]byte) string {
+	h := sha256.Sum256(append([]byte("ory-kratos-cipher-key-id:"), secret[:]...))
+	return hex.EncodeToString(h[:4])
+}
+
+// splitCiphertext returns the key ID and the hex-encoded payload of the
+// ciphertext. The key ID is empty for ciphertexts without a key ID.
+func splitCiphertext(ciphertext string) (keyID, payload string) {
+	if keyID, payload, ok := strings.Cut(ciphertext, keyIDSeparator); ok {
+		return keyID, payload
+	}
+	return "", ciphertext
+}
+
+// decryptionSecrets returns the secrets to try when decrypting a ciphertext
+// with the given key ID. Ciphertexts without a key ID are tried with all
+// secrets.
+func decryptionSecrets(secrets [][32]byte, keyID string) ([][32]byte, error) {
+	if keyID == "" {
+		return secrets, nil
+	}
+	for i := range secrets {
+		if KeyID(secrets[i]) == keyID {
+			return secrets[i : i+1], nil
+		}
+	}
+	return nil, errors.WithStack(herodot.ErrForbidden().WithReasonf("Unable to decipher the encrypted message because the secret with key ID %q is not configured.", keyID))
+}
+
+func needsRotation(secrets [][32]byte, ciphertext string) bool {
+	if ciphertext == "" || len(secrets) == 0 {
+		return false
+	}
+	keyID, _ := splitCiphertext(ciphertext)
+	return keyID != KeyID(secrets[0])
+}
+
+// Reencrypt encrypts the ciphertext with the primary secret if it was
+// encrypted with another secret, and reports whether it did so.
+func Reencrypt(ctx context.Context, c Cipher, ciphertext string) (string, bool, error) {
+	r, ok := c.(Rotator)
+	if !ok || !r.NeedsRotation(ctx, ciphertext) {
+		return ciphertext, false, nil
+	}
+
+	plaintext, err := c.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return "", false, err
+	}
+
+	rotated, err := c.Encrypt(ctx, plaintext)
+	if err != nil {
+		return "", false, err
+	}
+	return rotated, true, nil
+}
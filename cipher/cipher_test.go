@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -116,6 +117,80 @@ func TestAESBackwardsCompatibility(t *testing.T) {
 	assert.Equal(t, "fixture: encrypted with caller-supplied nonce", string(plain2))
 }
 
+func TestKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	const (
+		oldSecret = "old-secret-thirty-two-characters"
+		newSecret = "new-secret-thirty-two-characters"
+	)
+
+	ctx := t.Context()
+	_, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValue(config.ViperKeySecretsCipher, []string{oldSecret}))
+	rotated := contextx.WithConfigValue(ctx, config.ViperKeySecretsCipher, []string{newSecret, oldSecret})
+
+	for _, c := range []cipher.Cipher{
+		cipher.NewCryptAES(reg.Config()),
+		cipher.NewCryptChaCha20(reg.Config()),
+	} {
+		t.Run(fmt.Sprintf("cipher=%T", c), func(t *testing.T) {
+			t.Parallel()
+			r, ok := c.(cipher.Rotator)
+			require.True(t, ok)
+
+			ciphertext, err := c.Encrypt(ctx, []byte("my secret message!"))
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(ciphertext, cipher.KeyID(config.ToCipherSecrets([]string{oldSecret})[0])+"."), ciphertext)
+			assert.False(t, r.NeedsRotation(ctx, ciphertext))
+
+			t.Run("case=decrypts with non-primary secret", func(t *testing.T) {
+				plaintext, err := c.Decrypt(rotated, ciphertext)
+				require.NoError(t, err)
+				assert.Equal(t, "my secret message!", string(plaintext))
+				assert.True(t, r.NeedsRotation(rotated, ciphertext))
+			})
+
+			t.Run("case=reencrypts with primary secret", func(t *testing.T) {
+				actual, ok, err := cipher.Reencrypt(rotated, c, ciphertext)
+				require.NoError(t, err)
+				require.True(t, ok)
+				assert.True(t, strings.HasPrefix(actual, cipher.KeyID(config.ToCipherSecrets([]string{newSecret})[0])+"."), actual)
+				assert.False(t, r.NeedsRotation(rotated, actual))
+
+				plaintext, err := c.Decrypt(rotated, actual)
+				require.NoError(t, err)
+				assert.Equal(t, "my secret message!", string(plaintext))
+
+				again, ok, err := cipher.Reencrypt(rotated, c, actual)
+				require.NoError(t, err)
+				assert.False(t, ok)
+				assert.Equal(t, actual, again)
+
+				_, err = c.Decrypt(ctx, actual)
+				require.Error(t, err, "the secret is not configured")
+			})
+
+			t.Run("case=legacy ciphertexts without key id", func(t *testing.T) {
+				_, legacy, found := strings.Cut(ciphertext, ".")
+				require.True(t, found)
+				assert.True(t, r.NeedsRotation(ctx, legacy))
+
+				plaintext, err := c.Decrypt(rotated, legacy)
+				require.NoError(t, err)
+				assert.Equal(t, "my secret message!", string(plaintext))
+			})
+		})
+	}
+
+	t.Run("cipher=noop", func(t *testing.T) {
+		t.Parallel()
+		actual, ok, err := cipher.Reencrypt(ctx, cipher.NewNoop(), "00")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "00", actual)
+	})
+}
+
 func testAllWork(ctx context.Context, t *testing.T, c cipher.Cipher) {
 	message := "my secret message!"
 
@@ -0,0 +1,373 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/x/httpx"
+)
+
+// kmsPrefix prefixes ciphertexts of the KMS cipher, followed by the ID of the
+// wrapping key, the wrapped data key, and the hex-encoded ciphertext,
+// separated by dots. Ciphertexts written before key IDs were introduced lack
+// the key ID.
+const kmsPrefix = "kms" + keyIDSeparator
+
+// kmsDataKeyCacheSize bounds the number of unwrapped data keys kept in memory.
+const kmsDataKeyCacheSize = 1024
+
+// KeyWrapper wraps and unwraps data keys with a key which never leaves the
+// key management service.
+type KeyWrapper interface {
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+type KMSDependencies interface {
+	config.Provider
+	httpx.ClientProvider
+}
+
+type kmsDataKey struct {
+	key     [32]byte
+	keyID   string
+	wrapped []byte
+	config  [32]byte
+	expires time.Time
+}
+
+// KMS implements envelope encryption: messages are encrypted with
+// XChaCha20-Poly1305 using a random data key, which is wrapped by a key
+// management service and stored alongside the ciphertext. Data keys are used
+// for encryption and cached in memory for the configured data key TTL.
+//
+// Ciphertexts written before the KMS cipher was enabled are decrypted with
+// the secrets at `secrets.cipher`. They, and ciphertexts wrapped with a
+// previous key management service key, are re-encrypted by a cipher key
+// rotation run.
+type KMS struct {
+	d KMSDependencies
+
+	mu      sync.Mutex
+	current *kmsDataKey
+
+	unwrapped *expirable.LRU[[32]byte, [32]byte]
+}
+
+var (
+	_ Cipher  = new(KMS)
+	_ Rotator = new(KMS)
+)
+
+func NewCryptKMS(ctx context.Context, d KMSDependencies) *KMS {
+	return &KMS{
+		d:         d,
+		unwrapped: expirable.NewLRU[[32]byte, [32]byte](kmsDataKeyCacheSize, nil, d.Config().CipherKMSDataKeyTTL(ctx)),
+	}
+}
+
+// NewKeyWrapper returns the key wrapper for the configured key management
+// service.
+func NewKeyWrapper(c *config.CipherKMSConfig, d KMSDependencies) (KeyWrapper, error) {
+	switch c.Provider {
+	case "vault":
+		if c.Vault == nil {
+			break
+		}
+		return &vaultTransit{c: c.Vault, d: d}, nil
+	case "aws":
+		if c.AWS == nil {
+			break
+		}
+		return &awsKMS{c: c.AWS, d: d}, nil
+	case "gcp":
+		if c.GCP == nil {
+			break
+		}
+		return &gcpKMS{c: c.GCP, d: d}, nil
+	default:
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unknown key management service %q.", c.Provider))
+	}
+	return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("The key management service %q is not configured.", c.Provider))
+}
+
+// KMSKeyID returns the ID of the wrapping key which is prefixed to
+// ciphertexts. It is derived from the provider and the key reference only, so
+// that rotating credentials does not change it.
+func KMSKeyID(c *config.CipherKMSConfig) string {
+	ref := []string{c.Provider}
+	switch {
+	case c.Provider == "vault" && c.Vault != nil:
+		ref = append(ref, c.Vault.URL, c.Vault.Namespace, c.Vault.Mount, c.Vault.Key)
+	case c.Provider == "aws" && c.AWS != nil:
+		ref = append(ref, c.AWS.Region, c.AWS.KeyID)
+	case c.Provider == "gcp" && c.GCP != nil:
+		ref = append(ref, c.GCP.KeyName)
+	}
+	h := sha256.Sum256([]byte("ory-kratos-kms-key-id:" + strings.Join(ref, "\x00")))
+	return hex.EncodeToString(h[:4])
+}
+
+func (k *KMS) wrapper(ctx context.Context) (KeyWrapper, string, [32]byte, error) {
+	c, err := k.d.Config().CipherKMS(ctx)
+	if err != nil {
+		return nil, "", [32]byte{}, err
+	}
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, "", [32]byte{}, errors.WithStack(err)
+	}
+	w, err := NewKeyWrapper(c, k.d)
+	return w, KMSKeyID(c), sha256.Sum256(raw), err
+}
+
+// unwrapper returns the key wrapper for the key with the given ID, which is
+// either the current key or one of the previous keys. Ciphertexts without a
+// key ID are unwrapped with the current key.
+func (k *KMS) unwrapper(ctx context.Context, keyID string) (KeyWrapper, error) {
+	c, err := k.d.Config().CipherKMS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if keyID == "" || KMSKeyID(c) == keyID {
+		return NewKeyWrapper(c, k.d)
+	}
+	for i := range c.Previous {
+		if KMSKeyID(&c.Previous[i]) == keyID {
+			return NewKeyWrapper(&c.Previous[i], k.d)
+		}
+	}
+	return nil, errors.WithStack(herodot.ErrForbidden().WithReasonf("Unable to decipher the encrypted message because the key management service key with key ID %q is not configured.", keyID))
+}
+
+// dataKey returns the data key used for encryption, generating and wrapping a
+// new one if there is none, it expired, or the KMS configuration changed.
+func (k *KMS) dataKey(ctx context.Context) (*kmsDataKey, error) {
+	w, keyID, fingerprint, err := k.wrapper(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.current != nil && k.current.config == fingerprint && time.Now().Before(k.current.expires) {
+		return k.current, nil
+	}
+
+	dk := &kmsDataKey{keyID: keyID, config: fingerprint, expires: time.Now().Add(k.d.Config().CipherKMSDataKeyTTL(ctx))}
+	if _, err := io.ReadFull(rand.Reader, dk.key[:]); err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError().WithWrap(err).WithReason("Unable to generate data key"))
+	}
+	dk.wrapped, err = w.WrapKey(ctx, dk.key[:])
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError().WithWrap(err).WithReasonf("Unable to wrap the data key: %s", err))
+	}
+
+	k.unwrapped.Add(unwrappedCacheKey(keyID, dk.wrapped), dk.key)
+	k.current = dk
+	return dk, nil
+}
+
+// unwrappedCacheKey returns the cache key of an unwrapped data key. It
+// includes the key ID, so that ciphertexts referencing a key which is not
+// configured are not decrypted with a cached data key.
+func unwrappedCacheKey(keyID string, wrapped []byte) [32]byte {
+	return sha256.Sum256(append([]byte(keyID+"\x00"), wrapped...))
+}
+
+func (k *KMS) unwrap(ctx context.Context, keyID string, wrapped []byte) ([32]byte, error) {
+	cacheKey := unwrappedCacheKey(keyID, wrapped)
+	if key, ok := k.unwrapped.Get(cacheKey); ok {
+		return key, nil
+	}
+
+	w, err := k.unwrapper(ctx, keyID)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	raw, err := w.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return [32]byte{}, errors.WithStack(herodot.ErrForbidden().WithWrap(err).WithReasonf("Unable to unwrap the data key: %s", err))
+	}
+
+	var key [32]byte
+	if len(raw) != len(key) {
+		return [32]byte{}, errors.WithStack(herodot.ErrForbidden().WithReason("The unwrapped data key has an invalid length."))
+	}
+	copy(key[:], raw)
+	k.unwrapped.Add(cacheKey, key)
+	return key, nil
+}
+
+// Encrypt returns the envelope encryption of the message.
+func (k *KMS) Encrypt(ctx context.Context, message []byte) (string, error) {
+	if len(message) == 0 {
+		return "", nil
+	}
+
+	dk, err := k.dataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := chacha20poly1305.NewX(dk.key[:])
+	if err != nil {
+		return "", errors.WithStack(herodot.ErrInternalServerError().WithWrap(err).WithReason("Unable to instantiate chacha20"))
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(message)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.WithStack(herodot.ErrInternalServerError().WithWrap(err).WithReason("Unable to generate nonce"))
+	}
+
+	// The wrapped data key is authenticated so it can not be swapped.
+	sealed := aead.Seal(nonce, nonce, message, dk.wrapped)
+	return kmsPrefix + dk.keyID + keyIDSeparator + base64.RawURLEncoding.EncodeToString(dk.wrapped) + keyIDSeparator + hex.EncodeToString(sealed), nil
+}
+
+// splitKMSCiphertext returns the key ID, the wrapped data key, and the
+// hex-encoded payload of a KMS ciphertext. The key ID is empty for
+// ciphertexts written before key IDs were introduced.
+func splitKMSCiphertext(ciphertext string) (keyID, wrapped, payload string, ok bool) {
+	if !strings.HasPrefix(ciphertext, kmsPrefix) {
+		return "", "", "", false
+	}
+	switch parts := strings.Split(strings.TrimPrefix(ciphertext, kmsPrefix), keyIDSeparator); len(parts) {
+	case 2:
+		return "", parts[0], parts[1], true
+	case 3:
+		return parts[0], parts[1], parts[2], true
+	default:
+		return "", "", "", false
+	}
+}
+
+// decryptStatic decrypts ciphertexts written before the KMS cipher was
+// enabled, using the secrets configured at `secrets.cipher`. Without cipher
+// secrets, the ciphertexts were written by the noop cipher.
+func (k *KMS) decryptStatic(ctx context.Context, ciphertext string) ([]byte, error) {
+	if len(k.d.Config().SecretsCipher(ctx)) == 0 {
+		return NewNoop().Decrypt(ctx, ciphertext)
+	}
+
+	plaintext, err := NewCryptChaCha20(k.d.Config()).Decrypt(ctx, ciphertext)
+	if err == nil {
+		return plaintext, nil
+	}
+	if plaintext, aesErr := NewCryptAES(k.d.Config()).Decrypt(ctx, ciphertext); aesErr == nil {
+		return plaintext, nil
+	}
+	return nil, err
+}
+
+// NeedsRotation reports whether the ciphertext was not encrypted using the
+// current key management service key, including ciphertexts written before
+// the KMS cipher was enabled.
+func (k *KMS) NeedsRotation(ctx context.Context, ciphertext string) bool {
+	if ciphertext == "" {
+		return false
+	}
+	keyID, _, _, ok := splitKMSCiphertext(ciphertext)
+	if !ok {
+		return true
+	}
+	c, err := k.d.Config().CipherKMS(ctx)
+	if err != nil {
+		return false
+	}
+	return keyID != KMSKeyID(c)
+}
+
+// Decrypt returns the decrypted envelope encrypted data.
+func (k *KMS) Decrypt(ctx context.Context, ciphertext string) ([]byte, error) {
+	if len(ciphertext) == 0 {
+		return nil, nil
+	}
+
+	keyID, wrappedKey, payload, ok := splitKMSCiphertext(ciphertext)
+	if !ok {
+		return k.decryptStatic(ctx, ciphertext)
+	}
+
+	wrapped, err := base64.RawURLEncoding.DecodeString(wrappedKey)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithWrap(err).WithReason("Unable to decode the wrapped data key"))
+	}
+	sealed, err := hex.DecodeString(payload)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithWrap(err).WithReason("Unable to decode hex encrypted string"))
+	}
+
+	key, err := k.unwrap(ctx, keyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.NewX(key[:])
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError().WithWrap(err).WithReason("Unable to instantiate chacha20"))
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReason("cipher text too short"))
+	}
+
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, wrapped)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrForbidden().WithReason("Unable to decrypt string"))
+	}
+	return plaintext, nil
+}
+
+// kmsPostJSON sends the payload to the key management service and decodes
+// the response into out.
+func kmsPostJSON(ctx context.Context, d KMSDependencies, req *retryablehttp.Request, out any) error {
+	res, err := d.HTTPClient(ctx).Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.Errorf("key management service replied with status code %d: %s", res.StatusCode, body)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return errors.Wrap(err, "unable to decode the key management service response")
+	}
+	return nil
+}
+
+func newKMSRequest(ctx context.Context, url string, payload any) (*retryablehttp.Request, []byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, body, nil
+}
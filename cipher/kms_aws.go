@@ -0,0 +1,146 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/driver/config"
+)
+
+// awsKMS wraps data keys using the AWS Key Management Service JSON API.
+type awsKMS struct {
+	c *config.AWSKMSConfig
+	d KMSDependencies
+}
+
+var _ KeyWrapper = new(awsKMS)
+
+func (a *awsKMS) do(ctx context.Context, operation string, payload any, out any) error {
+	endpoint := a.c.URL
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com/", a.c.Region)
+	}
+
+	req, body, err := newKMSRequest(ctx, endpoint, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+operation)
+	if err := a.sign(req, body, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	return kmsPostJSON(ctx, a.d, req, out)
+}
+
+// sign adds an AWS Signature Version 4 to the request.
+func (a *awsKMS) sign(req *retryablehttp.Request, body []byte, now time.Time) error {
+	const (
+		algorithm = "AWS4-HMAC-SHA256"
+		service   = "kms"
+	)
+
+	u, err := url.Parse(req.URL.String())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if a.c.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", a.c.SessionToken)
+	}
+
+	headers := map[string]string{"host": u.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		u.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := strings.Join([]string{date, a.c.Region, service, "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{algorithm, amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+a.c.SecretAccessKey), date)
+	key = hmacSHA256(key, a.c.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", algorithm, a.c.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func (a *awsKMS) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	var res struct {
+		CiphertextBlob string `json:"CiphertextBlob"`
+	}
+	if err := a.do(ctx, "Encrypt", map[string]string{
+		"KeyId":     a.c.KeyID,
+		"Plaintext": base64.StdEncoding.EncodeToString(key),
+	}, &res); err != nil {
+		return nil, err
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(res.CiphertextBlob)
+	return wrapped, errors.WithStack(err)
+}
+
+func (a *awsKMS) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var res struct {
+		Plaintext string `json:"Plaintext"`
+	}
+	if err := a.do(ctx, "Decrypt", map[string]string{
+		"KeyId":          a.c.KeyID,
+		"CiphertextBlob": base64.StdEncoding.EncodeToString(wrapped),
+	}, &res); err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(res.Plaintext)
+	return key, errors.WithStack(err)
+}
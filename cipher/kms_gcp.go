@@ -0,0 +1,136 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+)
+
+const (
+	gcpKMSDefaultURL      = "https://cloudkms.googleapis.com"
+	gcpKMSDefaultTokenURL = "https://oauth2.googleapis.com/token"
+	gcpKMSScope           = "https://www.googleapis.com/auth/cloudkms"
+)
+
+// gcpTokenSources caches the access token sources per service account key.
+var gcpTokenSources sync.Map
+
+// gcpKMS wraps data keys using Google Cloud KMS.
+type gcpKMS struct {
+	c *config.GCPKMSConfig
+	d KMSDependencies
+}
+
+var _ KeyWrapper = new(gcpKMS)
+
+type gcpServiceAccountKey struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+func (g *gcpKMS) accessToken(ctx context.Context) (string, error) {
+	if g.c.AccessToken != "" {
+		return g.c.AccessToken, nil
+	}
+
+	raw := []byte(g.c.ServiceAccountKey)
+	if g.c.ServiceAccountKeyPath != "" {
+		var err error
+		raw, err = os.ReadFile(g.c.ServiceAccountKeyPath)
+		if err != nil {
+			return "", errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to read %s: %s", g.c.ServiceAccountKeyPath, err))
+		}
+	}
+
+	cacheKey := sha256.Sum256(raw)
+	ts, ok := gcpTokenSources.Load(cacheKey)
+	if !ok {
+		var key gcpServiceAccountKey
+		if err := json.Unmarshal(raw, &key); err != nil {
+			return "", errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to parse the Google Cloud service account key: %s", err))
+		}
+		if key.ClientEmail == "" || key.PrivateKey == "" {
+			return "", errors.WithStack(herodot.ErrMisconfiguration().WithReason("The Google Cloud service account key must contain a client_email and a private_key."))
+		}
+		if key.TokenURI == "" {
+			key.TokenURI = gcpKMSDefaultTokenURL
+		}
+
+		conf := &jwt.Config{
+			Email:        key.ClientEmail,
+			PrivateKey:   []byte(key.PrivateKey),
+			PrivateKeyID: key.PrivateKeyID,
+			Scopes:       []string{gcpKMSScope},
+			TokenURL:     key.TokenURI,
+		}
+
+		// The token source outlives this request, so it must not inherit its
+		// cancellation.
+		tsCtx := context.WithValue(context.WithoutCancel(ctx), oauth2.HTTPClient, g.d.HTTPClient(ctx).HTTPClient)
+		ts, _ = gcpTokenSources.LoadOrStore(cacheKey, oauth2.ReuseTokenSource(nil, conf.TokenSource(tsCtx)))
+	}
+
+	token, err := ts.(oauth2.TokenSource).Token()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return token.AccessToken, nil
+}
+
+func (g *gcpKMS) do(ctx context.Context, operation string, payload any, out any) error {
+	base := g.c.URL
+	if base == "" {
+		base = gcpKMSDefaultURL
+	}
+
+	req, _, err := newKMSRequest(ctx, strings.TrimRight(base, "/")+"/v1/"+strings.Trim(g.c.KeyName, "/")+":"+operation, payload)
+	if err != nil {
+		return err
+	}
+
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return kmsPostJSON(ctx, g.d, req, out)
+}
+
+func (g *gcpKMS) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	var res struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	if err := g.do(ctx, "encrypt", map[string]string{"plaintext": base64.StdEncoding.EncodeToString(key)}, &res); err != nil {
+		return nil, err
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(res.Ciphertext)
+	return wrapped, errors.WithStack(err)
+}
+
+func (g *gcpKMS) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var res struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := g.do(ctx, "decrypt", map[string]string{"ciphertext": base64.StdEncoding.EncodeToString(wrapped)}, &res); err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(res.Plaintext)
+	return key, errors.WithStack(err)
+}
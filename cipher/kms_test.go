@@ -0,0 +1,252 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/cipher"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/x/configx"
+)
+
+// kmsStandIn is a local stand-in for a key management service. Wrapped keys
+// are random handles to keys kept in memory.
+type kmsStandIn struct {
+	keys    sync.Map
+	wraps   atomic.Int32
+	unwraps atomic.Int32
+}
+
+func (k *kmsStandIn) wrap(plaintext string) string {
+	k.wraps.Add(1)
+	handle := uuid.Must(uuid.NewV4()).String()
+	k.keys.Store(handle, plaintext)
+	return handle
+}
+
+func (k *kmsStandIn) unwrap(t *testing.T, handle string) string {
+	k.unwraps.Add(1)
+	plaintext, ok := k.keys.Load(handle)
+	require.True(t, ok, "unknown wrapped key %q", handle)
+	return plaintext.(string)
+}
+
+func newKMSStandIn(t *testing.T) (*kmsStandIn, *httptest.Server) {
+	k := new(kmsStandIn)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		out := map[string]any{}
+
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v1/transit/"):
+			assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+			if strings.HasPrefix(r.URL.Path, "/v1/transit/encrypt/my-key") {
+				out["data"] = map[string]string{"ciphertext": "vault:v1:" + k.wrap(in["plaintext"])}
+			} else {
+				assert.Equal(t, "/v1/transit/decrypt/my-key", r.URL.Path)
+				out["data"] = map[string]string{"plaintext": k.unwrap(t, strings.TrimPrefix(in["ciphertext"], "vault:v1:"))}
+			}
+		case r.URL.Path == "/":
+			assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), r.Header.Get("Authorization"))
+			assert.Contains(t, r.Header.Get("Authorization"), "/eu-central-1/kms/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-target, Signature=")
+			assert.Equal(t, "alias/kratos", in["KeyId"])
+			switch r.Header.Get("X-Amz-Target") {
+			case "TrentService.Encrypt":
+				out["CiphertextBlob"] = base64.StdEncoding.EncodeToString([]byte(k.wrap(in["Plaintext"])))
+			case "TrentService.Decrypt":
+				handle, err := base64.StdEncoding.DecodeString(in["CiphertextBlob"])
+				require.NoError(t, err)
+				out["Plaintext"] = k.unwrap(t, string(handle))
+			default:
+				t.Errorf("unexpected target %q", r.Header.Get("X-Amz-Target"))
+			}
+		default:
+			assert.Equal(t, "Bearer gcp-token", r.Header.Get("Authorization"))
+			switch r.URL.Path {
+			case "/v1/projects/p/locations/global/keyRings/r/cryptoKeys/k:encrypt":
+				out["ciphertext"] = base64.StdEncoding.EncodeToString([]byte(k.wrap(in["plaintext"])))
+			case "/v1/projects/p/locations/global/keyRings/r/cryptoKeys/k:decrypt":
+				handle, err := base64.StdEncoding.DecodeString(in["ciphertext"])
+				require.NoError(t, err)
+				out["plaintext"] = k.unwrap(t, string(handle))
+			default:
+				t.Errorf("unexpected path %q", r.URL.Path)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(out))
+	}))
+	t.Cleanup(srv.Close)
+	return k, srv
+}
+
+func TestKMS(t *testing.T) {
+	t.Parallel()
+
+	standIn, srv := newKMSStandIn(t)
+
+	for provider, conf := range map[string]map[string]any{
+		"vault": {"url": srv.URL, "token": "vault-token", "key": "my-key"},
+		"aws": {
+			"url":               srv.URL + "/",
+			"region":            "eu-central-1",
+			"key_id":            "alias/kratos",
+			"access_key_id":     "AKIDEXAMPLE",
+			"secret_access_key": "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		},
+		"gcp": {"url": srv.URL, "key_name": "projects/p/locations/global/keyRings/r/cryptoKeys/k", "access_token": "gcp-token"},
+	} {
+		t.Run("provider="+provider, func(t *testing.T) {
+			t.Parallel()
+
+			_, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+				config.ViperKeyCipherAlgorithm: "kms",
+				config.ViperKeyCipherKMS:       map[string]any{"provider": provider, provider: conf},
+			}))
+			ctx := t.Context()
+			c := reg.Cipher(ctx)
+			require.IsType(t, new(cipher.KMS), c)
+
+			testAllWork(ctx, t, c)
+
+			first, err := c.Encrypt(ctx, []byte("first"))
+			require.NoError(t, err)
+			second, err := c.Encrypt(ctx, []byte("second"))
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(first, "kms."), first)
+
+			partsFirst := strings.Split(strings.TrimPrefix(first, "kms."), ".")
+			partsSecond := strings.Split(strings.TrimPrefix(second, "kms."), ".")
+			require.Len(t, partsFirst, 3)
+			require.Len(t, partsSecond, 3)
+			assert.Equal(t, partsFirst[1], partsSecond[1], "the data key is reused")
+			wrappedSecond := partsSecond[1]
+
+			kmsConf, err := reg.Config().CipherKMS(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, cipher.KMSKeyID(kmsConf), partsFirst[0])
+			assert.False(t, c.(cipher.Rotator).NeedsRotation(ctx, first))
+
+			t.Run("case=ciphertexts without key ID are decrypted", func(t *testing.T) {
+				legacy := "kms." + partsFirst[1] + "." + partsFirst[2]
+				plaintext, err := c.Decrypt(ctx, legacy)
+				require.NoError(t, err)
+				assert.Equal(t, "first", string(plaintext))
+				assert.True(t, c.(cipher.Rotator).NeedsRotation(ctx, legacy))
+			})
+
+			t.Run("case=unwrapped data keys are cached", func(t *testing.T) {
+				fresh := cipher.NewCryptKMS(ctx, reg)
+				unwraps := standIn.unwraps.Load()
+				for range 3 {
+					plaintext, err := fresh.Decrypt(ctx, first)
+					require.NoError(t, err)
+					assert.Equal(t, "first", string(plaintext))
+				}
+				assert.EqualValues(t, 1, standIn.unwraps.Load()-unwraps)
+			})
+
+			t.Run("case=tampering is detected", func(t *testing.T) {
+				_, err := c.Decrypt(ctx, "kms."+partsSecond[0]+"."+wrappedSecond+"."+strings.Repeat("00", 48))
+				require.Error(t, err)
+
+				_, err = c.Decrypt(ctx, "kms.00000000."+wrappedSecond+"."+partsSecond[2])
+				require.Error(t, err, "the key ID is not configured")
+
+				_, err = c.Decrypt(ctx, strings.TrimPrefix(first, "kms."))
+				require.Error(t, err)
+			})
+		})
+	}
+
+	t.Run("case=ciphertexts of the static cipher are decrypted and rotated", func(t *testing.T) {
+		t.Parallel()
+
+		const secret = "static-secret-thirty-two-chars!!"
+		conf, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+			config.ViperKeySecretsCipher: []string{secret},
+			config.ViperKeyCipherKMS:     map[string]any{"provider": "vault", "vault": map[string]any{"url": srv.URL, "token": "vault-token", "key": "my-key"}},
+		}))
+		ctx := t.Context()
+
+		for name, static := range map[string]cipher.Cipher{
+			"xchacha20-poly1305": cipher.NewCryptChaCha20(conf),
+			"aes":                cipher.NewCryptAES(conf),
+		} {
+			t.Run("algorithm="+name, func(t *testing.T) {
+				legacy, err := static.Encrypt(ctx, []byte("legacy"))
+				require.NoError(t, err)
+
+				c := cipher.NewCryptKMS(ctx, reg)
+				plaintext, err := c.Decrypt(ctx, legacy)
+				require.NoError(t, err)
+				assert.Equal(t, "legacy", string(plaintext))
+
+				rotated, ok, err := cipher.Reencrypt(ctx, c, legacy)
+				require.NoError(t, err)
+				require.True(t, ok)
+				assert.True(t, strings.HasPrefix(rotated, "kms."), rotated)
+				assert.False(t, c.NeedsRotation(ctx, rotated))
+			})
+		}
+	})
+
+	t.Run("case=ciphertexts of previous keys are decrypted and rotated", func(t *testing.T) {
+		t.Parallel()
+
+		vault := map[string]any{"url": srv.URL, "token": "vault-token", "key": "my-key"}
+		gcp := map[string]any{"url": srv.URL, "key_name": "projects/p/locations/global/keyRings/r/cryptoKeys/k", "access_token": "gcp-token"}
+
+		conf, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+			config.ViperKeyCipherKMS: map[string]any{"provider": "vault", "vault": vault},
+		}))
+		ctx := t.Context()
+
+		old, err := cipher.NewCryptKMS(ctx, reg).Encrypt(ctx, []byte("old"))
+		require.NoError(t, err)
+
+		conf.MustSet(ctx, config.ViperKeyCipherKMS, map[string]any{
+			"provider": "gcp",
+			"gcp":      gcp,
+			"previous": []map[string]any{{"provider": "vault", "vault": vault}},
+		})
+		c := cipher.NewCryptKMS(ctx, reg)
+		require.True(t, c.NeedsRotation(ctx, old))
+
+		rotated, ok, err := cipher.Reencrypt(ctx, c, old)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.False(t, c.NeedsRotation(ctx, rotated))
+
+		plaintext, err := c.Decrypt(ctx, rotated)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(plaintext))
+	})
+
+	t.Run("case=unconfigured provider", func(t *testing.T) {
+		t.Parallel()
+
+		_, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+			config.ViperKeyCipherAlgorithm: "kms",
+			config.ViperKeyCipherKMS:       map[string]any{"provider": "vault"},
+		}), configx.SkipValidation())
+		_, err := cipher.NewCryptKMS(t.Context(), reg).Encrypt(t.Context(), []byte("secret"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/driver/config"
+)
+
+// vaultTransit wraps data keys using the transit secrets engine of
+// HashiCorp Vault.
+type vaultTransit struct {
+	c *config.VaultTransitConfig
+	d KMSDependencies
+}
+
+var _ KeyWrapper = new(vaultTransit)
+
+type vaultTransitResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  string `json:"plaintext"`
+	} `json:"data"`
+}
+
+func (v *vaultTransit) endpoint(operation string) string {
+	mount := v.c.Mount
+	if mount == "" {
+		mount = "transit"
+	}
+	return strings.TrimRight(v.c.URL, "/") + "/v1/" + strings.Trim(mount, "/") + "/" + operation + "/" + url.PathEscape(v.c.Key)
+}
+
+func (v *vaultTransit) do(ctx context.Context, operation string, payload any) (*vaultTransitResponse, error) {
+	req, _, err := newKMSRequest(ctx, v.endpoint(operation), payload)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.c.Token)
+	if v.c.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.c.Namespace)
+	}
+
+	var res vaultTransitResponse
+	if err := kmsPostJSON(ctx, v.d, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (v *vaultTransit) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	res, err := v.do(ctx, "encrypt", map[string]string{"plaintext": base64.StdEncoding.EncodeToString(key)})
+	if err != nil {
+		return nil, err
+	}
+	if res.Data.Ciphertext == "" {
+		return nil, errors.New("vault returned an empty ciphertext")
+	}
+	return []byte(res.Data.Ciphertext), nil
+}
+
+func (v *vaultTransit) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	res, err := v.do(ctx, "decrypt", map[string]string{"ciphertext": string(wrapped)})
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(res.Data.Plaintext)
+	return key, errors.WithStack(err)
+}
@@ -0,0 +1,27 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/x/configx"
+)
+
+// NewCipherCmd creates a new cipher command
+func NewCipherCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "cipher",
+		Short: "Commands related to the encryption of stored secrets",
+	}
+	configx.RegisterFlags(c.PersistentFlags())
+	return c
+}
+
+func RegisterCommandRecursive(parent *cobra.Command, dOpts []driver.RegistryOption) {
+	c := NewCipherCmd()
+	parent.AddCommand(c)
+	c.AddCommand(NewRotateCmd(dOpts))
+}
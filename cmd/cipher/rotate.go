@@ -0,0 +1,78 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package cipher
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/flagx"
+)
+
+type outputRotationResult identity.CipherRotationProgress
+
+func (outputRotationResult) Header() []string {
+	return []string{"SCANNED", "ROTATED", "FAILED"}
+}
+
+func (r outputRotationResult) Columns() []string {
+	return []string{strconv.Itoa(r.Scanned), strconv.Itoa(r.Rotated), strconv.Itoa(r.Failed)}
+}
+
+func (r outputRotationResult) Interface() interface{} {
+	return identity.CipherRotationProgress(r)
+}
+
+func NewRotateCmd(dOpts []driver.RegistryOption) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "rotate",
+		Short: "Re-encrypt stored secrets with the primary cipher secret",
+		Long: `Re-encrypt stored secrets, such as the OpenID Connect tokens of identities and the secrets of OpenID Connect providers managed through the admin API, with the primary cipher secret.
+
+To rotate the cipher secret, prepend the new secret to "secrets.cipher" and keep the old secrets configured. Once this command reports no failures, the old secrets can be removed.
+With the "kms" algorithm, this command also re-encrypts secrets written before switching to "kms". To change the key or the provider, move the old configuration to "ciphers.kms.previous" and run this command.
+Alternatively, run "kratos serve --watch-cipher-rotation" on one instance to re-encrypt secrets in the background.`,
+		Example: `{{ .CommandPath }} --config kratos.yml --batch-size 500`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			r, err := driver.New(cmd.Context(), cmd.ErrOrStderr(), append(dOpts, driver.WithConfigOptions(configx.WithFlags(cmd.Flags())))...)
+			if err != nil {
+				return err
+			}
+
+			return RotateCipherKeys(cmd, r)
+		},
+	}
+	c.Flags().Int("batch-size", 100, "The number of rows to process per batch.")
+	cmdx.RegisterFormatFlags(c.Flags())
+	return c
+}
+
+// RotateCipherKeys re-encrypts all stored ciphertexts with the primary
+// cipher secret and prints the progress to stderr.
+func RotateCipherKeys(cmd *cobra.Command, r driver.Registry) error {
+	batchSize := flagx.MustGetInt(cmd, "batch-size")
+	if batchSize <= 0 {
+		return errors.New("--batch-size must be positive")
+	}
+
+	result, err := identity.RotateCipherKeys(cmd.Context(), r, batchSize, func(p identity.CipherRotationProgress) {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Scanned %d ciphertexts, re-encrypted %d, failed %d.\n", p.Scanned, p.Rotated, p.Failed)
+	})
+	if err != nil {
+		return err
+	}
+
+	cmdx.PrintRow(cmd, outputRotationResult(*result))
+	if result.Failed > 0 {
+		return errors.Errorf("%d ciphertexts could not be re-encrypted, see the logs for details", result.Failed)
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ func NewCleanupSQLCmd() *cobra.Command {
 		Long: `Run this command as frequently as you need.
 It is recommended to run this command close to the SQL instance (e.g. same subnet) instead of over the public internet.
 This decreases risk of failure and decreases time required.
+Identities whose deletion grace period has passed are purged together with their sessions, flows and courier messages.
 You can read in the database URL using the -e flag, for example:
 	export DSN=...
 	kratos cleanup sql -e
@@ -43,26 +43,30 @@ var messages map[string]*text.Message
 
 func init() {
 	messages = map[string]*text.Message{
-		"NewInfoNodeLabelVerifyOTP":                       text.NewInfoNodeLabelVerifyOTP(),
-		"NewInfoNodeLabelVerificationCode":                text.NewInfoNodeLabelVerificationCode(),
-		"NewInfoNodeLabelRecoveryCode":                    text.NewInfoNodeLabelRecoveryCode(),
-		"NewInfoNodeInputPassword":                        text.NewInfoNodeInputPassword(),
-		"NewInfoNodeInputPhoneNumber":                     text.NewInfoNodeInputPhoneNumber(),
-		"NewInfoNodeLabelGenerated":                       text.NewInfoNodeLabelGenerated("{title}", "{name}"),
-		"NewInfoNodeLabelSave":                            text.NewInfoNodeLabelSave(),
-		"NewInfoNodeLabelSubmit":                          text.NewInfoNodeLabelSubmit(),
-		"NewInfoNodeLabelID":                              text.NewInfoNodeLabelID(),
-		"NewErrorValidationSettingsFlowExpired":           text.NewErrorValidationSettingsFlowExpired(docExpiredClock, aSecondAgo),
-		"NewInfoSelfServiceSettingsTOTPQRCode":            text.NewInfoSelfServiceSettingsTOTPQRCode(),
-		"NewInfoSelfServiceSettingsTOTPSecret":            text.NewInfoSelfServiceSettingsTOTPSecret("{secret}"),
-		"NewInfoSelfServiceSettingsTOTPSecretLabel":       text.NewInfoSelfServiceSettingsTOTPSecretLabel(),
-		"NewInfoSelfServiceSettingsUpdateSuccess":         text.NewInfoSelfServiceSettingsUpdateSuccess(),
-		"NewInfoSelfServiceSettingsUpdateUnlinkTOTP":      text.NewInfoSelfServiceSettingsUpdateUnlinkTOTP(),
-		"NewInfoSelfServiceSettingsRevealLookup":          text.NewInfoSelfServiceSettingsRevealLookup(),
-		"NewInfoSelfServiceSettingsRegenerateLookup":      text.NewInfoSelfServiceSettingsRegenerateLookup(),
-		"NewInfoSelfServiceSettingsDisableLookup":         text.NewInfoSelfServiceSettingsDisableLookup(),
-		"NewInfoSelfServiceSettingsLookupConfirm":         text.NewInfoSelfServiceSettingsLookupConfirm(),
-		"NewInfoSelfServiceSettingsManagedByOrganization": text.NewInfoSelfServiceSettingsManagedByOrganization(),
+		"NewInfoNodeLabelVerifyOTP":                          text.NewInfoNodeLabelVerifyOTP(),
+		"NewInfoNodeLabelVerificationCode":                   text.NewInfoNodeLabelVerificationCode(),
+		"NewInfoNodeLabelRecoveryCode":                       text.NewInfoNodeLabelRecoveryCode(),
+		"NewInfoNodeInputPassword":                           text.NewInfoNodeInputPassword(),
+		"NewInfoNodeInputPhoneNumber":                        text.NewInfoNodeInputPhoneNumber(),
+		"NewInfoNodeLabelGenerated":                          text.NewInfoNodeLabelGenerated("{title}", "{name}"),
+		"NewInfoNodeLabelSave":                               text.NewInfoNodeLabelSave(),
+		"NewInfoNodeLabelSubmit":                             text.NewInfoNodeLabelSubmit(),
+		"NewInfoNodeLabelID":                                 text.NewInfoNodeLabelID(),
+		"NewErrorValidationSettingsFlowExpired":              text.NewErrorValidationSettingsFlowExpired(docExpiredClock, aSecondAgo),
+		"NewInfoSelfServiceSettingsTOTPQRCode":               text.NewInfoSelfServiceSettingsTOTPQRCode(),
+		"NewInfoSelfServiceSettingsTOTPSecret":               text.NewInfoSelfServiceSettingsTOTPSecret("{secret}"),
+		"NewInfoSelfServiceSettingsTOTPSecretLabel":          text.NewInfoSelfServiceSettingsTOTPSecretLabel(),
+		"NewInfoSelfServiceSettingsUpdateSuccess":            text.NewInfoSelfServiceSettingsUpdateSuccess(),
+		"NewInfoSelfServiceSettingsUpdateUnlinkTOTP":         text.NewInfoSelfServiceSettingsUpdateUnlinkTOTP(),
+		"NewInfoSelfServiceSettingsRevealLookup":             text.NewInfoSelfServiceSettingsRevealLookup(),
+		"NewInfoSelfServiceSettingsRegenerateLookup":         text.NewInfoSelfServiceSettingsRegenerateLookup(),
+		"NewInfoSelfServiceSettingsDisableLookup":            text.NewInfoSelfServiceSettingsDisableLookup(),
+		"NewInfoSelfServiceSettingsLookupConfirm":            text.NewInfoSelfServiceSettingsLookupConfirm(),
+		"NewInfoSelfServiceSettingsManagedByOrganization":    text.NewInfoSelfServiceSettingsManagedByOrganization(),
+		"NewInfoSelfServiceSettingsDeleteAccount":            text.NewInfoSelfServiceSettingsDeleteAccount(),
+		"NewInfoSelfServiceSettingsDeleteAccountConfirm":     text.NewInfoSelfServiceSettingsDeleteAccountConfirm("{phrase}"),
+		"NewInfoSelfServiceSettingsAccountDeleted":           text.NewInfoSelfServiceSettingsAccountDeleted(),
+		"NewInfoSelfServiceSettingsAccountDeletionScheduled": text.NewInfoSelfServiceSettingsAccountDeletionScheduled(inAMinute),
 		"NewInfoSelfServiceSettingsLookupSecretList": text.NewInfoSelfServiceSettingsLookupSecretList([]string{"{secrets_list}"}, []interface{}{
 			text.NewInfoSelfServiceSettingsLookupSecret("{secret}"),
 			text.NewInfoSelfServiceSettingsLookupSecretUsed(aSecondAgo),
@@ -119,6 +123,7 @@ func init() {
 		"NewErrorValidationDeviceAuthnVerifierWrong":                   text.NewErrorValidationDeviceAuthnVerifierWrong(),
 		"NewErrorValidationDeviceAuthnRelaxedAttestationNoLongerValid": text.NewErrorValidationDeviceAuthnRelaxedAttestationNoLongerValid(),
 		"NewErrorValidationDeviceAuthnKeyReenrollmentRequired":         text.NewErrorValidationDeviceAuthnKeyReenrollmentRequired(),
+		"NewErrorValidationTraitNotWritable":                           text.NewErrorValidationTraitNotWritable("{property}"),
 		"NewErrorValidationLookupAlreadyUsed":                          text.NewErrorValidationLookupAlreadyUsed(),
 		"NewErrorValidationLookupInvalid":                              text.NewErrorValidationLookupInvalid(),
 		"NewErrorValidationIdentifierMissing":                          text.NewErrorValidationIdentifierMissing(),
@@ -205,6 +210,7 @@ func init() {
 		"NewErrorValidationPhone":                                      text.NewErrorValidationPhone("{value}"),
 		"NewErrorValidationIdentityDisabled":                           text.NewErrorValidationIdentityDisabled(),
 		"NewErrorValidationSettingsTooManyAddressChanges":              text.NewErrorValidationSettingsTooManyAddressChanges(),
+		"NewErrorValidationSettingsDeletionNotConfirmed":               text.NewErrorValidationSettingsDeletionNotConfirmed("{phrase}"),
 	}
 }
 
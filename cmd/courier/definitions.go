@@ -0,0 +1,116 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/x/cmdx"
+)
+
+type (
+	outputMessage           courier.Message
+	outputMessageCollection struct {
+		Messages      []courier.Message `json:"messages"`
+		NextPageToken string            `json:"next_page_token,omitempty"`
+	}
+	outputMessageDetail courier.Message
+	outputRequeueResult struct {
+		Requeued int `json:"requeued"`
+	}
+)
+
+func (outputMessage) Header() []string {
+	return []string{"ID", "STATUS", "TYPE", "CHANNEL", "RECIPIENT", "TEMPLATE", "SEND COUNT", "CREATED AT"}
+}
+
+func (m outputMessage) Columns() []string {
+	channel := cmdx.None
+	if m.Channel != "" {
+		channel = m.Channel.String()
+	}
+	return []string{
+		m.ID.String(),
+		m.Status.String(),
+		m.Type.String(),
+		channel,
+		m.Recipient,
+		string(m.TemplateType),
+		strconv.Itoa(m.SendCount),
+		m.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func (m outputMessage) Interface() interface{} {
+	return courier.Message(m)
+}
+
+func (outputMessageCollection) Header() []string {
+	return outputMessage{}.Header()
+}
+
+func (c outputMessageCollection) Table() [][]string {
+	rows := make([][]string, len(c.Messages))
+	for i, m := range c.Messages {
+		rows[i] = outputMessage(m).Columns()
+	}
+	return append(rows,
+		[]string{""},
+		[]string{"NEXT PAGE TOKEN", c.NextPageToken},
+	)
+}
+
+func (c outputMessageCollection) Interface() interface{} {
+	return c
+}
+
+func (c *outputMessageCollection) Len() int {
+	return len(c.Messages)
+}
+
+func (outputMessageDetail) Header() []string {
+	return append(outputMessage{}.Header(), "SUBJECT", "DISPATCHES")
+}
+
+func (m outputMessageDetail) Columns() []string {
+	dispatches := make([]string, 0, len(m.Dispatches))
+	for _, d := range m.Dispatches {
+		line := fmt.Sprintf("%s %s", d.CreatedAt.UTC().Format(time.RFC3339), d.Status)
+		if len(d.Error) > 0 {
+			reason := gjson.GetBytes(d.Error, "reason").String()
+			if reason == "" {
+				reason = gjson.GetBytes(d.Error, "message").String()
+			}
+			line += ": " + reason
+		}
+		dispatches = append(dispatches, line)
+	}
+	if len(dispatches) == 0 {
+		dispatches = append(dispatches, cmdx.None)
+	}
+
+	return append(outputMessage(m).Columns(), m.Subject, strings.Join(dispatches, "\n"))
+}
+
+func (m outputMessageDetail) Interface() interface{} {
+	return courier.Message(m)
+}
+
+func (outputRequeueResult) Header() []string {
+	return []string{"REQUEUED"}
+}
+
+func (r outputRequeueResult) Columns() []string {
+	return []string{strconv.Itoa(r.Requeued)}
+}
+
+func (r outputRequeueResult) Interface() interface{} {
+	return r
+}
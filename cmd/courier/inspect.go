@@ -0,0 +1,59 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+)
+
+func NewInspectCmd(dOpts []driver.RegistryOption) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "inspect <id>",
+		Short: "Show a courier message and all of its delivery attempts",
+		Long: `Show a courier message together with every delivery attempt and the error it failed with.
+
+This command reads the message directly from the database configured in the Ory Kratos configuration.`,
+		Example: "{{ .CommandPath }} 3a9a3d43-6d8c-4b6b-a3c4-0c4b8ddd5c8e --format json",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := driver.New(cmd.Context(), cmd.ErrOrStderr(), append(dOpts, driver.WithConfigOptions(configx.WithFlags(cmd.Flags())))...)
+			if err != nil {
+				return err
+			}
+
+			return InspectMessage(cmd, r, args[0])
+		},
+	}
+	cmdx.RegisterFormatFlags(c.Flags())
+	return c
+}
+
+// InspectMessage prints the message with the given ID and its dispatches.
+func InspectMessage(cmd *cobra.Command, r driver.Registry, id string) error {
+	ctx := cmd.Context()
+
+	messageID, err := uuid.FromString(id)
+	if err != nil {
+		return errors.Errorf("invalid message id %q: %s", id, err)
+	}
+
+	message, err := r.CourierPersister().FetchMessage(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !r.Config().IsInsecureDevMode(ctx) {
+		message.Subject = redactedUnlessDevMode
+		message.Body = redactedUnlessDevMode
+	}
+
+	cmdx.PrintRow(cmd, outputMessageDetail(*message))
+	return nil
+}
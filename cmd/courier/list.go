@@ -0,0 +1,94 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/driver"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/flagx"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+)
+
+// redactedUnlessDevMode replaces message contents in the output, as the
+// admin API does.
+const redactedUnlessDevMode = "<redacted-unless-dev-mode>"
+
+func NewListCmd(dOpts []driver.RegistryOption) *cobra.Command {
+	c := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List courier messages",
+		Long: `List the messages stored in the courier queue, newest first.
+
+This command reads the messages directly from the database configured in the Ory Kratos configuration.`,
+		Example: "{{ .CommandPath }} --status abandoned --page-size 50",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := driver.New(cmd.Context(), cmd.ErrOrStderr(), append(dOpts, driver.WithConfigOptions(configx.WithFlags(cmd.Flags())))...)
+			if err != nil {
+				return err
+			}
+
+			return ListMessages(cmd, r)
+		},
+	}
+	c.Flags().String("status", "", "Only list messages with this status. One of queued, processing, sent, or abandoned.")
+	c.Flags().String("recipient", "", "Only list messages sent to this recipient.")
+	cmdx.RegisterTokenPaginationFlags(c)
+	cmdx.RegisterFormatFlags(c.Flags())
+	return c
+}
+
+// ListMessages prints a page of courier messages.
+func ListMessages(cmd *cobra.Command, r driver.Registry) error {
+	ctx := cmd.Context()
+
+	var filter courier.ListCourierMessagesParameters
+	if s := flagx.MustGetString(cmd, "status"); s != "" {
+		status, err := courier.ToMessageStatus(s)
+		if err != nil {
+			return err
+		}
+		filter.Status = &status
+	}
+	filter.Recipient = flagx.MustGetString(cmd, "recipient")
+
+	pageToken, pageSize, err := cmdx.ParseTokenPaginationArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	keys := r.Config().SecretsPagination(ctx)
+	opts := []keysetpagination.Option{keysetpagination.WithSize(pageSize)}
+	if pageToken != "" {
+		token, err := keysetpagination.ParsePageToken(keys, pageToken)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, keysetpagination.WithToken(token))
+	}
+
+	messages, nextPage, err := r.CourierPersister().ListMessages(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+
+	out := &outputMessageCollection{Messages: messages}
+	if !nextPage.IsLast() {
+		out.NextPageToken = nextPage.PageToken().Encrypt(keys)
+	}
+	if !r.Config().IsInsecureDevMode(ctx) {
+		for i := range out.Messages {
+			out.Messages[i].Subject = redactedUnlessDevMode
+			out.Messages[i].Body = redactedUnlessDevMode
+		}
+	}
+
+	cmdx.PrintTable(cmd, out)
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/pkg"
+)
+
+func newTestCmd(t *testing.T, c *cobra.Command, flags map[string]string) (*cobra.Command, *bytes.Buffer) {
+	var out bytes.Buffer
+	c.SetContext(t.Context())
+	c.SetOut(&out)
+	require.NoError(t, c.Flags().Set("format", "json"))
+	for k, v := range flags {
+		require.NoError(t, c.Flags().Set(k, v))
+	}
+	return c, &out
+}
+
+func TestMessageCommands(t *testing.T) {
+	conf, reg := pkg.NewFastRegistryWithMocks(t)
+	ctx := t.Context()
+	p := reg.CourierPersister()
+
+	sent := courier.Message{Type: courier.MessageTypeEmail, Recipient: "sent@example.org", Subject: "secret", Channel: "email"}
+	abandoned := courier.Message{Type: courier.MessageTypeEmail, Recipient: "abandoned@example.org", Channel: "email"}
+	for _, m := range []*courier.Message{&sent, &abandoned} {
+		require.NoError(t, p.AddMessage(ctx, m))
+	}
+	require.NoError(t, p.SetMessageStatus(ctx, sent.ID, courier.MessageStatusSent))
+	require.NoError(t, p.IncrementMessageSendCount(ctx, abandoned.ID))
+	require.NoError(t, p.RecordDispatch(ctx, abandoned.ID, courier.CourierMessageDispatchStatusFailed, errors.New("connection refused")))
+	require.NoError(t, p.SetMessageStatus(ctx, abandoned.ID, courier.MessageStatusAbandoned))
+
+	t.Run("command=list", func(t *testing.T) {
+		cmd, out := newTestCmd(t, NewListCmd(nil), map[string]string{"status": "abandoned"})
+		require.NoError(t, ListMessages(cmd, reg))
+
+		messages := gjson.Get(out.String(), "messages").Array()
+		require.Len(t, messages, 1, out.String())
+		assert.Equal(t, abandoned.ID.String(), messages[0].Get("id").String())
+		assert.Equal(t, "abandoned", messages[0].Get("status").String())
+
+		t.Run("case=paginates", func(t *testing.T) {
+			cmd, out := newTestCmd(t, NewListCmd(nil), map[string]string{"page-size": "1"})
+			require.NoError(t, ListMessages(cmd, reg))
+			token := gjson.Get(out.String(), "next_page_token").String()
+			require.NotEmpty(t, token, out.String())
+			first := gjson.Get(out.String(), "messages.0.id").String()
+
+			cmd, out = newTestCmd(t, NewListCmd(nil), map[string]string{"page-size": "1", "page-token": token})
+			require.NoError(t, ListMessages(cmd, reg))
+			second := gjson.Get(out.String(), "messages.0.id").String()
+			assert.NotEmpty(t, second)
+			assert.NotEqual(t, first, second)
+		})
+
+		t.Run("case=invalid status", func(t *testing.T) {
+			cmd, _ := newTestCmd(t, NewListCmd(nil), map[string]string{"status": "unknown"})
+			require.Error(t, ListMessages(cmd, reg))
+		})
+	})
+
+	t.Run("command=inspect", func(t *testing.T) {
+		cmd, out := newTestCmd(t, NewInspectCmd(nil), nil)
+		require.NoError(t, InspectMessage(cmd, reg, abandoned.ID.String()))
+
+		var actual courier.Message
+		require.NoError(t, json.Unmarshal(out.Bytes(), &actual), out.String())
+		assert.Equal(t, abandoned.ID, actual.ID)
+		require.Len(t, actual.Dispatches, 1)
+		assert.Equal(t, courier.CourierMessageDispatchStatusFailed, actual.Dispatches[0].Status)
+		assert.Contains(t, string(actual.Dispatches[0].Error), "connection refused")
+
+		t.Run("case=table output lists the dispatch errors", func(t *testing.T) {
+			cmd, out := newTestCmd(t, NewInspectCmd(nil), map[string]string{"format": "table"})
+			require.NoError(t, InspectMessage(cmd, reg, abandoned.ID.String()))
+			assert.Contains(t, out.String(), "failed: connection refused")
+		})
+
+		t.Run("case=content is redacted", func(t *testing.T) {
+			conf.MustSet(ctx, "dev", false)
+			cmd, out := newTestCmd(t, NewInspectCmd(nil), nil)
+			require.NoError(t, InspectMessage(cmd, reg, sent.ID.String()))
+			assert.Equal(t, redactedUnlessDevMode, gjson.Get(out.String(), "subject").String())
+		})
+
+		t.Run("case=unknown message", func(t *testing.T) {
+			cmd, _ := newTestCmd(t, NewInspectCmd(nil), nil)
+			require.Error(t, InspectMessage(cmd, reg, uuid.Must(uuid.NewV4()).String()))
+			require.Error(t, InspectMessage(cmd, reg, "not-a-uuid"))
+		})
+	})
+
+	t.Run("command=requeue", func(t *testing.T) {
+		t.Run("case=by id", func(t *testing.T) {
+			cmd, out := newTestCmd(t, NewRequeueCmd(nil), nil)
+			require.NoError(t, RequeueMessages(cmd, reg, []string{abandoned.ID.String()}))
+			assert.EqualValues(t, 1, gjson.Get(out.String(), "requeued").Int())
+
+			actual, err := p.FetchMessage(ctx, abandoned.ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusQueued, actual.Status)
+			assert.Zero(t, actual.SendCount)
+
+			require.Error(t, RequeueMessages(cmd, reg, []string{sent.ID.String()}), "sent messages can not be requeued")
+		})
+
+		t.Run("case=by status", func(t *testing.T) {
+			require.NoError(t, p.SetMessageStatus(ctx, abandoned.ID, courier.MessageStatusAbandoned))
+
+			cmd, out := newTestCmd(t, NewRequeueCmd(nil), map[string]string{"status": "abandoned", "since": "1h"})
+			require.NoError(t, RequeueMessages(cmd, reg, nil))
+			assert.EqualValues(t, 1, gjson.Get(out.String(), "requeued").Int())
+
+			actual, err := p.FetchMessage(ctx, abandoned.ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusQueued, actual.Status)
+		})
+
+		t.Run("case=outside of the time window", func(t *testing.T) {
+			require.NoError(t, p.SetMessageStatus(ctx, abandoned.ID, courier.MessageStatusAbandoned))
+			time.Sleep(10 * time.Millisecond)
+
+			cmd, out := newTestCmd(t, NewRequeueCmd(nil), map[string]string{"since": "1ns"})
+			require.NoError(t, RequeueMessages(cmd, reg, nil))
+			assert.EqualValues(t, 0, gjson.Get(out.String(), "requeued").Int())
+		})
+
+		t.Run("case=sent messages can not be requeued", func(t *testing.T) {
+			cmd, _ := newTestCmd(t, NewRequeueCmd(nil), map[string]string{"status": "sent"})
+			require.Error(t, RequeueMessages(cmd, reg, nil))
+		})
+	})
+}
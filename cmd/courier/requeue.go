@@ -0,0 +1,85 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/driver"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/flagx"
+)
+
+func NewRequeueCmd(dOpts []driver.RegistryOption) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "requeue [<id> ...]",
+		Short: "Put courier messages back into the queue",
+		Long: `Put courier messages back into the queue and reset their send count, so that the courier delivers them again.
+
+If message IDs are given, these abandoned messages are requeued. Otherwise, all messages with the given status created within the given duration are requeued.
+Use this command after resolving an outage of the mail or SMS provider. Requeueing messages stuck in the processing status is only safe if no courier is running.`,
+		Example: `{{ .CommandPath }} --status abandoned --since 1h
+{{ .CommandPath }} 3a9a3d43-6d8c-4b6b-a3c4-0c4b8ddd5c8e`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := driver.New(cmd.Context(), cmd.ErrOrStderr(), append(dOpts, driver.WithConfigOptions(configx.WithFlags(cmd.Flags())))...)
+			if err != nil {
+				return err
+			}
+
+			return RequeueMessages(cmd, r, args)
+		},
+	}
+	c.Flags().String("status", courier.MessageStatusAbandoned.String(), "Requeue messages with this status. One of abandoned or processing.")
+	c.Flags().Duration("since", time.Hour, "Requeue messages created within this duration.")
+	cmdx.RegisterFormatFlags(c.Flags())
+	return c
+}
+
+// RequeueMessages requeues the messages with the given IDs, or all messages
+// matching the status and since flags if no IDs are given.
+func RequeueMessages(cmd *cobra.Command, r driver.Registry, ids []string) error {
+	ctx := cmd.Context()
+	p := r.CourierPersister()
+
+	if len(ids) > 0 {
+		for _, id := range ids {
+			messageID, err := uuid.FromString(id)
+			if err != nil {
+				return errors.Errorf("invalid message id %q: %s", id, err)
+			}
+			if err := p.RequeueMessage(ctx, messageID); err != nil {
+				return errors.WithMessagef(err, "unable to requeue message %s, it may not exist or not be abandoned", id)
+			}
+		}
+		cmdx.PrintRow(cmd, outputRequeueResult{Requeued: len(ids)})
+		return nil
+	}
+
+	status, err := courier.ToMessageStatus(flagx.MustGetString(cmd, "status"))
+	if err != nil {
+		return err
+	}
+	if status != courier.MessageStatusAbandoned && status != courier.MessageStatusProcessing {
+		return errors.Errorf("only abandoned or processing messages can be requeued, got %q", status)
+	}
+
+	since := flagx.MustGetDuration(cmd, "since")
+	if since <= 0 {
+		return errors.New("--since must be a positive duration")
+	}
+
+	count, err := p.RequeueMessages(ctx, status, time.Now().UTC().Add(-since))
+	if err != nil {
+		return err
+	}
+
+	cmdx.PrintRow(cmd, outputRequeueResult{Requeued: count})
+	return nil
+}
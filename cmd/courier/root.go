@@ -23,5 +23,10 @@ func NewCourierCmd() *cobra.Command {
 func RegisterCommandRecursive(parent *cobra.Command, dOpts []driver.RegistryOption) {
 	c := NewCourierCmd()
 	parent.AddCommand(c)
-	c.AddCommand(NewWatchCmd(dOpts))
+	c.AddCommand(
+		NewWatchCmd(dOpts),
+		NewListCmd(dOpts),
+		NewInspectCmd(dOpts),
+		NewRequeueCmd(dOpts),
+	)
 }
@@ -339,6 +339,15 @@ func courierTask(ctx context.Context, d driver.Registry) func() error {
 	}
 }
 
+func cipherRotationTask(ctx context.Context, d driver.Registry) func() error {
+	return func() error {
+		if d.Config().IsBackgroundCipherRotationEnabled(ctx) {
+			return identity.WatchCipherRotation(ctx, d, d.Config().CipherRotationInterval(ctx), d.Config().CipherRotationBatchSize(ctx))
+		}
+		return nil
+	}
+}
+
 func ServeAll(d *driver.RegistryDefault) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
 		ctx := cmd.Context()
@@ -362,6 +371,7 @@ func ServeAll(d *driver.RegistryDefault) func(cmd *cobra.Command, args []string)
 			publicSrv,
 			adminSrv,
 			courierTask(ctx, d),
+			cipherRotationTask(ctx, d),
 		}
 		for _, task := range tasks {
 			g.Go(task)
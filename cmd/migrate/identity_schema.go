@@ -0,0 +1,139 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package migrate
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/kratos/driver"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/flagx"
+)
+
+func NewMigrateIdentitySchemaCmd(opts ...driver.RegistryOption) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "identity-schema <migration-id> [database-url]",
+		Short: "Migrate identities to another identity schema",
+		Long: `Runs an identity schema migration configured in identity.schema_migrations.
+
+All identities using the migration's "from" schema are transformed with the
+migration's Jsonnet snippet and moved to its "to" schema. Identities which can
+not be transformed or do not validate against the "to" schema are listed in a
+report at the end and keep their schema, so running the migration again retries
+them.
+
+Use --dry-run to validate all identities against the "to" schema without
+changing them. The migration can be interrupted and resumed using the
+--start-after flag with the last ID printed in the progress output.
+
+You can read in the database URL using the -e flag, for example:
+	export DSN=...
+	kratos migrate identity-schema customer-v1-to-v2 -e
+
+This command is also available as "kratos identities migrate-schema".
+
+### WARNING ###
+Before running this command on an existing database, create a back up!
+`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := MigrateIdentitySchema(cmd, args, opts...)
+			if err != nil {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), err)
+				return err
+			}
+			return nil
+		},
+	}
+
+	configx.RegisterFlags(c.PersistentFlags())
+	c.Flags().BoolP("read-from-env", "e", false, "If set, reads the database connection string from the environment variable DSN or config file key dsn.")
+	c.Flags().IntP("batch-size", "b", 100, "Number of identities to process per batch")
+	c.Flags().Bool("dry-run", false, "If set, only validate the migrated identities without writing them")
+	c.Flags().String("start-after", "", "Resume after the last processed identity ID")
+
+	return c
+}
+
+// NewIdentitiesCmd groups the identity maintenance commands which operate on
+// the database directly.
+func NewIdentitiesCmd(opts ...driver.RegistryOption) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "identities",
+		Short: "Identity maintenance helpers",
+	}
+
+	migrateSchema := NewMigrateIdentitySchemaCmd(opts...)
+	migrateSchema.Use = "migrate-schema <migration-id> [database-url]"
+	c.AddCommand(migrateSchema)
+
+	return c
+}
+
+func MigrateIdentitySchema(cmd *cobra.Command, args []string, opts ...driver.RegistryOption) error {
+	d, err := getPersister(cmd, args[1:], opts)
+	if err != nil {
+		return err
+	}
+
+	m, err := d.Config().IdentitySchemaMigration(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	o := identity.SchemaMigrationOptions{
+		DryRun:    flagx.MustGetBool(cmd, "dry-run"),
+		BatchSize: flagx.MustGetInt(cmd, "batch-size"),
+	}
+	if startAfter := flagx.MustGetString(cmd, "start-after"); startAfter != "" {
+		if o.After, err = uuid.FromString(startAfter); err != nil {
+			return errors.Wrapf(err, "invalid UUID in --start-after %q", startAfter)
+		}
+	}
+	if o.DryRun {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Dry run mode enabled. No changes will be written.")
+	}
+
+	report, err := identity.MigrateSchema(cmd.Context(), d, m, o, func(r identity.SchemaMigrationReport) {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "processed %d identities so far (--start-after %s)\n", r.Scanned, r.NextPageToken)
+	})
+	if err != nil {
+		return err
+	}
+
+	printSchemaMigrationReport(cmd, report)
+	if len(report.Failures) > 0 {
+		return cmdx.FailSilently(cmd)
+	}
+	return nil
+}
+
+func printSchemaMigrationReport(cmd *cobra.Command, report *identity.SchemaMigrationReport) {
+	out := cmd.OutOrStdout()
+
+	_, _ = fmt.Fprintln(out)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "SCANNED\tMIGRATED\tFAILED")
+	_, _ = fmt.Fprintf(tw, "%d\t%d\t%d\n", report.Scanned, report.Migrated, len(report.Failures))
+	_ = tw.Flush()
+
+	if len(report.Failures) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(out)
+	_, _ = fmt.Fprintln(tw, "IDENTITY ID\tERROR")
+	for _, f := range report.Failures {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\n", f.IdentityID, f.Error)
+	}
+	_ = tw.Flush()
+}
@@ -24,8 +24,10 @@ func RegisterCommandRecursive(parent *cobra.Command) {
 	configx.RegisterFlags(c.PersistentFlags())
 	c.AddCommand(NewMigrateSQLCmd())
 	c.AddCommand(NewNormalizePhoneCmd())
+	c.AddCommand(NewMigrateIdentitySchemaCmd())
 
 	parent.AddCommand(c)
+	parent.AddCommand(NewIdentitiesCmd())
 }
 
 func NewMigrateSQLDownCmd(opts ...driver.RegistryOption) *cobra.Command {
@@ -50,6 +50,7 @@ DON'T DO THIS IN PRODUCTION!
 	serveCmd.PersistentFlags().Bool("sqa-opt-out", false, "Disable anonymized telemetry reports - for more information please visit https://www.ory.com/docs/ecosystem/sqa")
 	serveCmd.PersistentFlags().Bool("dev", false, "Disables critical security features to make development easier")
 	serveCmd.PersistentFlags().Bool("watch-courier", false, "Run the message courier as a background task, to simplify single-instance setup")
+	serveCmd.PersistentFlags().Bool("watch-cipher-rotation", false, "Re-encrypt stored secrets with the primary cipher secret as a background task. Enable it on one instance only")
 	return serveCmd
 }
 
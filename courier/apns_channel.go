@@ -0,0 +1,160 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/otelx"
+)
+
+const (
+	apnsDefaultURL = "https://api.push.apple.com"
+
+	// APNs rejects provider tokens older than one hour and throttles
+	// providers which refresh them more often than every 20 minutes.
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+type apnsProviderToken struct {
+	token    string
+	issuedAt time.Time
+}
+
+// apnsProviderTokens caches the signed provider tokens per team and key.
+var apnsProviderTokens sync.Map
+
+type apnsChannel struct {
+	id  string
+	cfg *config.APNsConfig
+	d   channelDependencies
+}
+
+var _ Channel = new(apnsChannel)
+
+func newAPNsChannel(id string, cfg *config.APNsConfig, d channelDependencies) (*apnsChannel, error) {
+	if cfg == nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Courier channel %q is missing its apns_config.", id))
+	}
+	return &apnsChannel{id: id, cfg: cfg, d: d}, nil
+}
+
+func (c *apnsChannel) ID() string {
+	return c.id
+}
+
+func (c *apnsChannel) providerToken() (string, error) {
+	cacheKey := c.cfg.TeamID + "/" + c.cfg.KeyID
+	if cached, ok := apnsProviderTokens.Load(cacheKey); ok {
+		if t := cached.(*apnsProviderToken); time.Since(t.issuedAt) < apnsTokenLifetime {
+			return t.token, nil
+		}
+	}
+
+	raw, err := loadChannelSecret(c.cfg.PrivateKey, c.cfg.PrivateKeyPath)
+	if err != nil {
+		return "", err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return "", errors.WithStack(herodot.ErrMisconfiguration().WithReason("The APNs authentication key is not PEM encoded."))
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return "", errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to parse the APNs authentication key: %s", err))
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return "", errors.WithStack(herodot.ErrMisconfiguration().WithReason("The APNs authentication key must be an ECDSA key."))
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:   c.cfg.TeamID,
+		IssuedAt: jwt.NewNumericDate(now),
+	})
+	token.Header["kid"] = c.cfg.KeyID
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	apnsProviderTokens.Store(cacheKey, &apnsProviderToken{token: signed, issuedAt: now})
+	return signed, nil
+}
+
+type apnsRequest struct {
+	APS          apnsPayload `json:"aps"`
+	TemplateType string      `json:"template_type"`
+}
+
+type apnsPayload struct {
+	Alert apnsAlert `json:"alert"`
+}
+
+type apnsAlert struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body"`
+}
+
+func (c *apnsChannel) Dispatch(ctx context.Context, msg Message) (err error) {
+	ctx, span := c.d.Tracer(ctx).Tracer().Start(ctx, "courier.apnsChannel.Dispatch")
+	defer otelx.End(span, &err)
+
+	_, deviceToken, err := x.ParsePushAddress(msg.Recipient)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	token, err := c.providerToken()
+	if err != nil {
+		return err
+	}
+
+	base := c.cfg.URL
+	if base == "" {
+		base = apnsDefaultURL
+	}
+	endpoint := strings.TrimRight(base, "/") + "/3/device/" + url.PathEscape(deviceToken)
+
+	header := http.Header{}
+	header.Set("Authorization", "bearer "+token)
+	header.Set("apns-topic", c.cfg.Topic)
+	header.Set("apns-push-type", "alert")
+	header.Set("apns-priority", "10")
+
+	if err := postJSON(ctx, c.d, endpoint, header, &apnsRequest{
+		APS:          apnsPayload{Alert: apnsAlert{Title: c.cfg.Title, Body: msg.Body}},
+		TemplateType: string(msg.TemplateType),
+	}); err != nil {
+		c.d.Logger().
+			WithError(err).
+			WithField("message_id", msg.ID).
+			WithField("message_nid", msg.NID).
+			WithField("message_template_type", msg.TemplateType).
+			Error("Sending push notification via APNs failed.")
+		return err
+	}
+
+	c.d.Logger().
+		WithField("message_id", msg.ID).
+		WithField("message_nid", msg.NID).
+		Debug("Courier sent out push notification via APNs.")
+	return nil
+}
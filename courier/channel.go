@@ -5,9 +5,67 @@ package courier
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/httpx"
 )
 
 type Channel interface {
 	ID() string
 	Dispatch(ctx context.Context, msg Message) error
 }
+
+// postJSON sends the payload to the URL and fails if the upstream server does
+// not reply with a 2xx status code.
+func postJSON(ctx context.Context, d channelDependencies, url string, header http.Header, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := d.HTTPClient(ctx,
+		// fail fast and let the courier retry if needed instead of blocking the queue
+		httpx.ResilientClientWithMaxRetry(0),
+		httpx.ResilientClientWithConnectionTimeout(10*time.Second),
+	).Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+
+	resBody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return errors.Errorf("upstream server replied with status code %d: %s", res.StatusCode, resBody)
+}
+
+// loadChannelSecret returns the contents of path if set, and value otherwise.
+func loadChannelSecret(value, path string) ([]byte, error) {
+	if path == "" {
+		return []byte(value), nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to read %s: %s", path, err))
+	}
+	return raw, nil
+}
@@ -34,6 +34,8 @@ type (
 		Work(ctx context.Context) error
 		QueueEmail(ctx context.Context, t EmailTemplate) (uuid.UUID, error)
 		QueueSMS(ctx context.Context, t SMSTemplate) (uuid.UUID, error)
+		QueuePush(ctx context.Context, t SMSTemplate) (uuid.UUID, error)
+		QueueWhatsApp(ctx context.Context, t SMSTemplate) (uuid.UUID, error)
 		DispatchQueue(ctx context.Context) error
 		DispatchMessage(ctx context.Context, msg Message) error
 		UseBackoff(b backoff.BackOff)
@@ -11,21 +11,34 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/ory/kratos/x"
 	"github.com/ory/kratos/x/events"
 	"github.com/ory/x/otelx"
 	"github.com/ory/x/otelx/semconv"
 )
 
-func (c *courier) channels(ctx context.Context, id string) (Channel, error) {
+// channels returns the channel delivering messages to the recipient via the
+// channel with the given id. Push notifications are delivered through the FCM
+// or APNs channel of the recipient's platform, so that several push channels
+// may share the "push" id.
+func (c *courier) channels(ctx context.Context, id, recipient string) (Channel, error) {
 	cs, err := c.deps.CourierConfig().CourierChannels(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var platform string
+	if id == "push" {
+		platform, _, _ = x.ParsePushAddress(recipient)
+	}
+
 	for _, channel := range cs {
 		if channel.ID != id {
 			continue
 		}
+		if (channel.Type == "fcm" || channel.Type == "apns") && channel.Type != platform {
+			continue
+		}
 		switch channel.Type {
 		case "smtp":
 			courierChannel, err := NewSMTPChannelWithCustomTemplates(c.deps, channel.SMTPConfig, c.newEmailTemplateFromMessage)
@@ -35,11 +48,20 @@ func (c *courier) channels(ctx context.Context, id string) (Channel, error) {
 			return courierChannel, nil
 		case "http":
 			return newHttpChannel(channel.ID, &channel.RequestConfig, c.deps), nil
+		case "fcm":
+			return newFCMChannel(channel.ID, channel.FCMConfig, c.deps)
+		case "apns":
+			return newAPNsChannel(channel.ID, channel.APNsConfig, c.deps)
+		case "whatsapp":
+			return newWhatsAppChannel(channel.ID, channel.WhatsAppConfig, c.deps)
 		default:
 			return nil, errors.Errorf("unknown courier channel type: %s", channel.Type)
 		}
 	}
 
+	if platform != "" {
+		return nil, errors.Errorf("no courier channels configured for: %s (platform %s)", id, platform)
+	}
 	return nil, errors.Errorf("no courier channels configured for: %s", id)
 }
 
@@ -69,7 +91,7 @@ func (c *courier) DispatchMessage(ctx context.Context, msg Message) (err error)
 		return err
 	}
 
-	channel, err := c.channels(ctx, msg.Channel.String())
+	channel, err := c.channels(ctx, msg.Channel.String(), msg.Recipient)
 	if err != nil {
 		return err
 	}
@@ -90,6 +112,20 @@ func (c *courier) DispatchMessage(ctx context.Context, msg Message) (err error)
 		return err
 	}
 
+	if c.deps.CourierConfig().CourierMessageRedactionEnabled(ctx) {
+		// The message was sent, so failing to redact it must not lead to it
+		// being sent again.
+		if err := msg.Redact(); err != nil {
+			logger.
+				WithError(err).
+				Error(`Unable to redact the sent message.`)
+		} else if err := c.deps.CourierPersister().RedactMessage(ctx, &msg); err != nil {
+			logger.
+				WithError(err).
+				Error(`Unable to store the redacted message.`)
+		}
+	}
+
 	dispatchDuration := time.Since(msg.CreatedAt).Milliseconds()
 	logger.WithField("dispatch_duration_ms", dispatchDuration).Debug("Courier sent out message.")
 
@@ -20,6 +20,7 @@ import (
 
 	"github.com/ory/kratos/courier"
 	templates "github.com/ory/kratos/courier/template/email"
+	"github.com/ory/kratos/courier/template/sms"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/pkg"
 	"github.com/ory/kratos/pkg/testhelpers"
@@ -174,3 +175,46 @@ func TestDispatchMessageEmitsEventWithNID(t *testing.T) {
 	require.GreaterOrEqual(t, k, 0, "NID attribute not found on event")
 	assert.NotEmpty(t, attrs[k].Value.AsString())
 }
+
+func TestDispatchPushByPlatform(t *testing.T) {
+	fcmRequests, fcmChannel := newFCMServer(t)
+	apnsServer, apnsRequests := newRecordingServer(t, nil)
+	_, apnsChannel := newAPNsChannel(t, apnsServer.URL, "TEAM111111", "KEY1111111")
+
+	_, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+		config.ViperKeyCourierChannels: channelsConfig(t, fcmChannel, apnsChannel),
+		config.ViperKeyCourierSMTPURL:  "http://foo.url",
+	}))
+
+	c, err := reg.Courier(t.Context())
+	require.NoError(t, err)
+	c.FailOnDispatchError()
+
+	_, err = c.QueuePush(t.Context(), sms.NewTestStub(&sms.TestStubModel{To: "apns:ios-device", Body: "Your code is 123456"}))
+	require.NoError(t, err)
+	_, err = c.QueuePush(t.Context(), sms.NewTestStub(&sms.TestStubModel{To: "fcm:android-device", Body: "Your code is 123456"}))
+	require.NoError(t, err)
+	require.NoError(t, c.DispatchQueue(t.Context()))
+
+	require.Len(t, apnsRequests, 1)
+	assert.Equal(t, "/3/device/ios-device", (<-apnsRequests).path)
+	require.Len(t, fcmRequests, 1)
+	assert.Equal(t, "android-device", gjson.Get((<-fcmRequests).body, "message.token").String())
+
+	t.Run("case=fails without a channel for the platform", func(t *testing.T) {
+		_, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+			config.ViperKeyCourierChannels: channelsConfig(t, apnsChannel),
+			config.ViperKeyCourierSMTPURL:  "http://foo.url",
+		}))
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+		c.FailOnDispatchError()
+
+		_, err = c.QueuePush(t.Context(), sms.NewTestStub(&sms.TestStubModel{To: "fcm:android-device", Body: "Your code is 123456"}))
+		require.NoError(t, err)
+		err = c.DispatchQueue(t.Context())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "platform fcm")
+		assert.Len(t, apnsRequests, 0)
+	})
+}
@@ -0,0 +1,254 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/mail/v3"
+)
+
+var defaultDKIMHeaders = []string{
+	"From", "To", "Cc", "Reply-To", "Subject", "Date", "Message-ID",
+	"MIME-Version", "Content-Type", "List-Unsubscribe", "List-Unsubscribe-Post",
+}
+
+// DKIMSigner signs raw RFC 5322 messages using DKIM (RFC 6376) with
+// relaxed/relaxed canonicalization. RSA keys produce rsa-sha256 and Ed25519
+// keys ed25519-sha256 (RFC 8463) signatures.
+type DKIMSigner struct {
+	domain    string
+	selector  string
+	headers   []string
+	key       crypto.Signer
+	algorithm string
+}
+
+// NewDKIMSigner returns a signer for the given configuration, or nil if DKIM
+// signing is disabled.
+func NewDKIMSigner(cfg *config.SMTPDKIMConfig) (*DKIMSigner, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+
+	if cfg.Domain == "" || cfg.Selector == "" {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReason("DKIM signing requires a domain and a selector."))
+	}
+
+	keyPEM := []byte(cfg.PrivateKey)
+	if cfg.PrivateKeyPath != "" {
+		var err error
+		keyPEM, err = os.ReadFile(cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to read the DKIM private key: %s", err))
+		}
+	}
+
+	key, err := parseDKIMPrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &DKIMSigner{
+		domain:   cfg.Domain,
+		selector: cfg.Selector,
+		headers:  cfg.Headers,
+		key:      key,
+	}
+	if len(s.headers) == 0 {
+		s.headers = defaultDKIMHeaders
+	}
+
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		s.algorithm = "rsa-sha256"
+	case ed25519.PrivateKey:
+		s.algorithm = "ed25519-sha256"
+	}
+
+	return s, nil
+}
+
+func parseDKIMPrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReason("The DKIM private key is not PEM encoded."))
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to parse the DKIM private key: %s", err))
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	}
+	return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReason("The DKIM private key must be an RSA or Ed25519 key."))
+}
+
+// Sign returns the message prefixed with a DKIM-Signature header.
+func (s *DKIMSigner) Sign(raw []byte) ([]byte, error) {
+	header, body, ok := bytes.Cut(raw, []byte("\r\n\r\n"))
+	if !ok {
+		return nil, errors.New("courier: unable to DKIM sign a message without a header/body separator")
+	}
+
+	bodyHash := sha256.Sum256(dkimCanonicalizeBody(body))
+	fields := splitHeaderFields(header)
+
+	// Pick the bottom-most unused instance of each signed header field, as
+	// described in RFC 6376 section 5.4.2.
+	used := make([]bool, len(fields))
+	var signedNames []string
+	var signed bytes.Buffer
+	for _, name := range s.headers {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if used[i] || !strings.EqualFold(headerFieldName(fields[i]), name) {
+				continue
+			}
+			used[i] = true
+			signedNames = append(signedNames, strings.ToLower(name))
+			signed.WriteString(dkimCanonicalizeHeader(fields[i]))
+			signed.WriteString("\r\n")
+			break
+		}
+	}
+
+	sig := "DKIM-Signature: v=1; a=" + s.algorithm + "; c=relaxed/relaxed;\r\n" +
+		"\td=" + s.domain + "; s=" + s.selector + "; t=" + strconv.FormatInt(time.Now().Unix(), 10) + ";\r\n" +
+		"\th=" + strings.Join(signedNames, ":") + ";\r\n" +
+		"\tbh=" + base64.StdEncoding.EncodeToString(bodyHash[:]) + ";\r\n" +
+		"\tb="
+	signed.WriteString(dkimCanonicalizeHeader(sig))
+
+	digest := sha256.Sum256(signed.Bytes())
+	var (
+		signature []byte
+		err       error
+	)
+	switch k := s.key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, digest[:])
+	default:
+		signature, err = k.Sign(rand.Reader, digest[:], crypto.SHA256)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	out := make([]byte, 0, len(sig)+len(raw)+512)
+	out = append(out, sig...)
+	out = append(out, base64.StdEncoding.EncodeToString(signature)...)
+	out = append(out, "\r\n"...)
+	out = append(out, raw...)
+	return out, nil
+}
+
+// splitHeaderFields splits a header block into fields, keeping folded
+// continuation lines with the field they belong to.
+func splitHeaderFields(header []byte) []string {
+	var fields []string
+	for _, line := range strings.Split(string(header), "\r\n") {
+		if len(fields) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			fields[len(fields)-1] += "\r\n" + line
+			continue
+		}
+		fields = append(fields, line)
+	}
+	return fields
+}
+
+func headerFieldName(field string) string {
+	name, _, _ := strings.Cut(field, ":")
+	return strings.TrimSpace(name)
+}
+
+// dkimCanonicalizeHeader implements the "relaxed" header canonicalization
+// algorithm (RFC 6376 section 3.4.2).
+func dkimCanonicalizeHeader(field string) string {
+	name, value, _ := strings.Cut(field, ":")
+	value = compressWSP(strings.ReplaceAll(value, "\r\n", ""))
+	return strings.ToLower(strings.TrimRight(name, " \t")) + ":" + strings.Trim(value, " ")
+}
+
+// dkimCanonicalizeBody implements the "relaxed" body canonicalization
+// algorithm (RFC 6376 section 3.4.4).
+func dkimCanonicalizeBody(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(compressWSP(line), " ")
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// compressWSP reduces every sequence of spaces and tabs to a single space.
+func compressWSP(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	inWSP := false
+	for i := 0; i < len(s); i++ {
+		if s[i] == ' ' || s[i] == '\t' {
+			if !inWSP {
+				b.WriteByte(' ')
+			}
+			inWSP = true
+			continue
+		}
+		inWSP = false
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// dkimSender signs every message before handing it to the wrapped sender.
+type dkimSender struct {
+	mail.Sender
+	signer *DKIMSigner
+}
+
+func (s *dkimSender) Send(ctx context.Context, from string, to []string, msg io.WriterTo) error {
+	var raw bytes.Buffer
+	if _, err := msg.WriteTo(&raw); err != nil {
+		return errors.WithStack(err)
+	}
+
+	signed, err := s.signer.Sign(raw.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return s.Sender.Send(ctx, from, to, bytes.NewBuffer(signed))
+}
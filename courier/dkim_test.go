@@ -0,0 +1,166 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier_test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/driver/config"
+)
+
+var wsp = regexp.MustCompile(`[ \t]+`)
+
+// verifyDKIM is a minimal relaxed/relaxed DKIM verifier used to check the
+// signer's output independently of its implementation.
+func verifyDKIM(t *testing.T, signed string, pub crypto.PublicKey) map[string]string {
+	t.Helper()
+
+	header, body, ok := strings.Cut(signed, "\r\n\r\n")
+	require.True(t, ok)
+
+	var fields []string
+	for _, line := range strings.Split(header, "\r\n") {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			fields[len(fields)-1] += "\r\n" + line
+			continue
+		}
+		fields = append(fields, line)
+	}
+	require.True(t, strings.HasPrefix(fields[0], "DKIM-Signature:"), "the signature must be prepended")
+
+	canon := func(f string) string {
+		name, value, _ := strings.Cut(f, ":")
+		value = wsp.ReplaceAllString(strings.ReplaceAll(value, "\r\n", ""), " ")
+		return strings.ToLower(strings.TrimSpace(name)) + ":" + strings.Trim(value, " ")
+	}
+
+	tags := map[string]string{}
+	_, sigValue, _ := strings.Cut(fields[0], ":")
+	for _, tag := range strings.Split(sigValue, ";") {
+		k, v, _ := strings.Cut(tag, "=")
+		tags[strings.TrimSpace(k)] = wsp.ReplaceAllString(strings.ReplaceAll(strings.TrimSpace(v), "\r\n", ""), "")
+	}
+
+	lines := strings.Split(body, "\r\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(wsp.ReplaceAllString(lines[i], " "), " ")
+	}
+	canonBody := strings.TrimRight(strings.Join(lines, "\r\n"), "\r\n") + "\r\n"
+	bh := sha256.Sum256([]byte(canonBody))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(bh[:]), tags["bh"], "body hash mismatch")
+
+	var data strings.Builder
+	for _, name := range strings.Split(tags["h"], ":") {
+		for i := len(fields) - 1; i > 0; i-- {
+			n, _, _ := strings.Cut(fields[i], ":")
+			if strings.EqualFold(strings.TrimSpace(n), name) {
+				data.WriteString(canon(fields[i]) + "\r\n")
+				break
+			}
+		}
+	}
+	idx := strings.LastIndex(fields[0], "b=")
+	data.WriteString(canon(fields[0][:idx+2]))
+	digest := sha256.Sum256([]byte(data.String()))
+
+	sig, err := base64.StdEncoding.DecodeString(tags["b"])
+	require.NoError(t, err)
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		require.NoError(t, rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig))
+	case ed25519.PublicKey:
+		require.True(t, ed25519.Verify(k, digest[:], sig))
+	}
+	return tags
+}
+
+func TestDKIMSigner(t *testing.T) {
+	message := "From: Bob <from@example.org>\r\n" +
+		"To: user@example.org\r\n" +
+		"Subject: Your   account\r\n" +
+		"List-Unsubscribe: <https://example.org/unsubscribe?recipient=user%40example.org>,\r\n" +
+		" <mailto:unsubscribe@example.org?subject=unsubscribe>\r\n" +
+		"X-Unsigned: foo\r\n" +
+		"\r\n" +
+		"Hello \t world  \r\n" +
+		"\r\n" +
+		"\r\n"
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name      string
+		keyPEM    []byte
+		pub       crypto.PublicKey
+		algorithm string
+	}{
+		{
+			name:      "rsa",
+			keyPEM:    pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			pub:       &rsaKey.PublicKey,
+			algorithm: "rsa-sha256",
+		},
+		{
+			name:      "ed25519",
+			keyPEM:    pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}),
+			pub:       edKey.Public(),
+			algorithm: "ed25519-sha256",
+		},
+	} {
+		t.Run("key="+tc.name, func(t *testing.T) {
+			s, err := courier.NewDKIMSigner(&config.SMTPDKIMConfig{
+				Enabled:    true,
+				Domain:     "example.org",
+				Selector:   "kratos",
+				PrivateKey: string(tc.keyPEM),
+			})
+			require.NoError(t, err)
+
+			signed, err := s.Sign([]byte(message))
+			require.NoError(t, err)
+			assert.True(t, strings.HasSuffix(string(signed), message), "the original message must be kept as is")
+
+			tags := verifyDKIM(t, string(signed), tc.pub)
+			assert.Equal(t, tc.algorithm, tags["a"])
+			assert.Equal(t, "example.org", tags["d"])
+			assert.Equal(t, "kratos", tags["s"])
+			assert.Equal(t, "from:to:subject:list-unsubscribe", tags["h"])
+		})
+	}
+
+	t.Run("case=disabled returns no signer", func(t *testing.T) {
+		s, err := courier.NewDKIMSigner(&config.SMTPDKIMConfig{Domain: "example.org"})
+		require.NoError(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("case=invalid key is rejected", func(t *testing.T) {
+		_, err := courier.NewDKIMSigner(&config.SMTPDKIMConfig{
+			Enabled:    true,
+			Domain:     "example.org",
+			Selector:   "kratos",
+			PrivateKey: "not a key",
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/otelx"
+)
+
+const (
+	fcmDefaultURL      = "https://fcm.googleapis.com"
+	fcmDefaultTokenURL = "https://oauth2.googleapis.com/token"
+	fcmScope           = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// fcmTokenSources caches the access token sources per service account key, as
+// channels are created for every dispatched message.
+var fcmTokenSources sync.Map
+
+type fcmChannel struct {
+	id  string
+	cfg *config.FCMConfig
+	d   channelDependencies
+}
+
+var _ Channel = new(fcmChannel)
+
+func newFCMChannel(id string, cfg *config.FCMConfig, d channelDependencies) (*fcmChannel, error) {
+	if cfg == nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Courier channel %q is missing its fcm_config.", id))
+	}
+	return &fcmChannel{id: id, cfg: cfg, d: d}, nil
+}
+
+func (c *fcmChannel) ID() string {
+	return c.id
+}
+
+type fcmServiceAccountKey struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+func (c *fcmChannel) tokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	raw, err := loadChannelSecret(c.cfg.ServiceAccountKey, c.cfg.ServiceAccountKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := sha256.Sum256(raw)
+	if ts, ok := fcmTokenSources.Load(cacheKey); ok {
+		return ts.(oauth2.TokenSource), nil
+	}
+
+	var key fcmServiceAccountKey
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to parse the FCM service account key: %s", err))
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReason("The FCM service account key must contain a client_email and a private_key."))
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = fcmDefaultTokenURL
+	}
+
+	conf := &jwt.Config{
+		Email:        key.ClientEmail,
+		PrivateKey:   []byte(key.PrivateKey),
+		PrivateKeyID: key.PrivateKeyID,
+		Scopes:       []string{fcmScope},
+		TokenURL:     key.TokenURI,
+	}
+
+	// The token source outlives this dispatch, so it must not inherit its
+	// cancellation.
+	ctx = context.WithValue(context.WithoutCancel(ctx), oauth2.HTTPClient, c.d.HTTPClient(ctx).HTTPClient)
+	ts, _ := fcmTokenSources.LoadOrStore(cacheKey, oauth2.ReuseTokenSource(nil, conf.TokenSource(ctx)))
+	return ts.(oauth2.TokenSource), nil
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body"`
+}
+
+func (c *fcmChannel) Dispatch(ctx context.Context, msg Message) (err error) {
+	ctx, span := c.d.Tracer(ctx).Tracer().Start(ctx, "courier.fcmChannel.Dispatch")
+	defer otelx.End(span, &err)
+
+	_, deviceToken, err := x.ParsePushAddress(msg.Recipient)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	ts, err := c.tokenSource(ctx)
+	if err != nil {
+		return err
+	}
+	token, err := ts.Token()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	base := c.cfg.URL
+	if base == "" {
+		base = fcmDefaultURL
+	}
+	endpoint := strings.TrimRight(base, "/") + "/v1/projects/" + url.PathEscape(c.cfg.ProjectID) + "/messages:send"
+
+	header := http.Header{}
+	token.SetAuthHeader(&http.Request{Header: header})
+
+	if err := postJSON(ctx, c.d, endpoint, header, &fcmRequest{
+		Message: fcmMessage{
+			Token:        deviceToken,
+			Notification: fcmNotification{Title: c.cfg.Title, Body: msg.Body},
+			Data:         map[string]string{"template_type": string(msg.TemplateType)},
+		},
+	}); err != nil {
+		c.d.Logger().
+			WithError(err).
+			WithField("message_id", msg.ID).
+			WithField("message_nid", msg.NID).
+			WithField("message_template_type", msg.TemplateType).
+			Error("Sending push notification via FCM failed.")
+		return err
+	}
+
+	c.d.Logger().
+		WithField("message_id", msg.ID).
+		WithField("message_nid", msg.NID).
+		Debug("Courier sent out push notification via FCM.")
+	return nil
+}
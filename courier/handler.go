@@ -10,19 +10,27 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/ory/herodot"
+	"github.com/ory/kratos/courier/template"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/x/nosurfx"
 	"github.com/ory/kratos/x/redir"
 	"github.com/ory/x/httprouterx"
 	"github.com/ory/x/httpx"
+	"github.com/ory/x/jsonx"
 	"github.com/ory/x/logrusx"
 	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+	"github.com/ory/x/urlx"
 )
 
 const (
-	AdminRouteCourier      = "/courier"
-	AdminRouteListMessages = AdminRouteCourier + "/messages"
-	AdminRouteGetMessage   = AdminRouteCourier + "/messages/{msgID}"
+	AdminRouteCourier       = "/courier"
+	AdminRouteListMessages  = AdminRouteCourier + "/messages"
+	AdminRouteGetMessage    = AdminRouteCourier + "/messages/{msgID}"
+	AdminRouteResendMessage = AdminRouteGetMessage + "/resend"
+
+	AdminRouteTemplates        = AdminRouteCourier + "/templates"
+	AdminRoutePreviewTemplate  = AdminRouteTemplates + "/{templateType}/preview"
+	AdminRouteSendTestTemplate = AdminRouteTemplates + "/{templateType}/send"
 )
 
 type (
@@ -31,6 +39,9 @@ type (
 		logrusx.Provider
 		nosurfx.CSRFProvider
 		PersistenceProvider
+		ConfigProvider
+		Provider
+		httpx.ClientProvider
 		config.Provider
 	}
 	Handler struct {
@@ -46,14 +57,27 @@ func NewHandler(r handlerDependencies) *Handler {
 }
 
 func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
-	h.r.CSRFHandler().IgnoreGlobs(httprouterx.AdminPrefix+AdminRouteListMessages, AdminRouteListMessages)
+	h.r.CSRFHandler().IgnoreGlobs(
+		httprouterx.AdminPrefix+AdminRouteListMessages, AdminRouteListMessages,
+		httprouterx.AdminPrefix+AdminRouteListMessages+"/*/resend", AdminRouteListMessages+"/*/resend",
+		httprouterx.AdminPrefix+AdminRouteTemplates+"/*/*", AdminRouteTemplates+"/*/*",
+		httprouterx.AdminPrefix+AdminRouteIntrospectUnsubscribeToken, AdminRouteIntrospectUnsubscribeToken,
+	)
 	public.GET(httprouterx.AdminPrefix+AdminRouteListMessages, redir.RedirectToAdminRoute(h.r))
 	public.GET(httprouterx.AdminPrefix+AdminRouteGetMessage, redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+AdminRouteResendMessage, redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+AdminRoutePreviewTemplate, redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+AdminRouteSendTestTemplate, redir.RedirectToAdminRoute(h.r))
+	public.POST(httprouterx.AdminPrefix+AdminRouteIntrospectUnsubscribeToken, redir.RedirectToAdminRoute(h.r))
 }
 
 func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
 	admin.GET(AdminRouteListMessages, h.listCourierMessages)
 	admin.GET(AdminRouteGetMessage, h.getCourierMessage)
+	admin.POST(AdminRouteResendMessage, h.resendCourierMessage)
+	admin.POST(AdminRoutePreviewTemplate, h.previewCourierTemplate)
+	admin.POST(AdminRouteSendTestTemplate, h.sendTestCourierTemplate)
+	admin.POST(AdminRouteIntrospectUnsubscribeToken, h.introspectUnsubscribeToken)
 }
 
 // Paginated Courier Message List Response
@@ -217,3 +241,272 @@ func (h *Handler) getCourierMessage(w http.ResponseWriter, r *http.Request) {
 
 	h.r.Writer().Write(w, r, message)
 }
+
+// Resend Courier Message Parameters
+//
+// swagger:parameters resendCourierMessage
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type resendCourierMessage struct {
+	// MessageID is the ID of the message.
+	//
+	// required: true
+	// in: path
+	MessageID string `json:"id"`
+}
+
+// swagger:route POST /admin/courier/messages/{id}/resend courier resendCourierMessage
+//
+// # Resend an Abandoned Message
+//
+// Puts a message that was abandoned after exceeding the configured number of retries, for example
+// during a channel outage, back into the queue and resets its send count.
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		200: message
+//		400: errorGeneric
+//		404: errorGeneric
+//		409: errorGeneric
+//		default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) resendCourierMessage(w http.ResponseWriter, r *http.Request) {
+	msgID, err := uuid.FromString(r.PathValue("msgID"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, herodot.ErrBadRequest().WithError(err.Error()).WithDebugf("could not parse parameter {id} as UUID, got %s", r.PathValue("msgID")))
+		return
+	}
+
+	message, err := h.r.CourierPersister().FetchMessage(r.Context(), msgID)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if message.Status != MessageStatusAbandoned {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrConflict().WithReasonf("Only abandoned messages can be resent, but the message is %s.", message.Status)))
+		return
+	}
+
+	if err := h.r.CourierPersister().RequeueMessage(r.Context(), msgID); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	message, err = h.r.CourierPersister().FetchMessage(r.Context(), msgID)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if !h.r.Config().IsInsecureDevMode(r.Context()) {
+		message.Body = "<redacted-unless-dev-mode>"
+		message.Subject = "<redacted-unless-dev-mode>"
+	}
+
+	h.r.Writer().Write(w, r, message)
+}
+
+// Preview Courier Template Request Body
+//
+// swagger:model previewCourierTemplateBody
+type PreviewCourierTemplateBody struct {
+	// Type is the message type to render the template for. Defaults to `email`.
+	Type MessageType `json:"type"`
+
+	// Model is the template model. Fields not set are filled with sample
+	// values, including the OAuth2 login request branding data.
+	Model map[string]any `json:"model"`
+}
+
+// Preview Courier Template Parameters
+//
+// swagger:parameters previewCourierTemplate
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type previewCourierTemplate struct {
+	// TemplateType is the type of the template to render.
+	//
+	// required: true
+	// in: path
+	TemplateType template.TemplateType `json:"templateType"`
+
+	// in: body
+	Body PreviewCourierTemplateBody
+}
+
+// swagger:route POST /admin/courier/templates/{templateType}/preview courier previewCourierTemplate
+//
+// # Preview a Template
+//
+// Renders the subject and bodies of a courier template with the given or a sample model without sending a message.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: courierTemplatePreview
+//	  400: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) previewCourierTemplate(w http.ResponseWriter, r *http.Request) {
+	var body PreviewCourierTemplateBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithError(err.Error())))
+		return
+	}
+	if body.Type == 0 {
+		body.Type = MessageTypeEmail
+	}
+
+	t, err := newTemplateFromModel(h.r, template.TemplateType(r.PathValue("templateType")), body.Type, "", body.Model)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	preview, err := RenderTemplatePreview(r.Context(), t)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, preview)
+}
+
+// Send Test Courier Template Request Body
+//
+// swagger:model sendTestCourierTemplateBody
+type SendTestCourierTemplateBody struct {
+	// Type is the message type to send. Defaults to `email`.
+	Type MessageType `json:"type"`
+
+	// Recipient is the email address or phone number the message is sent to.
+	//
+	// required: true
+	Recipient string `json:"recipient"`
+
+	// Model is the template model. Fields not set are filled with sample
+	// values, including the OAuth2 login request branding data.
+	Model map[string]any `json:"model"`
+}
+
+// Send Test Courier Template Parameters
+//
+// swagger:parameters sendTestCourierTemplate
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type sendTestCourierTemplate struct {
+	// TemplateType is the type of the template to send.
+	//
+	// required: true
+	// in: path
+	TemplateType template.TemplateType `json:"templateType"`
+
+	// in: body
+	Body SendTestCourierTemplateBody
+}
+
+// swagger:route POST /admin/courier/templates/{templateType}/send courier sendTestCourierTemplate
+//
+// # Send a Test Message
+//
+// Renders a courier template with the given or a sample model and queues it for delivery to the given recipient.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  201: message
+//	  400: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) sendTestCourierTemplate(w http.ResponseWriter, r *http.Request) {
+	var body SendTestCourierTemplateBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithError(err.Error())))
+		return
+	}
+	if body.Type == 0 {
+		body.Type = MessageTypeEmail
+	}
+	if body.Recipient == "" {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReason("The recipient must be set.")))
+		return
+	}
+
+	t, err := newTemplateFromModel(h.r, template.TemplateType(r.PathValue("templateType")), body.Type, body.Recipient, body.Model)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	c, err := h.r.Courier(r.Context())
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	var id uuid.UUID
+	switch t := t.(type) {
+	case EmailTemplate:
+		id, err = c.QueueEmail(r.Context(), t)
+	case SMSTemplate:
+		id, err = c.QueueSMS(r.Context(), t)
+	default:
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Template %q can not be sent as message type %s.", r.PathValue("templateType"), body.Type)))
+		return
+	}
+	if err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to queue the test message: %s", err)))
+		return
+	}
+
+	message, err := h.r.CourierPersister().FetchMessage(r.Context(), id)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if !h.r.Config().IsInsecureDevMode(r.Context()) {
+		message.Body = "<redacted-unless-dev-mode>"
+		message.Subject = "<redacted-unless-dev-mode>"
+	}
+
+	h.r.Writer().WriteCreated(w, r,
+		urlx.AppendPaths(h.r.Config().SelfAdminURL(r.Context()), "courier", "messages", id.String()).String(),
+		message,
+	)
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-faker/faker/v4"
@@ -282,4 +283,95 @@ func TestHandler(t *testing.T) {
 			}
 		})
 	})
+	t.Run("handler=resendCourierMessage", func(t *testing.T) {
+		resend := func(t *testing.T, id string, expectCode int) gjson.Result {
+			t.Helper()
+			res, err := adminTS.Client().Post(adminTS.URL+"/admin/courier/messages/"+id+"/resend", "application/json", nil)
+			require.NoError(t, err)
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			assert.EqualValuesf(t, expectCode, res.StatusCode, "%s", body)
+			return gjson.ParseBytes(body)
+		}
+
+		message := courier.Message{}
+		require.NoError(t, faker.FakeData(&message))
+		message.Type = courier.MessageTypeEmail
+		require.NoError(t, reg.CourierPersister().AddMessage(ctx, &message))
+
+		t.Run("case=rejects messages which are not abandoned", func(t *testing.T) {
+			resend(t, message.ID.String(), http.StatusConflict)
+		})
+
+		t.Run("case=requeues an abandoned message", func(t *testing.T) {
+			require.NoError(t, reg.CourierPersister().IncrementMessageSendCount(ctx, message.ID))
+			require.NoError(t, reg.CourierPersister().SetMessageStatus(ctx, message.ID, courier.MessageStatusAbandoned))
+
+			body := resend(t, message.ID.String(), http.StatusOK)
+			assert.Equal(t, message.ID.String(), body.Get("id").String())
+			assert.Equal(t, "queued", body.Get("status").String())
+			assert.EqualValues(t, 0, body.Get("send_count").Int())
+		})
+
+		t.Run("case=returns an error if the message does not exist", func(t *testing.T) {
+			resend(t, uuid.Nil.String(), http.StatusNotFound)
+		})
+
+		t.Run("case=returns an error if parameter is malformed", func(t *testing.T) {
+			resend(t, "not-a-uuid", http.StatusBadRequest)
+		})
+	})
+
+	t.Run("handler=previewCourierTemplate", func(t *testing.T) {
+		post := func(t *testing.T, href, body string, expectCode int) gjson.Result {
+			t.Helper()
+			res, err := adminTS.Client().Post(adminTS.URL+href, "application/json", strings.NewReader(body))
+			require.NoError(t, err)
+			raw, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			assert.EqualValuesf(t, expectCode, res.StatusCode, "%s", raw)
+			return gjson.ParseBytes(raw)
+		}
+
+		t.Run("case=renders an email template with a sampled model", func(t *testing.T) {
+			preview := post(t, "/admin/courier/templates/login_code_valid/preview", `{}`, http.StatusOK)
+			assert.Equal(t, "login_code_valid", preview.Get("template_type").String())
+			assert.Equal(t, "email", preview.Get("type").String())
+			assert.Equal(t, "Use code 123456 to log in", preview.Get("subject").String())
+			assert.Contains(t, preview.Get("body_plaintext").String(), "123456")
+			assert.Contains(t, preview.Get("body_html").String(), "123456")
+			assert.False(t, preview.Get("sms_body").Exists())
+		})
+
+		t.Run("case=renders an sms template with a supplied model", func(t *testing.T) {
+			preview := post(t, "/admin/courier/templates/login_code_valid/preview", `{"type":"sms","model":{"login_code":"654321","request_url_domain":""}}`, http.StatusOK)
+			assert.Equal(t, "sms", preview.Get("type").String())
+			assert.Contains(t, preview.Get("sms_body").String(), "Your login code is: 654321")
+			assert.False(t, preview.Get("subject").Exists())
+		})
+
+		t.Run("case=rejects unknown template types", func(t *testing.T) {
+			post(t, "/admin/courier/templates/does_not_exist/preview", `{}`, http.StatusBadRequest)
+		})
+
+		t.Run("case=queues a test message to the given recipient", func(t *testing.T) {
+			conf.MustSet(ctx, "dev", true)
+			message := post(t, "/admin/courier/templates/login_code_valid/send", `{"recipient":"test-send@ory.sh"}`, http.StatusCreated)
+			assert.Equal(t, "test-send@ory.sh", message.Get("recipient").String())
+			assert.Equal(t, "queued", message.Get("status").String())
+			assert.Equal(t, "login_code_valid", message.Get("template_type").String())
+			assert.Equal(t, "Use code 123456 to log in", message.Get("subject").String())
+
+			id := uuid.FromStringOrNil(message.Get("id").String())
+			stored, err := reg.CourierPersister().FetchMessage(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, "test-send@ory.sh", stored.Recipient)
+		})
+
+		t.Run("case=requires a recipient for test messages", func(t *testing.T) {
+			post(t, "/admin/courier/templates/login_code_valid/send", `{}`, http.StatusBadRequest)
+		})
+	})
 }
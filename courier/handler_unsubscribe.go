@@ -0,0 +1,79 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/jsonx"
+)
+
+const AdminRouteIntrospectUnsubscribeToken = AdminRouteCourier + "/unsubscribe-tokens/introspect"
+
+// Introspect Courier Unsubscribe Token Request Body
+//
+// swagger:model introspectCourierUnsubscribeTokenBody
+type IntrospectUnsubscribeTokenBody struct {
+	// Token is the value of the `token` query parameter of the one-click
+	// unsubscribe URL.
+	//
+	// required: true
+	Token string `json:"token"`
+}
+
+// Introspect Courier Unsubscribe Token Parameters
+//
+// swagger:parameters introspectCourierUnsubscribeToken
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type introspectCourierUnsubscribeToken struct {
+	// in: body
+	Body IntrospectUnsubscribeTokenBody
+}
+
+// swagger:route POST /admin/courier/unsubscribe-tokens/introspect courier introspectCourierUnsubscribeToken
+//
+// # Introspect an Unsubscribe Token
+//
+// Resolves the opaque token of a one-click List-Unsubscribe URL to the recipient address and the template type
+// of the message it was sent with. Call this endpoint from the service receiving the unsubscribe requests configured
+// at `courier.smtp.list_unsubscribe.url`.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Security:
+//		oryAccessToken:
+//
+//	Schemes: http, https
+//
+//	Responses:
+//		200: courierUnsubscribeToken
+//		400: errorGeneric
+//		default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-medium
+func (h *Handler) introspectUnsubscribeToken(w http.ResponseWriter, r *http.Request) {
+	var body IntrospectUnsubscribeTokenBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to decode the request body: %s", err)))
+		return
+	}
+
+	token, err := ParseUnsubscribeToken(h.r.Config().SecretsDefault(r.Context()), body.Token)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, token)
+}
@@ -149,7 +149,7 @@ func newTemplate(d template.Dependencies, msg Message) (Template, error) {
 	switch msg.Type {
 	case MessageTypeEmail:
 		return NewEmailTemplateFromMessage(d, msg)
-	case MessageTypeSMS:
+	case MessageTypeSMS, MessageTypePush:
 		return NewSMSTemplateFromMessage(d, msg)
 	default:
 		return nil, fmt.Errorf("received unexpected message type: %s", msg.Type)
@@ -99,7 +99,7 @@ func (ms *MessageStatus) UnmarshalJSON(data []byte) error {
 
 // A Message's Type
 //
-// It can either be `email` or `phone`
+// It can either be `email`, `sms`, or `push`
 //
 // swagger:model courierMessageType
 type MessageType int
@@ -107,11 +107,13 @@ type MessageType int
 const (
 	MessageTypeEmail MessageType = iota + 1
 	MessageTypeSMS
+	MessageTypePush
 )
 
 const (
 	messageTypeEmailText = "email"
 	messageTypeSMSText   = "sms"
+	messageTypePushText  = "push"
 )
 
 func ToMessageType(str string) (MessageType, error) {
@@ -120,6 +122,8 @@ func ToMessageType(str string) (MessageType, error) {
 		return MessageTypeEmail, nil
 	case s.AddCase(messageTypeSMSText):
 		return MessageTypeSMS, nil
+	case s.AddCase(messageTypePushText):
+		return MessageTypePush, nil
 	default:
 		return 0, errors.WithStack(herodot.ErrBadRequest().WithWrap(s.ToUnknownCaseErr()).WithReason("Message type is not valid"))
 	}
@@ -131,6 +135,8 @@ func (mt MessageType) String() string {
 		return messageTypeEmailText
 	case MessageTypeSMS:
 		return messageTypeSMSText
+	case MessageTypePush:
+		return messageTypePushText
 	default:
 		return ""
 	}
@@ -138,7 +144,7 @@ func (mt MessageType) String() string {
 
 func (mt MessageType) IsValid() error {
 	switch mt {
-	case MessageTypeEmail, MessageTypeSMS:
+	case MessageTypeEmail, MessageTypeSMS, MessageTypePush:
 		return nil
 	default:
 		return errors.WithStack(herodot.ErrBadRequest().WithReason("Message type is not valid"))
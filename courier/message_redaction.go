@@ -0,0 +1,64 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RedactedValue replaces codes and links in redacted messages.
+const RedactedValue = "<redacted>"
+
+// redactedTemplateDataKeys are the template model fields holding one-time
+// codes or links which grant access to an account.
+var redactedTemplateDataKeys = []string{
+	"login_code",
+	"registration_code",
+	"recovery_code",
+	"recovery_url",
+	"verification_code",
+	"verification_url",
+}
+
+// Redact removes the codes and links contained in the message's template data
+// from the template data, the subject, and the body.
+func (m *Message) Redact() error {
+	if len(m.TemplateData) == 0 {
+		return nil
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(m.TemplateData, &data); err != nil {
+		return errors.WithStack(err)
+	}
+
+	var secrets []string
+	for _, key := range redactedTemplateDataKeys {
+		if v, ok := data[key].(string); ok && v != "" {
+			secrets = append(secrets, v)
+			data[key] = RedactedValue
+		}
+	}
+
+	// Replace longer values first so that links are redacted as a whole
+	// before the codes they may contain.
+	slices.SortFunc(secrets, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	for _, secret := range secrets {
+		m.Subject = strings.ReplaceAll(m.Subject, secret, RedactedValue)
+		m.Body = strings.ReplaceAll(m.Body, secret, RedactedValue)
+	}
+
+	redacted, err := json.Marshal(data)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	m.TemplateData = redacted
+
+	return nil
+}
@@ -6,6 +6,7 @@ package courier_test
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ory/herodot"
@@ -59,3 +60,20 @@ func TestToMessageType(t *testing.T) {
 		require.ErrorIs(t, result.IsValid(), herodot.ErrBadRequest())
 	})
 }
+
+func TestMessageRedact(t *testing.T) {
+	m := courier.Message{
+		Subject:      "Use code 123456 to log in",
+		Body:         "Your code is 123456. Or open https://www.ory.sh/verify?code=123456 to continue.",
+		TemplateData: []byte(`{"to":"foo@ory.sh","login_code":"123456","verification_url":"https://www.ory.sh/verify?code=123456","expires_in_minutes":15}`),
+	}
+	require.NoError(t, m.Redact())
+
+	assert.Equal(t, "Use code <redacted> to log in", m.Subject)
+	assert.Equal(t, "Your code is <redacted>. Or open <redacted> to continue.", m.Body)
+	assert.JSONEq(t, `{"to":"foo@ory.sh","login_code":"<redacted>","verification_url":"<redacted>","expires_in_minutes":15}`, string(m.TemplateData))
+
+	empty := courier.Message{Body: "body"}
+	require.NoError(t, empty.Redact())
+	assert.Equal(t, "body", empty.Body)
+}
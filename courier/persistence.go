@@ -5,6 +5,7 @@ package courier
 
 import (
 	"context"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
@@ -36,6 +37,24 @@ type (
 		// Records an attempt of sending out a courier message
 		// Returns an error if it fails
 		RecordDispatch(ctx context.Context, msgID uuid.UUID, status CourierMessageDispatchStatus, err error) error
+
+		// RedactMessage overwrites the stored subject, body and template data
+		// of the message with the values of the given message.
+		RedactMessage(context.Context, *Message) error
+
+		// RequeueMessage puts an abandoned message back into the queue and
+		// resets its send count. Returns an error if no abandoned message
+		// with the id exists.
+		RequeueMessage(context.Context, uuid.UUID) error
+
+		// RequeueMessages puts all messages with the given status created at
+		// or after since back into the queue and resets their send counts.
+		// Returns the number of requeued messages.
+		RequeueMessages(ctx context.Context, status MessageStatus, since time.Time) (int, error)
+
+		// DeleteExpiredMessages deletes up to limit messages with the given
+		// status created before the given time.
+		DeleteExpiredMessages(ctx context.Context, status MessageStatus, before time.Time, limit int) error
 	}
 	PersistenceProvider interface {
 		CourierPersister() Persister
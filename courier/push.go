@@ -0,0 +1,17 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+)
+
+// QueuePush queues a push notification. Push notifications reuse the SMS
+// templates; the template's recipient is the push address, i.e. the device
+// token prefixed with its platform ("fcm:" or "apns:").
+func (c *courier) QueuePush(ctx context.Context, t SMSTemplate) (uuid.UUID, error) {
+	return c.queueTextMessage(ctx, t, MessageTypePush, "push")
+}
@@ -0,0 +1,261 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/courier/template/sms"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/x/configx"
+)
+
+type recordedRequest struct {
+	path   string
+	header http.Header
+	body   string
+}
+
+func newRecordingServer(t *testing.T, handle func(w http.ResponseWriter, r *http.Request) bool) (*httptest.Server, chan recordedRequest) {
+	requests := make(chan recordedRequest, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handle != nil && handle(w, r) {
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests <- recordedRequest{path: r.URL.Path, header: r.Header, body: string(body)}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests
+}
+
+func channelsConfig(t *testing.T, channels ...map[string]any) string {
+	raw, err := json.Marshal(channels)
+	require.NoError(t, err)
+	return string(raw)
+}
+
+// newFCMServer returns a recording server which also issues FCM access
+// tokens, and the push channel configuration delivering to it.
+func newFCMServer(t *testing.T) (chan recordedRequest, map[string]any) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	srv, requests := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path != "/token" {
+			return false
+		}
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"fcm-access-token","token_type":"Bearer","expires_in":3600}`))
+		return true
+	})
+
+	serviceAccountKey, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "kratos@example.iam.gserviceaccount.com",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"private_key_id": "key-id",
+		"token_uri":      srv.URL + "/token",
+	})
+	require.NoError(t, err)
+
+	return requests, map[string]any{
+		"id":   "push",
+		"type": "fcm",
+		"fcm_config": map[string]any{
+			"url":                 srv.URL,
+			"project_id":          "my-project",
+			"service_account_key": string(serviceAccountKey),
+			"title":               "My App",
+		},
+	}
+}
+
+// newAPNsChannel returns the push channel configuration delivering to the
+// APNs server at url, and the key verifying its provider tokens.
+func newAPNsChannel(t *testing.T, url, teamID, keyID string) (*ecdsa.PublicKey, map[string]any) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return &key.PublicKey, map[string]any{
+		"id":   "push",
+		"type": "apns",
+		"apns_config": map[string]any{
+			"url":         url,
+			"team_id":     teamID,
+			"key_id":      keyID,
+			"private_key": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+			"topic":       "sh.ory.app",
+		},
+	}
+}
+
+func TestQueuePush(t *testing.T) {
+	t.Run("type=fcm", func(t *testing.T) {
+		requests, channel := newFCMServer(t)
+
+		_, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+			config.ViperKeyCourierChannels: channelsConfig(t, channel),
+			config.ViperKeyCourierSMTPURL:  "http://foo.url",
+		}))
+
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+
+		_, err = c.QueuePush(t.Context(), sms.NewTestStub(&sms.TestStubModel{To: "fcm:Device-Token", Body: "Your code is 123456"}))
+		require.NoError(t, err)
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		require.Len(t, requests, 1)
+		req := <-requests
+		assert.Equal(t, "/v1/projects/my-project/messages:send", req.path)
+		assert.Equal(t, "Bearer fcm-access-token", req.header.Get("Authorization"))
+		assert.Equal(t, "Device-Token", gjson.Get(req.body, "message.token").String(), "device tokens must not be normalized")
+		assert.Equal(t, "My App", gjson.Get(req.body, "message.notification.title").String())
+		assert.Equal(t, "Your code is 123456", gjson.Get(req.body, "message.notification.body").String())
+		assert.Equal(t, "stub", gjson.Get(req.body, "message.data.template_type").String())
+	})
+
+	t.Run("type=apns", func(t *testing.T) {
+		srv, requests := newRecordingServer(t, nil)
+		key, channel := newAPNsChannel(t, srv.URL, "TEAM123456", "KEY1234567")
+
+		_, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+			config.ViperKeyCourierChannels: channelsConfig(t, channel),
+			config.ViperKeyCourierSMTPURL:  "http://foo.url",
+		}))
+
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+
+		_, err = c.QueuePush(t.Context(), sms.NewTestStub(&sms.TestStubModel{To: "apns:abcdef0123", Body: "Your code is 123456"}))
+		require.NoError(t, err)
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		require.Len(t, requests, 1)
+		req := <-requests
+		assert.Equal(t, "/3/device/abcdef0123", req.path)
+		assert.Equal(t, "sh.ory.app", req.header.Get("apns-topic"))
+		assert.Equal(t, "alert", req.header.Get("apns-push-type"))
+		assert.Equal(t, "Your code is 123456", gjson.Get(req.body, "aps.alert.body").String())
+
+		token, err := jwt.Parse(strings.TrimPrefix(req.header.Get("Authorization"), "bearer "), func(*jwt.Token) (any, error) {
+			return key, nil
+		}, jwt.WithValidMethods([]string{"ES256"}))
+		require.NoError(t, err)
+		assert.Equal(t, "KEY1234567", token.Header["kid"])
+		assert.Equal(t, "TEAM123456", token.Claims.(jwt.MapClaims)["iss"])
+	})
+
+	t.Run("case=upstream errors are returned", func(t *testing.T) {
+		srv, _ := newRecordingServer(t, func(w http.ResponseWriter, r *http.Request) bool {
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+			return true
+		})
+
+		_, channel := newAPNsChannel(t, srv.URL, "TEAM654321", "KEY7654321")
+
+		_, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+			config.ViperKeyCourierChannels: channelsConfig(t, channel),
+			config.ViperKeyCourierSMTPURL:  "http://foo.url",
+		}))
+
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+		c.FailOnDispatchError()
+
+		_, err = c.QueuePush(t.Context(), sms.NewTestStub(&sms.TestStubModel{To: "apns:abcdef0123", Body: "Your code is 123456"}))
+		require.NoError(t, err)
+		err = c.DispatchQueue(t.Context())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Unregistered")
+	})
+}
+
+func TestWhatsAppChannel(t *testing.T) {
+	srv, requests := newRecordingServer(t, nil)
+
+	_, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+		config.ViperKeyCourierChannels: channelsConfig(t, map[string]any{
+			"id":   "whatsapp",
+			"type": "whatsapp",
+			"whatsapp_config": map[string]any{
+				"url":             srv.URL,
+				"phone_number_id": "1234567890",
+				"access_token":    "whatsapp-token",
+				"templates": map[string]any{
+					"login_code_valid": map[string]any{
+						"name":             "login_code",
+						"language":         "de",
+						"parameters":       []string{"login_code"},
+						"button_parameter": "login_code",
+					},
+				},
+			},
+		}),
+		config.ViperKeyCourierSMTPURL: "http://foo.url",
+	}))
+
+	c, err := reg.Courier(t.Context())
+	require.NoError(t, err)
+
+	_, err = c.QueueWhatsApp(t.Context(), sms.NewLoginCodeValid(reg, &sms.LoginCodeValidModel{To: "+12065550101", LoginCode: "123456"}))
+	require.NoError(t, err)
+	require.NoError(t, c.DispatchQueue(t.Context()))
+
+	require.Len(t, requests, 1)
+	req := <-requests
+	assert.Equal(t, "/1234567890/messages", req.path)
+	assert.Equal(t, "Bearer whatsapp-token", req.header.Get("Authorization"))
+	assert.JSONEq(t, `{
+		"messaging_product": "whatsapp",
+		"to": "+12065550101",
+		"type": "template",
+		"template": {
+			"name": "login_code",
+			"language": {"code": "de"},
+			"components": [
+				{"type": "body", "parameters": [{"type": "text", "text": "123456"}]},
+				{"type": "button", "sub_type": "url", "index": "0", "parameters": [{"type": "text", "text": "123456"}]}
+			]
+		}
+	}`, req.body)
+
+	t.Run("case=unmapped template types are not delivered", func(t *testing.T) {
+		c.FailOnDispatchError()
+		_, err = c.QueueWhatsApp(t.Context(), sms.NewTestStub(&sms.TestStubModel{To: "+12065550101", Body: "test"}))
+		require.NoError(t, err)
+		err := c.DispatchQueue(t.Context())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no WhatsApp message template")
+		assert.Len(t, requests, 0)
+	})
+}
@@ -8,9 +8,23 @@ import (
 	"encoding/json"
 
 	"github.com/gofrs/uuid"
+
+	"github.com/ory/x/sqlxx"
 )
 
 func (c *courier) QueueSMS(ctx context.Context, t SMSTemplate) (uuid.UUID, error) {
+	return c.queueTextMessage(ctx, t, MessageTypeSMS, "sms")
+}
+
+// QueueWhatsApp queues a text message for the WhatsApp channel. The template's
+// recipient is the phone number.
+func (c *courier) QueueWhatsApp(ctx context.Context, t SMSTemplate) (uuid.UUID, error) {
+	return c.queueTextMessage(ctx, t, MessageTypeSMS, "whatsapp")
+}
+
+// queueTextMessage queues a message rendered from an SMS template. The
+// template's recipient is used as is, so it may also be a push address.
+func (c *courier) queueTextMessage(ctx context.Context, t SMSTemplate, mt MessageType, channel string) (uuid.UUID, error) {
 	recipient, err := t.PhoneNumber()
 	if err != nil {
 		return uuid.Nil, err
@@ -36,8 +50,8 @@ func (c *courier) QueueSMS(ctx context.Context, t SMSTemplate) (uuid.UUID, error
 
 	message := &Message{
 		Status:         MessageStatusQueued,
-		Type:           MessageTypeSMS,
-		Channel:        "sms",
+		Type:           mt,
+		Channel:        sqlxx.NullString(channel),
 		Recipient:      recipient,
 		TemplateType:   t.TemplateType(),
 		TemplateData:   templateData,
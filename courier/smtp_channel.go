@@ -8,6 +8,8 @@ import (
 	"net"
 	stdmail "net/mail"
 	"net/textproto"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +29,7 @@ import (
 type (
 	SMTPChannel struct {
 		smtpClient *SMTPClient
+		dkim       *DKIMSigner
 		d          Dependencies
 
 		newEmailTemplateFromMessage func(d template.Dependencies, msg Message) (EmailTemplate, error)
@@ -44,8 +47,18 @@ func NewSMTPChannelWithCustomTemplates(deps Dependencies, cfg *config.SMTPConfig
 	if err != nil {
 		return nil, err
 	}
+	dkim, err := NewDKIMSigner(cfg.DKIM)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ListUnsubscribe != nil && cfg.ListUnsubscribe.URL != "" {
+		if _, err := parseListUnsubscribeURL(cfg.ListUnsubscribe.URL); err != nil {
+			return nil, err
+		}
+	}
 	return &SMTPChannel{
 		smtpClient:                  smtpClient,
+		dkim:                        dkim,
 		d:                           deps,
 		newEmailTemplateFromMessage: newEmailTemplateFromMessage,
 	}, nil
@@ -108,6 +121,17 @@ func (c *SMTPChannel) Dispatch(ctx context.Context, msg Message) (err error) {
 		gm.SetHeader(k, v)
 	}
 
+	unsubscribe, oneClick, err := listUnsubscribeHeader(cfg.ListUnsubscribe, c.d.CourierConfig().SecretsDefault(ctx), msg)
+	if err != nil {
+		return err
+	}
+	if unsubscribe != "" {
+		gm.SetHeader("List-Unsubscribe", unsubscribe)
+	}
+	if oneClick {
+		gm.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+
 	gm.SetBody("text/plain", msg.Body)
 
 	logger := c.d.Logger().
@@ -151,11 +175,16 @@ func (c *SMTPChannel) Dispatch(ctx context.Context, msg Message) (err error) {
 	}
 	defer func() { _ = snd.Close() }()
 
+	var sender mail.Sender = snd
+	if c.dkim != nil {
+		sender = &dkimSender{Sender: snd, signer: c.dkim}
+	}
+
 	sendCtx, sendSpan := c.d.Tracer(ctx).Tracer().Start(ctx, "courier.SMTPChannel.Dispatch.Send")
 	if rfcErr == nil {
 		// mail.Send derives the envelope from the To/Cc/Bcc headers, so Cc/Bcc
 		// configured via courier.smtp.headers are delivered.
-		err = mail.Send(sendCtx, snd, gm)
+		err = mail.Send(sendCtx, sender, gm)
 	} else {
 		// A non-RFC recipient bypasses mail.Send's strict header re-parse with
 		// an explicit envelope; Cc/Bcc headers are not delivered on this path.
@@ -164,7 +193,7 @@ func (c *SMTPChannel) Dispatch(ctx context.Context, msg Message) (err error) {
 				logger.Warnf("Configured %q recipients are not delivered for a non-RFC email address.", k)
 			}
 		}
-		err = snd.Send(sendCtx, cfg.FromAddress, []string{msg.Recipient}, gm)
+		err = sender.Send(sendCtx, cfg.FromAddress, []string{msg.Recipient}, gm)
 	}
 	otelx.End(sendSpan, &err)
 
@@ -199,3 +228,58 @@ func (c *SMTPChannel) Dispatch(ctx context.Context, msg Message) (err error) {
 
 	return nil
 }
+
+// parseListUnsubscribeURL parses the one-click unsubscribe URL. RFC 8058
+// requires it to be an HTTPS URI.
+func parseListUnsubscribeURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to parse the List-Unsubscribe URL: %s", err))
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReason("The List-Unsubscribe URL must be an absolute HTTPS URL."))
+	}
+	return u, nil
+}
+
+// listUnsubscribeHeader returns the List-Unsubscribe header value (RFC 2369)
+// for the message, or an empty string if the message's template is not
+// configured to carry one. oneClick reports whether the value contains the
+// HTTPS URL required for the List-Unsubscribe-Post header (RFC 8058). The
+// one-click URL carries an opaque unsubscribe token instead of the recipient
+// address, so that the address does not end up in access logs and
+// unsubscribe requests can not be forged.
+func listUnsubscribeHeader(cfg *config.SMTPListUnsubscribeConfig, secrets [][]byte, msg Message) (value string, oneClick bool, _ error) {
+	if cfg == nil || (cfg.URL == "" && cfg.MailTo == "") {
+		return "", false, nil
+	}
+
+	templates := cfg.Templates
+	if len(templates) == 0 {
+		templates = []string{string(template.TypeVerifiableAddressChanged), string(template.TypeAuthenticatorKeyAdded)}
+	}
+	if !slices.Contains(templates, string(msg.TemplateType)) {
+		return "", false, nil
+	}
+
+	var values []string
+	if cfg.URL != "" {
+		u, err := parseListUnsubscribeURL(cfg.URL)
+		if err != nil {
+			return "", false, err
+		}
+		token, err := NewUnsubscribeToken(secrets, UnsubscribeToken{Recipient: msg.Recipient, TemplateType: msg.TemplateType})
+		if err != nil {
+			return "", false, err
+		}
+		q := u.Query()
+		q.Set("token", token)
+		u.RawQuery = q.Encode()
+		values = append(values, "<"+u.String()+">")
+		oneClick = true
+	}
+	if cfg.MailTo != "" {
+		values = append(values, "<mailto:"+url.PathEscape(cfg.MailTo)+"?subject=unsubscribe>")
+	}
+	return strings.Join(values, ", "), oneClick, nil
+}
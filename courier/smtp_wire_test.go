@@ -5,8 +5,13 @@ package courier_test
 
 import (
 	"bufio"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net"
+	"net/url"
 	"strings"
 	"sync"
 	"testing"
@@ -106,11 +111,17 @@ func rcptLines(cmds []string) []string {
 
 func headerLines(data []string, prefix string) []string {
 	var out []string
+	matched := false
 	for _, d := range data {
 		if d == "" {
 			break // End of headers.
 		}
-		if strings.HasPrefix(d, prefix) {
+		if matched && (strings.HasPrefix(d, " ") || strings.HasPrefix(d, "\t")) {
+			out[len(out)-1] += strings.TrimLeft(d, " \t") // Unfold continuation lines.
+			continue
+		}
+		matched = strings.HasPrefix(d, prefix)
+		if matched {
 			out = append(out, d)
 		}
 	}
@@ -173,6 +184,121 @@ func TestDispatchSMTPWireProtocol(t *testing.T) {
 		}, 5*time.Second, 50*time.Millisecond)
 	})
 
+	t.Run("case=notification templates carry signed one-click unsubscribe headers", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		smtpURL, rec := newRecordingSMTPServer(t)
+		c, conf := newWireCourier(t, smtpURL, map[string]any{
+			config.ViperKeyCourierSMTPDKIM + ".enabled":              true,
+			config.ViperKeyCourierSMTPDKIM + ".domain":               "ory.sh",
+			config.ViperKeyCourierSMTPDKIM + ".selector":             "kratos",
+			config.ViperKeyCourierSMTPDKIM + ".private_key":          string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+			config.ViperKeyCourierSMTPListUnsubscribe + ".url":       "https://example.org/unsubscribe",
+			config.ViperKeyCourierSMTPListUnsubscribe + ".templates": []string{"stub"},
+		})
+
+		_, err = c.QueueEmail(t.Context(), templates.NewTestStub(&templates.TestStubModel{
+			To: "user@example.org", Subject: "s", Body: "b",
+		}))
+		require.NoError(t, err)
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		require.EventuallyWithT(t, func(t *assert.CollectT) {
+			_, data := rec.snapshot()
+			assert.Len(t, headerLines(data, "List-Unsubscribe:"), 1)
+			assert.Equal(t, []string{"List-Unsubscribe-Post: List-Unsubscribe=One-Click"}, headerLines(data, "List-Unsubscribe-Post:"))
+			assert.Len(t, headerLines(data, "DKIM-Signature:"), 1)
+		}, 5*time.Second, 50*time.Millisecond)
+
+		_, data := rec.snapshot()
+		header := strings.TrimPrefix(headerLines(data, "List-Unsubscribe:")[0], "List-Unsubscribe: ")
+		assert.NotContains(t, header, "user")
+		u, err := url.Parse(strings.Trim(header, "<>"))
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.org/unsubscribe", u.Scheme+"://"+u.Host+u.Path)
+
+		token, err := courier.ParseUnsubscribeToken(conf.SecretsDefault(t.Context()), u.Query().Get("token"))
+		require.NoError(t, err)
+		assert.Equal(t, "user@example.org", token.Recipient)
+		assert.Equal(t, template.TypeTestStub, token.TemplateType)
+
+		verifyDKIM(t, strings.Join(data, "\r\n")+"\r\n", &key.PublicKey)
+	})
+
+	t.Run("case=mailbox-only unsubscribe headers are not one-click", func(t *testing.T) {
+		smtpURL, rec := newRecordingSMTPServer(t)
+		c, _ := newWireCourier(t, smtpURL, map[string]any{
+			config.ViperKeyCourierSMTPListUnsubscribe + ".mailto":    "unsubscribe@example.org",
+			config.ViperKeyCourierSMTPListUnsubscribe + ".templates": []string{"stub"},
+		})
+
+		_, err := c.QueueEmail(t.Context(), templates.NewTestStub(&templates.TestStubModel{
+			To: "user@example.org", Subject: "s", Body: "b",
+		}))
+		require.NoError(t, err)
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		require.EventuallyWithT(t, func(t *assert.CollectT) {
+			_, data := rec.snapshot()
+			assert.Equal(t, []string{"List-Unsubscribe: <mailto:unsubscribe@example.org?subject=unsubscribe>"}, headerLines(data, "List-Unsubscribe:"))
+			assert.Empty(t, headerLines(data, "List-Unsubscribe-Post:"))
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("case=transactional templates do not carry unsubscribe headers", func(t *testing.T) {
+		smtpURL, rec := newRecordingSMTPServer(t)
+		c, _ := newWireCourier(t, smtpURL, map[string]any{
+			config.ViperKeyCourierSMTPListUnsubscribe + ".url": "https://example.org/unsubscribe",
+		})
+
+		_, err := c.QueueEmail(t.Context(), templates.NewTestStub(&templates.TestStubModel{
+			To: "user@example.org", Subject: "s", Body: "b",
+		}))
+		require.NoError(t, err)
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		require.EventuallyWithT(t, func(t *assert.CollectT) {
+			cmds, data := rec.snapshot()
+			assert.NotEmpty(t, rcptLines(cmds))
+			assert.NotEmpty(t, headerLines(data, "To:"))
+			assert.Empty(t, headerLines(data, "List-Unsubscribe"))
+			assert.Empty(t, headerLines(data, "DKIM-Signature:"))
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("case=codes are redacted from sent messages", func(t *testing.T) {
+		smtpURL, rec := newRecordingSMTPServer(t)
+		_, reg := pkg.NewRegistryDefaultWithDSN(t, "", configx.WithValues(map[string]any{
+			config.ViperKeyCourierSMTPURL:                 smtpURL,
+			config.ViperKeyCourierSMTPFrom:                "from@ory.sh",
+			config.ViperKeyClientSMTPNoPrivateIPRanges:    false,
+			config.ViperKeyCourierMessageRedactionEnabled: true,
+		}))
+		c, err := reg.Courier(t.Context())
+		require.NoError(t, err)
+		c.FailOnDispatchError()
+
+		id, err := c.QueueEmail(t.Context(), templates.NewLoginCodeValid(reg, &templates.LoginCodeValidModel{
+			To: "user@example.org", LoginCode: "987654",
+		}))
+		require.NoError(t, err)
+		require.NoError(t, c.DispatchQueue(t.Context()))
+
+		require.EventuallyWithT(t, func(t *assert.CollectT) {
+			_, data := rec.snapshot()
+			assert.Contains(t, strings.Join(data, "\n"), "987654", "the recipient receives the code")
+		}, 5*time.Second, 50*time.Millisecond)
+
+		stored, err := reg.CourierPersister().FetchMessage(t.Context(), id)
+		require.NoError(t, err)
+		assert.Equal(t, courier.MessageStatusSent, stored.Status)
+		assert.NotContains(t, stored.Subject, "987654")
+		assert.NotContains(t, stored.Body, "987654")
+		assert.NotContains(t, string(stored.TemplateData), "987654")
+		assert.Contains(t, stored.Body, courier.RedactedValue)
+	})
+
 	t.Run("case=poisoned recipient is abandoned at dispatch, nothing hits the wire", func(t *testing.T) {
 		smtpURL, rec := newRecordingSMTPServer(t)
 		_, reg := pkg.NewRegistryDefaultWithDSN(t, "", configx.WithValues(map[string]any{
@@ -0,0 +1,152 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/courier/template"
+)
+
+// Courier Template Preview
+//
+// swagger:model courierTemplatePreview
+type TemplatePreview struct {
+	// TemplateType is the type of the rendered template.
+	//
+	// required: true
+	TemplateType template.TemplateType `json:"template_type"`
+
+	// Type is the message type the template was rendered for.
+	//
+	// required: true
+	Type MessageType `json:"type"`
+
+	// Subject is the rendered email subject. Empty for SMS templates.
+	Subject string `json:"subject,omitempty"`
+
+	// BodyPlaintext is the rendered plaintext email body. Empty for SMS templates.
+	BodyPlaintext string `json:"body_plaintext,omitempty"`
+
+	// BodyHTML is the rendered HTML email body. Empty for SMS templates.
+	BodyHTML string `json:"body_html,omitempty"`
+
+	// SMSBody is the rendered SMS body. Empty for email templates.
+	SMSBody string `json:"sms_body,omitempty"`
+}
+
+// sampleTemplateData returns a template model containing placeholder values
+// for the fields of every built-in email and SMS template model. Unknown
+// fields are ignored when the data is decoded into a specific model.
+func sampleTemplateData(recipient string) map[string]any {
+	now := time.Now().UTC().Format(time.RFC3339)
+	identity := map[string]any{
+		"id":        "9f425a8d-7efc-4768-8f23-7647a74fdf13",
+		"schema_id": "default",
+		"state":     "active",
+		"traits": map[string]any{
+			"email": recipient,
+			"name":  map[string]any{"first": "Jane", "last": "Doe"},
+		},
+	}
+
+	return map[string]any{
+		"to":                 recipient,
+		"identity":           identity,
+		"traits":             identity["traits"],
+		"login_code":         "123456",
+		"recovery_code":      "123456",
+		"verification_code":  "123456",
+		"registration_code":  "123456",
+		"recovery_url":       "https://www.ory.sh/self-service/recovery?flow=9f425a8d-7efc-4768-8f23-7647a74fdf13&token=sample",
+		"verification_url":   "https://www.ory.sh/self-service/verification?flow=9f425a8d-7efc-4768-8f23-7647a74fdf13&code=123456",
+		"request_url":        "https://www.ory.sh/self-service/login/browser",
+		"request_url_domain": "www.ory.sh",
+		"expires_in_minutes": 60,
+		"changed_at":         now,
+		"added_at":           now,
+		"transient_payload":  map[string]any{},
+		"subject":            "Sample subject",
+		"body":               "Sample body",
+		"oauth2_login_request": template.OAuth2LoginRequest{
+			Challenge: "sample-login-challenge",
+			Client: template.OAuth2Client{
+				ClientID:   "sample-client",
+				ClientName: "Sample Application",
+				ClientURI:  "https://www.ory.sh",
+				LogoURI:    "https://www.ory.sh/logo.png",
+			},
+		},
+	}
+}
+
+// newTemplateFromModel builds a template of the given type for the given
+// message type. The model is merged over the sample model, so callers only
+// need to supply the fields they want to override. If recipient is set, it
+// replaces the model's recipient.
+func newTemplateFromModel(d template.Dependencies, tt template.TemplateType, mt MessageType, recipient string, model map[string]any) (Template, error) {
+	data := sampleTemplateData("jane.doe@example.org")
+	if mt == MessageTypeSMS || mt == MessageTypePush {
+		data = sampleTemplateData("+12065550100")
+	}
+	for k, v := range model {
+		data[k] = v
+	}
+	if recipient != "" {
+		data["to"] = recipient
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	msg := Message{Type: mt, TemplateType: tt, TemplateData: raw}
+	var t Template
+	switch mt {
+	case MessageTypeEmail:
+		t, err = NewEmailTemplateFromMessage(d, msg)
+	case MessageTypeSMS, MessageTypePush:
+		t, err = NewSMSTemplateFromMessage(d, msg)
+	default:
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReason("Message type is not valid"))
+	}
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to build the %s template %q: %s", mt, tt, err))
+	}
+	return t, nil
+}
+
+// RenderTemplatePreview renders the template with the given model.
+func RenderTemplatePreview(ctx context.Context, t Template) (_ *TemplatePreview, err error) {
+	preview := &TemplatePreview{TemplateType: t.TemplateType()}
+
+	switch t := t.(type) {
+	case EmailTemplate:
+		preview.Type = MessageTypeEmail
+		if preview.Subject, err = t.EmailSubject(ctx); err != nil {
+			return nil, err
+		}
+		if preview.BodyPlaintext, err = t.EmailBodyPlaintext(ctx); err != nil {
+			return nil, err
+		}
+		if preview.BodyHTML, err = t.EmailBody(ctx); err != nil {
+			return nil, err
+		}
+	case SMSTemplate:
+		preview.Type = MessageTypeSMS
+		if preview.SMSBody, err = t.SMSBody(ctx); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unable to render template of type %T", t)
+	}
+
+	return preview, nil
+}
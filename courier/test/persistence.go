@@ -369,5 +369,114 @@ func TestPersister(ctx context.Context, newNetworkUnlessExisting NetworkWrapper,
 				require.ErrorIs(t, err, sqlcon.ErrNoRows())
 			})
 		})
+
+		t.Run("case=RedactMessage", func(t *testing.T) {
+			msg := courier.Message{Subject: "code 123456", Body: "body 123456", TemplateData: []byte(`{"login_code":"123456"}`)}
+			require.NoError(t, p.AddMessage(ctx, &msg))
+			require.NoError(t, msg.Redact())
+			require.NoError(t, p.RedactMessage(ctx, &msg))
+
+			actual, err := p.FetchMessage(ctx, msg.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "code <redacted>", actual.Subject)
+			assert.Equal(t, "body <redacted>", actual.Body)
+			assert.JSONEq(t, `{"login_code":"<redacted>"}`, string(actual.TemplateData))
+
+			t.Run("can not update on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+				require.ErrorIs(t, p.RedactMessage(ctx, &msg), sqlcon.ErrNoRows())
+			})
+		})
+
+		t.Run("case=RequeueMessage", func(t *testing.T) {
+			msg := courier.Message{}
+			require.NoError(t, p.AddMessage(ctx, &msg))
+
+			require.ErrorIs(t, p.RequeueMessage(ctx, msg.ID), sqlcon.ErrNoRows(), "queued messages can not be requeued")
+
+			require.NoError(t, p.IncrementMessageSendCount(ctx, msg.ID))
+			require.NoError(t, p.SetMessageStatus(ctx, msg.ID, courier.MessageStatusAbandoned))
+
+			t.Run("can not update on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+				require.ErrorIs(t, p.RequeueMessage(ctx, msg.ID), sqlcon.ErrNoRows())
+			})
+
+			require.NoError(t, p.RequeueMessage(ctx, msg.ID))
+			actual, err := p.FetchMessage(ctx, msg.ID)
+			require.NoError(t, err)
+			assert.Equal(t, courier.MessageStatusQueued, actual.Status)
+			assert.Zero(t, actual.SendCount)
+		})
+
+		t.Run("case=RequeueMessages", func(t *testing.T) {
+			old, recent, sent := courier.Message{}, courier.Message{}, courier.Message{}
+			for _, m := range []*courier.Message{&old, &recent, &sent} {
+				require.NoError(t, p.AddMessage(ctx, m))
+			}
+			require.NoError(t, p.IncrementMessageSendCount(ctx, recent.ID))
+			require.NoError(t, p.SetMessageStatus(ctx, old.ID, courier.MessageStatusAbandoned))
+			require.NoError(t, p.SetMessageStatus(ctx, recent.ID, courier.MessageStatusAbandoned))
+			require.NoError(t, p.SetMessageStatus(ctx, sent.ID, courier.MessageStatusSent))
+			require.NoError(t, p.GetConnection(ctx).RawQuery(
+				"UPDATE courier_messages SET created_at = ? WHERE id = ? AND nid = ?",
+				time.Now().UTC().Add(-48*time.Hour), old.ID, nid).Exec())
+
+			t.Run("does not requeue on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+				count, err := p.RequeueMessages(ctx, courier.MessageStatusAbandoned, time.Now().Add(-time.Hour))
+				require.NoError(t, err)
+				assert.Zero(t, count)
+			})
+
+			count, err := p.RequeueMessages(ctx, courier.MessageStatusAbandoned, time.Now().Add(-time.Hour))
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, count, 1)
+
+			for id, expected := range map[uuid.UUID]courier.MessageStatus{
+				old.ID:    courier.MessageStatusAbandoned,
+				recent.ID: courier.MessageStatusQueued,
+				sent.ID:   courier.MessageStatusSent,
+			} {
+				actual, err := p.FetchMessage(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, expected, actual.Status)
+			}
+			actual, err := p.FetchMessage(ctx, recent.ID)
+			require.NoError(t, err)
+			assert.Zero(t, actual.SendCount)
+		})
+
+		t.Run("case=DeleteExpiredMessages", func(t *testing.T) {
+			old, recent, queued := courier.Message{}, courier.Message{}, courier.Message{}
+			for _, m := range []*courier.Message{&old, &recent, &queued} {
+				require.NoError(t, p.AddMessage(ctx, m))
+			}
+			require.NoError(t, p.SetMessageStatus(ctx, old.ID, courier.MessageStatusSent))
+			require.NoError(t, p.SetMessageStatus(ctx, recent.ID, courier.MessageStatusSent))
+			for _, id := range []uuid.UUID{old.ID, queued.ID} {
+				createdAt := time.Now().UTC().Add(-48 * time.Hour)
+				require.NoError(t, p.GetConnection(ctx).RawQuery(
+					"UPDATE courier_messages SET created_at = ? WHERE id = ? AND nid = ?",
+					createdAt, id, nid).Exec())
+			}
+			require.NoError(t, p.RecordDispatch(ctx, old.ID, courier.CourierMessageDispatchStatusSuccess, nil))
+
+			t.Run("does not delete on another network", func(t *testing.T) {
+				_, p := newNetwork(t, ctx)
+				require.NoError(t, p.DeleteExpiredMessages(ctx, courier.MessageStatusSent, time.Now().Add(-24*time.Hour), 100))
+			})
+			_, err := p.FetchMessage(ctx, old.ID)
+			require.NoError(t, err)
+
+			require.NoError(t, p.DeleteExpiredMessages(ctx, courier.MessageStatusSent, time.Now().Add(-24*time.Hour), 100))
+
+			_, err = p.FetchMessage(ctx, old.ID)
+			require.ErrorIs(t, err, sqlcon.ErrNoRows())
+			_, err = p.FetchMessage(ctx, recent.ID)
+			require.NoError(t, err, "messages within the retention period are kept")
+			_, err = p.FetchMessage(ctx, queued.ID)
+			require.NoError(t, err, "messages with another status are kept")
+		})
 	}
 }
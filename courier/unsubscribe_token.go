@@ -0,0 +1,98 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/courier/template"
+	"github.com/ory/x/aead"
+)
+
+// unsubscribeTokenContext binds unsubscribe tokens to their purpose so that
+// other payloads sealed with the same secret can not be used as tokens.
+const unsubscribeTokenContext = "ory-kratos-courier-unsubscribe"
+
+// Unsubscribe Token
+//
+// The content of a List-Unsubscribe token.
+//
+// swagger:model courierUnsubscribeToken
+type UnsubscribeToken struct {
+	// Recipient is the address which asked to be unsubscribed.
+	//
+	// required: true
+	Recipient string `json:"recipient"`
+
+	// TemplateType is the template of the message the request originates from.
+	//
+	// required: true
+	TemplateType template.TemplateType `json:"template_type"`
+}
+
+func unsubscribeTokenKey(secret []byte) [32]byte {
+	return sha512.Sum512_256(append([]byte(unsubscribeTokenContext+":"), secret...))
+}
+
+// NewUnsubscribeToken seals the token with the first secret. The result is
+// opaque and can not be forged without the secret.
+func NewUnsubscribeToken(secrets [][]byte, token UnsubscribeToken) (string, error) {
+	if len(secrets) == 0 {
+		return "", errors.WithStack(herodot.ErrMisconfiguration().WithReason("No secret is configured to sign unsubscribe tokens."))
+	}
+
+	raw, err := json.Marshal(token)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	a, err := aead.New(unsubscribeTokenKey(secrets[0]))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	nonce := make([]byte, a.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(a.Seal(nonce, nonce, raw, []byte(unsubscribeTokenContext))), nil
+}
+
+// ParseUnsubscribeToken opens a token sealed with any of the secrets.
+func ParseUnsubscribeToken(secrets [][]byte, token string) (*UnsubscribeToken, error) {
+	invalid := errors.WithStack(herodot.ErrBadRequest().WithReason("The unsubscribe token is invalid."))
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+
+	for _, secret := range secrets {
+		a, err := aead.New(unsubscribeTokenKey(secret))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if len(raw) < a.NonceSize() {
+			return nil, invalid
+		}
+
+		opened, err := a.Open(nil, raw[:a.NonceSize()], raw[a.NonceSize():], []byte(unsubscribeTokenContext))
+		if err != nil {
+			continue
+		}
+
+		var t UnsubscribeToken
+		if err := json.Unmarshal(opened, &t); err != nil {
+			return nil, invalid
+		}
+		return &t, nil
+	}
+	return nil, invalid
+}
@@ -0,0 +1,46 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/courier/template"
+)
+
+func TestUnsubscribeToken(t *testing.T) {
+	t.Parallel()
+
+	current, previous := []byte("current-secret-of-32-characters!"), []byte("previous-secret-of-32-characters")
+	expected := courier.UnsubscribeToken{Recipient: "user@example.org", TemplateType: template.TypeTestStub}
+
+	t.Run("case=round trips with rotated secrets", func(t *testing.T) {
+		token, err := courier.NewUnsubscribeToken([][]byte{previous}, expected)
+		require.NoError(t, err)
+		assert.NotContains(t, token, "user")
+
+		actual, err := courier.ParseUnsubscribeToken([][]byte{current, previous}, token)
+		require.NoError(t, err)
+		assert.Equal(t, expected, *actual)
+	})
+
+	t.Run("case=rejects tokens sealed with unknown secrets", func(t *testing.T) {
+		token, err := courier.NewUnsubscribeToken([][]byte{previous}, expected)
+		require.NoError(t, err)
+
+		_, err = courier.ParseUnsubscribeToken([][]byte{current}, token)
+		require.Error(t, err)
+	})
+
+	t.Run("case=rejects malformed tokens", func(t *testing.T) {
+		for _, token := range []string{"", "not base64!", "c2hvcnQ"} {
+			_, err := courier.ParseUnsubscribeToken([][]byte{current}, token)
+			require.Error(t, err, token)
+		}
+	})
+}
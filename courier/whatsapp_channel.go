@@ -0,0 +1,148 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package courier
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/x/otelx"
+)
+
+const whatsAppDefaultURL = "https://graph.facebook.com/v21.0"
+
+type whatsAppChannel struct {
+	id  string
+	cfg *config.WhatsAppConfig
+	d   channelDependencies
+}
+
+var _ Channel = new(whatsAppChannel)
+
+func newWhatsAppChannel(id string, cfg *config.WhatsAppConfig, d channelDependencies) (*whatsAppChannel, error) {
+	if cfg == nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Courier channel %q is missing its whatsapp_config.", id))
+	}
+	return &whatsAppChannel{id: id, cfg: cfg, d: d}, nil
+}
+
+func (c *whatsAppChannel) ID() string {
+	return c.id
+}
+
+type whatsAppRequest struct {
+	MessagingProduct string           `json:"messaging_product"`
+	To               string           `json:"to"`
+	Type             string           `json:"type"`
+	Template         whatsAppTemplate `json:"template"`
+}
+
+type whatsAppTemplate struct {
+	Name       string              `json:"name"`
+	Language   whatsAppLanguage    `json:"language"`
+	Components []whatsAppComponent `json:"components,omitempty"`
+}
+
+type whatsAppLanguage struct {
+	Code string `json:"code"`
+}
+
+type whatsAppComponent struct {
+	Type       string              `json:"type"`
+	SubType    string              `json:"sub_type,omitempty"`
+	Index      string              `json:"index,omitempty"`
+	Parameters []whatsAppParameter `json:"parameters"`
+}
+
+type whatsAppParameter struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// newWhatsAppRequest maps the message to the WhatsApp message template
+// configured for its template type. WhatsApp only delivers pre-approved
+// templates, so the rendered body is not used.
+func newWhatsAppRequest(cfg *config.WhatsAppConfig, msg Message) (*whatsAppRequest, error) {
+	tmpl, ok := cfg.Templates[string(msg.TemplateType)]
+	if !ok {
+		return nil, errors.Errorf("no WhatsApp message template is configured for template type %q", msg.TemplateType)
+	}
+
+	language := tmpl.Language
+	if language == "" {
+		language = "en"
+	}
+
+	data := gjson.ParseBytes(msg.TemplateData)
+	req := &whatsAppRequest{
+		MessagingProduct: "whatsapp",
+		To:               msg.Recipient,
+		Type:             "template",
+		Template: whatsAppTemplate{
+			Name:     tmpl.Name,
+			Language: whatsAppLanguage{Code: language},
+		},
+	}
+
+	if len(tmpl.Parameters) > 0 {
+		body := whatsAppComponent{Type: "body"}
+		for _, path := range tmpl.Parameters {
+			body.Parameters = append(body.Parameters, whatsAppParameter{Type: "text", Text: data.Get(path).String()})
+		}
+		req.Template.Components = append(req.Template.Components, body)
+	}
+
+	if tmpl.ButtonParameter != "" {
+		req.Template.Components = append(req.Template.Components, whatsAppComponent{
+			Type:       "button",
+			SubType:    "url",
+			Index:      "0",
+			Parameters: []whatsAppParameter{{Type: "text", Text: data.Get(tmpl.ButtonParameter).String()}},
+		})
+	}
+
+	return req, nil
+}
+
+func (c *whatsAppChannel) Dispatch(ctx context.Context, msg Message) (err error) {
+	ctx, span := c.d.Tracer(ctx).Tracer().Start(ctx, "courier.whatsAppChannel.Dispatch")
+	defer otelx.End(span, &err)
+
+	payload, err := newWhatsAppRequest(c.cfg, msg)
+	if err != nil {
+		return err
+	}
+
+	base := c.cfg.URL
+	if base == "" {
+		base = whatsAppDefaultURL
+	}
+	endpoint := strings.TrimRight(base, "/") + "/" + url.PathEscape(c.cfg.PhoneNumberID) + "/messages"
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+c.cfg.AccessToken)
+
+	if err := postJSON(ctx, c.d, endpoint, header, payload); err != nil {
+		c.d.Logger().
+			WithError(err).
+			WithField("message_id", msg.ID).
+			WithField("message_nid", msg.NID).
+			WithField("message_template_type", msg.TemplateType).
+			Error("Sending message via WhatsApp failed.")
+		return err
+	}
+
+	c.d.Logger().
+		WithField("message_id", msg.ID).
+		WithField("message_nid", msg.NID).
+		Debug("Courier sent out WhatsApp message.")
+	return nil
+}
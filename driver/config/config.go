@@ -82,7 +82,11 @@ const (
 	ViperKeyCourierSMTPFromName                              = "courier.smtp.from_name"
 	ViperKeyCourierSMTPHeaders                               = "courier.smtp.headers"
 	ViperKeyCourierSMTPLocalName                             = "courier.smtp.local_name"
+	ViperKeyCourierSMTPDKIM                                  = "courier.smtp.dkim"
+	ViperKeyCourierSMTPListUnsubscribe                       = "courier.smtp.list_unsubscribe"
 	ViperKeyCourierMessageRetries                            = "courier.message_retries"
+	ViperKeyCourierMessageRetention                          = "courier.message_retention"
+	ViperKeyCourierMessageRedactionEnabled                   = "courier.message_redaction.enabled"
 	ViperKeyCourierWorkerPullCount                           = "courier.worker.pull_count"
 	ViperKeyCourierWorkerPullWait                            = "courier.worker.pull_wait"
 	ViperKeyCourierChannels                                  = "courier.channels"
@@ -90,6 +94,7 @@ const (
 	ViperKeySecretsCookie                                    = "secrets.cookie"
 	ViperKeySecretsCipher                                    = "secrets.cipher"
 	ViperKeySecretsPagination                                = "secrets.pagination"
+	ViperKeySecretsPepper                                    = "secrets.pepper"
 	ViperKeyPublicBaseURL                                    = "serve.public.base_url"
 	ViperKeyAdminBaseURL                                     = "serve.admin.base_url"
 	ViperKeySessionLifespan                                  = "session.lifespan"
@@ -143,6 +148,7 @@ const (
 	ViperKeySelfServiceSettingsRequestLifespan               = "selfservice.flows.settings.lifespan"
 	ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter = "selfservice.flows.settings.privileged_session_max_age"
 	ViperKeySelfServiceSettingsRequiredAAL                   = "selfservice.flows.settings.required_aal"
+	ViperKeySelfServiceSettingsDataExportEnabled             = "selfservice.flows.settings.data_export.enabled"
 	ViperKeySelfServiceRecoveryAfter                         = "selfservice.flows.recovery.after"
 	ViperKeySelfServiceRecoveryBeforeHooks                   = "selfservice.flows.recovery.before.hooks"
 	ViperKeySelfServiceRecoveryEnabled                       = "selfservice.flows.recovery.enabled"
@@ -161,6 +167,9 @@ const (
 	ViperKeySelfServiceVerificationNotifyUnknownRecipients   = "selfservice.flows.verification.notify_unknown_recipients"
 	ViperKeyDefaultIdentitySchemaID                          = "identity.default_schema_id"
 	ViperKeyIdentitySchemas                                  = "identity.schemas"
+	ViperKeyIdentitySchemaMigrations                         = "identity.schema_migrations"
+	ViperKeyIdentityDeletionGracePeriod                      = "identity.deletion.grace_period"
+	ViperKeyIdentityMergeTransformURL                        = "identity.merge.transform_url"
 	ViperKeyHasherAlgorithm                                  = "hashers.algorithm"
 	ViperKeyHasherArgon2ConfigMemory                         = "hashers.argon2.memory"
 	ViperKeyHasherArgon2ConfigIterations                     = "hashers.argon2.iterations"
@@ -172,6 +181,10 @@ const (
 	ViperKeyHasherArgon2ConfigDedicatedMemory                = "hashers.argon2.dedicated_memory"
 	ViperKeyHasherBcryptCost                                 = "hashers.bcrypt.cost"
 	ViperKeyCipherAlgorithm                                  = "ciphers.algorithm"
+	ViperKeyCipherKMS                                        = "ciphers.kms"
+	ViperKeyCipherKMSDataKeyTTL                              = "ciphers.kms.data_key_ttl"
+	ViperKeyCipherRotationInterval                           = "ciphers.rotation.interval"
+	ViperKeyCipherRotationBatchSize                          = "ciphers.rotation.batch_size"
 	ViperKeyDatabaseCleanupSleepTables                       = "database.cleanup.sleep.tables"
 	ViperKeyDatabaseCleanupBatchSize                         = "database.cleanup.batch_size"
 	ViperKeyLinkLifespan                                     = "selfservice.methods.link.config.lifespan"
@@ -204,6 +217,8 @@ const (
 	ViperKeyPasskeyRegistrationTimeout                       = "selfservice.methods.passkey.config.timeouts.registration"
 	ViperKeyPasskeyLoginTimeout                              = "selfservice.methods.passkey.config.timeouts.login"
 	ViperKeyOrganizations                                    = "selfservice.methods.b2b.config.organizations"
+	ViperKeyOrganizationsEnforceSSO                          = "selfservice.methods.b2b.config.enforce_sso"
+	ViperKeyDeletionGracePeriod                              = "selfservice.methods.deletion.config.grace_period"
 	ViperKeyOAuth2ProviderURL                                = "oauth2_provider.url"
 	ViperKeyOAuth2ProviderHeader                             = "oauth2_provider.headers"
 	ViperKeyOAuth2ProviderOverrideReturnTo                   = "oauth2_provider.override_return_to"
@@ -262,6 +277,27 @@ type (
 		ID                    string `json:"id" koanf:"id"`
 		URL                   string `json:"url" koanf:"url"`
 		SelfserviceSelectable bool   `json:"selfservice_selectable" koanf:"selfservice_selectable"`
+
+		// Version is the version of the schema at URL. Identities are pinned
+		// to the version they were validated against.
+		Version string `json:"version,omitempty" koanf:"version"`
+
+		// Versions are the previous versions of the schema, which are still
+		// used to validate identities pinned to them.
+		Versions []SchemaVersion `json:"versions,omitempty" koanf:"versions"`
+	}
+	// SchemaVersion is an immutable previous version of an identity schema.
+	SchemaVersion struct {
+		Version string `json:"version" koanf:"version"`
+		URL     string `json:"url" koanf:"url"`
+	}
+	// IdentitySchemaMigration moves identities from one identity schema to
+	// another, transforming their traits and metadata with a Jsonnet snippet.
+	IdentitySchemaMigration struct {
+		ID           string `json:"id" koanf:"id"`
+		From         string `json:"from" koanf:"from"`
+		To           string `json:"to" koanf:"to"`
+		TransformURL string `json:"transform_url" koanf:"transform_url"`
 	}
 	PasswordPolicy struct {
 		HaveIBeenPwnedHost               string `json:"haveibeenpwned_host"`
@@ -291,6 +327,69 @@ type (
 		Type          string         `json:"type" koanf:"type"`
 		SMTPConfig    *SMTPConfig    `json:"smtp_config" koanf:"smtp_config"`
 		RequestConfig request.Config `json:"request_config" koanf:"request_config"`
+
+		FCMConfig      *FCMConfig      `json:"fcm_config" koanf:"fcm_config"`
+		APNsConfig     *APNsConfig     `json:"apns_config" koanf:"apns_config"`
+		WhatsAppConfig *WhatsAppConfig `json:"whatsapp_config" koanf:"whatsapp_config"`
+	}
+	CipherKMSConfig struct {
+		Provider string              `json:"provider" koanf:"provider"`
+		Vault    *VaultTransitConfig `json:"vault" koanf:"vault"`
+		AWS      *AWSKMSConfig       `json:"aws" koanf:"aws"`
+		GCP      *GCPKMSConfig       `json:"gcp" koanf:"gcp"`
+		// Previous lists keys which were used before, to unwrap data keys
+		// until all ciphertexts were rotated to the current key.
+		Previous []CipherKMSConfig `json:"previous,omitempty" koanf:"previous"`
+	}
+	VaultTransitConfig struct {
+		URL       string `json:"url" koanf:"url"`
+		Token     string `json:"token" koanf:"token"`
+		Namespace string `json:"namespace" koanf:"namespace"`
+		Mount     string `json:"mount" koanf:"mount"`
+		Key       string `json:"key" koanf:"key"`
+	}
+	AWSKMSConfig struct {
+		URL             string `json:"url" koanf:"url"`
+		Region          string `json:"region" koanf:"region"`
+		KeyID           string `json:"key_id" koanf:"key_id"`
+		AccessKeyID     string `json:"access_key_id" koanf:"access_key_id"`
+		SecretAccessKey string `json:"secret_access_key" koanf:"secret_access_key"`
+		SessionToken    string `json:"session_token" koanf:"session_token"`
+	}
+	GCPKMSConfig struct {
+		URL                   string `json:"url" koanf:"url"`
+		KeyName               string `json:"key_name" koanf:"key_name"`
+		ServiceAccountKey     string `json:"service_account_key" koanf:"service_account_key"`
+		ServiceAccountKeyPath string `json:"service_account_key_path" koanf:"service_account_key_path"`
+		AccessToken           string `json:"access_token" koanf:"access_token"`
+	}
+	FCMConfig struct {
+		URL                   string `json:"url" koanf:"url"`
+		ProjectID             string `json:"project_id" koanf:"project_id"`
+		ServiceAccountKey     string `json:"service_account_key" koanf:"service_account_key"`
+		ServiceAccountKeyPath string `json:"service_account_key_path" koanf:"service_account_key_path"`
+		Title                 string `json:"title" koanf:"title"`
+	}
+	APNsConfig struct {
+		URL            string `json:"url" koanf:"url"`
+		TeamID         string `json:"team_id" koanf:"team_id"`
+		KeyID          string `json:"key_id" koanf:"key_id"`
+		PrivateKey     string `json:"private_key" koanf:"private_key"`
+		PrivateKeyPath string `json:"private_key_path" koanf:"private_key_path"`
+		Topic          string `json:"topic" koanf:"topic"`
+		Title          string `json:"title" koanf:"title"`
+	}
+	WhatsAppConfig struct {
+		URL           string                      `json:"url" koanf:"url"`
+		PhoneNumberID string                      `json:"phone_number_id" koanf:"phone_number_id"`
+		AccessToken   string                      `json:"access_token" koanf:"access_token"`
+		Templates     map[string]WhatsAppTemplate `json:"templates" koanf:"templates"`
+	}
+	WhatsAppTemplate struct {
+		Name            string   `json:"name" koanf:"name"`
+		Language        string   `json:"language" koanf:"language"`
+		Parameters      []string `json:"parameters" koanf:"parameters"`
+		ButtonParameter string   `json:"button_parameter" koanf:"button_parameter"`
 	}
 	SMTPConfig struct {
 		ConnectionURI  string            `json:"connection_uri" koanf:"connection_uri"`
@@ -300,6 +399,22 @@ type (
 		FromName       string            `json:"from_name" koanf:"from_name"`
 		Headers        map[string]string `json:"headers" koanf:"headers"`
 		LocalName      string            `json:"local_name" koanf:"local_name"`
+
+		DKIM            *SMTPDKIMConfig            `json:"dkim" koanf:"dkim"`
+		ListUnsubscribe *SMTPListUnsubscribeConfig `json:"list_unsubscribe" koanf:"list_unsubscribe"`
+	}
+	SMTPDKIMConfig struct {
+		Enabled        bool     `json:"enabled" koanf:"enabled"`
+		Domain         string   `json:"domain" koanf:"domain"`
+		Selector       string   `json:"selector" koanf:"selector"`
+		PrivateKey     string   `json:"private_key" koanf:"private_key"`
+		PrivateKeyPath string   `json:"private_key_path" koanf:"private_key_path"`
+		Headers        []string `json:"headers" koanf:"headers"`
+	}
+	SMTPListUnsubscribeConfig struct {
+		URL       string   `json:"url" koanf:"url"`
+		MailTo    string   `json:"mailto" koanf:"mailto"`
+		Templates []string `json:"templates" koanf:"templates"`
 	}
 	PasswordMigrationHook struct {
 		Enabled bool           `json:"enabled" koanf:"enabled"`
@@ -336,10 +451,13 @@ type (
 		CourierTemplatesAuthenticatorKeyAdded(ctx context.Context) *CourierEmailTemplate
 		CourierSMSTemplatesAuthenticatorKeyAdded(ctx context.Context) *CourierSMSTemplate
 		CourierMessageRetries(ctx context.Context) int
+		CourierMessageRetention(ctx context.Context, status string) time.Duration
+		CourierMessageRedactionEnabled(ctx context.Context) bool
 		CourierWorkerPullCount(ctx context.Context) int
 		CourierWorkerPullWait(ctx context.Context) time.Duration
 		CourierChannels(context.Context) ([]*CourierChannel, error)
 		ClientSMTPNoPrivateIPRanges(ctx context.Context) bool
+		SecretsDefault(ctx context.Context) [][]byte
 	}
 )
 
@@ -653,6 +771,37 @@ func (p *Config) IdentityTraitsSchemas(ctx context.Context) (ss Schemas, err err
 	return ss, nil
 }
 
+// IdentityDeletionGracePeriod returns for how long identities deleted through
+// the admin API are kept before they are purged. Zero means they are deleted
+// immediately.
+func (p *Config) IdentityDeletionGracePeriod(ctx context.Context) time.Duration {
+	return p.GetProvider(ctx).DurationF(ViperKeyIdentityDeletionGracePeriod, 0)
+}
+
+// IdentityMergeTransformURL returns the URL of the Jsonnet snippet which
+// merges the traits and metadata of identities merged through the admin API.
+func (p *Config) IdentityMergeTransformURL(ctx context.Context) string {
+	return p.GetProvider(ctx).String(ViperKeyIdentityMergeTransformURL)
+}
+
+func (p *Config) IdentitySchemaMigrations(ctx context.Context) (ms []IdentitySchemaMigration) {
+	if err := p.GetProvider(ctx).Unmarshal(ViperKeyIdentitySchemaMigrations, &ms); err != nil {
+		return nil
+	}
+	return ms
+}
+
+// IdentitySchemaMigration returns the identity schema migration with the
+// given ID.
+func (p *Config) IdentitySchemaMigration(ctx context.Context, id string) (*IdentitySchemaMigration, error) {
+	for _, m := range p.IdentitySchemaMigrations(ctx) {
+		if m.ID == id {
+			return &m, nil
+		}
+	}
+	return nil, errors.WithStack(herodot.ErrNotFound().WithReasonf("Identity schema migration %q is not configured.", id))
+}
+
 func (p *Config) DSN(ctx context.Context) string {
 	pp := p.GetProvider(ctx)
 	dsn := pp.String(ViperKeyDSN)
@@ -950,6 +1099,19 @@ func ToCipherSecrets(secrets []string) [][32]byte {
 	return result
 }
 
+// SecretsPepper returns the secrets used to pepper passwords before hashing.
+// The first secret peppers new hashes, the others verify older hashes.
+func (p *Config) SecretsPepper(ctx context.Context) [][]byte {
+	secrets := p.GetProvider(ctx).Strings(ViperKeySecretsPepper)
+
+	result := make([][]byte, len(secrets))
+	for k, v := range secrets {
+		result[k] = []byte(v)
+	}
+
+	return result
+}
+
 func (p *Config) SecretsPagination(ctx context.Context) [][32]byte {
 	secrets := p.GetProvider(ctx).Strings(ViperKeySecretsPagination)
 
@@ -1249,6 +1411,16 @@ func (p *Config) CourierMessageRetries(ctx context.Context) int {
 	return p.GetProvider(ctx).IntF(ViperKeyCourierMessageRetries, 5)
 }
 
+// CourierMessageRetention returns how long messages with the given status
+// ("sent" or "abandoned") are kept. Zero means they are kept forever.
+func (p *Config) CourierMessageRetention(ctx context.Context, status string) time.Duration {
+	return p.GetProvider(ctx).Duration(ViperKeyCourierMessageRetention + "." + status)
+}
+
+func (p *Config) CourierMessageRedactionEnabled(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool(ViperKeyCourierMessageRedactionEnabled)
+}
+
 func (p *Config) CourierWorkerPullCount(ctx context.Context) int {
 	return p.GetProvider(ctx).Int(ViperKeyCourierWorkerPullCount)
 }
@@ -1387,6 +1559,13 @@ func (p *Config) SelfServiceLinkMethodLifespan(ctx context.Context) time.Duratio
 	return p.GetProvider(ctx).DurationF(ViperKeyLinkLifespan, time.Hour)
 }
 
+// SelfServiceDeletionMethodGracePeriod returns how long an account deletion
+// requested in the settings flow is deferred. During the grace period, signing
+// in cancels the deletion. A zero grace period deletes the account immediately.
+func (p *Config) SelfServiceDeletionMethodGracePeriod(ctx context.Context) time.Duration {
+	return p.GetProvider(ctx).DurationF(ViperKeyDeletionGracePeriod, 0)
+}
+
 func (p *Config) SelfServiceCodeMethodLifespan(ctx context.Context) time.Duration {
 	return p.GetProvider(ctx).DurationF(ViperKeyCodeLifespan, time.Hour)
 }
@@ -1492,6 +1671,10 @@ func (p *Config) SelfServiceSettingsRequiredAAL(ctx context.Context) string {
 	return p.GetProvider(ctx).String(ViperKeySelfServiceSettingsRequiredAAL)
 }
 
+func (p *Config) SelfServiceSettingsDataExportEnabled(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool(ViperKeySelfServiceSettingsDataExportEnabled)
+}
+
 func (p *Config) CookieSameSiteMode(ctx context.Context) http.SameSite {
 	switch p.GetProvider(ctx).StringF(ViperKeyCookieSameSite, "Lax") {
 	case "Lax":
@@ -1633,10 +1816,30 @@ func (p *Config) PasskeyConfig(ctx context.Context) *webauthn.Config {
 }
 
 type Organization struct {
-	ID              uuid.UUID     `koanf:"id"`
-	Domains         []string      `koanf:"domains"`
-	DefaultRegion   region.Region `koanf:"default_region"`
-	SessionLifespan time.Duration `koanf:"session_lifespan"`
+	ID              uuid.UUID        `koanf:"id"`
+	Label           string           `koanf:"label"`
+	Domains         []string         `koanf:"domains"`
+	DefaultRegion   region.Region    `koanf:"default_region"`
+	SessionLifespan time.Duration    `koanf:"session_lifespan"`
+	SCIM            OrganizationSCIM `koanf:"scim"`
+}
+
+// OrganizationSCIM configures SCIM provisioning of an organization's
+// identities. Provisioning is disabled if no bearer tokens are configured.
+type OrganizationSCIM struct {
+	BearerTokens []string `koanf:"bearer_tokens"`
+	MapperURL    string   `koanf:"mapper_url"`
+	SchemaID     string   `koanf:"identity_schema_id"`
+}
+
+// Organization returns the configured organization with the given ID.
+func (p *Config) Organization(ctx context.Context, id uuid.UUID) (*Organization, bool) {
+	for _, org := range p.Organizations(ctx) {
+		if org.ID == id {
+			return &org, true
+		}
+	}
+	return nil, false
 }
 
 func (p *Config) Organizations(ctx context.Context) (orgs []Organization) {
@@ -1646,6 +1849,13 @@ func (p *Config) Organizations(ctx context.Context) (orgs []Organization) {
 	return orgs
 }
 
+// OrganizationsEnforceSSO returns true if identities whose identifier belongs
+// to an organization with single sign-on providers must sign in with one of
+// them.
+func (p *Config) OrganizationsEnforceSSO(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool(ViperKeyOrganizationsEnforceSSO)
+}
+
 func (p *Config) HasherPasswordHashingAlgorithm(ctx context.Context) string {
 	configValue := p.GetProvider(ctx).StringF(ViperKeyHasherAlgorithm, DefaultPasswordHashingAlgorithm)
 	switch configValue {
@@ -1665,6 +1875,8 @@ func (p *Config) CipherAlgorithm(ctx context.Context) string {
 		return configValue
 	case "xchacha20-poly1305":
 		return configValue
+	case "kms":
+		return configValue
 	case "aes":
 		fallthrough
 	default:
@@ -1672,6 +1884,33 @@ func (p *Config) CipherAlgorithm(ctx context.Context) string {
 	}
 }
 
+func (p *Config) CipherKMS(ctx context.Context) (*CipherKMSConfig, error) {
+	var c CipherKMSConfig
+	if err := p.GetProvider(ctx).Unmarshal(ViperKeyCipherKMS, &c); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &c, nil
+}
+
+func (p *Config) CipherKMSDataKeyTTL(ctx context.Context) time.Duration {
+	return p.GetProvider(ctx).DurationF(ViperKeyCipherKMSDataKeyTTL, time.Hour)
+}
+
+// IsBackgroundCipherRotationEnabled reports whether this instance runs the
+// cipher key rotation job. It is set per instance, like the background
+// courier, so that only one replica re-encrypts ciphertexts.
+func (p *Config) IsBackgroundCipherRotationEnabled(ctx context.Context) bool {
+	return p.GetProvider(ctx).Bool("watch-cipher-rotation")
+}
+
+func (p *Config) CipherRotationInterval(ctx context.Context) time.Duration {
+	return p.GetProvider(ctx).DurationF(ViperKeyCipherRotationInterval, time.Hour)
+}
+
+func (p *Config) CipherRotationBatchSize(ctx context.Context) int {
+	return p.GetProvider(ctx).IntF(ViperKeyCipherRotationBatchSize, 100)
+}
+
 func (p *Config) GetProvider(ctx context.Context) *configx.Provider {
 	return p.c.Config(ctx, p.p)
 }
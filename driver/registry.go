@@ -15,10 +15,13 @@ import (
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/export"
 	"github.com/ory/kratos/hash"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/persistence"
 	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/scim"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/logout"
@@ -84,6 +87,7 @@ type Registry interface {
 	continuity.PersistenceProvider
 
 	cipher.Provider
+	identity.CipherRotationTargetsProvider
 
 	courier.Provider
 
@@ -101,12 +105,26 @@ type Registry interface {
 	identity.PrivilegedPoolProvider
 	identity.ManagementProvider
 	identity.ActiveCredentialsCounterStrategyProvider
+	identity.ScheduledDeletionPersistenceProvider
 
 	courier.HandlerProvider
 	courier.PersistenceProvider
 
+	scim.HandlerProvider
+	scim.PersistenceProvider
+
+	export.HandlerProvider
+	export.ExporterProvider
+
+	organization.HandlerProvider
+	organization.ManagementProvider
+	organization.PersistenceProvider
+	organization.ResolverProvider
+	organization.ProviderBinderProvider
+
 	schema.HandlerProvider
 	schema.IdentitySchemaProvider
+	schema.PersistenceProvider
 
 	password2.ValidationProvider
 
@@ -126,6 +144,7 @@ type Registry interface {
 	login.HookExecutorProvider
 	login.HandlerProvider
 	login.StrategyProvider
+	login.OrganizationSSOStarterProvider
 
 	logout.HandlerProvider
 
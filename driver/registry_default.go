@@ -6,6 +6,7 @@ package driver
 import (
 	"context"
 	"crypto/sha256"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
@@ -26,12 +27,15 @@ import (
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/export"
 	"github.com/ory/kratos/hash"
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/persistence"
 	"github.com/ory/kratos/persistence/sql"
 	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/scim"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/flow/login"
@@ -42,6 +46,7 @@ import (
 	"github.com/ory/kratos/selfservice/flow/verification"
 	"github.com/ory/kratos/selfservice/hook"
 	"github.com/ory/kratos/selfservice/strategy/code"
+	"github.com/ory/kratos/selfservice/strategy/deletion"
 	"github.com/ory/kratos/selfservice/strategy/idfirst"
 	"github.com/ory/kratos/selfservice/strategy/link"
 	"github.com/ory/kratos/selfservice/strategy/lookup"
@@ -112,6 +117,15 @@ type RegistryDefault struct {
 
 	courierHandler *courier.Handler
 
+	scimHandler *scim.Handler
+
+	dataExporter      *export.Exporter
+	dataExportHandler *export.Handler
+
+	organizationHandler        *organization.Handler
+	organizationManager        *organization.Manager
+	organizationDomainResolver organization.TXTResolver
+
 	continuityManager *continuity.Manager
 
 	schemaHandler *schema.Handler
@@ -188,9 +202,11 @@ func (m *RegistryDefault) RegisterPublicRoutes(ctx context.Context, router *http
 	m.SettingsHandler().RegisterPublicRoutes(router)
 	m.IdentityHandler().RegisterPublicRoutes(router)
 	m.CourierHandler().RegisterPublicRoutes(router)
+	m.SCIMHandler().RegisterPublicRoutes(router)
 	m.SessionHandler().RegisterPublicRoutes(router)
 	m.SelfServiceErrorHandler().RegisterPublicRoutes(router)
 	m.SchemaHandler().RegisterPublicRoutes(router)
+	m.DataExportHandler().RegisterPublicRoutes(router)
 
 	m.RecoveryHandler().RegisterPublicRoutes(router)
 
@@ -218,6 +234,8 @@ func (m *RegistryDefault) RegisterAdminRoutes(ctx context.Context, router *httpr
 	m.SettingsHandler().RegisterAdminRoutes(router)
 	m.IdentityHandler().RegisterAdminRoutes(router)
 	m.CourierHandler().RegisterAdminRoutes(router)
+	m.OrganizationHandler().RegisterAdminRoutes(router)
+	m.DataExportHandler().RegisterAdminRoutes(router)
 	m.SelfServiceErrorHandler().RegisterAdminRoutes(router)
 
 	m.RecoveryHandler().RegisterAdminRoutes(router)
@@ -351,6 +369,7 @@ func (m *RegistryDefault) selfServiceStrategies() []any {
 				webauthn.NewStrategy(m),
 				lookup.NewStrategy(m),
 				idfirst.NewStrategy(m),
+				deletion.NewStrategy(m),
 			}
 		}
 	}
@@ -472,6 +491,33 @@ func (m *RegistryDefault) ActiveCredentialsCounterStrategies(_ context.Context)
 	return
 }
 
+func (m *RegistryDefault) UpstreamLogouters() (upstreamLogouters []logout.UpstreamLogouter) {
+	for _, strategy := range m.selfServiceStrategies() {
+		if s, ok := strategy.(logout.UpstreamLogouter); ok {
+			upstreamLogouters = append(upstreamLogouters, s)
+		}
+	}
+	return
+}
+
+func (m *RegistryDefault) OrganizationSSOStarters() (starters []login.OrganizationSSOStarter) {
+	for _, strategy := range m.selfServiceStrategies() {
+		if s, ok := strategy.(login.OrganizationSSOStarter); ok {
+			starters = append(starters, s)
+		}
+	}
+	return
+}
+
+func (m *RegistryDefault) OrganizationProviderBinders() (binders []organization.ProviderBinder) {
+	for _, strategy := range m.selfServiceStrategies() {
+		if s, ok := strategy.(organization.ProviderBinder); ok {
+			binders = append(binders, s)
+		}
+	}
+	return
+}
+
 func (m *RegistryDefault) IdentityValidator() *identity.Validator {
 	return m.identityValidator
 }
@@ -519,6 +565,54 @@ func (m *RegistryDefault) CourierHandler() *courier.Handler {
 	return m.courierHandler
 }
 
+func (m *RegistryDefault) SCIMHandler() *scim.Handler {
+	if m.scimHandler == nil {
+		m.scimHandler = scim.NewHandler(m)
+	}
+	return m.scimHandler
+}
+
+func (m *RegistryDefault) DataExporter() *export.Exporter {
+	if m.dataExporter == nil {
+		m.dataExporter = export.NewExporter(m)
+	}
+	return m.dataExporter
+}
+
+func (m *RegistryDefault) DataExportHandler() *export.Handler {
+	if m.dataExportHandler == nil {
+		m.dataExportHandler = export.NewHandler(m)
+	}
+	return m.dataExportHandler
+}
+
+func (m *RegistryDefault) OrganizationHandler() *organization.Handler {
+	if m.organizationHandler == nil {
+		m.organizationHandler = organization.NewHandler(m)
+	}
+	return m.organizationHandler
+}
+
+func (m *RegistryDefault) OrganizationManager() *organization.Manager {
+	if m.organizationManager == nil {
+		m.organizationManager = organization.NewManager(m)
+	}
+	return m.organizationManager
+}
+
+func (m *RegistryDefault) OrganizationDomainResolver() organization.TXTResolver {
+	if m.organizationDomainResolver == nil {
+		return net.DefaultResolver
+	}
+	return m.organizationDomainResolver
+}
+
+// WithOrganizationDomainResolver sets the resolver used to verify the
+// ownership of organization domains.
+func (m *RegistryDefault) WithOrganizationDomainResolver(r organization.TXTResolver) {
+	m.organizationDomainResolver = r
+}
+
 func (m *RegistryDefault) SchemaHandler() *schema.Handler {
 	if m.schemaHandler == nil {
 		m.schemaHandler = schema.NewHandler(m)
@@ -540,6 +634,8 @@ func (m *RegistryDefault) Cipher(ctx context.Context) cipher.Cipher {
 			return cipher.NewCryptChaCha20(m.Config())
 		case "aes":
 			return cipher.NewCryptAES(m.Config())
+		case "kms":
+			return cipher.NewCryptKMS(ctx, m)
 		default:
 			m.l.Logger.Warning("No encryption configuration found. The default algorithm (noop) will be used, resulting in sensitive data being stored in plaintext")
 			return cipher.NewNoop()
@@ -547,6 +643,13 @@ func (m *RegistryDefault) Cipher(ctx context.Context) cipher.Cipher {
 	})
 }
 
+func (m *RegistryDefault) CipherRotationTargets() []identity.CipherRotationTarget {
+	return []identity.CipherRotationTarget{
+		identity.NewOIDCCredentialsCipherRotation(m),
+		oidc.NewProviderSecretsCipherRotation(m),
+	}
+}
+
 // WithHashers registers additional password hashers, keyed by the
 // hashers.algorithm value that selects them. See WithExtraHashers.
 func (m *RegistryDefault) WithHashers(hashers map[string]NewHasherFn) {
@@ -557,12 +660,12 @@ func (m *RegistryDefault) Hasher(ctx context.Context) hash.Hasher {
 	return m.passwordHasher.Get(func() hash.Hasher {
 		alg := m.c.HasherPasswordHashingAlgorithm(ctx)
 		if newHasher, ok := m.extraHashers[alg]; ok {
-			return newHasher(m)
+			return hash.NewHasherPeppered(newHasher(m), m)
 		}
 		if alg == "bcrypt" {
-			return hash.NewHasherBcrypt(m)
+			return hash.NewHasherPeppered(hash.NewHasherBcrypt(m), m)
 		}
-		return hash.NewHasherArgon2(m)
+		return hash.NewHasherPeppered(hash.NewHasherArgon2(m), m)
 	})
 }
 
@@ -840,6 +943,8 @@ func (m *RegistryDefault) SettingsFlowPersister() settings.FlowPersister
 func (m *RegistryDefault) SelfServiceErrorPersister() errorx.Persister           { return m.persister }
 func (m *RegistryDefault) SessionPersister() session.Persister                   { return m.persister }
 func (m *RegistryDefault) CourierPersister() courier.Persister                   { return m.persister }
+func (m *RegistryDefault) SCIMPersister() scim.Persister                         { return m.persister }
+func (m *RegistryDefault) OrganizationPersister() organization.Persister         { return m.persister }
 func (m *RegistryDefault) RecoveryTokenPersister() link.RecoveryTokenPersister   { return m.persister }
 func (m *RegistryDefault) RecoveryCodePersister() code.RecoveryCodePersister     { return m.persister }
 func (m *RegistryDefault) LoginCodePersister() code.LoginCodePersister           { return m.persister }
@@ -858,6 +963,18 @@ func (m *RegistryDefault) PendingTraitsChangePersister() identity.PendingTraitsC
 	return m.Persister()
 }
 
+func (m *RegistryDefault) ScheduledDeletionPersister() identity.ScheduledDeletionPersister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) IdentitySchemaPersister() schema.Persister {
+	return m.Persister()
+}
+
+func (m *RegistryDefault) OIDCProviderPersister() oidc.ProviderPersister {
+	return m.Persister()
+}
+
 func (m *RegistryDefault) TransactionalPersisterProvider() transaction.Persister {
 	return m.persister
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/selfservice/strategy/deletion"
 )
 
 func (m *RegistryDefault) PostSettingsPrePersistHooks(ctx context.Context, settingsType string) ([]settings.PostHookPrePersistExecutor, error) {
@@ -33,7 +34,8 @@ func (m *RegistryDefault) PostSettingsPostPersistHooks(ctx context.Context, sett
 		}
 	}
 
-	if m.Config().SelfServiceFlowVerificationEnabled(ctx) {
+	// A deleted account has no addresses left to verify.
+	if m.Config().SelfServiceFlowVerificationEnabled(ctx) && settingsType != deletion.StrategyID {
 		hooks = slices.Insert(hooks, 0, settings.PostHookPostPersistExecutor(m.HookVerifier()))
 	}
 
@@ -896,7 +896,7 @@ func TestDefaultRegistry_AllStrategies(t *testing.T) {
 	})
 
 	t.Run("case=all settings strategies", func(t *testing.T) {
-		expects := []string{"profile", "password", "oidc", "totp", "passkey", "webauthn", "lookup_secret"}
+		expects := []string{"profile", "password", "oidc", "totp", "passkey", "webauthn", "lookup_secret", "deletion"}
 		s := reg.AllSettingsStrategies()
 		require.Len(t, s, len(expects))
 		for k, e := range expects {
@@ -992,8 +992,9 @@ func TestDriverDefault_ExtraHashers(t *testing.T) {
 		},
 	})
 
+	// Every hasher is wrapped to support password peppering.
 	h := reg.Hasher(ctx)
-	require.IsType(t, &hash.Pbkdf2{}, h)
+	require.IsType(t, &hash.Peppered{}, h)
 
 	generated, err := h.Generate(ctx, []byte("password"))
 	require.NoError(t, err)
@@ -0,0 +1,145 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+// Package export bundles all data stored about an identity into a single
+// document, for example to answer data subject access requests.
+package export
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/session"
+	"github.com/ory/x/otelx"
+	keysetpagination "github.com/ory/x/pagination/keysetpagination_v2"
+)
+
+// exportPageSize is the number of sessions and courier messages loaded at
+// once while collecting an export.
+const exportPageSize = 500
+
+type (
+	exporterDependencies interface {
+		identity.PrivilegedPoolProvider
+		session.PersistenceProvider
+		courier.PersistenceProvider
+		otelx.Provider
+	}
+	Exporter struct {
+		r exporterDependencies
+	}
+	ExporterProvider interface {
+		DataExporter() *Exporter
+	}
+)
+
+// Identity Data Export
+//
+// All data stored about an identity.
+//
+// swagger:model identityDataExport
+type Export struct {
+	// ExportedAt is the time the export was created.
+	//
+	// required: true
+	ExportedAt time.Time `json:"exported_at"`
+
+	// Identity contains the identity's traits, metadata, verifiable and
+	// recovery addresses and its credentials without their secrets.
+	//
+	// required: true
+	Identity identity.WithCredentialsNoConfigAndAdminMetadataInJSON `json:"identity"`
+
+	// Sessions contains all sessions of the identity, including their
+	// devices.
+	//
+	// required: true
+	Sessions []session.Session `json:"sessions"`
+
+	// CourierMessages contains all messages sent to any of the identity's
+	// addresses.
+	//
+	// required: true
+	CourierMessages []courier.Message `json:"courier_messages"`
+}
+
+func NewExporter(r exporterDependencies) *Exporter {
+	return &Exporter{r: r}
+}
+
+// Export collects all data stored about the identity. The identity's admin
+// metadata is only included if includeAdminMetadata is true.
+func (e *Exporter) Export(ctx context.Context, identityID uuid.UUID, includeAdminMetadata bool) (_ *Export, err error) {
+	ctx, span := e.r.Tracer(ctx).Tracer().Start(ctx, "export.Exporter.Export")
+	defer otelx.End(span, &err)
+
+	i, err := e.r.PrivilegedIdentityPool().GetIdentity(ctx, identityID, identity.ExpandEverything)
+	if err != nil {
+		return nil, err
+	}
+	if !includeAdminMetadata {
+		i.MetadataAdmin = nil
+	}
+
+	sessions, err := e.sessions(ctx, identityID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := e.messages(ctx, i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Export{
+		ExportedAt:      time.Now().UTC(),
+		Identity:        identity.WithCredentialsNoConfigAndAdminMetadataInJSON(*i),
+		Sessions:        sessions,
+		CourierMessages: messages,
+	}, nil
+}
+
+func (e *Exporter) sessions(ctx context.Context, identityID uuid.UUID) ([]session.Session, error) {
+	sessions := make([]session.Session, 0)
+	for page := 1; ; page++ {
+		batch, total, err := e.r.SessionPersister().ListSessionsByIdentity(ctx, identityID, nil, page, exportPageSize, uuid.Nil, session.Expandables{session.ExpandSessionDevices})
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, batch...)
+		if len(batch) == 0 || int64(len(sessions)) >= total {
+			return sessions, nil
+		}
+	}
+}
+
+func (e *Exporter) messages(ctx context.Context, i *identity.Identity) ([]courier.Message, error) {
+	recipients := make(map[string]struct{})
+	for _, a := range i.VerifiableAddresses {
+		recipients[a.Value] = struct{}{}
+	}
+	for _, a := range i.RecoveryAddresses {
+		recipients[a.Value] = struct{}{}
+	}
+
+	messages := make([]courier.Message, 0)
+	for recipient := range recipients {
+		opts := []keysetpagination.Option{keysetpagination.WithSize(exportPageSize)}
+		for {
+			batch, next, err := e.r.CourierPersister().ListMessages(ctx, courier.ListCourierMessagesParameters{Recipient: recipient}, opts)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, batch...)
+			if next.IsLast() {
+				break
+			}
+			opts = next.ToOptions()
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,191 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package export
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/nosurfx"
+	"github.com/ory/x/httprouterx"
+	"github.com/ory/x/httpx"
+)
+
+const (
+	RouteAdminExport       = "/identities/{id}/export"
+	RouteSelfServiceExport = "/self-service/export"
+)
+
+type (
+	handlerDependencies interface {
+		config.Provider
+		httpx.WriterProvider
+		nosurfx.CSRFProvider
+		session.ManagementProvider
+		ExporterProvider
+	}
+	Handler struct {
+		r handlerDependencies
+	}
+	HandlerProvider interface {
+		DataExportHandler() *Handler
+	}
+)
+
+func NewHandler(r handlerDependencies) *Handler {
+	return &Handler{r: r}
+}
+
+func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
+	h.r.CSRFHandler().IgnorePath(RouteSelfServiceExport)
+	public.GET(RouteSelfServiceExport, h.exportMyData)
+}
+
+func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
+	admin.GET(RouteAdminExport, h.exportIdentity)
+}
+
+// Export Identity Data Parameters
+//
+// swagger:parameters exportIdentityData
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type exportIdentityData struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// Identity Data Export Response
+//
+// swagger:response identityDataExport
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type identityDataExportResponse struct {
+	// in: body
+	Body Export
+}
+
+// swagger:route GET /admin/identities/{id}/export identity exportIdentityData
+//
+// # Export All Data of an Identity
+//
+// Returns a single JSON document with all data stored about the [identity](https://www.ory.com/docs/kratos/concepts/identity-user-model):
+// its traits, public and admin metadata, verifiable and recovery addresses, credentials without their secrets,
+// sessions including their devices, and the courier messages sent to any of its addresses.
+//
+// Use this endpoint to answer data subject access requests.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identityDataExport
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) exportIdentity(w http.ResponseWriter, r *http.Request) {
+	data, err := h.r.DataExporter().Export(r.Context(), x.ParseUUID(r.PathValue("id")), true)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, data)
+}
+
+// Export My Data Parameters
+//
+// swagger:parameters exportMyData
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type exportMyData struct {
+	// Set the Session Token when calling from non-browser clients. A session token has a format of `MP2YWEMeM8MxjkGKpH4dqOQ4Q4DlSPaj`.
+	//
+	// in: header
+	SessionToken string `json:"X-Session-Token"`
+
+	// Set the Cookie Header. This is especially useful when calling this endpoint from a server-side application. In that
+	// scenario you must include the HTTP Cookie Header which originally was included in the request to your server.
+	// An example of a session in the HTTP Cookie Header is: `ory_kratos_session=a19iOVAbdzdgl70Rq1QZmrKmcjDtdsviCTZx7m9a9yHIUS8Wa9T7hvqyGTsLHi6Qifn2WUfpAKx9DWp0SJGleIn9vh2YF4A16id93kXFTgIgmwIOvbVAScyrx7yVl6bPZnCx27ec4WQDtaTewC1CpgudeDV2jQQnSaCP6ny3xa8qLH-QUgYqdQuoA_LF1phxgRCUfIrCLQOkolX5nv3ze_f==`.
+	//
+	// It is ok if more than one cookie are included here as all other cookies will be ignored.
+	//
+	// in: header
+	Cookie string `json:"Cookie"`
+}
+
+// swagger:route GET /self-service/export frontend exportMyData
+//
+// # Export All Data Stored About Me
+//
+// Returns a single JSON document with all data stored about the signed in identity. The identity's admin metadata
+// is not included.
+//
+// This endpoint must be enabled with `selfservice.flows.settings.data_export.enabled` and requires a privileged
+// session, see `selfservice.flows.settings.privileged_session_max_age`. If the session is too old, the endpoint
+// returns a 403 error with the ID `session_refresh_required` and the user has to sign in again.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: identityDataExport
+//	  401: errorGeneric
+//	  403: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+func (h *Handler) exportMyData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !h.r.Config().SelfServiceSettingsDataExportEnabled(ctx) {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrNotFound().WithReason("The self-service data export is disabled.")))
+		return
+	}
+
+	s, err := h.r.SessionManager().FetchFromRequest(ctx, r, session.ExpandDefault, identity.ExpandDefault)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.r.SessionManager().DoesSessionSatisfy(ctx, s, h.r.Config().SelfServiceSettingsRequiredAAL(ctx)); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if !h.r.SessionManager().IsPrivileged(ctx, s) {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrForbidden().WithID(text.ErrIDNeedsPrivilegedSession).
+			WithReason("The login session is too old to export your data. Please re-authenticate.")))
+		return
+	}
+
+	data, err := h.r.DataExporter().Export(ctx, s.IdentityID, false)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, data)
+}
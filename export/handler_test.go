@@ -0,0 +1,131 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package export_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/kratos/text"
+	"github.com/ory/x/configx"
+)
+
+func TestHandler(t *testing.T) {
+	conf, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+		configx.WithValues(map[string]any{
+			config.ViperKeySelfServiceSettingsRequiredAAL:                   "aal1",
+			config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter: "5m",
+		}),
+	)
+	ctx := t.Context()
+	publicTS, adminTS := testhelpers.NewKratosServer(t, reg)
+
+	email := testhelpers.RandomEmail()
+	i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+	i.Traits = identity.Traits(`{"email":"` + email + `"}`)
+	i.MetadataPublic = []byte(`{"plan":"free"}`)
+	i.MetadataAdmin = []byte(`{"note":"internal"}`)
+	i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+		Type:        identity.CredentialsTypePassword,
+		Identifiers: []string{email},
+		Config:      []byte(`{"hashed_password":"$2a$04$secret"}`),
+	})
+	require.NoError(t, reg.IdentityManager().Create(ctx, i))
+
+	for _, recipient := range []string{email, testhelpers.RandomEmail()} {
+		require.NoError(t, reg.CourierPersister().AddMessage(ctx, &courier.Message{
+			Status:       courier.MessageStatusSent,
+			Type:         courier.MessageTypeEmail,
+			Recipient:    recipient,
+			Subject:      "subject",
+			Body:         "body",
+			TemplateType: "stub",
+		}))
+	}
+
+	client := testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, i)
+
+	get := func(t *testing.T, c *http.Client, url string, expectCode int) gjson.Result {
+		t.Helper()
+		res, err := c.Get(url)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.EqualValuesf(t, expectCode, res.StatusCode, "%s", body)
+		return gjson.ParseBytes(body)
+	}
+
+	assertExport := func(t *testing.T, actual gjson.Result) {
+		assert.Equal(t, i.ID.String(), actual.Get("identity.id").String(), actual.Raw)
+		assert.Equal(t, email, actual.Get("identity.traits.email").String(), actual.Raw)
+		assert.JSONEq(t, `{"plan":"free"}`, actual.Get("identity.metadata_public").Raw, actual.Raw)
+		assert.Equal(t, email, actual.Get("identity.verifiable_addresses.0.value").String(), actual.Raw)
+		assert.Equal(t, email, actual.Get("identity.recovery_addresses.0.value").String(), actual.Raw)
+
+		assert.Equal(t, email, actual.Get("identity.credentials.password.identifiers.0").String(), actual.Raw)
+		assert.False(t, actual.Get("identity.credentials.password.config").Exists(), actual.Raw)
+		assert.NotContains(t, actual.Raw, "$2a$04$secret")
+
+		assert.Len(t, actual.Get("sessions").Array(), 1, actual.Raw)
+		assert.True(t, actual.Get("sessions.0.devices").IsArray(), actual.Raw)
+
+		require.Len(t, actual.Get("courier_messages").Array(), 1, actual.Raw)
+		assert.Equal(t, email, actual.Get("courier_messages.0.recipient").String(), actual.Raw)
+	}
+
+	t.Run("endpoint=admin", func(t *testing.T) {
+		t.Run("case=exports all data of the identity", func(t *testing.T) {
+			actual := get(t, adminTS.Client(), adminTS.URL+"/admin/identities/"+i.ID.String()+"/export", http.StatusOK)
+			assertExport(t, actual)
+			assert.JSONEq(t, `{"note":"internal"}`, actual.Get("identity.metadata_admin").Raw, actual.Raw)
+		})
+
+		t.Run("case=returns 404 for unknown identities", func(t *testing.T) {
+			get(t, adminTS.Client(), adminTS.URL+"/admin/identities/"+uuid.Must(uuid.NewV4()).String()+"/export", http.StatusNotFound)
+		})
+	})
+
+	t.Run("endpoint=self-service", func(t *testing.T) {
+		url := publicTS.URL + "/self-service/export"
+
+		t.Run("case=is disabled by default", func(t *testing.T) {
+			get(t, client, url, http.StatusNotFound)
+		})
+
+		conf.MustSet(ctx, config.ViperKeySelfServiceSettingsDataExportEnabled, true)
+
+		t.Run("case=requires a session", func(t *testing.T) {
+			get(t, new(http.Client), url, http.StatusUnauthorized)
+		})
+
+		t.Run("case=exports the data without admin metadata", func(t *testing.T) {
+			actual := get(t, client, url, http.StatusOK)
+			assertExport(t, actual)
+			assert.False(t, actual.Get("identity.metadata_admin").Exists(), actual.Raw)
+		})
+
+		t.Run("case=requires a privileged session", func(t *testing.T) {
+			conf.MustSet(ctx, config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "1ns")
+			t.Cleanup(func() {
+				conf.MustSet(ctx, config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "5m")
+			})
+
+			actual := get(t, client, url, http.StatusForbidden)
+			assert.Equal(t, text.ErrIDNeedsPrivilegedSession, actual.Get("error.id").String(), actual.Raw)
+		})
+	})
+}
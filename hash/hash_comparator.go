@@ -430,6 +430,10 @@ func IsMD5Hash(hash []byte) bool            { return isMD5Hash.Match(hash) }
 func IsHMACHash(hash []byte) bool           { return isHMACHash.Match(hash) }
 
 func IsValidHashFormat(hash []byte) bool {
+	if isValidPepperedHashFormat(hash) {
+		return true
+	}
+
 	for _, h := range supportedHashers {
 		if h.Is(hash) {
 			return true
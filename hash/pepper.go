@@ -0,0 +1,141 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package hash
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/driver/config"
+)
+
+// ErrUnknownPepper is returned when a hash was peppered with a secret which
+// is no longer configured.
+var ErrUnknownPepper = errors.New("the password hash was peppered with an unknown secret")
+
+// pepperPrefix prefixes peppered hashes, followed by the ID of the pepper
+// secret and the hash of the peppered password, for example
+// `$pepper$1a2b3c4d$argon2id$v=19$...`.
+const pepperPrefix = "$pepper$"
+
+var isPepperedHash = regexp.MustCompile(`^\$pepper\$[0-9a-f]{8}\$`)
+
+func IsPepperedHash(hash []byte) bool { return isPepperedHash.Match(hash) }
+
+// PepperID returns the ID of the pepper secret which is stored in peppered
+// hashes. It is derived from the secret and does not reveal it.
+func PepperID(secret []byte) string {
+	h := sha256.Sum256(append([]byte("ory-kratos-pepper-id:"), secret...))
+	return hex.EncodeToString(h[:4])
+}
+
+// splitPepperedHash returns the pepper ID and the inner hash of a peppered
+// hash.
+func splitPepperedHash(hash []byte) (id string, inner []byte) {
+	rest := hash[len(pepperPrefix):]
+	return string(rest[:8]), rest[8:]
+}
+
+// pepper returns the HMAC-SHA256 of the password keyed with the secret. It is
+// base64-encoded so that it never contains NUL bytes and stays within the
+// 72 byte limit of bcrypt.
+func pepper(secret, password []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(password)
+	return []byte(base64.RawStdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+type PepperConfiguration interface {
+	config.Provider
+}
+
+// Peppered wraps a hasher and peppers passwords with the first configured
+// pepper secret before they are hashed. If no pepper secrets are configured,
+// passwords are hashed as is.
+type Peppered struct {
+	h Hasher
+	c PepperConfiguration
+}
+
+var _ Hasher = new(Peppered)
+
+func NewHasherPeppered(h Hasher, c PepperConfiguration) *Peppered {
+	return &Peppered{h: h, c: c}
+}
+
+func (p *Peppered) Generate(ctx context.Context, password []byte) ([]byte, error) {
+	secrets := p.c.Config().SecretsPepper(ctx)
+	if len(secrets) == 0 {
+		return p.h.Generate(ctx, password)
+	}
+
+	// The peppered password always fits into bcrypt, so the length limit
+	// must be enforced on the original password.
+	if _, ok := p.h.(*Bcrypt); ok {
+		if err := validateBcryptPasswordLength(password); err != nil {
+			return nil, err
+		}
+	}
+
+	hash, err := p.h.Generate(ctx, pepper(secrets[0], password))
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(pepperPrefix+PepperID(secrets[0])), hash...), nil
+}
+
+func (p *Peppered) Understands(hash []byte) bool {
+	if IsPepperedHash(hash) {
+		_, inner := splitPepperedHash(hash)
+		return p.h.Understands(inner)
+	}
+	return p.h.Understands(hash)
+}
+
+// NeedsRepeppering returns true if the hash was not peppered with the first
+// configured pepper secret, or is peppered although peppering is disabled.
+func NeedsRepeppering(ctx context.Context, c PepperConfiguration, hash []byte) bool {
+	secrets := c.Config().SecretsPepper(ctx)
+	if !IsPepperedHash(hash) {
+		return len(secrets) > 0
+	}
+	if len(secrets) == 0 {
+		return true
+	}
+	id, _ := splitPepperedHash(hash)
+	return id != PepperID(secrets[0])
+}
+
+// CompareWithPepper works like Compare but also supports peppered hashes,
+// using the configured pepper secret the hash was peppered with.
+func CompareWithPepper(ctx context.Context, c PepperConfiguration, password, hash []byte) error {
+	if !IsPepperedHash(hash) {
+		return Compare(ctx, password, hash)
+	}
+
+	id, inner := splitPepperedHash(hash)
+	for _, secret := range c.Config().SecretsPepper(ctx) {
+		if PepperID(secret) == id {
+			return Compare(ctx, pepper(secret, password), inner)
+		}
+	}
+
+	return errors.WithStack(ErrUnknownPepper)
+}
+
+func isValidPepperedHashFormat(hash []byte) bool {
+	if !IsPepperedHash(hash) {
+		return false
+	}
+	_, inner := splitPepperedHash(hash)
+	return !bytes.HasPrefix(inner, []byte(pepperPrefix)) && IsValidHashFormat(inner)
+}
@@ -0,0 +1,73 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package hash_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/hash"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/x/contextx"
+)
+
+func TestPepperedHasher(t *testing.T) {
+	t.Parallel()
+
+	const (
+		oldPepper = "old-pepper-thirty-two-characters"
+		newPepper = "new-pepper-thirty-two-characters"
+	)
+
+	_, reg := pkg.NewVeryFastRegistryWithoutDB(t)
+	ctx := contextx.WithConfigValue(t.Context(), config.ViperKeySecretsPepper, []string{oldPepper})
+	rotated := contextx.WithConfigValue(t.Context(), config.ViperKeySecretsPepper, []string{newPepper, oldPepper})
+	unpeppered := contextx.WithConfigValue(t.Context(), config.ViperKeySecretsPepper, []string{})
+
+	for _, inner := range []hash.Hasher{hash.NewHasherArgon2(reg), hash.NewHasherBcrypt(reg)} {
+		h := hash.NewHasherPeppered(inner, reg)
+		password := []byte("my super secret password")
+
+		hashed, err := h.Generate(ctx, password)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(hashed), "$pepper$"+hash.PepperID([]byte(oldPepper))+"$"), "%s", hashed)
+		assert.True(t, h.Understands(hashed))
+		assert.True(t, hash.IsValidHashFormat(hashed))
+		assert.False(t, hash.NeedsRepeppering(ctx, reg, hashed))
+
+		require.NoError(t, hash.CompareWithPepper(ctx, reg, password, hashed))
+		require.Error(t, hash.CompareWithPepper(ctx, reg, []byte("wrong password"), hashed))
+		require.ErrorIs(t, hash.Compare(ctx, password, hashed), hash.ErrUnknownHashAlgorithm, "peppered hashes must not verify without the pepper")
+
+		t.Run("case=old pepper versions are verified and need repeppering", func(t *testing.T) {
+			require.NoError(t, hash.CompareWithPepper(rotated, reg, password, hashed))
+			assert.True(t, hash.NeedsRepeppering(rotated, reg, hashed))
+
+			rehashed, err := h.Generate(rotated, password)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(string(rehashed), "$pepper$"+hash.PepperID([]byte(newPepper))+"$"), "%s", rehashed)
+			assert.False(t, hash.NeedsRepeppering(rotated, reg, rehashed))
+			require.ErrorIs(t, hash.CompareWithPepper(ctx, reg, password, rehashed), hash.ErrUnknownPepper)
+		})
+
+		t.Run("case=unpeppered hashes are verified and need peppering", func(t *testing.T) {
+			plain, err := h.Generate(unpeppered, password)
+			require.NoError(t, err)
+			assert.False(t, hash.IsPepperedHash(plain))
+			assert.False(t, hash.NeedsRepeppering(unpeppered, reg, plain))
+
+			require.NoError(t, hash.CompareWithPepper(ctx, reg, password, plain))
+			assert.True(t, hash.NeedsRepeppering(ctx, reg, plain))
+		})
+	}
+
+	t.Run("case=bcrypt password length is enforced", func(t *testing.T) {
+		_, err := hash.NewHasherPeppered(hash.NewHasherBcrypt(reg), reg).Generate(ctx, mkpw(t, 73))
+		require.Error(t, err)
+	})
+}
@@ -4,6 +4,8 @@
 package identity
 
 const (
-	AddressTypeEmail = "email"
-	AddressTypeSMS   = "sms"
+	AddressTypeEmail    = "email"
+	AddressTypeSMS      = "sms"
+	AddressTypePush     = "push"
+	AddressTypeWhatsApp = "whatsapp"
 )
@@ -0,0 +1,185 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/kratos/cipher"
+	"github.com/ory/x/logrusx"
+	"github.com/ory/x/otelx"
+)
+
+type (
+	cipherRotationDependencies interface {
+		CipherRotationTargetsProvider
+		cipher.Provider
+		logrusx.Provider
+		otelx.Provider
+	}
+
+	// CipherRotationTarget is a kind of persisted ciphertexts, for example
+	// the OpenID Connect tokens of identities, which can be re-encrypted with
+	// the primary cipher secret.
+	CipherRotationTarget interface {
+		// RotateCiphertexts re-encrypts the target's ciphertexts in batches
+		// of batchSize, adds its progress to result, and calls progress, if
+		// set, after every batch.
+		RotateCiphertexts(ctx context.Context, batchSize int, result *CipherRotationProgress, progress func(CipherRotationProgress)) error
+	}
+
+	// CipherRotationTargetsProvider returns every kind of persisted
+	// ciphertexts. A cipher key rotation run processes all of them.
+	CipherRotationTargetsProvider interface {
+		CipherRotationTargets() []CipherRotationTarget
+	}
+
+	oidcCredentialsCipherRotation struct {
+		d oidcCredentialsCipherRotationDependencies
+	}
+
+	oidcCredentialsCipherRotationDependencies interface {
+		PrivilegedPoolProvider
+		cipher.Provider
+		logrusx.Provider
+	}
+
+	// CipherRotationProgress reports how many credentials a cipher key
+	// rotation run has processed so far.
+	CipherRotationProgress struct {
+		// Scanned is the number of credentials which were checked.
+		Scanned int `json:"scanned"`
+
+		// Rotated is the number of credentials which were re-encrypted with
+		// the primary cipher secret.
+		Rotated int `json:"rotated"`
+
+		// Failed is the number of credentials which could not be
+		// re-encrypted, for example because the secret they were encrypted
+		// with is no longer configured.
+		Failed int `json:"failed"`
+	}
+)
+
+// RotateCipherKeys re-encrypts all persisted ciphertexts which were not
+// encrypted with the primary cipher secret. Ciphertexts are processed in
+// batches of batchSize; progress, if set, is called after every batch.
+//
+// Ciphertexts which can not be re-encrypted are logged and skipped so that a
+// single broken row does not block the rotation of all others.
+func RotateCipherKeys(ctx context.Context, d cipherRotationDependencies, batchSize int, progress func(CipherRotationProgress)) (_ *CipherRotationProgress, err error) {
+	ctx, span := d.Tracer(ctx).Tracer().Start(ctx, "identity.RotateCipherKeys")
+	defer otelx.End(span, &err)
+
+	var result CipherRotationProgress
+	if _, ok := d.Cipher(ctx).(cipher.Rotator); !ok {
+		// Ciphertexts of this cipher do not carry a key ID and can not be
+		// rotated.
+		return &result, nil
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	for _, target := range d.CipherRotationTargets() {
+		if err := target.RotateCiphertexts(ctx, batchSize, &result, progress); err != nil {
+			return &result, err
+		}
+	}
+	return &result, nil
+}
+
+// NewOIDCCredentialsCipherRotation returns the rotation target for the
+// OpenID Connect tokens stored in the identities' credentials.
+func NewOIDCCredentialsCipherRotation(d oidcCredentialsCipherRotationDependencies) CipherRotationTarget {
+	return &oidcCredentialsCipherRotation{d: d}
+}
+
+func (r *oidcCredentialsCipherRotation) RotateCiphertexts(ctx context.Context, batchSize int, result *CipherRotationProgress, progress func(CipherRotationProgress)) error {
+	after := uuid.Nil
+	for {
+		ids, err := r.d.PrivilegedIdentityPool().ListIdentityIDsByCredentialsType(ctx, CredentialsTypeOIDC, after, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			rotated, err := r.rotate(ctx, id)
+			result.Scanned++
+			switch {
+			case err != nil:
+				result.Failed++
+				r.d.Logger().
+					WithError(err).
+					WithField("identity_id", id).
+					Warn("Unable to re-encrypt the identity's OpenID Connect tokens with the primary cipher secret.")
+			case rotated:
+				result.Rotated++
+			}
+		}
+
+		if progress != nil {
+			progress(*result)
+		}
+		if len(ids) < batchSize {
+			return nil
+		}
+		after = ids[len(ids)-1]
+	}
+}
+
+func (r *oidcCredentialsCipherRotation) rotate(ctx context.Context, identityID uuid.UUID) (rotated bool, err error) {
+	c := r.d.Cipher(ctx)
+	err = r.d.PrivilegedIdentityPool().UpdateCredentialsConfig(ctx, identityID, CredentialsTypeOIDC, UpdateConfig(func(cfg *CredentialsOIDC) error {
+		// mutate may run more than once.
+		rotated = false
+		for i := range cfg.Providers {
+			for _, token := range []*string{
+				&cfg.Providers[i].InitialIDToken,
+				&cfg.Providers[i].InitialAccessToken,
+				&cfg.Providers[i].InitialRefreshToken,
+			} {
+				ciphertext, ok, err := cipher.Reencrypt(ctx, c, *token)
+				if err != nil {
+					return err
+				}
+				if ok {
+					*token = ciphertext
+					rotated = true
+				}
+			}
+		}
+		return nil
+	}))
+	return rotated, err
+}
+
+// WatchCipherRotation runs RotateCipherKeys every interval until the context
+// is canceled.
+func WatchCipherRotation(ctx context.Context, d cipherRotationDependencies, interval time.Duration, batchSize int) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := RotateCipherKeys(ctx, d, batchSize, nil)
+		if err != nil {
+			d.Logger().WithError(err).Error("Unable to rotate the cipher keys.")
+		} else if result.Rotated > 0 || result.Failed > 0 {
+			d.Logger().
+				WithField("scanned", result.Scanned).
+				WithField("rotated", result.Rotated).
+				WithField("failed", result.Failed).
+				Info("Re-encrypted ciphertexts with the primary cipher secret.")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/cipher"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/x/configx"
+)
+
+func TestRotateCipherKeys(t *testing.T) {
+	const (
+		oldSecret     = "old-secret-thirty-two-characters"
+		newSecret     = "new-secret-thirty-two-characters"
+		removedSecret = "removed-secret-thirty-two-chars!"
+	)
+
+	conf, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+		config.ViperKeyCipherAlgorithm: "xchacha20-poly1305",
+		config.ViperKeySecretsCipher:   []string{oldSecret},
+	}))
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/identity.schema.json")
+	ctx := t.Context()
+	c := reg.Cipher(ctx)
+
+	createIdentity := func(t *testing.T) *identity.Identity {
+		encrypt := func(token string) string {
+			ciphertext, err := c.Encrypt(ctx, []byte(token))
+			require.NoError(t, err)
+			return ciphertext
+		}
+		creds, err := identity.NewCredentialsOIDC(&identity.CredentialsOIDCEncryptedTokens{
+			IDToken:      encrypt("id-token"),
+			AccessToken:  encrypt("access-token"),
+			RefreshToken: encrypt("refresh-token"),
+		}, "google", uuid.Must(uuid.NewV4()).String(), "")
+		require.NoError(t, err)
+
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.SetCredentials(identity.CredentialsTypeOIDC, *creds)
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		return i
+	}
+
+	tokens := func(t *testing.T, id uuid.UUID) identity.CredentialsOIDCProvider {
+		i, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, id)
+		require.NoError(t, err)
+		var cfg identity.CredentialsOIDC
+		_, err = i.ParseCredentials(identity.CredentialsTypeOIDC, &cfg)
+		require.NoError(t, err)
+		require.Len(t, cfg.Providers, 1)
+		return cfg.Providers[0]
+	}
+
+	rotated := []*identity.Identity{createIdentity(t), createIdentity(t), createIdentity(t)}
+
+	conf.MustSet(ctx, config.ViperKeySecretsCipher, []string{removedSecret})
+	broken := createIdentity(t)
+
+	conf.MustSet(ctx, config.ViperKeySecretsCipher, []string{newSecret, oldSecret})
+
+	var batches []identity.CipherRotationProgress
+	result, err := identity.RotateCipherKeys(ctx, reg, 2, func(p identity.CipherRotationProgress) {
+		batches = append(batches, p)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, identity.CipherRotationProgress{Scanned: 4, Rotated: 3, Failed: 1}, *result)
+	// Two batches of identities, an empty one, and one for the providers
+	// managed through the admin API.
+	assert.Len(t, batches, 4, "%+v", batches)
+
+	newKeyID := cipher.KeyID(config.ToCipherSecrets([]string{newSecret})[0])
+	for k, i := range rotated {
+		t.Run(fmt.Sprintf("identity=%d", k), func(t *testing.T) {
+			actual := tokens(t, i.ID)
+			for expected, ciphertext := range map[string]string{
+				"id-token":      actual.InitialIDToken,
+				"access-token":  actual.InitialAccessToken,
+				"refresh-token": actual.InitialRefreshToken,
+			} {
+				assert.True(t, strings.HasPrefix(ciphertext, newKeyID+"."), ciphertext)
+				plaintext, err := c.Decrypt(ctx, ciphertext)
+				require.NoError(t, err)
+				assert.Equal(t, expected, string(plaintext))
+			}
+		})
+	}
+
+	t.Run("case=undecryptable tokens are left untouched", func(t *testing.T) {
+		before := tokens(t, broken.ID)
+		assert.False(t, strings.HasPrefix(before.InitialIDToken, newKeyID+"."))
+	})
+
+	t.Run("case=second run has nothing to rotate", func(t *testing.T) {
+		result, err := identity.RotateCipherKeys(ctx, reg, 100, nil)
+		require.NoError(t, err)
+		assert.Equal(t, identity.CipherRotationProgress{Scanned: 4, Rotated: 0, Failed: 1}, *result)
+	})
+}
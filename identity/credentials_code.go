@@ -39,8 +39,10 @@ func (c *CodeChannel) Scan(src interface{}) error {
 }
 
 const (
-	CodeChannelEmail CodeChannel = AddressTypeEmail
-	CodeChannelSMS   CodeChannel = AddressTypeSMS
+	CodeChannelEmail    CodeChannel = AddressTypeEmail
+	CodeChannelSMS      CodeChannel = AddressTypeSMS
+	CodeChannelPush     CodeChannel = AddressTypePush
+	CodeChannelWhatsApp CodeChannel = AddressTypeWhatsApp
 )
 
 func NewCodeChannel(value string) (CodeChannel, error) {
@@ -49,6 +51,10 @@ func NewCodeChannel(value string) (CodeChannel, error) {
 		return CodeChannelEmail, nil
 	case f.AddCase(string(CodeChannelSMS)):
 		return CodeChannelSMS, nil
+	case f.AddCase(string(CodeChannelPush)):
+		return CodeChannelPush, nil
+	case f.AddCase(string(CodeChannelWhatsApp)):
+		return CodeChannelWhatsApp, nil
 	default:
 		return "", errors.Wrap(ErrInvalidCodeAddressType(), f.ToUnknownCaseErr().Error())
 	}
@@ -89,3 +95,35 @@ func (c *CredentialsCodeAddress) UnmarshalJSON(data []byte) (err error) {
 	*c = CredentialsCodeAddress(ac)
 	return nil
 }
+
+// PushAddresses returns the push addresses the identity has registered for
+// receiving codes via push notifications.
+func (i *Identity) PushAddresses() []AddressRef {
+	return i.codeAddresses(CodeChannelPush)
+}
+
+// WhatsAppAddresses returns the phone numbers the identity has registered for
+// receiving codes via WhatsApp.
+func (i *Identity) WhatsAppAddresses() []AddressRef {
+	return i.codeAddresses(CodeChannelWhatsApp)
+}
+
+func (i *Identity) codeAddresses(channel CodeChannel) []AddressRef {
+	cred, ok := i.GetCredentials(CredentialsTypeCodeAuth)
+	if !ok {
+		return nil
+	}
+
+	var conf CredentialsCode
+	if err := json.Unmarshal(cred.Config, &conf); err != nil {
+		return nil
+	}
+
+	var out []AddressRef
+	for _, a := range conf.Addresses {
+		if a.Channel == channel {
+			out = append(out, AddressRef{Value: a.Address, Via: string(channel)})
+		}
+	}
+	return out
+}
@@ -88,6 +88,18 @@ func TestNewCodeAddressType(t *testing.T) {
 			want:    CodeChannelSMS,
 			wantErr: false,
 		},
+		{
+			name:    "valid push address type",
+			input:   "push",
+			want:    CodeChannelPush,
+			wantErr: false,
+		},
+		{
+			name:    "valid WhatsApp address type",
+			input:   "whatsapp",
+			want:    CodeChannelWhatsApp,
+			wantErr: false,
+		},
 		{
 			name:    "invalid address type",
 			input:   "invalid",
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -31,6 +32,9 @@ type CredentialsOIDCProvider struct {
 	InitialRefreshToken string `json:"initial_refresh_token"`
 	Organization        string `json:"organization,omitempty"`
 	UseAutoLink         bool   `json:"use_auto_link,omitzero"`
+
+	// AccessTokenExpiresAt is the expiry of the stored access token, if known.
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at,omitzero"`
 }
 
 // swagger:ignore
@@ -38,6 +42,9 @@ type CredentialsOIDCEncryptedTokens struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 	IDToken      string `json:"id_token,omitempty"`
 	AccessToken  string `json:"access_token,omitempty"`
+
+	// ExpiresAt is the expiry of the access token, if known.
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
 }
 
 func (c *CredentialsOIDCEncryptedTokens) GetRefreshToken() string {
@@ -54,6 +61,13 @@ func (c *CredentialsOIDCEncryptedTokens) GetAccessToken() string {
 	return c.AccessToken
 }
 
+func (c *CredentialsOIDCEncryptedTokens) GetExpiresAt() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	return c.ExpiresAt
+}
+
 func (c *CredentialsOIDCEncryptedTokens) GetIDToken() string {
 	if c == nil {
 		return ""
@@ -86,6 +100,8 @@ func NewOIDCLikeCredentials(tokens *CredentialsOIDCEncryptedTokens, t Credential
 				InitialAccessToken:  tokens.GetAccessToken(),
 				InitialRefreshToken: tokens.GetRefreshToken(),
 				Organization:        organization,
+
+				AccessTokenExpiresAt: tokens.GetExpiresAt(),
 			},
 		},
 	}); err != nil {
@@ -105,6 +121,7 @@ func (c *CredentialsOIDCProvider) GetTokens() *CredentialsOIDCEncryptedTokens {
 		RefreshToken: c.InitialRefreshToken,
 		IDToken:      c.InitialIDToken,
 		AccessToken:  c.InitialAccessToken,
+		ExpiresAt:    c.AccessTokenExpiresAt,
 	}
 }
 
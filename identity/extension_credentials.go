@@ -71,6 +71,8 @@ func (r *SchemaExtensionCredentials) Run(ctx jsonschema.ValidationContext, s sch
 			return ctx.Error("ory.sh~/kratos/credentials/code/via", "channel type %q must be one of %s", s.Credentials.Code.Via, strings.Join([]string{
 				string(CodeChannelEmail),
 				string(CodeChannelSMS),
+				string(CodeChannelPush),
+				string(CodeChannelWhatsApp),
 			}, ", "))
 		}
 
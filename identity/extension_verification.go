@@ -34,8 +34,10 @@ func NewSchemaExtensionVerification(i *Identity, lifespan time.Duration) *Schema
 }
 
 const (
-	ChannelTypeEmail = "email"
-	ChannelTypeSMS   = "sms"
+	ChannelTypeEmail    = "email"
+	ChannelTypeSMS      = "sms"
+	ChannelTypePush     = "push"
+	ChannelTypeWhatsApp = "whatsapp"
 )
 
 func (r *SchemaExtensionVerification) Run(ctx jsonschema.ValidationContext, s schema.ExtensionConfig, value interface{}) error {
@@ -0,0 +1,161 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+)
+
+type (
+	// FilterField is a field of an identity which can be filtered by.
+	FilterField string
+
+	// FilterOperator compares a field of an identity with a filter value.
+	FilterOperator string
+
+	// Filter restricts a list of identities to the ones whose field matches
+	// the filter's value.
+	Filter struct {
+		Field FilterField
+
+		// Path is the path to the value inside of traits or metadata.
+		Path []string
+
+		Operator FilterOperator
+		Value    string
+	}
+)
+
+const (
+	FilterFieldState          FilterField = "state"
+	FilterFieldSchemaID       FilterField = "schema_id"
+	FilterFieldCreatedAt      FilterField = "created_at"
+	FilterFieldTraits         FilterField = "traits"
+	FilterFieldMetadataPublic FilterField = "metadata_public"
+	FilterFieldMetadataAdmin  FilterField = "metadata_admin"
+
+	FilterOperatorEqual              FilterOperator = "="
+	FilterOperatorPrefix             FilterOperator = "^="
+	FilterOperatorGreaterThan        FilterOperator = ">"
+	FilterOperatorGreaterThanOrEqual FilterOperator = ">="
+	FilterOperatorLessThan           FilterOperator = "<"
+	FilterOperatorLessThanOrEqual    FilterOperator = "<="
+
+	// FiltersLimit is the maximum number of filters per request.
+	FiltersLimit = 10
+)
+
+// filterOperators is ordered so that two-character operators are matched
+// before their one-character prefixes.
+var filterOperators = []FilterOperator{
+	FilterOperatorPrefix,
+	FilterOperatorGreaterThanOrEqual,
+	FilterOperatorLessThanOrEqual,
+	FilterOperatorEqual,
+	FilterOperatorGreaterThan,
+	FilterOperatorLessThan,
+}
+
+var filterPathSegment = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ParseFilter parses a filter expression of the form
+// `<field><operator><value>`, for example `traits.email^=ada@` or
+// `metadata_admin.customer_id=42`.
+//
+// Traits and metadata can be filtered by equality, prefix, and range using a
+// dot-separated path. The state and schema ID can be filtered by equality,
+// and the creation date by range using an RFC 3339 timestamp.
+func ParseFilter(expression string) (*Filter, error) {
+	field, op, value, ok := cutFilterOperator(expression)
+	if !ok {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Filter `%s` must be of the form `<field><operator><value>`.", expression))
+	}
+
+	segments := strings.Split(field, ".")
+	f := &Filter{Field: FilterField(segments[0]), Path: segments[1:], Operator: op, Value: value}
+	switch f.Field {
+	case FilterFieldState:
+		if err := State(value).IsValid(); err != nil && State(value) != StatePendingDeletion {
+			return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Filter `%s` must compare with a valid identity state.", expression))
+		}
+		fallthrough
+	case FilterFieldSchemaID:
+		if len(f.Path) > 0 || op != FilterOperatorEqual {
+			return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Filter `%s` must use the operator `=`.", expression))
+		}
+	case FilterFieldCreatedAt:
+		if len(f.Path) > 0 || op == FilterOperatorEqual || op == FilterOperatorPrefix {
+			return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Filter `%s` must use one of the operators `>`, `>=`, `<`, or `<=`.", expression))
+		}
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Filter `%s` must compare with an RFC 3339 timestamp.", expression))
+		}
+	case FilterFieldTraits, FilterFieldMetadataPublic, FilterFieldMetadataAdmin:
+		if len(f.Path) == 0 {
+			return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Filter `%s` must specify a path, for example `%s.email`.", expression, f.Field))
+		}
+		for _, segment := range f.Path {
+			if !filterPathSegment.MatchString(segment) {
+				return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Filter `%s` has an invalid path. Path segments may only contain letters, digits, `_`, and `-`.", expression))
+			}
+		}
+	default:
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Filter `%s` uses unknown field `%s`.", expression, f.Field))
+	}
+
+	return f, nil
+}
+
+func cutFilterOperator(expression string) (field string, op FilterOperator, value string, ok bool) {
+	at := -1
+	for _, candidate := range filterOperators {
+		if i := strings.Index(expression, string(candidate)); i > 0 && (at == -1 || i < at) {
+			at, op = i, candidate
+		}
+	}
+	if at == -1 {
+		return "", "", "", false
+	}
+	return expression[:at], op, expression[at+len(op):], true
+}
+
+// IsJSONPath returns true if the filter compares a value inside of traits or
+// metadata.
+func (f *Filter) IsJSONPath() bool {
+	return len(f.Path) > 0
+}
+
+// Number returns the filter value as a number, if it is a valid JSON number.
+func (f *Filter) Number() (float64, bool) {
+	var n float64
+	if err := json.Unmarshal([]byte(f.Value), &n); err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// JSONValues returns the JSON values a trait or metadata value must equal to
+// match the filter. The value is matched as a string and, if it is a valid
+// JSON number or boolean, also as that type.
+func (f *Filter) JSONValues() []json.RawMessage {
+	values := make([]json.RawMessage, 0, 2)
+	s, _ := json.Marshal(f.Value)
+	values = append(values, s)
+
+	var v any
+	if err := json.Unmarshal([]byte(f.Value), &v); err == nil {
+		switch v.(type) {
+		case float64, bool:
+			values = append(values, json.RawMessage(f.Value))
+		}
+	}
+	return values
+}
@@ -0,0 +1,92 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/identity"
+)
+
+func TestParseFilter(t *testing.T) {
+	for _, tc := range []struct {
+		expression string
+		expected   identity.Filter
+	}{
+		{
+			expression: "traits.phone=+4917612345678",
+			expected:   identity.Filter{Field: identity.FilterFieldTraits, Path: []string{"phone"}, Operator: identity.FilterOperatorEqual, Value: "+4917612345678"},
+		},
+		{
+			expression: "metadata_admin.customer.id=a=b",
+			expected:   identity.Filter{Field: identity.FilterFieldMetadataAdmin, Path: []string{"customer", "id"}, Operator: identity.FilterOperatorEqual, Value: "a=b"},
+		},
+		{
+			expression: "traits.email^=ada@",
+			expected:   identity.Filter{Field: identity.FilterFieldTraits, Path: []string{"email"}, Operator: identity.FilterOperatorPrefix, Value: "ada@"},
+		},
+		{
+			expression: "metadata_public.score>=10",
+			expected:   identity.Filter{Field: identity.FilterFieldMetadataPublic, Path: []string{"score"}, Operator: identity.FilterOperatorGreaterThanOrEqual, Value: "10"},
+		},
+		{
+			expression: "metadata_public.score<10",
+			expected:   identity.Filter{Field: identity.FilterFieldMetadataPublic, Path: []string{"score"}, Operator: identity.FilterOperatorLessThan, Value: "10"},
+		},
+		{
+			expression: "state=inactive",
+			expected:   identity.Filter{Field: identity.FilterFieldState, Path: []string{}, Operator: identity.FilterOperatorEqual, Value: "inactive"},
+		},
+		{
+			expression: "created_at>2026-01-01T00:00:00Z",
+			expected:   identity.Filter{Field: identity.FilterFieldCreatedAt, Path: []string{}, Operator: identity.FilterOperatorGreaterThan, Value: "2026-01-01T00:00:00Z"},
+		},
+	} {
+		t.Run("expression="+tc.expression, func(t *testing.T) {
+			actual, err := identity.ParseFilter(tc.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, *actual)
+		})
+	}
+
+	for _, expression := range []string{
+		"traits.email",
+		"=foo",
+		"traits=foo",
+		"traits.em'ail=foo",
+		"traits..email=foo",
+		"credentials.password=foo",
+		"state=deleted",
+		"state^=act",
+		"schema_id>default",
+		"created_at=2026-01-01T00:00:00Z",
+		"created_at>yesterday",
+	} {
+		t.Run("invalid="+expression, func(t *testing.T) {
+			_, err := identity.ParseFilter(expression)
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("case=json values", func(t *testing.T) {
+		for value, expected := range map[string][]string{
+			"42":    {`"42"`, `42`},
+			"true":  {`"true"`, `true`},
+			"ada":   {`"ada"`},
+			`"ada"`: {`"\"ada\""`},
+		} {
+			f := identity.Filter{Value: value}
+			actual := make([]string, 0)
+			for _, v := range f.JSONValues() {
+				actual = append(actual, string(v))
+				assert.True(t, json.Valid(v))
+			}
+			assert.Equal(t, expected, actual, value)
+		}
+	})
+}
@@ -9,11 +9,13 @@ import (
 	"io"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ory/kratos/x/nosurfx"
 	"github.com/ory/kratos/x/redir"
+	"github.com/ory/kratos/x/transaction"
 	"github.com/ory/x/httprouterx"
 	"github.com/ory/x/httpx"
 
@@ -27,6 +29,7 @@ import (
 	"github.com/ory/x/sqlcon"
 
 	"github.com/ory/kratos/hash"
+	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/strategy/deviceauthn"
 	"github.com/ory/kratos/x"
 
@@ -37,8 +40,11 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/ory/x/decoderx"
+	"github.com/ory/x/jsonnetsecure"
 	"github.com/ory/x/jsonx"
+	"github.com/ory/x/logrusx"
 	"github.com/ory/x/openapix"
+	"github.com/ory/x/otelx"
 	"github.com/ory/x/region"
 	"github.com/ory/x/sqlxx"
 	"github.com/ory/x/urlx"
@@ -59,12 +65,19 @@ type (
 	dependencies interface {
 		PoolProvider
 		PrivilegedPoolProvider
+		ScheduledDeletionPersistenceProvider
 		ManagementProvider
 		httpx.WriterProvider
 		config.Provider
+		schema.IdentitySchemaProvider
 		nosurfx.CSRFProvider
 		cipher.Provider
 		hash.HashProvider
+		httpx.ClientProvider
+		jsonnetsecure.VMProvider
+		logrusx.Provider
+		otelx.Provider
+		transaction.PersistenceProvider
 	}
 	HandlerProvider interface {
 		IdentityHandler() *Handler
@@ -108,6 +121,7 @@ func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
 	admin.GET(RouteItem, h.get)
 	admin.GET(RouteCollection+"/by/external/{externalID}", h.getByExternalID)
 	admin.DELETE(RouteItem, h.delete)
+	admin.POST(RouteRestore, h.restore)
 	admin.PATCH(RouteItem, h.patch)
 
 	admin.POST(RouteCollection, h.create)
@@ -115,6 +129,9 @@ func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
 	admin.PUT(RouteItem, h.update)
 
 	admin.DELETE(RouteCredentialItem, h.deleteIdentityCredentials)
+
+	admin.POST(RouteSchemaMigration, h.runSchemaMigration)
+	admin.POST(RouteMerge, h.merge)
 }
 
 // Paginated Identity List Response
@@ -181,6 +198,23 @@ type _ struct {
 	// in: query
 	OrganizationID string `json:"organization_id"`
 
+	// Filter identities by their traits, metadata, state, schema, or creation
+	// date. Each filter has the form `<field><operator><value>`; multiple
+	// filters must all match.
+	//
+	// - `traits.<path>`, `metadata_public.<path>`, and `metadata_admin.<path>` support the operators
+	//   `=` (equality), `^=` (prefix), and `>`, `>=`, `<`, `<=` (range), for example `traits.phone=+4917612345678`
+	//   or `metadata_admin.customer_id=42`. Path segments are separated by dots.
+	// - `state` and `schema_id` support `=`, for example `state=inactive`.
+	// - `created_at` supports `>`, `>=`, `<`, and `<=` with an RFC 3339 timestamp, for example `created_at>=2026-01-01T00:00:00Z`.
+	//
+	// On MySQL, filters on traits and metadata can not use an index unless the path is indexed with a generated
+	// column, and scan all identities otherwise.
+	//
+	// required: false
+	// in: query
+	Filter []string `json:"filter"`
+
 	crdbx.ConsistencyRequestParameters
 }
 
@@ -212,6 +246,20 @@ func parseListIdentitiesParameters(r *http.Request) (params ListIdentityParamete
 		}
 	}
 
+	if filters := query["filter"]; len(filters) > 0 {
+		requestedFilters++
+		if len(filters) > FiltersLimit {
+			return params, errors.WithStack(herodot.ErrBadRequest().WithReasonf("The number of filters must not exceed %d.", FiltersLimit))
+		}
+		for _, v := range filters {
+			f, err := ParseFilter(v)
+			if err != nil {
+				return params, err
+			}
+			params.Filters = append(params.Filters, *f)
+		}
+	}
+
 	if identifier := query.Get("credentials_identifier"); identifier != "" {
 		requestedFilters++
 		params.Expand = ExpandEverything
@@ -281,12 +329,15 @@ func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
 
 	if params.PagePagination != nil {
 		total := int64(len(is))
-		if params.CredentialsIdentifier == "" {
+		switch {
+		case len(params.Filters) > 0:
+			total, err = h.r.IdentityPool().CountFilteredIdentities(r.Context(), params.Filters)
+		case params.CredentialsIdentifier == "":
 			total, err = h.r.IdentityPool().CountIdentities(r.Context())
-			if err != nil {
-				h.r.Writer().WriteError(w, r, err)
-				return
-			}
+		}
+		if err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
 		}
 		u := *r.URL
 		pagepagination.PaginationHeader(w, &u, total, params.PagePagination.Page, params.PagePagination.ItemsPerPage)
@@ -1079,11 +1130,20 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if ur.SchemaID != "" {
+	if ur.SchemaID != "" && ur.SchemaID != identity.SchemaID {
+		// The identity is pinned to the current version of the new schema.
 		identity.SchemaID = ur.SchemaID
+		if err := h.r.IdentityManager().PinCurrentSchemaVersion(r.Context(), identity); err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
 	}
 
 	if ur.State != "" && identity.State != ur.State {
+		if identity.State == StatePendingDeletion {
+			h.r.Writer().WriteError(w, r, errors.WithStack(errPendingDeletionStateChange()))
+			return
+		}
 		if err := ur.State.IsValid(); err != nil {
 			h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReasonf("%s", err).WithWrap(err)))
 			return
@@ -1142,6 +1202,14 @@ type deleteIdentity struct {
 	// required: true
 	// in: path
 	ID string `json:"id"`
+
+	// Purge the identity right away, even if `identity.deletion.grace_period` is set or the
+	// identity is already pending deletion. The courier messages sent to the identity are
+	// deleted as well.
+	//
+	// required: false
+	// in: query
+	Purge bool `json:"purge"`
 }
 
 // swagger:route DELETE /admin/identities/{id} identity deleteIdentity
@@ -1151,6 +1219,11 @@ type deleteIdentity struct {
 // Calling this endpoint irrecoverably and permanently deletes the [identity](https://www.ory.com/docs/kratos/concepts/identity-user-model) given its ID. This action can not be undone.
 // This endpoint returns 204 when the identity was deleted or 404 if the identity was not found.
 //
+// If `identity.deletion.grace_period` is set, the identity is instead marked as `pending_deletion`, can no longer
+// sign in, and has its sessions revoked. It is purged by `kratos cleanup sql` once the grace period has passed and
+// can be restored until then. Set `purge=true` to delete the identity right away instead, for example to purge an
+// identity which is already pending deletion.
+//
 //	Produces:
 //	- application/json
 //
@@ -1167,7 +1240,39 @@ type deleteIdentity struct {
 //	Extensions:
 //	  x-ory-ratelimit-bucket: kratos-admin-low
 func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
-	if err := h.r.PrivilegedIdentityPool().DeleteIdentity(r.Context(), x.ParseUUID(r.PathValue("id"))); err != nil {
+	ctx := r.Context()
+	id := x.ParseUUID(r.PathValue("id"))
+
+	var purge bool
+	if v := r.URL.Query().Get("purge"); v != "" {
+		var err error
+		if purge, err = strconv.ParseBool(v); err != nil {
+			h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Invalid value `%s` for parameter `purge`.", v)))
+			return
+		}
+	}
+
+	if purge {
+		if err := h.r.ScheduledDeletionPersister().PurgeIdentity(ctx, id); err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if grace := h.r.Config().IdentityDeletionGracePeriod(ctx); grace > 0 {
+		if err := h.r.ScheduledDeletionPersister().SoftDeleteIdentity(ctx, id, time.Now().Add(grace)); err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := h.r.PrivilegedIdentityPool().DeleteIdentity(ctx, id); err != nil {
 		h.r.Writer().WriteError(w, r, err)
 		return
 	}
@@ -1320,7 +1425,21 @@ func (h *Handler) patch(w http.ResponseWriter, r *http.Request) {
 	// ApplyJSONPatch can't see omitempty-stripped fields; carry forward.
 	patchedIdentity.Region = ident.Region
 
+	// Changing the schema pins the identity to the current version of the
+	// new schema, unless the patch also sets the version.
+	if patchedIdentity.SchemaID != ident.SchemaID && patchedIdentity.SchemaVersion == ident.SchemaVersion {
+		if err := h.r.IdentityManager().PinCurrentSchemaVersion(r.Context(), (*Identity)(&patchedIdentity)); err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+	}
+
 	if oldState != patchedIdentity.State {
+		if oldState == StatePendingDeletion {
+			h.r.Writer().WriteError(w, r, errors.WithStack(errPendingDeletionStateChange()))
+			return
+		}
+
 		// Check if the changed state was actually valid
 		if err := patchedIdentity.State.IsValid(); err != nil {
 			h.r.Writer().WriteError(w, r, errors.WithStack(
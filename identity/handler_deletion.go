@@ -0,0 +1,74 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"net/http"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/x"
+)
+
+const RouteRestore = RouteItem + "/restore"
+
+func errPendingDeletionStateChange() *herodot.DefaultError {
+	return herodot.ErrBadRequest().WithReasonf("The identity is pending deletion and its state can not be changed. Restore the identity instead.")
+}
+
+// Restore Identity Parameters
+//
+// swagger:parameters restoreIdentity
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type restoreIdentity struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route POST /admin/identities/{id}/restore identity restoreIdentity
+//
+// # Restore a Deleted Identity
+//
+// Restores an [identity](https://www.ory.com/docs/kratos/concepts/identity-user-model) which is pending deletion
+// because it was deleted while `identity.deletion.grace_period` is set. The identity gets back the state it had
+// before it was deleted. Sessions revoked by the deletion are not restored.
+//
+// This endpoint returns 404 if the identity was not found or is not pending deletion.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identity
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := x.ParseUUID(r.PathValue("id"))
+
+	if err := h.r.ScheduledDeletionPersister().RestoreIdentity(ctx, id); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	i, err := h.r.PrivilegedIdentityPool().GetIdentity(ctx, id, ExpandDefault)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, WithCredentialsNoConfigAndAdminMetadataInJSON(*i))
+}
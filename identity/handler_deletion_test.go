@@ -0,0 +1,170 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/courier"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/sqlcon"
+)
+
+func TestHandlerSoftDelete(t *testing.T) {
+	conf, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.IdentitySchemasConfig(map[string]string{
+			"default": "file://./stub/handler/customer.schema.json",
+		})),
+		configx.WithValue(config.ViperKeyIdentityDeletionGracePeriod, "24h"),
+	)
+	ctx := t.Context()
+	_, adminTS := testhelpers.NewKratosServerWithCSRF(t, reg)
+
+	do := func(t *testing.T, method, href string, body string, expectCode int) gjson.Result {
+		t.Helper()
+		req, err := http.NewRequest(method, adminTS.URL+href, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := adminTS.Client().Do(req)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		actual, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.EqualValuesf(t, expectCode, res.StatusCode, "%s", actual)
+		return gjson.ParseBytes(actual)
+	}
+
+	create := func(t *testing.T, state identity.State) (uuid.UUID, string) {
+		email := testhelpers.RandomEmail()
+		res := do(t, "POST", "/admin/identities", `{"state":"`+string(state)+`","traits":{"email":"`+email+`"}}`, http.StatusCreated)
+		return uuid.FromStringOrNil(res.Get("id").String()), email
+	}
+
+	t.Run("case=marks the identity as pending deletion", func(t *testing.T) {
+		id, _ := create(t, identity.StateActive)
+		i, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, id, identity.ExpandDefault)
+		require.NoError(t, err)
+		testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, i)
+
+		do(t, "DELETE", "/admin/identities/"+id.String(), "", http.StatusNoContent)
+
+		res := do(t, "GET", "/admin/identities/"+id.String(), "", http.StatusOK)
+		assert.EqualValues(t, identity.StatePendingDeletion, res.Get("state").String(), res.Raw)
+
+		scheduled, err := reg.ScheduledDeletionPersister().GetScheduledIdentityDeletion(ctx, id)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), scheduled.DeleteAfter, time.Minute)
+
+		sessions, _, err := reg.SessionPersister().ListSessionsByIdentity(ctx, id, nil, 0, 10, uuid.Nil, identity.ExpandNothing)
+		require.NoError(t, err)
+		require.NotEmpty(t, sessions)
+		for _, s := range sessions {
+			assert.False(t, s.Active)
+		}
+
+		res = do(t, "GET", "/admin/identities?filter=state%3Dpending_deletion", "", http.StatusOK)
+		assert.Contains(t, res.Get("#.id").String(), id.String(), res.Raw)
+
+		t.Run("case=can not change the state", func(t *testing.T) {
+			do(t, "PATCH", "/admin/identities/"+id.String(), `[{"op":"replace","path":"/state","value":"active"}]`, http.StatusBadRequest)
+		})
+
+		t.Run("case=deleting again does not reschedule", func(t *testing.T) {
+			do(t, "DELETE", "/admin/identities/"+id.String(), "", http.StatusNoContent)
+
+			actual, err := reg.ScheduledDeletionPersister().GetScheduledIdentityDeletion(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, scheduled.ID, actual.ID)
+		})
+	})
+
+	t.Run("case=restores the previous state", func(t *testing.T) {
+		for _, state := range []identity.State{identity.StateActive, identity.StateInactive} {
+			t.Run("state="+string(state), func(t *testing.T) {
+				id, _ := create(t, state)
+				do(t, "DELETE", "/admin/identities/"+id.String(), "", http.StatusNoContent)
+
+				res := do(t, "POST", "/admin/identities/"+id.String()+"/restore", "", http.StatusOK)
+				assert.EqualValues(t, state, res.Get("state").String(), res.Raw)
+
+				_, err := reg.ScheduledDeletionPersister().GetScheduledIdentityDeletion(ctx, id)
+				require.ErrorIs(t, err, sqlcon.ErrNoRows())
+			})
+		}
+	})
+
+	t.Run("case=can not restore an identity which is not pending deletion", func(t *testing.T) {
+		id, _ := create(t, identity.StateActive)
+		do(t, "POST", "/admin/identities/"+id.String()+"/restore", "", http.StatusNotFound)
+		do(t, "POST", "/admin/identities/"+uuid.Must(uuid.NewV4()).String()+"/restore", "", http.StatusNotFound)
+	})
+
+	t.Run("case=purges the identity and its courier messages after the grace period", func(t *testing.T) {
+		id, email := create(t, identity.StateActive)
+		require.NoError(t, reg.CourierPersister().AddMessage(ctx, &courier.Message{
+			Status:       courier.MessageStatusSent,
+			Type:         courier.MessageTypeEmail,
+			Recipient:    email,
+			Subject:      "subject",
+			Body:         "body",
+			TemplateType: "stub",
+		}))
+
+		do(t, "DELETE", "/admin/identities/"+id.String(), "", http.StatusNoContent)
+
+		deleted, err := reg.ScheduledDeletionPersister().DeleteScheduledIdentities(ctx, time.Now(), 100)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+
+		_, err = reg.ScheduledDeletionPersister().DeleteScheduledIdentities(ctx, time.Now().Add(25*time.Hour), 100)
+		require.NoError(t, err)
+
+		do(t, "GET", "/admin/identities/"+id.String(), "", http.StatusNotFound)
+		messages, _, err := reg.CourierPersister().ListMessages(ctx, courier.ListCourierMessagesParameters{Recipient: email}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+
+	t.Run("case=purges an identity which is pending deletion", func(t *testing.T) {
+		id, _ := create(t, identity.StateActive)
+		do(t, "DELETE", "/admin/identities/"+id.String(), "", http.StatusNoContent)
+		do(t, "GET", "/admin/identities/"+id.String(), "", http.StatusOK)
+
+		do(t, "DELETE", "/admin/identities/"+id.String()+"?purge=true", "", http.StatusNoContent)
+		do(t, "GET", "/admin/identities/"+id.String(), "", http.StatusNotFound)
+
+		_, err := reg.ScheduledDeletionPersister().GetScheduledIdentityDeletion(ctx, id)
+		require.ErrorIs(t, err, sqlcon.ErrNoRows())
+	})
+
+	t.Run("case=rejects an invalid purge parameter", func(t *testing.T) {
+		id, _ := create(t, identity.StateActive)
+		do(t, "DELETE", "/admin/identities/"+id.String()+"?purge=maybe", "", http.StatusBadRequest)
+		do(t, "GET", "/admin/identities/"+id.String(), "", http.StatusOK)
+	})
+
+	t.Run("case=deletes immediately without a grace period", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyIdentityDeletionGracePeriod, "0s")
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeyIdentityDeletionGracePeriod, "24h")
+		})
+
+		id, _ := create(t, identity.StateActive)
+		do(t, "DELETE", "/admin/identities/"+id.String(), "", http.StatusNoContent)
+		do(t, "GET", "/admin/identities/"+id.String(), "", http.StatusNotFound)
+	})
+}
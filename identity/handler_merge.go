@@ -0,0 +1,113 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/jsonx"
+)
+
+// RouteMerge merges two identities. It is not nested below RouteItem because
+// both identities are passed in the request body.
+const RouteMerge = RouteCollection + "/merge"
+
+// Merge Identities Request
+//
+// swagger:parameters mergeIdentities
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type mergeIdentities struct {
+	// in: body
+	Body MergeIdentitiesBody
+}
+
+// Merge Identities Body
+//
+// swagger:model mergeIdentitiesBody
+type MergeIdentitiesBody struct {
+	// TargetIdentityID is the identity which is kept.
+	//
+	// required: true
+	TargetIdentityID uuid.UUID `json:"target_identity_id"`
+
+	// SourceIdentityID is the identity which is merged into the target and
+	// deleted afterwards.
+	//
+	// required: true
+	SourceIdentityID uuid.UUID `json:"source_identity_id"`
+
+	// CredentialsConflict decides which credential is kept if both identities
+	// have a password, TOTP, lookup secret, WebAuthn or passkey credential.
+	// Defaults to `keep_target`, which fails with a conflict for WebAuthn and
+	// passkey credentials of the source.
+	//
+	// enum: keep_target,keep_source
+	CredentialsConflict MergeCredentialsConflict `json:"credentials_conflict"`
+}
+
+// swagger:route POST /admin/identities/merge identity mergeIdentities
+//
+// # Merge Two Identities
+//
+// Merges the source [identity](https://www.ory.com/docs/kratos/concepts/identity-user-model) into the target
+// identity and deletes the source identity, all in one transaction. Use this endpoint if a user ended up with
+// two identities, for example by signing up with a social sign in provider using a different email address.
+//
+// The target takes over the source's credentials, verifiable and recovery addresses, and sessions. Social sign in
+// providers and one-time code addresses of both identities are combined. For passwords, TOTP and lookup secrets,
+// the target keeps its own credential unless `credentials_conflict` is set to `keep_source`. If both identities
+// have WebAuthn or passkey credentials, the merge fails with a conflict unless `credentials_conflict` is set to
+// `keep_source`, so that the source's security keys are not dropped silently.
+//
+// Traits and metadata are merged with the Jsonnet snippet configured at `identity.merge.transform_url`. If no
+// snippet is configured, the target keeps its traits and takes over the source's metadata keys it does not have.
+// Addresses of the source which are not part of the merged traits are dropped.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identity
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) merge(w http.ResponseWriter, r *http.Request) {
+	var body MergeIdentitiesBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to decode the request body: %s", err)))
+		return
+	}
+	if body.TargetIdentityID == uuid.Nil || body.SourceIdentityID == uuid.Nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReason("Both target_identity_id and source_identity_id must be set.")))
+		return
+	}
+
+	i, err := MergeIdentities(r.Context(), h.r, body.TargetIdentityID, body.SourceIdentityID, MergeOptions{
+		CredentialsConflict: body.CredentialsConflict,
+	})
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, WithCredentialsNoConfigAndAdminMetadataInJSON(*i))
+}
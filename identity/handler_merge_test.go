@@ -0,0 +1,168 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/x/configx"
+)
+
+func TestHandlerMerge(t *testing.T) {
+	conf, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.IdentitySchemasConfig(map[string]string{
+			"default":  "file://./stub/handler/customer.schema.json",
+			"multiple": "file://./stub/handler/multiple_emails.schema.json",
+		})),
+	)
+	ctx := t.Context()
+	_, adminTS := testhelpers.NewKratosServerWithCSRF(t, reg)
+
+	merge := func(t *testing.T, body string, expectCode int) gjson.Result {
+		t.Helper()
+		req, err := http.NewRequest("POST", adminTS.URL+"/admin/identities/merge", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := adminTS.Client().Do(req)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		actual, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.EqualValuesf(t, expectCode, res.StatusCode, "%s", actual)
+		return gjson.ParseBytes(actual)
+	}
+
+	create := func(t *testing.T, subject, hashedPassword, metadata string) *identity.Identity {
+		email := testhelpers.RandomEmail()
+		i := identity.NewIdentity("default")
+		i.Traits = identity.Traits(`{"email":"` + email + `"}`)
+		i.MetadataPublic = []byte(metadata)
+		i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+			Identifiers: []string{email},
+			Config:      []byte(`{"hashed_password":"` + hashedPassword + `"}`),
+		})
+		require.NoError(t, i.SetCredentialsWithConfig(identity.CredentialsTypeOIDC, identity.Credentials{
+			Identifiers: []string{identity.OIDCUniqueID("google", subject)},
+		}, identity.CredentialsOIDC{Providers: []identity.CredentialsOIDCProvider{{Provider: "google", Subject: subject}}}))
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+		return i
+	}
+
+	body := func(target, source *identity.Identity, conflict string) string {
+		return `{"target_identity_id":"` + target.ID.String() + `","source_identity_id":"` + source.ID.String() + `","credentials_conflict":"` + conflict + `"}`
+	}
+
+	t.Run("case=merges the source into the target", func(t *testing.T) {
+		targetSubject, sourceSubject := uuid.Must(uuid.NewV4()).String(), uuid.Must(uuid.NewV4()).String()
+		target := create(t, targetSubject, "$2a$04$target", `{"plan":"free"}`)
+		source := create(t, sourceSubject, "$2a$04$source", `{"plan":"pro","company":"ory"}`)
+		testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, source)
+
+		res := merge(t, body(target, source, ""), http.StatusOK)
+		assert.Equal(t, target.ID.String(), res.Get("id").String(), res.Raw)
+		assert.JSONEq(t, `{"plan":"free","company":"ory"}`, res.Get("metadata_public").Raw, res.Raw)
+		assert.ElementsMatch(t, []string{
+			identity.OIDCUniqueID("google", targetSubject),
+			identity.OIDCUniqueID("google", sourceSubject),
+		}, res.Get("credentials.oidc.identifiers").Value(), res.Raw)
+
+		actual, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, target.ID)
+		require.NoError(t, err)
+		assert.Contains(t, string(actual.Credentials[identity.CredentialsTypePassword].Config), "$2a$04$target")
+
+		found, _, err := reg.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, identity.CredentialsTypeOIDC, identity.OIDCUniqueID("google", sourceSubject))
+		require.NoError(t, err)
+		assert.Equal(t, target.ID, found.ID)
+
+		sessions, _, err := reg.SessionPersister().ListSessionsByIdentity(ctx, target.ID, nil, 0, 10, uuid.Nil, identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.Len(t, sessions, 1)
+
+		_, err = reg.PrivilegedIdentityPool().GetIdentity(ctx, source.ID, identity.ExpandNothing)
+		require.Error(t, err)
+	})
+
+	t.Run("case=keeps the source's password", func(t *testing.T) {
+		target := create(t, uuid.Must(uuid.NewV4()).String(), "$2a$04$target", `{}`)
+		source := create(t, uuid.Must(uuid.NewV4()).String(), "$2a$04$source", `{}`)
+
+		merge(t, body(target, source, "keep_source"), http.StatusOK)
+
+		actual, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, target.ID)
+		require.NoError(t, err)
+		assert.Contains(t, string(actual.Credentials[identity.CredentialsTypePassword].Config), "$2a$04$source")
+	})
+
+	t.Run("case=rejects dropping the source's WebAuthn credentials", func(t *testing.T) {
+		withWebAuthn := func(t *testing.T, i *identity.Identity) {
+			i.SetCredentials(identity.CredentialsTypeWebAuthn, identity.Credentials{
+				Identifiers: []string{uuid.Must(uuid.NewV4()).String()},
+				Config:      []byte(`{"credentials":[{"id":"` + base64.StdEncoding.EncodeToString(uuid.Must(uuid.NewV4()).Bytes()) + `","display_name":"key"}],"user_handle":"` + base64.StdEncoding.EncodeToString(i.ID.Bytes()) + `"}`),
+			})
+			require.NoError(t, reg.PrivilegedIdentityPool().UpdateIdentity(ctx, i))
+		}
+		target := create(t, uuid.Must(uuid.NewV4()).String(), "$2a$04$target", `{}`)
+		source := create(t, uuid.Must(uuid.NewV4()).String(), "$2a$04$source", `{}`)
+		withWebAuthn(t, target)
+		withWebAuthn(t, source)
+
+		merge(t, body(target, source, ""), http.StatusConflict)
+
+		_, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, source.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+
+		merge(t, body(target, source, "keep_source"), http.StatusOK)
+
+		actual, err := reg.PrivilegedIdentityPool().GetIdentityConfidential(ctx, target.ID)
+		require.NoError(t, err)
+		assert.Equal(t, source.Credentials[identity.CredentialsTypeWebAuthn].Identifiers, actual.Credentials[identity.CredentialsTypeWebAuthn].Identifiers)
+	})
+
+	t.Run("case=merges traits with the configured transform", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyIdentityMergeTransformURL, "file://./stub/merge/emails.jsonnet")
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeyIdentityMergeTransformURL, "")
+		})
+
+		createWithEmail := func(t *testing.T, email string) *identity.Identity {
+			i := identity.NewIdentity("multiple")
+			i.Traits = identity.Traits(`{"emails":["` + email + `"]}`)
+			require.NoError(t, reg.IdentityManager().Create(ctx, i))
+			return i
+		}
+		targetEmail, sourceEmail := testhelpers.RandomEmail(), testhelpers.RandomEmail()
+		target, source := createWithEmail(t, targetEmail), createWithEmail(t, sourceEmail)
+
+		res := merge(t, body(target, source, ""), http.StatusOK)
+		assert.Equal(t, []any{targetEmail, sourceEmail}, res.Get("traits.emails").Value(), res.Raw)
+		assert.ElementsMatch(t, []any{targetEmail, sourceEmail}, res.Get("verifiable_addresses.#.value").Value(), res.Raw)
+		assert.ElementsMatch(t, []any{targetEmail, sourceEmail}, res.Get("recovery_addresses.#.value").Value(), res.Raw)
+	})
+
+	t.Run("case=fails for invalid requests", func(t *testing.T) {
+		target := create(t, uuid.Must(uuid.NewV4()).String(), "$2a$04$target", `{}`)
+		source := create(t, uuid.Must(uuid.NewV4()).String(), "$2a$04$source", `{}`)
+
+		merge(t, body(target, target, ""), http.StatusBadRequest)
+		merge(t, body(target, source, "keep_both"), http.StatusBadRequest)
+		merge(t, body(target, &identity.Identity{ID: uuid.Must(uuid.NewV4())}, ""), http.StatusNotFound)
+		merge(t, `{"target_identity_id":"`+target.ID.String()+`"}`, http.StatusBadRequest)
+
+		_, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, source.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+	})
+}
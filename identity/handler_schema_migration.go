@@ -0,0 +1,125 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/jsonx"
+)
+
+const (
+	// RouteSchemaMigration is not nested below RouteCollection so that it does
+	// not conflict with the routes of single identities.
+	RouteSchemaMigration = "/identity-schema-migrations/{migration}"
+
+	SchemaMigrationBatchSizeLimit = 1000
+)
+
+// Run Identity Schema Migration Request
+//
+// swagger:parameters runIdentitySchemaMigration
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type runIdentitySchemaMigration struct {
+	// ID of the migration as configured in `identity.schema_migrations`.
+	//
+	// required: true
+	// in: path
+	Migration string `json:"migration"`
+
+	// in: body
+	Body RunIdentitySchemaMigrationBody
+}
+
+// Run Identity Schema Migration Body
+//
+// swagger:model runIdentitySchemaMigrationBody
+type RunIdentitySchemaMigrationBody struct {
+	// DryRun transforms and validates the identities without changing them.
+	DryRun bool `json:"dry_run"`
+
+	// BatchSize is the number of identities to migrate with this request.
+	// Defaults to 100, and may be at most 1000.
+	BatchSize int `json:"batch_size"`
+
+	// PageToken continues a previous run. Set it to the `next_page_token` of
+	// the previous response.
+	PageToken string `json:"page_token"`
+}
+
+// swagger:route POST /admin/identity-schema-migrations/{migration} identity runIdentitySchemaMigration
+//
+// # Run an Identity Schema Migration
+//
+// Migrates a batch of identities from the migration's source identity schema
+// to its target identity schema, transforming their traits and metadata with
+// the migration's Jsonnet snippet.
+//
+// Identities which can not be transformed or do not validate against the
+// target schema are listed in the response and keep their schema. Repeat the
+// request with the returned `next_page_token` until it is empty.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identitySchemaMigrationReport
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) runSchemaMigration(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body RunIdentitySchemaMigrationBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to decode the request body: %s", err)))
+		return
+	}
+	if body.BatchSize > SchemaMigrationBatchSizeLimit {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReasonf(
+			"The maximum number of identities per request that can be migrated at once is %d.", SchemaMigrationBatchSizeLimit)))
+		return
+	}
+
+	opts := SchemaMigrationOptions{DryRun: body.DryRun, BatchSize: body.BatchSize, MaxBatches: 1}
+	if body.PageToken != "" {
+		after, err := uuid.FromString(body.PageToken)
+		if err != nil {
+			h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReason("The page token is invalid.")))
+			return
+		}
+		opts.After = after
+	}
+
+	m, err := h.r.Config().IdentitySchemaMigration(ctx, r.PathValue("migration"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	report, err := MigrateSchema(ctx, h.r, m, opts, nil)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, report)
+}
@@ -3322,6 +3322,14 @@ func TestHandler(t *testing.T) {
 				assert.Equal(t, count/perPage, pages)
 			})
 		})
+
+		t.Run("counts filtered identities in page pagination", func(t *testing.T) {
+			_, res := getFull(t, ts, "/admin/identities?page=0&per_page=10&filter="+url.QueryEscape("schema_id="+config.DefaultIdentityTraitsSchemaID), http.StatusOK)
+			assert.Equal(t, strconv.Itoa(count), res.Header.Get("X-Total-Count"))
+
+			_, res = getFull(t, ts, "/admin/identities?page=0&per_page=10&filter="+url.QueryEscape("schema_id=customer"), http.StatusOK)
+			assert.Equal(t, "0", res.Header.Get("X-Total-Count"))
+		})
 	})
 }
 
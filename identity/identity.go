@@ -27,7 +27,7 @@ import (
 
 // An Identity's State
 //
-// The state can either be `active` or `inactive`.
+// The state can either be `active`, `inactive` or `pending_deletion`.
 //
 // swagger:enum State
 type State string
@@ -35,6 +35,11 @@ type State string
 const (
 	StateActive   State = "active"
 	StateInactive State = "inactive"
+
+	// StatePendingDeletion marks identities which were deleted and will be
+	// purged once the deletion grace period has passed. It can not be set
+	// directly, instead the identity has to be deleted or restored.
+	StatePendingDeletion State = "pending_deletion"
 )
 
 func (lt State) IsValid() error {
@@ -89,6 +94,12 @@ type Identity struct {
 	// required: true
 	SchemaID string `json:"schema_id" faker:"-" db:"schema_id"`
 
+	// SchemaVersion is the version of the identity schema the identity is
+	// pinned to. The identity's traits are validated against this version
+	// until it is changed, for example by an identity schema migration. It
+	// is empty if the identity schema is not versioned.
+	SchemaVersion string `json:"schema_version,omitempty" faker:"-" db:"schema_version"`
+
 	// SchemaURL is the URL of the endpoint where the identity's traits schema can be fetched from.
 	//
 	// format: url
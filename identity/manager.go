@@ -579,7 +579,12 @@ func (m *Manager) UpdateSchemaID(ctx context.Context, id uuid.UUID, schemaID str
 		return errors.WithStack(ErrProtectedFieldModified())
 	}
 
-	original.SchemaID = schemaID
+	if original.SchemaID != schemaID {
+		original.SchemaID = schemaID
+		if err := m.PinCurrentSchemaVersion(ctx, original); err != nil {
+			return err
+		}
+	}
 	if err := m.ValidateIdentity(ctx, original, o); err != nil {
 		return err
 	}
@@ -587,6 +592,12 @@ func (m *Manager) UpdateSchemaID(ctx context.Context, id uuid.UUID, schemaID str
 	return m.r.PrivilegedIdentityPool().UpdateIdentity(ctx, original)
 }
 
+// PinCurrentSchemaVersion pins the identity to the current version of its
+// identity schema. It is used when an identity changes its schema.
+func (m *Manager) PinCurrentSchemaVersion(ctx context.Context, i *Identity) error {
+	return m.r.IdentityValidator().pinCurrentSchemaVersion(ctx, i)
+}
+
 func (m *Manager) SetTraits(ctx context.Context, id uuid.UUID, traits Traits, opts ...ManagerOption) (_ *Identity, err error) {
 	ctx, span := m.r.Tracer(ctx).Tracer().Start(ctx, "identity.Manager.SetTraits")
 	defer otelx.End(span, &err)
@@ -719,7 +730,8 @@ type AddressRef struct {
 // the appropriate courier channel, sharing the courier, identity-model, and
 // error-collection plumbing across the concrete notification types. buildEmail
 // and buildSMS construct the channel-specific template for a single recipient
-// from the identity model and a shared RFC3339 timestamp. Errors from individual
+// from the identity model and a shared RFC3339 timestamp; push notifications
+// and WhatsApp messages reuse the SMS template. Errors from individual
 // targets are collected and returned as a joined error but do not short-circuit
 // the batch — a failure to notify one recipient must not prevent others from
 // being notified.
@@ -766,6 +778,20 @@ func (m *Manager) sendIdentityNotifications(
 					Warn("Failed to queue identity notification SMS.")
 				errs = append(errs, qerr)
 			}
+		case AddressTypePush:
+			if _, qerr := c.QueuePush(ctx, buildSMS(t.Value, model, at)); qerr != nil {
+				m.r.Logger().WithError(qerr).
+					WithField("via", t.Via).
+					Warn("Failed to queue identity notification push notification.")
+				errs = append(errs, qerr)
+			}
+		case AddressTypeWhatsApp:
+			if _, qerr := c.QueueWhatsApp(ctx, buildSMS(t.Value, model, at)); qerr != nil {
+				m.r.Logger().WithError(qerr).
+					WithField("via", t.Via).
+					Warn("Failed to queue identity notification WhatsApp message.")
+				errs = append(errs, qerr)
+			}
 		default:
 			m.r.Logger().
 				WithField("via", t.Via).
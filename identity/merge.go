@@ -0,0 +1,328 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/x/transaction"
+	"github.com/ory/pop/v6"
+	"github.com/ory/x/fetcher"
+	"github.com/ory/x/httpx"
+	"github.com/ory/x/jsonnetsecure"
+	"github.com/ory/x/otelx"
+)
+
+type (
+	mergeDependencies interface {
+		config.Provider
+		PrivilegedPoolProvider
+		ManagementProvider
+		httpx.ClientProvider
+		jsonnetsecure.VMProvider
+		otelx.Provider
+		transaction.PersistenceProvider
+	}
+
+	// MergeCredentialsConflict decides which credential is kept if both
+	// identities have a credential of a type which can not be combined, for
+	// example a password.
+	MergeCredentialsConflict string
+
+	// MergeOptions configures the merge of two identities.
+	MergeOptions struct {
+		// CredentialsConflict decides which credential is kept if both
+		// identities have a credential of a type which can not be combined.
+		// Defaults to MergeCredentialsConflictKeepTarget.
+		CredentialsConflict MergeCredentialsConflict
+	}
+)
+
+const (
+	MergeCredentialsConflictKeepTarget MergeCredentialsConflict = "keep_target"
+	MergeCredentialsConflictKeepSource MergeCredentialsConflict = "keep_source"
+)
+
+// MergeIdentities merges the source identity into the target identity and
+// deletes the source identity.
+//
+// The target takes over the source's credentials, verifiable and recovery
+// addresses, and sessions. OIDC and SAML providers and one-time code addresses
+// are combined; for all other credential types the target keeps its own
+// credential unless opts.CredentialsConflict says otherwise. Traits and
+// metadata are merged with the Jsonnet snippet configured at
+// `identity.merge.transform_url`. Addresses of the source which are not part
+// of the merged traits are dropped when the target is validated.
+func MergeIdentities(ctx context.Context, d mergeDependencies, targetID, sourceID uuid.UUID, opts MergeOptions) (_ *Identity, err error) {
+	ctx, span := d.Tracer(ctx).Tracer().Start(ctx, "identity.MergeIdentities")
+	defer otelx.End(span, &err)
+
+	switch opts.CredentialsConflict {
+	case "":
+		opts.CredentialsConflict = MergeCredentialsConflictKeepTarget
+	case MergeCredentialsConflictKeepTarget, MergeCredentialsConflictKeepSource:
+	default:
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Credentials conflict resolution must be one of %q or %q.",
+			MergeCredentialsConflictKeepTarget, MergeCredentialsConflictKeepSource))
+	}
+
+	if targetID == sourceID {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReason("An identity can not be merged into itself."))
+	}
+
+	// The Jsonnet snippet is fetched before the identities are locked.
+	transformURL := d.Config().IdentityMergeTransformURL(ctx)
+	var transform string
+	if transformURL != "" {
+		fetch := fetcher.NewFetcher(fetcher.WithClient(d.HTTPClient(ctx)))
+		snippet, err := fetch.FetchContext(ctx, transformURL)
+		if err != nil {
+			return nil, err
+		}
+		transform = snippet.String()
+	}
+
+	// Both identities are locked while they are merged, so that concurrent
+	// changes to either of them are not lost.
+	var target *Identity
+	if err := d.TransactionalPersisterProvider().Transaction(ctx, func(ctx context.Context, _ *pop.Connection) (err error) {
+		if err := d.PrivilegedIdentityPool().LockIdentities(ctx, targetID, sourceID); err != nil {
+			return err
+		}
+
+		target, err = d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if target.State == StatePendingDeletion {
+			return errors.WithStack(herodot.ErrBadRequest().WithReason("The target identity is pending deletion. Restore it before merging identities into it."))
+		}
+
+		source, err := d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+
+		if err := mergeIdentityData(ctx, d, transformURL, transform, target, source); err != nil {
+			return err
+		}
+		if err := mergeCredentials(target, source, opts.CredentialsConflict); err != nil {
+			return err
+		}
+		mergeAddresses(target, source)
+
+		if err := d.IdentityManager().ValidateIdentity(ctx, target, &ManagerOptions{ExposeValidationErrors: true}); err != nil {
+			return err
+		}
+
+		return d.PrivilegedIdentityPool().MergeIdentities(ctx, target, source.ID)
+	}); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// mergeIdentityData merges the traits and metadata of the source into the
+// target with the Jsonnet snippet transform fetched from transformURL. Without
+// a snippet, the target keeps its traits and takes over the source's metadata
+// keys it does not have itself.
+func mergeIdentityData(ctx context.Context, d mergeDependencies, transformURL, transform string, target, source *Identity) error {
+	if transformURL == "" {
+		var err error
+		if target.MetadataPublic, err = mergeMetadata(target.MetadataPublic, source.MetadataPublic); err != nil {
+			return err
+		}
+		if target.MetadataAdmin, err = mergeMetadata(target.MetadataAdmin, source.MetadataAdmin); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	vm, err := d.JsonnetVM(ctx)
+	if err != nil {
+		return err
+	}
+	for name, i := range map[string]*Identity{"target": target, "source": source} {
+		input, err := json.Marshal(map[string]any{
+			"id":              i.ID,
+			"schema_id":       i.SchemaID,
+			"traits":          json.RawMessage(i.Traits),
+			"metadata_public": i.MetadataPublic,
+			"metadata_admin":  i.MetadataAdmin,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		vm.ExtCode(name, string(input))
+	}
+
+	evaluated, err := vm.EvaluateAnonymousSnippet(transformURL, transform)
+	if err != nil {
+		return errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to merge the identities: %s", err))
+	}
+
+	traits := gjson.Get(evaluated, "identity.traits")
+	if !traits.IsObject() {
+		return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Identity merge Jsonnet transform did not return an object for key identity.traits. Please check your Jsonnet code!"))
+	}
+	target.Traits = Traits(traits.Raw)
+
+	for key, metadata := range map[string]*[]byte{
+		"identity.metadata_public": (*[]byte)(&target.MetadataPublic),
+		"identity.metadata_admin":  (*[]byte)(&target.MetadataAdmin),
+	} {
+		switch result := gjson.Get(evaluated, key); {
+		case !result.Exists():
+		case result.Type == gjson.Null:
+			*metadata = nil
+		case result.IsObject():
+			*metadata = []byte(result.Raw)
+		default:
+			return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Identity merge Jsonnet transform did not return an object for key %s. Please check your Jsonnet code!", key))
+		}
+	}
+	return nil
+}
+
+// mergeMetadata adds the top-level keys of source which target does not have
+// to target.
+func mergeMetadata(target, source []byte) ([]byte, error) {
+	if len(source) == 0 || string(source) == "null" {
+		return target, nil
+	}
+	if len(target) == 0 || string(target) == "null" {
+		return source, nil
+	}
+
+	var merged, additional map[string]json.RawMessage
+	if err := json.Unmarshal(target, &merged); err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to merge the metadata of the target identity: %s", err))
+	}
+	if err := json.Unmarshal(source, &additional); err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to merge the metadata of the source identity: %s", err))
+	}
+	for key, value := range additional {
+		if _, ok := merged[key]; !ok {
+			merged[key] = value
+		}
+	}
+
+	out, err := json.Marshal(merged)
+	return out, errors.WithStack(err)
+}
+
+// mergeCredentials moves the source's credentials to the target.
+func mergeCredentials(target, source *Identity, conflict MergeCredentialsConflict) error {
+	for _, ct := range slices.Sorted(maps.Keys(source.Credentials)) {
+		c := source.Credentials[ct]
+		c.ID = uuid.Nil
+
+		existing, ok := target.Credentials[ct]
+		if !ok {
+			target.SetCredentials(ct, c)
+			continue
+		}
+
+		switch ct {
+		case CredentialsTypeOIDC, CredentialsTypeSAML:
+			var merged, additional CredentialsOIDC
+			if err := json.Unmarshal(existing.Config, &merged); err != nil {
+				return errors.WithStack(herodot.ErrInternalServerError().WithReasonf("Unable to decode the %s credentials of the target identity: %s", ct, err))
+			}
+			if err := json.Unmarshal(c.Config, &additional); err != nil {
+				return errors.WithStack(herodot.ErrInternalServerError().WithReasonf("Unable to decode the %s credentials of the source identity: %s", ct, err))
+			}
+			for _, p := range additional.Providers {
+				if !slices.ContainsFunc(merged.Providers, func(e CredentialsOIDCProvider) bool {
+					return e.Provider == p.Provider && e.Subject == p.Subject
+				}) {
+					merged.Providers = append(merged.Providers, p)
+				}
+			}
+			if err := setMergedCredentials(target, existing, c, &merged); err != nil {
+				return err
+			}
+		case CredentialsTypeCodeAuth:
+			var merged, additional CredentialsCode
+			if err := json.Unmarshal(existing.Config, &merged); err != nil {
+				return errors.WithStack(herodot.ErrInternalServerError().WithReasonf("Unable to decode the %s credentials of the target identity: %s", ct, err))
+			}
+			if err := json.Unmarshal(c.Config, &additional); err != nil {
+				return errors.WithStack(herodot.ErrInternalServerError().WithReasonf("Unable to decode the %s credentials of the source identity: %s", ct, err))
+			}
+			for _, a := range additional.Addresses {
+				if !slices.ContainsFunc(merged.Addresses, func(e CredentialsCodeAddress) bool {
+					return e.Channel == a.Channel && e.Address == a.Address
+				}) {
+					merged.Addresses = append(merged.Addresses, a)
+				}
+			}
+			if err := setMergedCredentials(target, existing, c, &merged); err != nil {
+				return err
+			}
+		case CredentialsTypeWebAuthn, CredentialsTypePasskey:
+			// WebAuthn credentials and passkeys are bound to the identity's
+			// user handle and can not be combined. Dropping the source's
+			// security keys silently would lock the user out of them.
+			if conflict == MergeCredentialsConflictKeepSource {
+				target.SetCredentials(ct, c)
+			} else if gjson.GetBytes(c.Config, "credentials.#").Int() > 0 {
+				return errors.WithStack(herodot.ErrConflict().WithReasonf(
+					"Both identities have %s credentials, which can not be combined. Delete the %s credentials of one of the identities or set credentials_conflict to %q.",
+					ct, ct, MergeCredentialsConflictKeepSource))
+			}
+		default:
+			// Passwords, TOTP and lookup secrets are bound to a single secret
+			// and can not be combined.
+			if conflict == MergeCredentialsConflictKeepSource {
+				target.SetCredentials(ct, c)
+			}
+		}
+	}
+	return nil
+}
+
+// setMergedCredentials sets the combined credentials of both identities on
+// the target.
+func setMergedCredentials(target *Identity, existing, additional Credentials, config any) error {
+	for _, identifier := range additional.Identifiers {
+		if !slices.Contains(existing.Identifiers, identifier) {
+			existing.Identifiers = append(existing.Identifiers, identifier)
+		}
+	}
+	return target.SetCredentialsWithConfig(existing.Type, existing, config)
+}
+
+// mergeAddresses moves the source's verifiable and recovery addresses which the
+// target does not have yet to the target.
+func mergeAddresses(target, source *Identity) {
+	for _, a := range source.VerifiableAddresses {
+		if !slices.ContainsFunc(target.VerifiableAddresses, func(e VerifiableAddress) bool {
+			return e.Via == a.Via && e.Value == a.Value
+		}) {
+			a.ID = uuid.Nil
+			a.IdentityID = target.ID
+			target.VerifiableAddresses = append(target.VerifiableAddresses, a)
+		}
+	}
+	for _, a := range source.RecoveryAddresses {
+		if !slices.ContainsFunc(target.RecoveryAddresses, func(e RecoveryAddress) bool {
+			return e.Via == a.Via && e.Value == a.Value
+		}) {
+			a.ID = uuid.Nil
+			a.IdentityID = target.ID
+			target.RecoveryAddresses = append(target.RecoveryAddresses, a)
+		}
+	}
+}
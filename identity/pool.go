@@ -110,6 +110,7 @@ type (
 		DeclassifyCredentials        []CredentialsType
 		KeySetPagination             []keysetpagination.Option
 		OrganizationID               uuid.UUID
+		Filters                      []Filter
 		ConsistencyLevel             crdbx.ConsistencyLevel
 		StatementTransformer         func(string) string
 
@@ -161,6 +162,10 @@ type (
 		// CountIdentities counts the number of identities in the store.
 		CountIdentities(ctx context.Context) (int64, error)
 
+		// CountFilteredIdentities counts the number of identities matching
+		// all filters.
+		CountFilteredIdentities(ctx context.Context, filters []Filter) (int64, error)
+
 		// GetIdentity returns an identity by its id. Will return an error if the identity does not exist or backend
 		// connectivity is broken.
 		GetIdentity(context.Context, uuid.UUID, sqlxx.Expandables) (*Identity, error)
@@ -211,6 +216,17 @@ type (
 		// UpdateIdentity updates an identity including its confidential / privileged / protected data.
 		UpdateIdentity(context.Context, *Identity, ...UpdateIdentityModifier) error
 
+		// MergeIdentities moves the sessions of the source identity to the
+		// target identity, deletes the source identity and updates the target
+		// identity, all in one transaction. The target identity must already
+		// contain the credentials and addresses taken over from the source.
+		MergeIdentities(ctx context.Context, target *Identity, sourceID uuid.UUID) error
+
+		// LockIdentities locks the given identities until the surrounding
+		// transaction ends. It must be called inside a transaction and fails
+		// with sqlcon.ErrNoRows if an identity does not exist.
+		LockIdentities(ctx context.Context, ids ...uuid.UUID) error
+
 		// UpdateIdentityColumns updates targeted columns of an identity.
 		UpdateIdentityColumns(ctx context.Context, i *Identity, columns ...string) error
 
@@ -230,6 +246,16 @@ type (
 		// WithDerivedIdentifiers.
 		UpdateCredentialsConfig(ctx context.Context, identityID uuid.UUID, ct CredentialsType, mutate func(config []byte) ([]byte, error), opts ...UpdateCredentialsConfigModifier) error
 
+		// ListIdentityIDsByCredentialsType lists the IDs of identities which have
+		// credentials of the given type, ordered by ID and starting after the
+		// given ID. Use uuid.Nil to start from the beginning.
+		ListIdentityIDsByCredentialsType(ctx context.Context, ct CredentialsType, after uuid.UUID, limit int) ([]uuid.UUID, error)
+
+		// ListIdentityIDsBySchemaID lists the IDs of identities which use the
+		// given identity schema, ordered by ID and starting after the given ID.
+		// Use uuid.Nil to start from the beginning.
+		ListIdentityIDsBySchemaID(ctx context.Context, schemaID string, after uuid.UUID, limit int) ([]uuid.UUID, error)
+
 		// GetIdentityConfidential returns the identity including it's raw credentials.
 		//
 		// This should only be used internally. Please be aware that this method uses HydrateIdentityAssociations
@@ -252,6 +278,10 @@ type (
 		// InjectTraitsSchemaURL sets the identity's traits JSON schema URL from the schema's ID.
 		InjectTraitsSchemaURL(ctx context.Context, i *Identity) error
 
+		// InjectTraitsSchemaURLs sets the traits JSON schema URL of all given
+		// identities, resolving the identity schemas only once.
+		InjectTraitsSchemaURLs(ctx context.Context, is ...*Identity) error
+
 		// FindIdentityByCredentialIdentifier returns an identity by matching the identifier to any of the identity's credentials.
 		FindIdentityByCredentialIdentifier(ctx context.Context, identifier string, caseSensitive bool, expandables Expandables) (*Identity, error)
 
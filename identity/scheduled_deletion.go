@@ -0,0 +1,82 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/x/sqlxx"
+)
+
+// ScheduledDeletion records that an identity requested the deletion of its
+// account, or that it was deleted through the admin API while a deletion
+// grace period is configured. The identity is deleted once DeleteAfter has
+// passed, unless the deletion is cancelled or the identity is restored before.
+//
+// swagger:ignore
+type ScheduledDeletion struct {
+	ID uuid.UUID `json:"id" db:"id"`
+
+	// IdentityID is the identity which will be deleted.
+	IdentityID uuid.UUID `json:"identity_id" db:"identity_id"`
+
+	// NID is the network ID (multi-tenant discriminator).
+	NID uuid.UUID `json:"-" db:"nid"`
+
+	// DeleteAfter is the time after which the identity will be deleted.
+	DeleteAfter time.Time `json:"delete_after" db:"delete_after"`
+
+	// RestoreState is the state the identity had before it was marked as
+	// pending deletion. It is empty if the identity's state was not changed.
+	RestoreState sqlxx.NullString `json:"restore_state,omitempty" db:"restore_state"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (ScheduledDeletion) TableName() string {
+	return "identity_scheduled_deletions"
+}
+
+// ScheduledDeletionPersister handles identities which are scheduled for
+// deletion.
+type ScheduledDeletionPersister interface {
+	// ScheduleIdentityDeletion schedules the deletion of an identity,
+	// replacing any previously scheduled deletion of the identity.
+	ScheduleIdentityDeletion(ctx context.Context, d *ScheduledDeletion) error
+
+	// GetScheduledIdentityDeletion returns the scheduled deletion of an identity.
+	GetScheduledIdentityDeletion(ctx context.Context, identityID uuid.UUID) (*ScheduledDeletion, error)
+
+	// CancelScheduledIdentityDeletion cancels the scheduled deletion of an
+	// identity. It does not fail if no deletion is scheduled.
+	CancelScheduledIdentityDeletion(ctx context.Context, identityID uuid.UUID) error
+
+	// SoftDeleteIdentity marks an identity as pending deletion, revokes its
+	// sessions and schedules its deletion. Soft-deleting an identity which
+	// is already pending deletion does nothing.
+	SoftDeleteIdentity(ctx context.Context, identityID uuid.UUID, deleteAfter time.Time) error
+
+	// RestoreIdentity cancels the scheduled deletion of an identity and
+	// restores the state it had before it was soft-deleted.
+	RestoreIdentity(ctx context.Context, identityID uuid.UUID) error
+
+	// PurgeIdentity deletes an identity right away, together with the
+	// courier messages sent to it, regardless of whether it is pending
+	// deletion.
+	PurgeIdentity(ctx context.Context, identityID uuid.UUID) error
+
+	// DeleteScheduledIdentities deletes up to limit identities whose
+	// scheduled deletion is due at the given time, together with the courier
+	// messages sent to them, and returns how many identities were deleted.
+	DeleteScheduledIdentities(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// ScheduledDeletionPersistenceProvider provides access to the persister.
+type ScheduledDeletionPersistenceProvider interface {
+	ScheduledDeletionPersister() ScheduledDeletionPersister
+}
@@ -0,0 +1,266 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/x/fetcher"
+	"github.com/ory/x/httpx"
+	"github.com/ory/x/jsonnetsecure"
+	"github.com/ory/x/logrusx"
+	"github.com/ory/x/otelx"
+)
+
+type (
+	schemaMigrationDependencies interface {
+		config.Provider
+		schema.IdentitySchemaProvider
+		PrivilegedPoolProvider
+		ManagementProvider
+		httpx.ClientProvider
+		jsonnetsecure.VMProvider
+		logrusx.Provider
+		otelx.Provider
+	}
+
+	// SchemaMigrationOptions configures a run of an identity schema
+	// migration.
+	SchemaMigrationOptions struct {
+		// DryRun transforms and validates identities without persisting them.
+		DryRun bool
+
+		// BatchSize is the number of identities loaded at once.
+		BatchSize int
+
+		// After is the ID of the last identity processed by a previous run.
+		After uuid.UUID
+
+		// MaxBatches stops the run after the given number of batches. Zero
+		// runs until all identities are migrated.
+		MaxBatches int
+	}
+
+	// Identity Schema Migration Report
+	//
+	// swagger:model identitySchemaMigrationReport
+	SchemaMigrationReport struct {
+		// MigrationID is the ID of the configured migration.
+		//
+		// required: true
+		MigrationID string `json:"migration_id"`
+
+		// DryRun is true if no identities were changed.
+		//
+		// required: true
+		DryRun bool `json:"dry_run"`
+
+		// Scanned is the number of identities which were processed.
+		//
+		// required: true
+		Scanned int `json:"scanned"`
+
+		// Migrated is the number of identities which were migrated or, in a
+		// dry run, would have been migrated.
+		//
+		// required: true
+		Migrated int `json:"migrated"`
+
+		// Failures lists the identities which could not be migrated.
+		//
+		// required: true
+		Failures []SchemaMigrationFailure `json:"failures"`
+
+		// NextPageToken continues the migration after the last processed
+		// identity. It is empty if all identities were processed.
+		NextPageToken string `json:"next_page_token,omitempty"`
+	}
+
+	// Identity Schema Migration Failure
+	//
+	// swagger:model identitySchemaMigrationFailure
+	SchemaMigrationFailure struct {
+		// IdentityID is the ID of the identity which could not be migrated.
+		//
+		// required: true
+		IdentityID uuid.UUID `json:"identity_id"`
+
+		// Error describes why the identity could not be migrated.
+		//
+		// required: true
+		Error string `json:"error"`
+	}
+)
+
+// MigrateSchema moves all identities using the migration's source schema to
+// its target schema. Traits and metadata are transformed with the migration's
+// Jsonnet snippet and must validate against the current version of the
+// target schema, to which they are pinned afterwards. progress, if set, is
+// called after every batch.
+//
+// Identities which fail to transform or validate are reported and skipped so
+// that a single broken identity does not block the migration of all others.
+// Because they keep the source schema, running the migration again retries
+// them.
+func MigrateSchema(ctx context.Context, d schemaMigrationDependencies, m *config.IdentitySchemaMigration, opts SchemaMigrationOptions, progress func(SchemaMigrationReport)) (_ *SchemaMigrationReport, err error) {
+	ctx, span := d.Tracer(ctx).Tracer().Start(ctx, "identity.MigrateSchema")
+	defer otelx.End(span, &err)
+
+	result := SchemaMigrationReport{MigrationID: m.ID, DryRun: opts.DryRun, Failures: []SchemaMigrationFailure{}}
+	schemas, err := d.IdentityTraitsSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := schemas.GetByID(m.To)
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Identity schema migration %q migrates to unknown identity schema %q.", m.ID, m.To))
+	}
+	// Migrating within the same schema upgrades identities to its current
+	// version, which requires the schema to be versioned.
+	if m.From == m.To && target.Version == "" {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Identity schema migration %q must migrate to a different identity schema or to a versioned identity schema.", m.ID))
+	}
+
+	var transform string
+	if m.TransformURL != "" {
+		fetch := fetcher.NewFetcher(fetcher.WithClient(d.HTTPClient(ctx)))
+		snippet, err := fetch.FetchContext(ctx, m.TransformURL)
+		if err != nil {
+			return nil, err
+		}
+		transform = snippet.String()
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+
+	after := opts.After
+	for batch := 1; ; batch++ {
+		ids, err := d.PrivilegedIdentityPool().ListIdentityIDsBySchemaID(ctx, m.From, after, opts.BatchSize)
+		if err != nil {
+			return &result, err
+		}
+
+		for _, id := range ids {
+			result.Scanned++
+			if err := migrateIdentitySchema(ctx, d, m, transform, id, opts.DryRun); err != nil {
+				result.Failures = append(result.Failures, SchemaMigrationFailure{IdentityID: id, Error: schemaMigrationErrorMessage(err)})
+				d.Logger().
+					WithError(err).
+					WithField("identity_id", id).
+					WithField("migration_id", m.ID).
+					Warn("Unable to migrate the identity to the new identity schema.")
+				continue
+			}
+			result.Migrated++
+		}
+
+		if len(ids) < opts.BatchSize {
+			result.NextPageToken = ""
+		} else {
+			after = ids[len(ids)-1]
+			result.NextPageToken = after.String()
+		}
+
+		if progress != nil {
+			progress(result)
+		}
+		if result.NextPageToken == "" || (opts.MaxBatches > 0 && batch >= opts.MaxBatches) {
+			return &result, nil
+		}
+	}
+}
+
+func migrateIdentitySchema(ctx context.Context, d schemaMigrationDependencies, m *config.IdentitySchemaMigration, transform string, id uuid.UUID, dryRun bool) error {
+	i, err := d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if transform != "" {
+		if err := transformIdentity(ctx, d, m, transform, i); err != nil {
+			return err
+		}
+	}
+	i.SchemaID = m.To
+	// Migrated identities are pinned to the current version of the target
+	// schema.
+	if err := d.IdentityManager().PinCurrentSchemaVersion(ctx, i); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return d.IdentityManager().ValidateIdentity(ctx, i, &ManagerOptions{ExposeValidationErrors: true})
+	}
+	return d.IdentityManager().Update(ctx, i, ManagerAllowWriteProtectedTraits, ManagerExposeValidationErrorsForInternalTypeAssertion)
+}
+
+func transformIdentity(ctx context.Context, d schemaMigrationDependencies, m *config.IdentitySchemaMigration, transform string, i *Identity) error {
+	input, err := json.Marshal(map[string]any{
+		"id":              i.ID,
+		"schema_id":       i.SchemaID,
+		"traits":          json.RawMessage(i.Traits),
+		"metadata_public": i.MetadataPublic,
+		"metadata_admin":  i.MetadataAdmin,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	vm, err := d.JsonnetVM(ctx)
+	if err != nil {
+		return err
+	}
+	vm.ExtCode("identity", string(input))
+
+	evaluated, err := vm.EvaluateAnonymousSnippet(m.TransformURL, transform)
+	if err != nil {
+		return errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to transform the identity: %s", err))
+	}
+
+	traits := gjson.Get(evaluated, "identity.traits")
+	if !traits.IsObject() {
+		return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Identity schema migration Jsonnet transform did not return an object for key identity.traits. Please check your Jsonnet code!"))
+	}
+	i.Traits = Traits(traits.Raw)
+
+	if metadata := gjson.Get(evaluated, "identity.metadata_public"); metadata.Exists() {
+		switch {
+		case metadata.Type == gjson.Null:
+			i.MetadataPublic = nil
+		case metadata.IsObject():
+			i.MetadataPublic = []byte(metadata.Raw)
+		default:
+			return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Identity schema migration Jsonnet transform did not return an object for key identity.metadata_public. Please check your Jsonnet code!"))
+		}
+	}
+	if metadata := gjson.Get(evaluated, "identity.metadata_admin"); metadata.Exists() {
+		switch {
+		case metadata.Type == gjson.Null:
+			i.MetadataAdmin = nil
+		case metadata.IsObject():
+			i.MetadataAdmin = []byte(metadata.Raw)
+		default:
+			return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Identity schema migration Jsonnet transform did not return an object for key identity.metadata_admin. Please check your Jsonnet code!"))
+		}
+	}
+	return nil
+}
+
+func schemaMigrationErrorMessage(err error) string {
+	var he herodot.ReasonCarrier
+	if errors.As(err, &he) && he.Reason() != "" {
+		return he.Reason()
+	}
+	return err.Error()
+}
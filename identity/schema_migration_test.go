@@ -0,0 +1,108 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/x/configx"
+)
+
+func TestMigrateSchema(t *testing.T) {
+	_, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.IdentitySchemasConfig(map[string]string{
+			"v1": "file://./stub/schema-migration/v1.schema.json",
+			"v2": "file://./stub/schema-migration/v2.schema.json",
+		})),
+		configx.WithValue(config.ViperKeyDefaultIdentitySchemaID, "v1"),
+		configx.WithValue(config.ViperKeyIdentitySchemaMigrations, []map[string]any{{
+			"id":            "v1-to-v2",
+			"from":          "v1",
+			"to":            "v2",
+			"transform_url": "file://./stub/schema-migration/v1-to-v2.jsonnet",
+		}}),
+	)
+	ctx := t.Context()
+
+	m, err := reg.Config().IdentitySchemaMigration(ctx, "v1-to-v2")
+	require.NoError(t, err)
+
+	create := func(t *testing.T, name string) *identity.Identity {
+		i := identity.NewIdentity("v1")
+		i.Traits = identity.Traits(`{"name":"` + name + `"}`)
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		return i
+	}
+	ada, mononym := create(t, "Ada Lovelace"), create(t, "Plato")
+
+	t.Run("case=dry run validates without changing identities", func(t *testing.T) {
+		report, err := identity.MigrateSchema(ctx, reg, m, identity.SchemaMigrationOptions{DryRun: true}, nil)
+		require.NoError(t, err)
+
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 2, report.Scanned)
+		assert.Equal(t, 1, report.Migrated)
+		require.Len(t, report.Failures, 1)
+		assert.Equal(t, mononym.ID, report.Failures[0].IdentityID)
+		assert.Contains(t, report.Failures[0].Error, "last")
+
+		actual, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, ada.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.Equal(t, "v1", actual.SchemaID)
+		assert.JSONEq(t, `{"name":"Ada Lovelace"}`, string(actual.Traits))
+	})
+
+	t.Run("case=migrates identities in batches", func(t *testing.T) {
+		var batches int
+		report, err := identity.MigrateSchema(ctx, reg, m, identity.SchemaMigrationOptions{BatchSize: 1}, func(identity.SchemaMigrationReport) {
+			batches++
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, batches)
+		assert.Equal(t, 2, report.Scanned)
+		assert.Equal(t, 1, report.Migrated)
+		require.Len(t, report.Failures, 1)
+		assert.Equal(t, mononym.ID, report.Failures[0].IdentityID)
+		assert.Empty(t, report.NextPageToken)
+
+		actual, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, ada.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.Equal(t, "v2", actual.SchemaID)
+		assert.JSONEq(t, `{"name":{"first":"Ada","last":"Lovelace"}}`, string(actual.Traits))
+		assert.JSONEq(t, `{"migrated_from":"v1"}`, string(actual.MetadataAdmin))
+
+		actual, err = reg.PrivilegedIdentityPool().GetIdentity(ctx, mononym.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.Equal(t, "v1", actual.SchemaID)
+	})
+
+	t.Run("case=stops after the maximum number of batches", func(t *testing.T) {
+		create(t, "Grace Hopper")
+		create(t, "Alan Turing")
+
+		report, err := identity.MigrateSchema(ctx, reg, m, identity.SchemaMigrationOptions{BatchSize: 1, MaxBatches: 1}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Scanned)
+		assert.NotEmpty(t, report.NextPageToken)
+
+		report, err = identity.MigrateSchema(ctx, reg, m, identity.SchemaMigrationOptions{After: uuid.FromStringOrNil(report.NextPageToken)}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Scanned)
+		assert.Empty(t, report.NextPageToken)
+	})
+
+	t.Run("case=fails for unknown target schemas", func(t *testing.T) {
+		_, err := identity.MigrateSchema(ctx, reg, &config.IdentitySchemaMigration{ID: "unknown", From: "v1", To: "v3"}, identity.SchemaMigrationOptions{}, nil)
+		require.Error(t, err)
+	})
+}
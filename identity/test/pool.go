@@ -1288,6 +1288,67 @@ func TestPool(ctx context.Context, p persistence.Persister, m *identity.Manager,
 				assert.Len(t, is, len(filterIds))
 			})
 
+			t.Run("list some using filters", func(t *testing.T) {
+				_, p := testhelpers.NewNetwork(t, ctx, p)
+
+				create := func(t *testing.T, traits, metadataAdmin string, state identity.State) *identity.Identity {
+					i := identity.NewIdentity(defaultSchema.ID)
+					i.Traits = identity.Traits(traits)
+					i.MetadataAdmin = []byte(metadataAdmin)
+					i.State = state
+					require.NoError(t, p.CreateIdentity(ctx, i))
+					return i
+				}
+				ada := create(t, `{"email":"ada@example.org","bar":"analytical","phone":"+4917612345678"}`, `{"customer_id":"42","plan":{"seats":5}}`, identity.StateActive)
+				grace := create(t, `{"email":"grace@example.org","bar":"compiler"}`, `{"customer_id":42,"plan":{"seats":50}}`, identity.StateActive)
+				alan := create(t, `{"email":"alan@example.com","bar":"machine"}`, `{"customer_id":"7"}`, identity.StateInactive)
+
+				for _, tc := range []struct {
+					filters  []string
+					expected []*identity.Identity
+				}{
+					{filters: []string{"traits.email=ada@example.org"}, expected: []*identity.Identity{ada}},
+					{filters: []string{"metadata_admin.customer_id=42"}, expected: []*identity.Identity{ada, grace}},
+					{filters: []string{"metadata_admin.customer_id^=4"}, expected: []*identity.Identity{ada}},
+					{filters: []string{"traits.phone=+4917612345678"}, expected: []*identity.Identity{ada}},
+					{filters: []string{"traits.email^=a"}, expected: []*identity.Identity{ada, alan}},
+					{filters: []string{"traits.email^=%"}, expected: []*identity.Identity{}},
+					{filters: []string{"metadata_admin.plan.seats>=10"}, expected: []*identity.Identity{grace}},
+					{filters: []string{"metadata_admin.plan.seats<10"}, expected: []*identity.Identity{ada}},
+					{filters: []string{"traits.bar>compiler"}, expected: []*identity.Identity{alan}},
+					{filters: []string{"state=inactive"}, expected: []*identity.Identity{alan}},
+					{filters: []string{"schema_id=" + defaultSchema.ID, "traits.email^=grace"}, expected: []*identity.Identity{grace}},
+					{filters: []string{"created_at<2000-01-01T00:00:00Z"}, expected: []*identity.Identity{}},
+					{filters: []string{"created_at>=2000-01-01T00:00:00Z", "state=active"}, expected: []*identity.Identity{ada, grace}},
+				} {
+					t.Run("filter="+strings.Join(tc.filters, "&"), func(t *testing.T) {
+						params := identity.ListIdentityParameters{Expand: identity.ExpandNothing}
+						for _, expression := range tc.filters {
+							f, err := identity.ParseFilter(expression)
+							require.NoError(t, err)
+							params.Filters = append(params.Filters, *f)
+						}
+
+						is, _, err := p.ListIdentities(ctx, params)
+						require.NoError(t, err)
+
+						count, err := p.CountFilteredIdentities(ctx, params.Filters)
+						require.NoError(t, err)
+						assert.EqualValues(t, len(tc.expected), count)
+
+						actual := make([]uuid.UUID, len(is))
+						for k := range is {
+							actual[k] = is[k].ID
+						}
+						expected := make([]uuid.UUID, len(tc.expected))
+						for k := range tc.expected {
+							expected[k] = tc.expected[k].ID
+						}
+						assert.ElementsMatch(t, expected, actual)
+					})
+				}
+			})
+
 			t.Run("eventually consistent", func(t *testing.T) {
 				if dbname != "cockroach" {
 					t.Skipf("Test only works with cockroachdb")
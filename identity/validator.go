@@ -13,12 +13,14 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
+	"golang.org/x/text/unicode/norm"
 
 	"github.com/ory/herodot"
 	"github.com/ory/jsonschema/v3"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/x"
+	"github.com/ory/x/jsonnetsecure"
 	"github.com/ory/x/otelx"
 )
 
@@ -26,6 +28,7 @@ type (
 	validatorDependencies interface {
 		schema.IdentitySchemaProvider
 		config.Provider
+		jsonnetsecure.VMProvider
 	}
 	Validator struct {
 		v *schema.Validator
@@ -34,6 +37,12 @@ type (
 	ValidationProvider interface {
 		IdentityValidator() *Validator
 	}
+
+	// computedTrait is a trait whose value is computed by a Jsonnet snippet.
+	computedTrait struct {
+		path    string
+		snippet string
+	}
 )
 
 func NewValidator(d validatorDependencies) *Validator {
@@ -56,11 +65,23 @@ func (v *Validator) ValidateWithRunner(ctx context.Context, i *Identity, runners
 		return err
 	}
 
+	// Identities are validated against the schema version they are pinned
+	// to. New identities are pinned to the current version. Stored identities
+	// which are not pinned yet predate the versioning of the schema and are
+	// pinned to its oldest version.
+	if i.SchemaVersion != "" || !i.CreatedAt.IsZero() {
+		s, err = s.AtVersion(i.SchemaVersion)
+		if err != nil {
+			return err
+		}
+	}
+	i.SchemaVersion = s.Version
+
 	if len(i.Traits) == 0 {
 		i.Traits = []byte(`{}`)
 	}
 
-	if err := v.normalizePhoneTraits(ctx, i, s.URL.String()); err != nil {
+	if err := v.normalizeTraits(ctx, i, s.URL.String()); err != nil {
 		return err
 	}
 
@@ -75,15 +96,35 @@ func (v *Validator) ValidateWithRunner(ctx context.Context, i *Identity, runners
 	)
 }
 
-// normalizePhoneTraits rewrites trait values used as phone-channel
+// pinCurrentSchemaVersion pins the identity to the current version of its
+// identity schema.
+func (v *Validator) pinCurrentSchemaVersion(ctx context.Context, i *Identity) error {
+	ss, err := v.d.IdentityTraitsSchemas(ctx)
+	if err != nil {
+		return err
+	}
+
+	s, err := ss.GetByID(i.SchemaID)
+	if err != nil {
+		return err
+	}
+
+	i.SchemaVersion = s.Version
+	return nil
+}
+
+// normalizeTraits applies the `normalize` keywords of the identity schema
+// extension to the traits, rewrites trait values used as phone-channel
 // identifiers (code+sms credential identifiers, recovery via sms, or
 // verification via sms) into the E.164 form Kratos stores in its side
-// tables. Without this, webhook payloads templated against
-// `identity.traits` see the raw user input while Kratos keys on the
-// normalized identifier, producing inconsistent values across systems.
+// tables, and finally sets the traits marked as `computed`. Without the
+// phone rewrite, webhook payloads templated against `identity.traits`
+// see the raw user input while Kratos keys on the normalized identifier,
+// producing inconsistent values across systems.
 //
 // Email-channel identifiers preserve case per the regression test for
-// https://github.com/ory/kratos/issues/3187.
+// https://github.com/ory/kratos/issues/3187, unless the schema asks for
+// `lowercase` normalization explicitly.
 //
 // Schema-load and compile errors are swallowed; the subsequent
 // `v.v.Validate` call uses the same loader and compiler wiring and
@@ -95,10 +136,10 @@ func (v *Validator) ValidateWithRunner(ctx context.Context, i *Identity, runners
 // scratch because github.com/ory/jsonschema/v3 does not expose the JSON
 // pointer of the value being validated to extension hooks. Once we move
 // to santhosh-tekuri/jsonschema/v6, which provides
-// ValidatorContext.ValueLocation(), this and walkPhoneTraits collapse
+// ValidatorContext.ValueLocation(), this and walkTraits collapse
 // into a single ValidateExtension that writes back to i.Traits inline.
 // Tracking: ~/.claude/docs/cloud/plans/jsonschema-v6-migration.md.
-func (v *Validator) normalizePhoneTraits(ctx context.Context, i *Identity, schemaURL string) error {
+func (v *Validator) normalizeTraits(ctx context.Context, i *Identity, schemaURL string) error {
 	swallow := func(err error) error {
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 			return err
@@ -112,7 +153,7 @@ func (v *Validator) normalizePhoneTraits(ctx context.Context, i *Identity, schem
 		return swallow(err)
 	}
 	// Register populates each compiled *Schema's Extensions map with the
-	// parsed *ExtensionConfig that walkPhoneTraits reads. Without this
+	// parsed *ExtensionConfig that walkTraits reads. Without this
 	// call, the walk sees nil extensions and never normalizes.
 	runner, err := schema.NewExtensionRunner(ctx)
 	if err != nil {
@@ -125,12 +166,46 @@ func (v *Validator) normalizePhoneTraits(ctx context.Context, i *Identity, schem
 		return swallow(err)
 	}
 
-	i.Traits = walkPhoneTraits(i.Traits, compiled.Properties["traits"], "", map[*jsonschema.Schema]struct{}{})
+	var computed []computedTrait
+	i.Traits = walkTraits(i.Traits, compiled.Properties["traits"], "", map[*jsonschema.Schema]struct{}{}, &computed)
+	return v.computeTraits(ctx, i, computed)
+}
+
+// computeTraits evaluates the Jsonnet snippets of computed traits in schema
+// order and sets their results. A snippet sees the normalized traits,
+// including the results of the computed traits evaluated before it.
+// Returning null removes the trait.
+func (v *Validator) computeTraits(ctx context.Context, i *Identity, computed []computedTrait) error {
+	for _, c := range computed {
+		vm, err := v.d.JsonnetVM(ctx)
+		if err != nil {
+			return err
+		}
+		vm.ExtCode("traits", string(i.Traits))
+
+		evaluated, err := vm.EvaluateAnonymousSnippet(c.path, c.snippet)
+		if err != nil {
+			return errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to compute trait %s: %s", c.path, err))
+		}
+
+		var updated []byte
+		if result := gjson.Parse(evaluated); result.Type == gjson.Null {
+			updated, err = sjson.DeleteBytes(i.Traits, c.path)
+		} else {
+			updated, err = sjson.SetRawBytes(i.Traits, c.path, []byte(result.Raw))
+		}
+		if err != nil {
+			return errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to set computed trait %s: %s", c.path, err))
+		}
+		i.Traits = updated
+	}
 	return nil
 }
 
-// walkPhoneTraits traverses the compiled schema tree and rewrites every
-// trait leaf marked as a phone-channel identifier to its E.164 form.
+// walkTraits traverses the compiled schema tree, applies the `normalize`
+// keywords to every trait leaf, rewrites every trait leaf marked as a
+// phone-channel identifier to its E.164 form, and collects the computed
+// traits.
 //
 // Properties extend the path with the property name; array items extend
 // it with the index. Combinators (allOf, anyOf, oneOf, if/then/else,
@@ -146,7 +221,7 @@ func (v *Validator) normalizePhoneTraits(ctx context.Context, i *Identity, schem
 // schema/prevalidate.go, but cycles with intermediate validation
 // (a $ref back to an ancestor that has `properties`) reach the walk
 // and need this guard.
-func walkPhoneTraits(traits Traits, node *jsonschema.Schema, path string, stack map[*jsonschema.Schema]struct{}) Traits {
+func walkTraits(traits Traits, node *jsonschema.Schema, path string, stack map[*jsonschema.Schema]struct{}, computed *[]computedTrait) Traits {
 	if node == nil {
 		return traits
 	}
@@ -156,14 +231,20 @@ func walkPhoneTraits(traits Traits, node *jsonschema.Schema, path string, stack
 	stack[node] = struct{}{}
 	defer delete(stack, node)
 
-	if isPhoneIdentifier(node) {
-		return normalizePhoneAt(traits, path)
+	if cfg, _ := node.Extensions[schema.ExtensionName].(*schema.ExtensionConfig); cfg != nil {
+		if cfg.Computed != "" && path != "" {
+			*computed = append(*computed, computedTrait{path: path, snippet: cfg.Computed})
+		}
+		traits = normalizeTraitAt(traits, path, cfg.Normalize)
+		if isPhoneIdentifier(cfg) {
+			return normalizePhoneAt(traits, path)
+		}
 	}
 
 	// Combinators evaluated at the same path.
 	for _, sub := range slices.Concat(node.AllOf, node.AnyOf, node.OneOf,
 		[]*jsonschema.Schema{node.Not, node.If, node.Then, node.Else, node.Ref}) {
-		traits = walkPhoneTraits(traits, sub, path, stack)
+		traits = walkTraits(traits, sub, path, stack, computed)
 	}
 
 	// Properties extend the path. Iterate in sorted order so the
@@ -173,7 +254,7 @@ func walkPhoneTraits(traits Traits, node *jsonschema.Schema, path string, stack
 		if path != "" {
 			next = path + "." + next
 		}
-		traits = walkPhoneTraits(traits, node.Properties[name], next, stack)
+		traits = walkTraits(traits, node.Properties[name], next, stack, computed)
 	}
 
 	// Array items. `Items` is nil, *Schema (homogeneous — every element
@@ -189,7 +270,7 @@ func walkPhoneTraits(traits Traits, node *jsonschema.Schema, path string, stack
 			if path != "" {
 				next = path + "." + next
 			}
-			traits = walkPhoneTraits(traits, items, next, stack)
+			traits = walkTraits(traits, items, next, stack, computed)
 			index++
 			return true
 		})
@@ -199,12 +280,49 @@ func walkPhoneTraits(traits Traits, node *jsonschema.Schema, path string, stack
 			if path != "" {
 				next = path + "." + next
 			}
-			traits = walkPhoneTraits(traits, item, next, stack)
+			traits = walkTraits(traits, item, next, stack, computed)
 		}
 	}
 	return traits
 }
 
+// normalizeTraitAt applies the given normalizations, in order, to the
+// string trait at `path`. Unknown normalizations are rejected by the
+// identity extension meta schema. Values which are not valid phone numbers
+// are left unchanged by `e164` so that validation reports them.
+func normalizeTraitAt(traits Traits, path string, normalizations []string) Traits {
+	if len(normalizations) == 0 || path == "" {
+		return traits
+	}
+	value := gjson.GetBytes(traits, path)
+	if value.Type != gjson.String {
+		return traits
+	}
+
+	normalized := value.String()
+	for _, n := range normalizations {
+		switch n {
+		case "trim":
+			normalized = strings.TrimSpace(normalized)
+		case "lowercase":
+			normalized = strings.ToLower(normalized)
+		case "nfkc":
+			normalized = norm.NFKC.String(normalized)
+		case "e164":
+			if number, err := x.NormalizeIdentifier(normalized, "sms"); err == nil {
+				normalized = number
+			}
+		}
+	}
+	if normalized == value.String() {
+		return traits
+	}
+	if updated, err := sjson.SetBytes(traits, path, normalized); err == nil {
+		return updated
+	}
+	return traits
+}
+
 // normalizePhoneAt rewrites the string trait at `path` to its E.164
 // form when present and parseable. It returns the input unchanged on
 // any miss so the caller can chain it through a walk.
@@ -244,11 +362,7 @@ func escapePathSegment(segment string) string {
 
 // isPhoneIdentifier reports whether any identity-extension hook would
 // normalize the value at this schema node as an E.164 phone number.
-func isPhoneIdentifier(node *jsonschema.Schema) bool {
-	cfg, _ := node.Extensions[schema.ExtensionName].(*schema.ExtensionConfig)
-	if cfg == nil {
-		return false
-	}
+func isPhoneIdentifier(cfg *schema.ExtensionConfig) bool {
 	return (cfg.Credentials.Code.Identifier && cfg.Credentials.Code.Via == "sms") ||
 		cfg.Recovery.Via == "sms" ||
 		cfg.Verification.Via == "sms"
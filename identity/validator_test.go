@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -342,7 +343,7 @@ func TestSchemaValidatorNormalizesIdentifierTraits(t *testing.T) {
 	router.HandleFunc("GET /schema/code-sms-recursive", func(w http.ResponseWriter, _ *http.Request) {
 		// Self-referential schema: `Person` has a `spouse` property
 		// pointing back to `Person`. Without the recursion-stack guard
-		// in walkPhoneTraits, the walk would loop forever; with it the
+		// in walkTraits, the walk would loop forever; with it the
 		// walk visits `phone` and `spouse.phone`, then stops.
 		_, _ = w.Write([]byte(`{
   "$id": "https://example.com/code-sms-recursive.schema.json",
@@ -501,7 +502,7 @@ func TestSchemaValidatorNormalizesIdentifierTraits(t *testing.T) {
 		},
 		{
 			// Cycle guard: a self-referential schema (Person.spouse
-			// → Person) must not send walkPhoneTraits into infinite
+			// → Person) must not send walkTraits into infinite
 			// recursion. The top-level phone still normalizes; the
 			// schema walk hits the spouse → Person cycle and
 			// short-circuits without exploding the stack. A hang or
@@ -564,3 +565,102 @@ func TestSchemaValidatorNormalizesIdentifierTraits(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	_, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValue(config.ViperKeyDefaultIdentitySchemaID, "default"),
+		configx.WithValue(config.ViperKeyIdentitySchemas, config.Schemas{{
+			ID:       "default",
+			URL:      "file://./stub/versioned/v2.schema.json",
+			Version:  "v2",
+			Versions: []config.SchemaVersion{{Version: "v1", URL: "file://./stub/versioned/v1.schema.json"}},
+		}}),
+	)
+	v := NewValidator(reg)
+	ctx := t.Context()
+
+	t.Run("case=pins stored unpinned identities to the oldest version", func(t *testing.T) {
+		i := &Identity{SchemaID: "default", Traits: Traits(`{"email":"foo@ory.sh"}`), CreatedAt: time.Now()}
+		require.NoError(t, v.Validate(ctx, i))
+		assert.Equal(t, "v1", i.SchemaVersion)
+	})
+
+	t.Run("case=pins new identities to the current version", func(t *testing.T) {
+		i := &Identity{SchemaID: "default", Traits: Traits(`{"email":"` + testhelpers.RandomEmail() + `"}`)}
+		require.Error(t, reg.IdentityManager().Create(ctx, i))
+		assert.Equal(t, "v2", i.SchemaVersion)
+
+		i = &Identity{SchemaID: "default", Traits: Traits(`{"email":"` + testhelpers.RandomEmail() + `","name":"Foo"}`)}
+		require.NoError(t, reg.IdentityManager().Create(ctx, i))
+		assert.Equal(t, "v2", i.SchemaVersion)
+	})
+
+	t.Run("case=validates against the pinned version", func(t *testing.T) {
+		i := &Identity{SchemaID: "default", SchemaVersion: "v1", Traits: Traits(`{"email":"foo@ory.sh"}`)}
+		require.NoError(t, v.Validate(ctx, i))
+		assert.Equal(t, "v1", i.SchemaVersion)
+
+		i.Traits = Traits(`{}`)
+		require.Error(t, v.Validate(ctx, i))
+	})
+
+	t.Run("case=fails for unknown versions", func(t *testing.T) {
+		i := &Identity{SchemaID: "default", SchemaVersion: "v0", Traits: Traits(`{"email":"foo@ory.sh","name":"Foo"}`)}
+		err := v.Validate(ctx, i)
+		var herr *herodot.DefaultError
+		require.ErrorAs(t, err, &herr)
+		assert.Equal(t, http.StatusNotFound, herr.CodeField)
+		assert.Contains(t, herr.Reason(), `"v0"`)
+	})
+}
+
+func TestValidatorNormalizedAndComputedTraits(t *testing.T) {
+	t.Parallel()
+
+	_, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.IdentitySchemasConfig(map[string]string{
+			"default": "file://./stub/computed/identity.schema.json",
+		})),
+	)
+	v := NewValidator(reg)
+
+	for _, tc := range []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "normalizes traits",
+			input:    `{"email":"  Foo@Ory.SH ","phone":"+49 176 671 11 638","given_name":" ｆｏｏ"}`,
+			expected: `{"email":"foo@ory.sh","phone":"+4917667111638","given_name":"foo"}`,
+		},
+		{
+			name:     "keeps invalid phone numbers for validation",
+			input:    `{"email":"foo@ory.sh","phone":"not a number"}`,
+			expected: `{"email":"foo@ory.sh","phone":"not a number"}`,
+		},
+		{
+			name:     "computes traits from normalized traits",
+			input:    `{"email":"foo@ory.sh","given_name":" Foo ","family_name":" Bar "}`,
+			expected: `{"email":"foo@ory.sh","given_name":"Foo","family_name":"Bar","name":"Foo Bar"}`,
+		},
+		{
+			name:     "overwrites submitted computed traits",
+			input:    `{"email":"foo@ory.sh","given_name":"Foo","family_name":"Bar","name":"Mallory"}`,
+			expected: `{"email":"foo@ory.sh","given_name":"Foo","family_name":"Bar","name":"Foo Bar"}`,
+		},
+		{
+			name:     "removes computed traits which evaluate to null",
+			input:    `{"email":"foo@ory.sh","given_name":"Foo","name":"Mallory"}`,
+			expected: `{"email":"foo@ory.sh","given_name":"Foo"}`,
+		},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			i := &Identity{SchemaID: "default", Traits: Traits(tc.input)}
+			require.NoError(t, v.Validate(t.Context(), i))
+			assert.JSONEq(t, tc.expected, string(i.Traits))
+		})
+	}
+}
@@ -0,0 +1,909 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package organization
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/cipher"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/httprouterx"
+	"github.com/ory/x/httpx"
+	"github.com/ory/x/jsonx"
+	"github.com/ory/x/pagination/keysetpagination"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+	"github.com/ory/x/urlx"
+)
+
+const (
+	RouteCollection  = "/organizations"
+	RouteItem        = RouteCollection + "/{id}"
+	RouteDomains     = RouteItem + "/domains"
+	RouteDomain      = RouteDomains + "/{domain}"
+	RouteVerify      = RouteDomain + "/verify"
+	RouteSSOProvider = RouteItem + "/sso-providers/{provider}"
+	RouteIdentities  = RouteItem + "/identities"
+	RouteIdentity    = RouteIdentities + "/{identity}"
+)
+
+type (
+	handlerDependencies interface {
+		config.Provider
+		httpx.WriterProvider
+		cipher.Provider
+		identity.PrivilegedPoolProvider
+		PersistenceProvider
+		ManagementProvider
+	}
+	Handler struct {
+		r handlerDependencies
+	}
+	HandlerProvider interface {
+		OrganizationHandler() *Handler
+	}
+)
+
+func NewHandler(r handlerDependencies) *Handler {
+	return &Handler{r: r}
+}
+
+func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
+	admin.GET(RouteCollection, h.list)
+	admin.POST(RouteCollection, h.create)
+	admin.GET(RouteItem, h.get)
+	admin.PUT(RouteItem, h.update)
+	admin.DELETE(RouteItem, h.delete)
+
+	admin.POST(RouteDomains, h.addDomain)
+	admin.POST(RouteVerify, h.verifyDomain)
+	admin.DELETE(RouteDomain, h.deleteDomain)
+
+	admin.PUT(RouteSSOProvider, h.bindProvider)
+	admin.DELETE(RouteSSOProvider, h.unbindProvider)
+
+	admin.GET(RouteIdentities, h.listIdentities)
+	admin.PUT(RouteIdentity, h.addIdentity)
+	admin.DELETE(RouteIdentity, h.removeIdentity)
+}
+
+func (h *Handler) organizationID(r *http.Request) (uuid.UUID, error) {
+	id, err := uuid.FromString(r.PathValue("id"))
+	if err != nil {
+		return uuid.Nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Invalid UUID value `%s` for the organization ID.", r.PathValue("id")))
+	}
+	return id, nil
+}
+
+// managedOrganization returns the organization in the path if it is managed
+// through the admin API.
+func (h *Handler) managedOrganization(r *http.Request) (*Organization, error) {
+	id, err := h.organizationID(r)
+	if err != nil {
+		return nil, err
+	}
+	return h.r.OrganizationManager().GetManaged(r.Context(), id)
+}
+
+// swagger:route GET /admin/organizations identity listOrganizations
+//
+// # List Organizations
+//
+// Lists the organizations defined in the configuration file followed by the organizations managed through the
+// admin API.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: listOrganizations
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	orgs, err := h.r.OrganizationManager().List(r.Context())
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	h.r.Writer().Write(w, r, orgs)
+}
+
+// List Organizations Response
+//
+// swagger:response listOrganizations
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listOrganizationsResponse struct {
+	// in: body
+	Body []Organization
+}
+
+// Create Organization Request Body
+//
+// swagger:model createOrganizationBody
+type CreateOrganizationBody struct {
+	// ID is the organization's ID. If not set, a random ID is assigned.
+	ID uuid.UUID `json:"id"`
+
+	// Label is a human-readable name of the organization.
+	Label string `json:"label"`
+
+	// SessionLifespan overrides the lifespan of sessions issued to the
+	// organization's identities.
+	SessionLifespan sqlxx.NullDuration `json:"session_lifespan"`
+
+	// SCIM configures SCIM provisioning of the organization's identities.
+	SCIM *SCIM `json:"scim"`
+}
+
+// Create Organization Parameters
+//
+// swagger:parameters createOrganization
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type createOrganization struct {
+	// in: body
+	// required: true
+	Body CreateOrganizationBody
+}
+
+// swagger:route POST /admin/organizations identity createOrganization
+//
+// # Create an Organization
+//
+// Creates an organization. Its domains have to be added and verified before they are used to match identities.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  201: organization
+//	  400: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body CreateOrganizationBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithError(err.Error())))
+		return
+	}
+	if _, ok := h.r.Config().Organization(ctx, body.ID); ok {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrConflict().WithReasonf("The organization %q is defined in the configuration file.", body.ID)))
+		return
+	}
+
+	if err := validateSessionLifespan(body.SessionLifespan); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	org := &Organization{ID: body.ID, Label: body.Label, Source: SourceAPI, SessionLifespan: body.SessionLifespan}
+	if err := org.SetSCIM(body.SCIM); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	if err := h.r.OrganizationPersister().CreateOrganization(ctx, org); err != nil {
+		if errors.Is(err, sqlcon.ErrUniqueViolation()) {
+			err = errors.WithStack(herodot.ErrConflict().WithReasonf("The organization %q already exists.", body.ID))
+		}
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	org.SSOProviders = []string{}
+
+	h.r.Writer().WriteCreated(w, r,
+		urlx.AppendPaths(h.r.Config().SelfAdminURL(ctx), "organizations", org.ID.String()).String(),
+		org,
+	)
+}
+
+func validateSessionLifespan(lifespan sqlxx.NullDuration) error {
+	if lifespan.Valid && lifespan.Duration <= 0 {
+		return errors.WithStack(herodot.ErrBadRequest().WithReasonf("The session lifespan must be positive, got %s.", lifespan.Duration))
+	}
+	return nil
+}
+
+// Get Organization Parameters
+//
+// swagger:parameters getOrganization deleteOrganization listOrganizationIdentities
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getOrganization struct {
+	// ID is the organization's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route GET /admin/organizations/{id} identity getOrganization
+//
+// # Get an Organization
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: organization
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	id, err := h.organizationID(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	org, err := h.r.OrganizationManager().Get(r.Context(), id)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	h.r.Writer().Write(w, r, org)
+}
+
+// Update Organization Request Body
+//
+// swagger:model updateOrganizationBody
+type UpdateOrganizationBody struct {
+	// Label is a human-readable name of the organization.
+	Label string `json:"label"`
+
+	// SessionLifespan overrides the lifespan of sessions issued to the
+	// organization's identities.
+	SessionLifespan sqlxx.NullDuration `json:"session_lifespan"`
+
+	// SCIM configures SCIM provisioning of the organization's identities.
+	// If the bearer tokens are not set, the current ones are kept. If not
+	// set, SCIM provisioning is disabled.
+	SCIM *SCIM `json:"scim"`
+}
+
+// Update Organization Parameters
+//
+// swagger:parameters updateOrganization
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type updateOrganization struct {
+	// ID is the organization's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// in: body
+	// required: true
+	Body UpdateOrganizationBody
+}
+
+// swagger:route PUT /admin/organizations/{id} identity updateOrganization
+//
+// # Update an Organization
+//
+// Updates an organization managed through the admin API. Organizations defined in the configuration file can not
+// be updated.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: organization
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
+	var body UpdateOrganizationBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithError(err.Error())))
+		return
+	}
+
+	if err := validateSessionLifespan(body.SessionLifespan); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	org, err := h.managedOrganization(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	org.Label = body.Label
+	org.SessionLifespan = body.SessionLifespan
+	if err := org.SetSCIM(body.SCIM); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	if err := h.r.OrganizationPersister().UpdateOrganization(r.Context(), org); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	h.r.Writer().Write(w, r, org)
+}
+
+// swagger:route DELETE /admin/organizations/{id} identity deleteOrganization
+//
+// # Delete an Organization
+//
+// Deletes an organization managed through the admin API together with its domains. The organization must not have
+// any identities or bound sign in providers.
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, err := h.managedOrganization(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if len(org.SSOProviders) > 0 {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrConflict().WithReasonf("The organization still has bound sign in providers: %v", org.SSOProviders)))
+		return
+	}
+	members, _, err := h.r.PrivilegedIdentityPool().ListIdentities(ctx, identity.ListIdentityParameters{
+		Expand:           identity.ExpandNothing,
+		OrganizationID:   org.ID,
+		KeySetPagination: []keysetpagination.Option{keysetpagination.WithSize(1)},
+	})
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	if len(members) > 0 {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrConflict().WithReason("The organization still has identities.")))
+		return
+	}
+
+	if err := h.r.OrganizationPersister().DeleteOrganization(ctx, org.ID); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Add Organization Domain Request Body
+//
+// swagger:model addOrganizationDomainBody
+type AddDomainBody struct {
+	// Domain is the domain name to claim, for example `example.org`.
+	//
+	// required: true
+	Domain string `json:"domain"`
+}
+
+// Add Organization Domain Parameters
+//
+// swagger:parameters addOrganizationDomain
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type addOrganizationDomain struct {
+	// ID is the organization's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// in: body
+	// required: true
+	Body AddDomainBody
+}
+
+// swagger:route POST /admin/organizations/{id}/domains identity addOrganizationDomain
+//
+// # Add a Domain to an Organization
+//
+// Claims a domain for an organization managed through the admin API. The response contains the DNS TXT record
+// which must be published to verify the ownership of the domain.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  201: organizationDomain
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) addDomain(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body AddDomainBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithError(err.Error())))
+		return
+	}
+
+	org, err := h.managedOrganization(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	d, err := h.r.OrganizationManager().AddDomain(ctx, org, body.Domain)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().WriteCreated(w, r,
+		urlx.AppendPaths(h.r.Config().SelfAdminURL(ctx), "organizations", org.ID.String(), "domains", d.Domain).String(),
+		d,
+	)
+}
+
+// Organization Domain Parameters
+//
+// swagger:parameters verifyOrganizationDomain deleteOrganizationDomain
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type organizationDomain struct {
+	// ID is the organization's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// Domain is the claimed domain.
+	//
+	// required: true
+	// in: path
+	Domain string `json:"domain"`
+}
+
+// swagger:route POST /admin/organizations/{id}/domains/{domain}/verify identity verifyOrganizationDomain
+//
+// # Verify an Organization's Domain
+//
+// Verifies the ownership of the domain by looking up its DNS TXT challenge record. Once verified, the domain is
+// used to match identities to the organization.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: organizationDomain
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) verifyDomain(w http.ResponseWriter, r *http.Request) {
+	org, err := h.managedOrganization(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	d, err := h.r.OrganizationManager().VerifyDomain(r.Context(), org, r.PathValue("domain"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	h.r.Writer().Write(w, r, d)
+}
+
+// swagger:route DELETE /admin/organizations/{id}/domains/{domain} identity deleteOrganizationDomain
+//
+// # Remove a Domain from an Organization
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) deleteDomain(w http.ResponseWriter, r *http.Request) {
+	org, err := h.managedOrganization(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	domain, err := NormalizeDomain(r.PathValue("domain"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	if err := h.r.OrganizationPersister().DeleteOrganizationDomain(r.Context(), org.ID, domain); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Organization SSO Provider Parameters
+//
+// swagger:parameters bindOrganizationSsoProvider unbindOrganizationSsoProvider
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type organizationSSOProvider struct {
+	// ID is the organization's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// Provider is the ID of the sign in provider.
+	//
+	// required: true
+	// in: path
+	Provider string `json:"provider"`
+}
+
+// swagger:route PUT /admin/organizations/{id}/sso-providers/{provider} identity bindOrganizationSsoProvider
+//
+// # Bind a Sign In Provider to an Organization
+//
+// Binds a sign in provider managed through the admin API, for example an OpenID Connect provider, to the
+// organization. A provider can only be bound to one organization; binding it again moves it. Providers defined in
+// the configuration file can not be bound.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: organization
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) bindProvider(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := h.organizationID(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	if _, err := h.r.OrganizationManager().Get(ctx, id); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.r.OrganizationManager().BindProvider(ctx, r.PathValue("provider"), uuid.NullUUID{UUID: id, Valid: true}); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	org, err := h.r.OrganizationManager().Get(ctx, id)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	h.r.Writer().Write(w, r, org)
+}
+
+// swagger:route DELETE /admin/organizations/{id}/sso-providers/{provider} identity unbindOrganizationSsoProvider
+//
+// # Unbind a Sign In Provider from an Organization
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) unbindProvider(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := h.organizationID(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	org, err := h.r.OrganizationManager().Get(ctx, id)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	provider := r.PathValue("provider")
+	if !slices.Contains(org.SSOProviders, provider) {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrNotFound().WithReasonf("The provider %q is not bound to the organization.", provider)))
+		return
+	}
+	if err := h.r.OrganizationManager().BindProvider(ctx, provider, uuid.NullUUID{}); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List Organization Identities Parameters
+//
+// swagger:parameters listOrganizationIdentities
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listOrganizationIdentities struct {
+	keysetpagination.RequestParameters
+}
+
+// swagger:route GET /admin/organizations/{id}/identities identity listOrganizationIdentities
+//
+// # List an Organization's Identities
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: listIdentities
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-medium
+func (h *Handler) listIdentities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := h.organizationID(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	if _, err := h.r.OrganizationManager().Get(ctx, id); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	pagination, err := keysetpagination.Parse(r.URL.Query(), keysetpagination.NewStringPageToken)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReason(err.Error())))
+		return
+	}
+
+	is, nextPage, err := h.r.PrivilegedIdentityPool().ListIdentities(ctx, identity.ListIdentityParameters{
+		Expand:           identity.ExpandDefault,
+		OrganizationID:   id,
+		KeySetPagination: pagination,
+	})
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if nextPage != nil {
+		u := *r.URL
+		keysetpagination.Header(w, &u, nextPage)
+	}
+
+	members := make([]identity.WithCredentialsAndAdminMetadataInJSON, len(is))
+	for k := range is {
+		emit, err := is[k].WithDeclassifiedCredentials(ctx, h.r, nil)
+		if err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+		members[k] = identity.WithCredentialsAndAdminMetadataInJSON(*emit)
+	}
+	h.r.Writer().Write(w, r, members)
+}
+
+// Organization Identity Parameters
+//
+// swagger:parameters addOrganizationIdentity removeOrganizationIdentity
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type organizationIdentity struct {
+	// ID is the organization's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// Identity is the identity's ID.
+	//
+	// required: true
+	// in: path
+	Identity string `json:"identity"`
+}
+
+// swagger:route PUT /admin/organizations/{id}/identities/{identity} identity addOrganizationIdentity
+//
+// # Add an Identity to an Organization
+//
+// Adds the identity to the organization. If the identity belongs to another organization, it is moved.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identity
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) addIdentity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := h.organizationID(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	if _, err := h.r.OrganizationManager().Get(ctx, id); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	i, err := h.identity(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	i.OrganizationID = uuid.NullUUID{UUID: id, Valid: true}
+	if err := h.r.PrivilegedIdentityPool().UpdateIdentityColumns(ctx, i, "organization_id"); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	h.r.Writer().Write(w, r, identity.WithCredentialsNoConfigAndAdminMetadataInJSON(*i))
+}
+
+// swagger:route DELETE /admin/organizations/{id}/identities/{identity} identity removeOrganizationIdentity
+//
+// # Remove an Identity from an Organization
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) removeIdentity(w http.ResponseWriter, r *http.Request) {
+	id, err := h.organizationID(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	i, err := h.identity(r)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	if !i.OrganizationID.Valid || i.OrganizationID.UUID != id {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrNotFound().WithReason("The identity does not belong to the organization.")))
+		return
+	}
+
+	i.OrganizationID = uuid.NullUUID{}
+	if err := h.r.PrivilegedIdentityPool().UpdateIdentityColumns(r.Context(), i, "organization_id"); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) identity(r *http.Request) (*identity.Identity, error) {
+	id, err := uuid.FromString(r.PathValue("identity"))
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Invalid UUID value `%s` for the identity ID.", r.PathValue("identity")))
+	}
+	return h.r.PrivilegedIdentityPool().GetIdentity(r.Context(), id, identity.ExpandNothing)
+}
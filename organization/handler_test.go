@@ -0,0 +1,316 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package organization_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/sqlcon"
+)
+
+type stubResolver struct {
+	sync.Mutex
+	records map[string][]string
+}
+
+func (r *stubResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	r.Lock()
+	defer r.Unlock()
+	records, ok := r.records[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func (r *stubResolver) set(name string, records ...string) {
+	r.Lock()
+	defer r.Unlock()
+	r.records[name] = records
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	configuredOrg := uuid.Must(uuid.NewV4())
+	_, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+		configx.WithValues(map[string]any{
+			config.ViperKeyOrganizations: []map[string]any{
+				{"id": configuredOrg.String(), "label": "Configured", "domains": []string{"configured.example.com"}},
+			},
+			fmt.Sprintf("%s.%s.enabled", config.ViperKeySelfServiceStrategyConfig, identity.CredentialsTypeOIDC): true,
+			fmt.Sprintf("%s.%s.config", config.ViperKeySelfServiceStrategyConfig, identity.CredentialsTypeOIDC): map[string]any{
+				"providers": []map[string]any{{
+					"id":              "configured",
+					"provider":        "github",
+					"client_id":       "client",
+					"mapper_url":      "file://./stub/oidc.jsonnet",
+					"organization_id": configuredOrg.String(),
+				}},
+			},
+		}),
+	)
+	resolver := &stubResolver{records: map[string][]string{}}
+	reg.WithOrganizationDomainResolver(resolver)
+	_, admin := testhelpers.NewKratosServer(t, reg)
+
+	do := func(t *testing.T, method, path string, body any) (*http.Response, []byte) {
+		var payload io.Reader
+		if body != nil {
+			raw, err := json.Marshal(body)
+			require.NoError(t, err)
+			payload = bytes.NewReader(raw)
+		}
+		req, err := http.NewRequestWithContext(t.Context(), method, admin.URL+"/admin"+path, payload)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := admin.Client().Do(req)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		raw, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, raw
+	}
+
+	t.Run("case=configured organizations are read-only", func(t *testing.T) {
+		res, body := do(t, http.MethodGet, "/organizations/"+configuredOrg.String(), nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, organization.SourceConfig, gjson.GetBytes(body, "source").String())
+		assert.Equal(t, "Configured", gjson.GetBytes(body, "label").String())
+		assert.True(t, gjson.GetBytes(body, "domains.0.verified").Bool())
+		assert.Equal(t, `["configured"]`, gjson.GetBytes(body, "sso_providers").Raw)
+
+		res, body = do(t, http.MethodPut, "/organizations/"+configuredOrg.String(), map[string]any{"label": "changed"})
+		assert.Equal(t, http.StatusConflict, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodPost, "/organizations", map[string]any{"id": configuredOrg})
+		assert.Equal(t, http.StatusConflict, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodPut, "/organizations/"+configuredOrg.String()+"/sso-providers/configured", nil)
+		assert.Equal(t, http.StatusConflict, res.StatusCode, "%s", body)
+	})
+
+	var orgID string
+	t.Run("case=manages organizations", func(t *testing.T) {
+		res, body := do(t, http.MethodPost, "/organizations", map[string]any{"label": "Acme"})
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", body)
+		orgID = gjson.GetBytes(body, "id").String()
+		assert.Equal(t, admin.URL+"/organizations/"+orgID, res.Header.Get("Location"))
+		assert.Equal(t, organization.SourceAPI, gjson.GetBytes(body, "source").String())
+
+		res, body = do(t, http.MethodPut, "/organizations/"+orgID, map[string]any{"label": "Acme Inc."})
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodGet, "/organizations", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, configuredOrg.String(), gjson.GetBytes(body, "0.id").String())
+		assert.Equal(t, "Acme Inc.", gjson.GetBytes(body, "1.label").String())
+
+		res, body = do(t, http.MethodGet, "/organizations/"+uuid.Must(uuid.NewV4()).String(), nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+	})
+
+	t.Run("case=stores session lifespan and SCIM settings", func(t *testing.T) {
+		ctx := t.Context()
+		res, body := do(t, http.MethodPost, "/organizations", map[string]any{"label": "Session", "session_lifespan": "0s"})
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodPost, "/organizations", map[string]any{
+			"label":            "Session",
+			"session_lifespan": "2h",
+			"scim":             map[string]any{"bearer_tokens": []string{"secret"}, "mapper_url": "file://./stub/scim.jsonnet"},
+		})
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", body)
+		assert.Equal(t, "2h0m0s", gjson.GetBytes(body, "session_lifespan").String())
+		assert.True(t, gjson.GetBytes(body, "scim.enabled").Bool())
+		assert.False(t, gjson.GetBytes(body, "scim.bearer_tokens").Exists())
+		id := uuid.FromStringOrNil(gjson.GetBytes(body, "id").String())
+
+		assert.Equal(t, 2*time.Hour, reg.OrganizationManager().OrganizationSessionLifespan(ctx, id))
+		assert.Equal(t, reg.Config().SessionLifespan(ctx), reg.OrganizationManager().OrganizationSessionLifespan(ctx, configuredOrg))
+		assert.Equal(t, reg.Config().SessionLifespan(ctx), reg.OrganizationManager().OrganizationSessionLifespan(ctx, uuid.Must(uuid.NewV4())))
+
+		org, ok, err := reg.OrganizationManager().AuthenticateSCIM(ctx, id, "secret")
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "file://./stub/scim.jsonnet", org.SCIM.MapperURL)
+		_, ok, err = reg.OrganizationManager().AuthenticateSCIM(ctx, id, "wrong")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		// Omitting the bearer tokens keeps the current ones.
+		res, body = do(t, http.MethodPut, "/organizations/"+id.String(), map[string]any{"label": "Session", "scim": map[string]any{"mapper_url": "file://./stub/other.jsonnet"}})
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.False(t, gjson.GetBytes(body, "session_lifespan").Exists())
+		assert.Equal(t, reg.Config().SessionLifespan(ctx), reg.OrganizationManager().OrganizationSessionLifespan(ctx, id))
+		_, ok, err = reg.OrganizationManager().AuthenticateSCIM(ctx, id, "secret")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		res, body = do(t, http.MethodPut, "/organizations/"+id.String(), map[string]any{"label": "Session"})
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.False(t, gjson.GetBytes(body, "scim").Exists())
+		_, ok, err = reg.OrganizationManager().AuthenticateSCIM(ctx, id, "secret")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		res, body = do(t, http.MethodDelete, "/organizations/"+id.String(), nil)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode, "%s", body)
+	})
+
+	t.Run("case=verifies domains", func(t *testing.T) {
+		res, body := do(t, http.MethodPost, "/organizations/"+orgID+"/domains", map[string]any{"domain": "configured.example.com"})
+		assert.Equal(t, http.StatusConflict, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodPost, "/organizations/"+orgID+"/domains", map[string]any{"domain": "not a domain"})
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodPost, "/organizations/"+orgID+"/domains", map[string]any{"domain": "Acme.Example.com"})
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", body)
+		assert.Equal(t, "acme.example.com", gjson.GetBytes(body, "domain").String())
+		assert.False(t, gjson.GetBytes(body, "verified").Bool())
+		name := gjson.GetBytes(body, "challenge.name").String()
+		value := gjson.GetBytes(body, "challenge.value").String()
+		assert.Equal(t, "_kratos-challenge.acme.example.com", name)
+
+		res, body = do(t, http.MethodPost, "/organizations/"+orgID+"/domains/acme.example.com/verify", nil)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+
+		orgs, err := reg.OrganizationManager().Organizations(t.Context())
+		require.NoError(t, err)
+		require.Len(t, orgs, 2)
+		assert.Empty(t, orgs[1].Domains)
+
+		resolver.set(name, "v=spf1 -all", value)
+		res, body = do(t, http.MethodPost, "/organizations/"+orgID+"/domains/acme.example.com/verify", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.True(t, gjson.GetBytes(body, "verified").Bool())
+		assert.False(t, gjson.GetBytes(body, "challenge").Exists())
+
+		orgs, err = reg.OrganizationManager().Organizations(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme.example.com"}, orgs[1].Domains)
+
+		found, err := reg.OrganizationManager().FindByDomain(t.Context(), "ACME.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, orgID, found.ID.String())
+		found, err = reg.OrganizationManager().FindByDomain(t.Context(), "configured.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, configuredOrg, found.ID)
+		_, err = reg.OrganizationManager().FindByDomain(t.Context(), "unknown.example.com")
+		require.ErrorIs(t, err, sqlcon.ErrNoRows())
+
+		res, body = do(t, http.MethodPost, "/organizations", map[string]any{"label": "Squatter"})
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", body)
+		squatter := gjson.GetBytes(body, "id").String()
+		res, body = do(t, http.MethodPost, "/organizations/"+squatter+"/domains", map[string]any{"domain": "acme.example.com"})
+		assert.Equal(t, http.StatusConflict, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodDelete, "/organizations/"+squatter, nil)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode, "%s", body)
+	})
+
+	t.Run("case=binds sign in providers", func(t *testing.T) {
+		res, body := do(t, http.MethodPost, "/oidc/providers", map[string]any{
+			"id":         "acme",
+			"provider":   "github",
+			"client_id":  "acme-client",
+			"mapper_url": "file://./stub/oidc.jsonnet",
+		})
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodPut, "/organizations/"+orgID+"/sso-providers/unknown", nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodPut, "/organizations/"+orgID+"/sso-providers/acme", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, `["acme"]`, gjson.GetBytes(body, "sso_providers").Raw)
+
+		res, body = do(t, http.MethodGet, "/oidc/providers/acme", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, orgID, gjson.GetBytes(body, "organization_id").String())
+
+		res, body = do(t, http.MethodDelete, "/organizations/"+orgID, nil)
+		assert.Equal(t, http.StatusConflict, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodDelete, "/organizations/"+configuredOrg.String()+"/sso-providers/acme", nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodDelete, "/organizations/"+orgID+"/sso-providers/acme", nil)
+		require.Equal(t, http.StatusNoContent, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodGet, "/organizations/"+orgID, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, `[]`, gjson.GetBytes(body, "sso_providers").Raw)
+	})
+
+	t.Run("case=manages members", func(t *testing.T) {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"email":"jane@acme.example.com"}`)
+		i.OrganizationID = uuid.NullUUID{UUID: configuredOrg, Valid: true}
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(t.Context(), i))
+
+		res, body := do(t, http.MethodGet, "/organizations/"+configuredOrg.String()+"/identities", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, i.ID.String(), gjson.GetBytes(body, "0.id").String())
+
+		res, body = do(t, http.MethodPut, "/organizations/"+orgID+"/identities/"+i.ID.String(), nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, orgID, gjson.GetBytes(body, "organization_id").String())
+
+		res, body = do(t, http.MethodGet, "/organizations/"+configuredOrg.String()+"/identities", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.EqualValues(t, 0, gjson.GetBytes(body, "#").Int(), "%s", body)
+
+		res, body = do(t, http.MethodGet, "/organizations/"+orgID+"/identities", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.EqualValues(t, 1, gjson.GetBytes(body, "#").Int())
+
+		res, body = do(t, http.MethodDelete, "/organizations/"+orgID, nil)
+		assert.Equal(t, http.StatusConflict, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodDelete, "/organizations/"+configuredOrg.String()+"/identities/"+i.ID.String(), nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodDelete, "/organizations/"+orgID+"/identities/"+i.ID.String(), nil)
+		require.Equal(t, http.StatusNoContent, res.StatusCode, "%s", body)
+
+		actual, err := reg.PrivilegedIdentityPool().GetIdentity(t.Context(), i.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.False(t, actual.OrganizationID.Valid)
+	})
+
+	t.Run("case=deletes organizations", func(t *testing.T) {
+		res, body := do(t, http.MethodDelete, "/organizations/"+orgID, nil)
+		require.Equal(t, http.StatusNoContent, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodGet, "/organizations/"+orgID, nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+
+		orgs, err := reg.OrganizationManager().Organizations(t.Context())
+		require.NoError(t, err)
+		assert.Len(t, orgs, 1)
+	})
+}
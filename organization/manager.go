@@ -0,0 +1,383 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package organization
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/x/logrusx"
+	"github.com/ory/x/randx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+)
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9-]{2,63}$`)
+
+type (
+	managerDependencies interface {
+		config.Provider
+		logrusx.Provider
+		PersistenceProvider
+		ResolverProvider
+		ProviderBinderProvider
+	}
+	Manager struct {
+		r managerDependencies
+	}
+	ManagementProvider interface {
+		OrganizationManager() *Manager
+	}
+)
+
+func NewManager(r managerDependencies) *Manager {
+	return &Manager{r: r}
+}
+
+// Organizations returns the organizations defined in the configuration file
+// followed by the ones managed through the admin API. Only verified domains of
+// the latter are included.
+func (m *Manager) Organizations(ctx context.Context) ([]config.Organization, error) {
+	orgs := m.r.Config().Organizations(ctx)
+
+	stored, err := m.r.OrganizationPersister().ListOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range stored {
+		if m.isConfigured(orgs, o.ID) {
+			continue
+		}
+		org := config.Organization{ID: o.ID, Label: o.Label, SessionLifespan: o.SessionLifespan.Duration}
+		if stored, err := o.storedSCIM(); err != nil {
+			return nil, err
+		} else if stored != nil {
+			org.SCIM = config.OrganizationSCIM{MapperURL: stored.MapperURL, SchemaID: stored.IdentitySchemaID}
+		}
+		for _, d := range o.Domains {
+			if !d.VerifiedAt.IsZero() {
+				org.Domains = append(org.Domains, d.Domain)
+			}
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, nil
+}
+
+// List returns all organizations.
+func (m *Manager) List(ctx context.Context) ([]Organization, error) {
+	configured := m.r.Config().Organizations(ctx)
+	orgs := make([]Organization, 0, len(configured))
+	for _, c := range configured {
+		orgs = append(orgs, fromConfig(c))
+	}
+
+	stored, err := m.r.OrganizationPersister().ListOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range stored {
+		if !m.isConfigured(configured, o.ID) {
+			orgs = append(orgs, o)
+		}
+	}
+
+	for k := range orgs {
+		if err := m.present(ctx, &orgs[k]); err != nil {
+			return nil, err
+		}
+	}
+	return orgs, nil
+}
+
+// Get returns the organization with the given ID.
+func (m *Manager) Get(ctx context.Context, id uuid.UUID) (*Organization, error) {
+	var org *Organization
+	if c, ok := m.r.Config().Organization(ctx, id); ok {
+		o := fromConfig(*c)
+		org = &o
+	} else {
+		var err error
+		if org, err = m.r.OrganizationPersister().GetOrganization(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.present(ctx, org); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// FindByDomain returns the organization the domain belongs to. Only verified
+// domains are considered. It runs on every sign in, so it looks up the domain
+// instead of loading all organizations.
+func (m *Manager) FindByDomain(ctx context.Context, domain string) (*Organization, error) {
+	domain = strings.ToLower(domain)
+	configured := m.r.Config().Organizations(ctx)
+	for _, c := range configured {
+		if slices.ContainsFunc(c.Domains, func(d string) bool { return strings.EqualFold(d, domain) }) {
+			return m.Get(ctx, c.ID)
+		}
+	}
+
+	verified, err := m.r.OrganizationPersister().FindVerifiedOrganizationDomain(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	// Organizations defined in the configuration file take precedence over
+	// stored ones with the same ID, including their domains.
+	if m.isConfigured(configured, verified.OrganizationID) {
+		return nil, errors.WithStack(sqlcon.ErrNoRows())
+	}
+	return m.Get(ctx, verified.OrganizationID)
+}
+
+// AuthenticateSCIM returns the organization with the given ID if the bearer
+// token is one of its SCIM bearer tokens. It returns false if the organization
+// does not exist, SCIM provisioning is not enabled for it, or the token does
+// not match.
+func (m *Manager) AuthenticateSCIM(ctx context.Context, id uuid.UUID, token string) (*config.Organization, bool, error) {
+	if token == "" {
+		return nil, false, nil
+	}
+	if c, ok := m.r.Config().Organization(ctx, id); ok {
+		for _, t := range c.SCIM.BearerTokens {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				return c, true, nil
+			}
+		}
+		return nil, false, nil
+	}
+
+	o, err := m.r.OrganizationPersister().GetOrganization(ctx, id)
+	if errors.Is(err, sqlcon.ErrNoRows()) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	stored, err := o.storedSCIM()
+	if err != nil || stored == nil {
+		return nil, false, err
+	}
+	hash := hashBearerToken(token)
+	for _, h := range stored.BearerTokenHashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			return &config.Organization{
+				ID:              o.ID,
+				Label:           o.Label,
+				SessionLifespan: o.SessionLifespan.Duration,
+				SCIM:            config.OrganizationSCIM{MapperURL: stored.MapperURL, SchemaID: stored.IdentitySchemaID},
+			}, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// SessionLifespan returns the project-level session lifespan.
+func (m *Manager) SessionLifespan(ctx context.Context) time.Duration {
+	return m.r.Config().SessionLifespan(ctx)
+}
+
+// OrganizationSessionLifespan returns the effective lifespan of sessions
+// issued to the organization's identities. The override of an organization
+// defined in the configuration file takes precedence over the one of an
+// organization managed through the admin API. If the organization has no
+// override, the project-level session lifespan is returned.
+func (m *Manager) OrganizationSessionLifespan(ctx context.Context, orgID uuid.UUID) time.Duration {
+	if orgID == uuid.Nil {
+		return m.SessionLifespan(ctx)
+	}
+	if c, ok := m.r.Config().Organization(ctx, orgID); ok {
+		if c.SessionLifespan > 0 {
+			return c.SessionLifespan
+		}
+		return m.SessionLifespan(ctx)
+	}
+
+	o, err := m.r.OrganizationPersister().GetOrganization(ctx, orgID)
+	if err != nil {
+		if !errors.Is(err, sqlcon.ErrNoRows()) {
+			m.r.Logger().WithError(err).WithField("organization_id", orgID).
+				Warn("Unable to load the organization's session lifespan, falling back to the project-level session lifespan.")
+		}
+		return m.SessionLifespan(ctx)
+	}
+	if o.SessionLifespan.Valid && o.SessionLifespan.Duration > 0 {
+		return o.SessionLifespan.Duration
+	}
+	return m.SessionLifespan(ctx)
+}
+
+// GetManaged returns the organization with the given ID if it is managed
+// through the admin API.
+func (m *Manager) GetManaged(ctx context.Context, id uuid.UUID) (*Organization, error) {
+	if _, ok := m.r.Config().Organization(ctx, id); ok {
+		return nil, errors.WithStack(herodot.ErrConflict().WithReasonf("The organization %q is defined in the configuration file and can not be changed through the admin API.", id))
+	}
+	return m.Get(ctx, id)
+}
+
+// AddDomain claims the domain for the organization. The domain has to be
+// verified before it is used to match identities.
+func (m *Manager) AddDomain(ctx context.Context, org *Organization, domain string) (*Domain, error) {
+	domain, err := NormalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(org.Domains, func(d Domain) bool { return d.Domain == domain }) {
+		return nil, errors.WithStack(herodot.ErrConflict().WithReasonf("The domain %q is already claimed by the organization.", domain))
+	}
+	if err := m.checkDomainAvailable(ctx, org.ID, domain); err != nil {
+		return nil, err
+	}
+
+	d := &Domain{OrganizationID: org.ID, Domain: domain, VerificationToken: randx.MustString(32, randx.AlphaNum)}
+	if err := m.r.OrganizationPersister().CreateOrganizationDomain(ctx, d); err != nil {
+		return nil, err
+	}
+	presented := d.present()
+	return &presented, nil
+}
+
+// VerifyDomain verifies the ownership of the organization's domain by looking
+// up its DNS TXT challenge record.
+func (m *Manager) VerifyDomain(ctx context.Context, org *Organization, domain string) (*Domain, error) {
+	domain, err := NormalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	idx := slices.IndexFunc(org.Domains, func(d Domain) bool { return d.Domain == domain })
+	if idx < 0 {
+		return nil, errors.WithStack(herodot.ErrNotFound().WithReasonf("The domain %q is not claimed by the organization.", domain))
+	}
+	d := org.Domains[idx]
+	if d.Verified {
+		return &d, nil
+	}
+
+	if err := m.checkDomainAvailable(ctx, org.ID, domain); err != nil {
+		return nil, err
+	}
+
+	records, err := m.r.OrganizationDomainResolver().LookupTXT(ctx, ChallengeRecordPrefix+domain)
+	if dnsErr := new(net.DNSError); errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		records, err = nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrUpstreamError().WithWrap(err).WithReasonf("Unable to look up the DNS TXT record %q: %s", ChallengeRecordPrefix+domain, err))
+	}
+	if !slices.Contains(records, ChallengeValuePrefix+d.VerificationToken) {
+		return nil, errors.WithStack(herodot.ErrBadRequest().
+			WithReasonf("The DNS TXT record %q does not contain the value %q.", ChallengeRecordPrefix+domain, ChallengeValuePrefix+d.VerificationToken))
+	}
+
+	d.VerifiedAt = sqlxx.NullTime(time.Now().UTC().Truncate(time.Microsecond))
+	if err := m.r.OrganizationPersister().UpdateOrganizationDomain(ctx, &d); err != nil {
+		// Another organization verified the domain concurrently.
+		if errors.Is(err, sqlcon.ErrUniqueViolation()) {
+			return nil, errors.WithStack(herodot.ErrConflict().WithReasonf("The domain %q belongs to another organization.", domain))
+		}
+		return nil, err
+	}
+	d.Challenge = nil
+	d = d.present()
+	return &d, nil
+}
+
+// checkDomainAvailable returns a conflict if the domain belongs to another
+// organization, either through the configuration file or a verified claim.
+func (m *Manager) checkDomainAvailable(ctx context.Context, organizationID uuid.UUID, domain string) error {
+	for _, c := range m.r.Config().Organizations(ctx) {
+		if c.ID != organizationID && slices.ContainsFunc(c.Domains, func(d string) bool { return strings.EqualFold(d, domain) }) {
+			return errors.WithStack(herodot.ErrConflict().WithReasonf("The domain %q belongs to another organization.", domain))
+		}
+	}
+
+	verified, err := m.r.OrganizationPersister().FindVerifiedOrganizationDomain(ctx, domain)
+	if errors.Is(err, sqlcon.ErrNoRows()) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if verified.OrganizationID != organizationID {
+		return errors.WithStack(herodot.ErrConflict().WithReasonf("The domain %q belongs to another organization.", domain))
+	}
+	return nil
+}
+
+// BindProvider binds the sign in provider to the organization, or unbinds it
+// if the organization ID is not valid.
+func (m *Manager) BindProvider(ctx context.Context, providerID string, organizationID uuid.NullUUID) error {
+	for _, b := range m.r.OrganizationProviderBinders() {
+		err := b.BindOrganizationProvider(ctx, providerID, organizationID)
+		if errors.Is(err, sqlcon.ErrNoRows()) {
+			continue
+		}
+		return err
+	}
+	return errors.WithStack(herodot.ErrNotFound().WithReasonf("The provider %q does not exist or can not be bound to organizations.", providerID))
+}
+
+func (m *Manager) present(ctx context.Context, org *Organization) error {
+	if org.Source == "" {
+		org.Source = SourceAPI
+	}
+	for k := range org.Domains {
+		org.Domains[k] = org.Domains[k].present()
+	}
+	if org.Source == SourceAPI {
+		if err := org.presentSCIM(); err != nil {
+			return err
+		}
+	}
+
+	org.SSOProviders = []string{}
+	for _, b := range m.r.OrganizationProviderBinders() {
+		providers, err := b.OrganizationProviders(ctx, org.ID)
+		if err != nil {
+			return err
+		}
+		org.SSOProviders = append(org.SSOProviders, providers...)
+	}
+	return nil
+}
+
+func (m *Manager) isConfigured(configured []config.Organization, id uuid.UUID) bool {
+	return slices.ContainsFunc(configured, func(c config.Organization) bool { return c.ID == id })
+}
+
+func fromConfig(c config.Organization) Organization {
+	org := Organization{ID: c.ID, Label: c.Label, Source: SourceConfig, Domains: make([]Domain, 0, len(c.Domains))}
+	if c.SessionLifespan > 0 {
+		org.SessionLifespan = sqlxx.NullDuration{Duration: c.SessionLifespan, Valid: true}
+	}
+	if len(c.SCIM.BearerTokens) > 0 || c.SCIM.MapperURL != "" || c.SCIM.SchemaID != "" {
+		org.SCIM = &SCIM{MapperURL: c.SCIM.MapperURL, IdentitySchemaID: c.SCIM.SchemaID, Enabled: len(c.SCIM.BearerTokens) > 0}
+	}
+	for _, d := range c.Domains {
+		org.Domains = append(org.Domains, Domain{OrganizationID: c.ID, Domain: strings.ToLower(d), Verified: true})
+	}
+	return org
+}
+
+// NormalizeDomain lowercases the domain and checks that it is a valid domain
+// name.
+func NormalizeDomain(domain string) (string, error) {
+	normalized := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if len(normalized) > 253 || !domainPattern.MatchString(normalized) {
+		return "", errors.WithStack(herodot.ErrBadRequest().WithReasonf("The domain %q is not a valid domain name.", domain))
+	}
+	return normalized, nil
+}
@@ -0,0 +1,284 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+// Package organization manages the organizations identities can belong to.
+// Organizations are either defined in the configuration file or managed
+// through the admin API.
+package organization
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/x/sqlxx"
+)
+
+const (
+	// SourceAPI marks organizations managed through the admin API.
+	SourceAPI = "api"
+	// SourceConfig marks organizations defined in the configuration file,
+	// which can not be changed through the admin API.
+	SourceConfig = "config"
+
+	// ChallengeRecordPrefix is prepended to a domain to form the name of the
+	// DNS TXT record which proves ownership of the domain.
+	ChallengeRecordPrefix = "_kratos-challenge."
+	// ChallengeValuePrefix is prepended to a domain's verification token to
+	// form the expected value of the DNS TXT record.
+	ChallengeValuePrefix = "kratos-domain-verification="
+)
+
+type (
+	// Organization
+	//
+	// swagger:model organization
+	Organization struct {
+		// ID is the organization's ID.
+		//
+		// required: true
+		ID  uuid.UUID `json:"id" db:"id"`
+		NID uuid.UUID `json:"-" db:"nid"`
+
+		// Label is a human-readable name of the organization.
+		Label string `json:"label" db:"label"`
+
+		// Source is either `api` for organizations managed through the admin
+		// API or `config` for organizations defined in the configuration file.
+		//
+		// required: true
+		Source string `json:"source" db:"-"`
+
+		// Domains are the email domains claimed by the organization. Only
+		// verified domains are used to match identities.
+		Domains []Domain `json:"domains" db:"-"`
+
+		// SSOProviders are the IDs of the sign in providers bound to the
+		// organization.
+		SSOProviders []string `json:"sso_providers" db:"-"`
+
+		// SessionLifespan overrides the lifespan of sessions issued to the
+		// organization's identities.
+		SessionLifespan sqlxx.NullDuration `json:"session_lifespan,omitzero" db:"session_lifespan"`
+
+		// SCIM configures SCIM provisioning of the organization's identities.
+		SCIM *SCIM `json:"scim,omitempty" db:"-"`
+
+		StoredSCIM sqlxx.NullJSONRawMessage `json:"-" db:"scim"`
+
+		// CreatedAt is the time the organization was created. It is not set
+		// for organizations defined in the configuration file.
+		CreatedAt time.Time `json:"created_at,omitzero" db:"created_at"`
+
+		// UpdatedAt is the time the organization was last updated. It is not
+		// set for organizations defined in the configuration file.
+		UpdatedAt time.Time `json:"updated_at,omitzero" db:"updated_at"`
+	}
+
+	// Organization SCIM Provisioning
+	//
+	// swagger:model organizationScim
+	SCIM struct {
+		// BearerTokens authenticate the organization's SCIM client. They are
+		// only stored as hashes and never returned.
+		BearerTokens []string `json:"bearer_tokens,omitempty"`
+
+		// MapperURL is the URL of the Jsonnet snippet which maps SCIM users
+		// to identity traits.
+		MapperURL string `json:"mapper_url,omitempty"`
+
+		// IdentitySchemaID is the identity schema of provisioned identities.
+		IdentitySchemaID string `json:"identity_schema_id,omitempty"`
+
+		// Enabled is true if bearer tokens are set.
+		//
+		// read only: true
+		Enabled bool `json:"enabled"`
+	}
+
+	storedSCIM struct {
+		BearerTokenHashes []string `json:"bearer_token_hashes"`
+		MapperURL         string   `json:"mapper_url,omitempty"`
+		IdentitySchemaID  string   `json:"identity_schema_id,omitempty"`
+	}
+
+	// Organization Domain
+	//
+	// swagger:model organizationDomain
+	Domain struct {
+		ID             uuid.UUID `json:"-" db:"id"`
+		NID            uuid.UUID `json:"-" db:"nid"`
+		OrganizationID uuid.UUID `json:"-" db:"organization_id"`
+
+		// Domain is the lowercased domain name.
+		//
+		// required: true
+		Domain string `json:"domain" db:"domain"`
+
+		// VerificationToken is compared against the domain's DNS TXT record.
+		VerificationToken string `json:"-" db:"verification_token"`
+
+		// Verified is true if the domain's ownership was verified. Domains
+		// of organizations defined in the configuration file are always
+		// verified.
+		//
+		// required: true
+		Verified bool `json:"verified" db:"-"`
+
+		// VerifiedAt is the time the domain's ownership was verified.
+		VerifiedAt sqlxx.NullTime `json:"verified_at,omitzero" db:"verified_at"`
+
+		// Challenge is the DNS TXT record which must be published to verify
+		// the domain's ownership. It is only returned for unverified domains.
+		Challenge *DomainChallenge `json:"challenge,omitempty" db:"-"`
+
+		CreatedAt time.Time `json:"created_at,omitzero" db:"created_at"`
+		UpdatedAt time.Time `json:"updated_at,omitzero" db:"updated_at"`
+	}
+
+	// Organization Domain Challenge
+	//
+	// swagger:model organizationDomainChallenge
+	DomainChallenge struct {
+		// Name is the name of the DNS TXT record.
+		//
+		// required: true
+		Name string `json:"name"`
+
+		// Value is the value of the DNS TXT record.
+		//
+		// required: true
+		Value string `json:"value"`
+	}
+
+	Persister interface {
+		CreateOrganization(ctx context.Context, o *Organization) error
+		GetOrganization(ctx context.Context, id uuid.UUID) (*Organization, error)
+		ListOrganizations(ctx context.Context) ([]Organization, error)
+		UpdateOrganization(ctx context.Context, o *Organization) error
+		DeleteOrganization(ctx context.Context, id uuid.UUID) error
+
+		CreateOrganizationDomain(ctx context.Context, d *Domain) error
+		UpdateOrganizationDomain(ctx context.Context, d *Domain) error
+		DeleteOrganizationDomain(ctx context.Context, organizationID uuid.UUID, domain string) error
+		// FindVerifiedOrganizationDomain returns the verified claim of the
+		// domain by any organization.
+		FindVerifiedOrganizationDomain(ctx context.Context, domain string) (*Domain, error)
+	}
+
+	PersistenceProvider interface {
+		OrganizationPersister() Persister
+	}
+
+	// TXTResolver looks up DNS TXT records. It is implemented by
+	// net.Resolver.
+	TXTResolver interface {
+		LookupTXT(ctx context.Context, name string) ([]string, error)
+	}
+
+	ResolverProvider interface {
+		OrganizationDomainResolver() TXTResolver
+	}
+
+	// ProviderBinder binds sign in providers to organizations. It is
+	// implemented by the strategies supporting organizations.
+	ProviderBinder interface {
+		// OrganizationProviders returns the IDs of the providers bound to
+		// the organization.
+		OrganizationProviders(ctx context.Context, organizationID uuid.UUID) ([]string, error)
+
+		// BindOrganizationProvider binds the provider to the organization, or
+		// unbinds it if the organization ID is not valid. It returns
+		// sqlcon.ErrNoRows if the binder does not know the provider.
+		BindOrganizationProvider(ctx context.Context, providerID string, organizationID uuid.NullUUID) error
+	}
+
+	ProviderBinderProvider interface {
+		OrganizationProviderBinders() []ProviderBinder
+	}
+)
+
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+func (Domain) TableName() string {
+	return "organization_domains"
+}
+
+// present sets the fields of the domain which are derived from the stored
+// ones.
+func (d Domain) present() Domain {
+	d.Verified = d.Verified || !d.VerifiedAt.IsZero()
+	if !d.Verified {
+		d.Challenge = &DomainChallenge{
+			Name:  ChallengeRecordPrefix + d.Domain,
+			Value: ChallengeValuePrefix + d.VerificationToken,
+		}
+	}
+	return d
+}
+
+// SetSCIM stores the SCIM provisioning settings with the bearer tokens
+// hashed. Bearer tokens which are not set are kept from the previous
+// settings. A nil value disables SCIM provisioning.
+func (o *Organization) SetSCIM(c *SCIM) error {
+	if c == nil {
+		o.StoredSCIM, o.SCIM = nil, nil
+		return nil
+	}
+
+	stored := storedSCIM{MapperURL: c.MapperURL, IdentitySchemaID: c.IdentitySchemaID}
+	if len(c.BearerTokens) > 0 {
+		for _, t := range c.BearerTokens {
+			stored.BearerTokenHashes = append(stored.BearerTokenHashes, hashBearerToken(t))
+		}
+	} else if previous, err := o.storedSCIM(); err != nil {
+		return err
+	} else if previous != nil {
+		stored.BearerTokenHashes = previous.BearerTokenHashes
+	}
+
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	o.StoredSCIM = raw
+	return o.presentSCIM()
+}
+
+func (o *Organization) storedSCIM() (*storedSCIM, error) {
+	if len(o.StoredSCIM) == 0 || string(o.StoredSCIM) == "null" {
+		return nil, nil
+	}
+	var stored storedSCIM
+	if err := json.Unmarshal(o.StoredSCIM, &stored); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &stored, nil
+}
+
+// presentSCIM sets the SCIM field from the stored settings.
+func (o *Organization) presentSCIM() error {
+	stored, err := o.storedSCIM()
+	if err != nil || stored == nil {
+		o.SCIM = nil
+		return err
+	}
+	o.SCIM = &SCIM{
+		MapperURL:        stored.MapperURL,
+		IdentitySchemaID: stored.IdentitySchemaID,
+		Enabled:          len(stored.BearerTokenHashes) > 0,
+	}
+	return nil
+}
+
+func hashBearerToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
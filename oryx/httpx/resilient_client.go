@@ -5,6 +5,7 @@ package httpx
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 	"log"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/ory/herodot"
+	"github.com/ory/x/ipx"
 	"github.com/ory/x/logrusx"
 )
 
@@ -25,6 +27,7 @@ type resilientOptions struct {
 	retryMax             int
 	noInternalIPs        bool
 	internalIPExceptions []string
+	tlsConfig            *tls.Config
 }
 
 func newResilientOptions() *resilientOptions {
@@ -76,6 +79,12 @@ func ResilientClientAllowInternalIPRequestsTo(urlGlobs ...string) ResilientOptio
 	return func(o *resilientOptions) { o.internalIPExceptions = urlGlobs }
 }
 
+// ResilientClientWithTLSConfig sets the TLS configuration used for
+// connections, for example to present a client certificate.
+func ResilientClientWithTLSConfig(c *tls.Config) ResilientOptions {
+	return func(o *resilientOptions) { o.tlsConfig = c }
+}
+
 // ResilientClientNoFollowRedirects configures the client to not follow redirects.
 func ResilientClientNoFollowRedirects() ResilientOptions {
 	return func(o *resilientOptions) {
@@ -92,14 +101,20 @@ func NewResilientClient(opts ...ResilientOptions) *retryablehttp.Client {
 		f(o)
 	}
 
+	allowInternal, prohibitInternal := allowInternalAllowIPv6, prohibitInternalAllowIPv6
+	if o.tlsConfig != nil {
+		allowInternal = OTELTraceTransport(tlsTransport(ipx.AllowInternalDialFunc, o.tlsConfig))
+		prohibitInternal = OTELTraceTransport(tlsTransport(ipx.ProhibitInternalDialFunc, o.tlsConfig))
+	}
+
 	if o.noInternalIPs {
 		o.c.Transport = &noInternalIPRoundTripper{
-			onWhitelist:          allowInternalAllowIPv6,
-			notOnWhitelist:       prohibitInternalAllowIPv6,
+			onWhitelist:          allowInternal,
+			notOnWhitelist:       prohibitInternal,
 			internalIPExceptions: o.internalIPExceptions,
 		}
 	} else {
-		o.c.Transport = allowInternalAllowIPv6
+		o.c.Transport = allowInternal
 	}
 
 	cl := retryablehttp.NewClient()
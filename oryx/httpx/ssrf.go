@@ -5,6 +5,7 @@ package httpx
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"net/http/httptrace"
@@ -59,6 +60,12 @@ func ssrfTransport(dial func(ctx context.Context, network, address string) (net.
 	}
 }
 
+func tlsTransport(dial func(ctx context.Context, network, address string) (net.Conn, error), c *tls.Config) *http.Transport {
+	t := ssrfTransport(dial)
+	t.TLSClientConfig = c
+	return t
+}
+
 // OTELTraceTransport wraps the given http.Transport with OpenTelemetry instrumentation.
 func OTELTraceTransport(t *http.Transport) http.RoundTripper {
 	return otelhttp.NewTransport(t, otelhttp.WithClientTrace(func(ctx context.Context) *httptrace.ClientTrace {
@@ -21,6 +21,9 @@ import (
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/scim"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/recovery"
@@ -29,6 +32,7 @@ import (
 	"github.com/ory/kratos/selfservice/flow/verification"
 	"github.com/ory/kratos/selfservice/strategy/code"
 	"github.com/ory/kratos/selfservice/strategy/link"
+	"github.com/ory/kratos/selfservice/strategy/oidc"
 	"github.com/ory/kratos/session"
 )
 
@@ -41,6 +45,7 @@ type Persister interface {
 	continuity.Persister
 	identity.PrivilegedPool
 	identity.PendingTraitsChangePersister
+	identity.ScheduledDeletionPersister
 	registration.FlowPersister
 	login.FlowPersister
 	settings.FlowPersister
@@ -56,6 +61,10 @@ type Persister interface {
 	code.VerificationCodePersister
 	code.RegistrationCodePersister
 	code.LoginCodePersister
+	oidc.ProviderPersister
+	scim.Persister
+	organization.Persister
+	schema.Persister
 
 	CleanupDatabase(context.Context, time.Duration, time.Duration, int) error
 	Close(context.Context) error
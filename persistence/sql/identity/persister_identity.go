@@ -4,6 +4,7 @@
 package identity
 
 import (
+	"bytes"
 	"cmp"
 	"context"
 	"database/sql"
@@ -11,6 +12,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"net/url"
 	"reflect"
 	"slices"
 	"sort"
@@ -43,6 +45,7 @@ import (
 	"github.com/ory/x/popx"
 	"github.com/ory/x/sqlcon"
 	"github.com/ory/x/sqlxx"
+	"github.com/ory/x/urlx"
 )
 
 var (
@@ -121,6 +124,73 @@ func (p *IdentityPersister) ListRecoveryAddresses(ctx context.Context, page, ite
 	return a, nil
 }
 
+func (p *IdentityPersister) ListIdentityIDsByCredentialsType(ctx context.Context, ct identity.CredentialsType, after uuid.UUID, limit int) (_ []uuid.UUID, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListIdentityIDsByCredentialsType",
+		trace.WithAttributes(
+			attribute.String("credentials.type", string(ct)),
+			attribute.Int("limit", limit),
+			attribute.Stringer("network.id", p.NetworkID(ctx))))
+	defer otelx.End(span, &err)
+
+	typeID, err := FindIdentityCredentialsTypeByName(p.GetConnection(ctx), ct)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		IdentityID uuid.UUID `db:"identity_id"`
+	}
+	if err := p.GetConnection(ctx).RawQuery(`
+		SELECT identity_id
+		FROM identity_credentials
+		WHERE nid = ?
+		  AND identity_credential_type_id = ?
+		  AND identity_id > ?
+		ORDER BY identity_id ASC
+		LIMIT ?`,
+		p.NetworkID(ctx), typeID, after, limit,
+	).All(&rows); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, r := range rows {
+		ids[i] = r.IdentityID
+	}
+	return ids, nil
+}
+
+func (p *IdentityPersister) ListIdentityIDsBySchemaID(ctx context.Context, schemaID string, after uuid.UUID, limit int) (_ []uuid.UUID, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListIdentityIDsBySchemaID",
+		trace.WithAttributes(
+			attribute.String("identity.schema_id", schemaID),
+			attribute.Int("limit", limit),
+			attribute.Stringer("network.id", p.NetworkID(ctx))))
+	defer otelx.End(span, &err)
+
+	var rows []struct {
+		ID uuid.UUID `db:"id"`
+	}
+	if err := p.GetConnection(ctx).RawQuery(`
+		SELECT id
+		FROM identities
+		WHERE nid = ?
+		  AND schema_id = ?
+		  AND id > ?
+		ORDER BY id ASC
+		LIMIT ?`,
+		p.NetworkID(ctx), schemaID, after, limit,
+	).All(&rows); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, r := range rows {
+		ids[i] = r.ID
+	}
+	return ids, nil
+}
+
 // PreferExactMatch returns the element from results whose value (extracted by getValue)
 // matches originalValue. If no exact match exists, the first element is returned.
 // Used by IN(normalized, original) queries to prefer the non-normalized match.
@@ -761,6 +831,31 @@ func (p *IdentityPersister) CountIdentities(ctx context.Context) (n int64, err e
 	return int64(count), nil
 }
 
+func (p *IdentityPersister) CountFilteredIdentities(ctx context.Context, filters []identity.Filter) (n int64, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CountFilteredIdentities",
+		trace.WithAttributes(
+			attribute.Stringer("network.id", p.NetworkID(ctx)),
+			attribute.Int("filters", len(filters))))
+	defer otelx.End(span, &err)
+
+	con := p.c.WithContext(ctx)
+	q := con.Where("identities.nid = ?", p.NetworkID(ctx))
+	for _, f := range filters {
+		condition, args, err := filterCondition(con.Dialect.Name(), f)
+		if err != nil {
+			return 0, err
+		}
+		q = q.Where(condition, args...)
+	}
+
+	count, err := q.Count(new(identity.Identity))
+	if err != nil {
+		return 0, sqlcon.HandleError(err)
+	}
+	span.SetAttributes(attribute.Int("num_identities", count))
+	return int64(count), nil
+}
+
 func (p *IdentityPersister) CreateIdentity(ctx context.Context, ident *identity.Identity) (err error) {
 	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateIdentity",
 		trace.WithAttributes(
@@ -818,10 +913,12 @@ func (p *IdentityPersister) CreateIdentities(ctx context.Context, identities []*
 			ident.Traits = identity.Traits("{}")
 		}
 
-		if err = p.InjectTraitsSchemaURL(ctx, ident); err != nil {
-			return err
-		}
+	}
 
+	if err = p.InjectTraitsSchemaURLs(ctx, identities...); err != nil {
+		return err
+	}
+	for _, ident := range identities {
 		if err = p.validateIdentity(ctx, ident); err != nil {
 			return err
 		}
@@ -1093,6 +1190,7 @@ func paginationAttributes(params *identity.ListIdentityParameters, paginator *ke
 		attribute.StringSlice("expand", params.Expand.ToEager()),
 		attribute.Bool("use:credential_identifier_filter", params.CredentialsIdentifier != ""),
 		attribute.Bool("use:credential_identifier_similar_filter", params.CredentialsIdentifierSimilar != ""),
+		attribute.Int("filters", len(params.Filters)),
 	}
 	if params.PagePagination != nil {
 		attrs = append(attrs,
@@ -1232,6 +1330,16 @@ func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.
 			args = append(args, params.OrganizationID.String())
 		}
 
+		for _, f := range params.Filters {
+			condition, filterArgs, err := filterCondition(con.Dialect.Name(), f)
+			if err != nil {
+				return err
+			}
+			wheres += `
+				AND ` + condition
+			args = append(args, filterArgs...)
+		}
+
 		columns := popx.DBColumns[identity.Identity](&popx.AliasQuoter{Alias: "identities", Quoter: con.Dialect})
 		if params.ColumnsTransformer != nil {
 			columns = params.ColumnsTransformer(columns)
@@ -1318,18 +1426,16 @@ func (p *IdentityPersister) ListIdentities(ctx context.Context, params identity.
 		return nil, nil, err
 	}
 
-	schemaCache := map[string]string{}
+	ptrs := make([]*identity.Identity, len(is))
 	for k := range is {
-		i := &is[k]
+		ptrs[k] = &is[k]
+	}
+	if err := p.InjectTraitsSchemaURLs(ctx, ptrs...); err != nil {
+		return nil, nil, err
+	}
 
-		if u, ok := schemaCache[i.SchemaID]; ok {
-			i.SchemaURL = u
-		} else {
-			if err := p.InjectTraitsSchemaURL(ctx, i); err != nil {
-				return nil, nil, err
-			}
-			schemaCache[i.SchemaID] = i.SchemaURL
-		}
+	for k := range is {
+		i := &is[k]
 
 		if err := i.Validate(); err != nil {
 			return nil, nil, err
@@ -1824,6 +1930,79 @@ func (p *IdentityPersister) DeleteIdentity(ctx context.Context, id uuid.UUID) (e
 	return nil
 }
 
+func (p *IdentityPersister) MergeIdentities(ctx context.Context, target *identity.Identity, sourceID uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.MergeIdentities",
+		trace.WithAttributes(
+			attribute.Stringer("identity.id", target.ID),
+			attribute.Stringer("identity.source_id", sourceID),
+			attribute.Stringer("network.id", p.NetworkID(ctx))))
+	defer otelx.End(span, &err)
+
+	nid := p.NetworkID(ctx)
+	if err := p.Transaction(ctx, func(ctx context.Context, tx *pop.Connection) error {
+		for _, table := range []string{"sessions", "session_devices"} {
+			if err := tx.RawQuery(fmt.Sprintf("UPDATE %s SET identity_id = ? WHERE identity_id = ? AND nid = ?", table),
+				target.ID,
+				sourceID,
+				nid,
+			).Exec(); err != nil {
+				return sqlcon.HandleError(err)
+			}
+		}
+
+		// The source identity is deleted before the target is updated so that
+		// the credential identifiers and addresses taken over from the source
+		// do not violate their unique constraints.
+		if err := p.DeleteIdentity(ctx, sourceID); err != nil {
+			return err
+		}
+		return p.UpdateIdentity(ctx, target)
+	}); err != nil {
+		return err
+	}
+
+	span.AddEvent(events.NewIdentitiesMerged(ctx, target.ID, sourceID))
+	return nil
+}
+
+func (p *IdentityPersister) LockIdentities(ctx context.Context, ids ...uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.LockIdentities",
+		trace.WithAttributes(attribute.Stringer("network.id", p.NetworkID(ctx))))
+	defer otelx.End(span, &err)
+
+	if !popx.InTransaction(ctx) {
+		return errors.WithStack(herodot.ErrInternalServerError().WithReason("LockIdentities must be called inside a transaction"))
+	}
+
+	conn := p.GetConnection(ctx)
+	nid := p.NetworkID(ctx)
+
+	// Locking in a stable order prevents deadlocks between transactions
+	// locking the same identities.
+	ids = slices.Clone(ids)
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a.Bytes(), b.Bytes()) })
+
+	query := "SELECT id FROM identities WHERE id = ? AND nid = ? FOR UPDATE"
+	if conn.Dialect.Name() == "sqlite3" {
+		// SQLite has no FOR UPDATE. A no-op write takes the database's write
+		// lock for the rest of the transaction instead.
+		if err := conn.RawQuery("UPDATE identities SET id = id WHERE 1 = 0").Exec(); err != nil {
+			return sqlcon.HandleError(err)
+		}
+		query = "SELECT id FROM identities WHERE id = ? AND nid = ?"
+	}
+
+	for _, id := range ids {
+		var row struct {
+			ID uuid.UUID `db:"id"`
+		}
+		if err := conn.RawQuery(query, id, nid).First(&row); err != nil {
+			return sqlcon.HandleError(err)
+		}
+	}
+	return nil
+}
+
 func (p *IdentityPersister) DeleteIdentities(ctx context.Context, ids []uuid.UUID) (err error) {
 	// This function is only used internally to cleanup partially created identities,
 	// when creating a batch of identities at once and some failed to be fully created.
@@ -2091,16 +2270,36 @@ func (p *IdentityPersister) InjectTraitsSchemaURL(ctx context.Context, i *identi
 	// ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.InjectTraitsSchemaURL")
 	// defer otelx.End(span, &err)
 
+	return p.InjectTraitsSchemaURLs(ctx, i)
+}
+
+func (p *IdentityPersister) InjectTraitsSchemaURLs(ctx context.Context, is ...*identity.Identity) (err error) {
+	if len(is) == 0 {
+		return nil
+	}
+
 	ss, err := p.r.IdentityTraitsSchemas(ctx)
 	if err != nil {
 		return err
 	}
-	s, err := ss.GetByID(i.SchemaID)
-	if err != nil {
-		return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf(
-			`The JSON Schema "%s" for this identity's traits could not be found.`, i.SchemaID))
+
+	publicURL := p.r.Config().SelfPublicURL(ctx)
+	for _, i := range is {
+		s, err := ss.GetByID(i.SchemaID)
+		if err != nil {
+			return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf(
+				`The JSON Schema "%s" for this identity's traits could not be found.`, i.SchemaID))
+		}
+		u := s.SchemaURL(publicURL)
+		if !s.IsCurrentVersion(i.SchemaVersion) {
+			version := i.SchemaVersion
+			if pinned, err := s.AtVersion(version); err == nil {
+				version = pinned.Version
+			}
+			u = urlx.SetQuery(u, url.Values{"version": {version}})
+		}
+		i.SchemaURL = u.String()
 	}
-	i.SchemaURL = s.SchemaURL(p.r.Config().SelfPublicURL(ctx)).String()
 	return nil
 }
 
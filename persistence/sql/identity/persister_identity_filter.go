@@ -0,0 +1,207 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/x"
+)
+
+// filterCondition returns the SQL condition which matches identities against
+// the filter.
+//
+// On PostgreSQL and CockroachDB, equality on traits and metadata is a JSONB
+// containment check which is served by the inverted indexes on these columns.
+//
+// MySQL can not index arbitrary JSON paths, so filters on traits and metadata
+// scan all identities of the network unless the path has an indexed generated
+// column, see mysqlGeneratedColumns.
+func filterCondition(dialect string, f identity.Filter) (string, []any, error) {
+	column := "identities." + string(f.Field)
+	switch f.Field {
+	case identity.FilterFieldState, identity.FilterFieldSchemaID:
+		return column + " = ?", []any{f.Value}, nil
+	case identity.FilterFieldCreatedAt:
+		t, err := time.Parse(time.RFC3339, f.Value)
+		if err != nil {
+			return "", nil, errors.WithStack(err)
+		}
+		return fmt.Sprintf("%s %s ?", column, f.Operator), []any{t.UTC()}, nil
+	}
+
+	switch dialect {
+	case "postgres", "cockroach":
+		return postgresJSONFilterCondition(column, f)
+	case "mysql":
+		return mysqlJSONFilterCondition(column, f)
+	case "sqlite3":
+		return sqliteJSONFilterCondition(column, f)
+	}
+	return "", nil, errors.Errorf("filtering identities is not supported on %s", dialect)
+}
+
+func postgresJSONFilterCondition(column string, f identity.Filter) (string, []any, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(f.Path)), ", ")
+	path := make([]any, len(f.Path))
+	for i, segment := range f.Path {
+		path[i] = segment
+	}
+	text := fmt.Sprintf("jsonb_extract_path_text(%s, %s)", column, placeholders)
+	typeOf := fmt.Sprintf("jsonb_typeof(jsonb_extract_path(%s, %s))", column, placeholders)
+
+	switch f.Operator {
+	case identity.FilterOperatorEqual:
+		var (
+			conditions []string
+			args       []any
+		)
+		for _, v := range f.JSONValues() {
+			doc, err := jsonDocumentAt(f.Path, v)
+			if err != nil {
+				return "", nil, err
+			}
+			conditions = append(conditions, column+" @> CAST(? AS JSONB)")
+			args = append(args, doc)
+		}
+		return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+	case identity.FilterOperatorPrefix:
+		return text + " LIKE ?", append(path, x.EscapeLikePattern(f.Value)+"%"), nil
+	}
+
+	args := append(append([]any{}, path...), path...)
+	if n, ok := f.Number(); ok {
+		return fmt.Sprintf("(CASE WHEN %s = 'number' THEN CAST(%s AS DECIMAL) END) %s ?", typeOf, text, f.Operator), append(args, n), nil
+	}
+	return fmt.Sprintf("(CASE WHEN %s = 'string' THEN %s END) %s ?", typeOf, text, f.Operator), append(args, f.Value), nil
+}
+
+// mysqlGeneratedColumns maps the paths support staff search most often to the
+// indexed generated columns holding their text on MySQL, see the
+// identities_search_generated_columns migration.
+var mysqlGeneratedColumns = map[string]string{
+	"traits.phone":               "traits_phone",
+	"metadata_admin.customer_id": "metadata_admin_customer_id",
+}
+
+// mysqlGeneratedColumnLength is the number of characters of the value the
+// generated columns hold.
+const mysqlGeneratedColumnLength = 255
+
+func mysqlJSONFilterCondition(column string, f identity.Filter) (string, []any, error) {
+	condition, args, err := mysqlJSONPathFilterCondition(column, f)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The generated column narrows the identities down using its index, and
+	// the condition on the JSON value keeps the exact semantics. Numbers are
+	// matched by their text, so `42` matches `42` but not `42.0`. Values of
+	// the column's length or longer may be truncated in the column.
+	generated, ok := mysqlGeneratedColumns[string(f.Field)+"."+strings.Join(f.Path, ".")]
+	if !ok || len(f.Value) >= mysqlGeneratedColumnLength {
+		return condition, args, nil
+	}
+	generated = "identities." + generated
+	switch f.Operator {
+	case identity.FilterOperatorEqual:
+		return fmt.Sprintf("(%s = ? AND %s)", generated, condition), append([]any{f.Value}, args...), nil
+	case identity.FilterOperatorPrefix:
+		return fmt.Sprintf("(%s LIKE ? AND %s)", generated, condition), append([]any{x.EscapeLikePattern(f.Value) + "%"}, args...), nil
+	}
+	if _, ok := f.Number(); ok {
+		return condition, args, nil
+	}
+	return fmt.Sprintf("(%s %s ? AND %s)", generated, f.Operator, condition), append([]any{f.Value}, args...), nil
+}
+
+func mysqlJSONPathFilterCondition(column string, f identity.Filter) (string, []any, error) {
+	// The path is inlined instead of bound so that the optimizer can match
+	// the expressions against functional indexes. Path segments are
+	// restricted to letters, digits, `_`, and `-` by identity.ParseFilter.
+	extract := fmt.Sprintf("JSON_EXTRACT(%s, '%s')", column, jsonPathExpression(f.Path))
+	typeOf := fmt.Sprintf("JSON_TYPE(%s)", extract)
+	text := fmt.Sprintf("JSON_UNQUOTE(%s)", extract)
+
+	switch f.Operator {
+	case identity.FilterOperatorEqual:
+		conditions := []string{fmt.Sprintf("(%s = 'STRING' AND %s = ?)", typeOf, text)}
+		args := []any{f.Value}
+		if n, ok := f.Number(); ok {
+			conditions = append(conditions, fmt.Sprintf("(%s IN ('INTEGER', 'UNSIGNED INTEGER', 'DOUBLE', 'DECIMAL') AND %s = ?)", typeOf, extract))
+			args = append(args, n)
+		} else if f.Value == "true" || f.Value == "false" {
+			conditions = append(conditions, fmt.Sprintf("(%s = 'BOOLEAN' AND %s = ?)", typeOf, text))
+			args = append(args, f.Value)
+		}
+		return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+	case identity.FilterOperatorPrefix:
+		return fmt.Sprintf("(%s = 'STRING' AND %s LIKE ?)", typeOf, text), []any{x.EscapeLikePattern(f.Value) + "%"}, nil
+	}
+
+	if n, ok := f.Number(); ok {
+		return fmt.Sprintf("(%s IN ('INTEGER', 'UNSIGNED INTEGER', 'DOUBLE', 'DECIMAL') AND %s %s ?)", typeOf, extract, f.Operator), []any{n}, nil
+	}
+	return fmt.Sprintf("(%s = 'STRING' AND %s %s ?)", typeOf, text, f.Operator), []any{f.Value}, nil
+}
+
+func sqliteJSONFilterCondition(column string, f identity.Filter) (string, []any, error) {
+	path := jsonPathExpression(f.Path)
+	extract := fmt.Sprintf("json_extract(%s, ?)", column)
+	typeOf := fmt.Sprintf("json_type(%s, ?)", column)
+
+	switch f.Operator {
+	case identity.FilterOperatorEqual:
+		conditions := []string{fmt.Sprintf("(%s = 'text' AND %s = ?)", typeOf, extract)}
+		args := []any{path, path, f.Value}
+		if n, ok := f.Number(); ok {
+			conditions = append(conditions, fmt.Sprintf("(%s IN ('integer', 'real') AND %s = ?)", typeOf, extract))
+			args = append(args, path, path, n)
+		} else if f.Value == "true" || f.Value == "false" {
+			conditions = append(conditions, fmt.Sprintf("%s = ?", typeOf))
+			args = append(args, path, f.Value)
+		}
+		return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+	case identity.FilterOperatorPrefix:
+		return fmt.Sprintf("(%s = 'text' AND %s LIKE ? ESCAPE '\\')", typeOf, extract), []any{path, path, x.EscapeLikePattern(f.Value) + "%"}, nil
+	}
+
+	if n, ok := f.Number(); ok {
+		return fmt.Sprintf("(%s IN ('integer', 'real') AND %s %s ?)", typeOf, extract, f.Operator), []any{path, path, n}, nil
+	}
+	return fmt.Sprintf("(%s = 'text' AND %s %s ?)", typeOf, extract, f.Operator), []any{path, path, f.Value}, nil
+}
+
+// jsonPathExpression returns the MySQL and SQLite JSON path for the path
+// segments, for example `$."name"."first"`.
+func jsonPathExpression(path []string) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, segment := range path {
+		b.WriteString(`."`)
+		b.WriteString(segment)
+		b.WriteString(`"`)
+	}
+	return b.String()
+}
+
+// jsonDocumentAt returns a JSON document with the value at the path, for
+// example `{"name":{"first":"Ada"}}`.
+func jsonDocumentAt(path []string, value json.RawMessage) (string, error) {
+	doc := value
+	for i := len(path) - 1; i >= 0; i-- {
+		b, err := json.Marshal(map[string]json.RawMessage{path[i]: doc})
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		doc = b
+	}
+	return string(doc), nil
+}
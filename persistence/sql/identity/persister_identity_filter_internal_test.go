@@ -0,0 +1,46 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/identity"
+)
+
+func TestMySQLFilterConditionUsesGeneratedColumns(t *testing.T) {
+	for _, tc := range []struct {
+		expression string
+		generated  string
+		args       []any
+	}{
+		{expression: "traits.phone=+4917612345678", generated: "(identities.traits_phone = ? AND ", args: []any{"+4917612345678"}},
+		{expression: "metadata_admin.customer_id=42", generated: "(identities.metadata_admin_customer_id = ? AND ", args: []any{"42"}},
+		{expression: "metadata_admin.customer_id^=4_", generated: "(identities.metadata_admin_customer_id LIKE ? AND ", args: []any{`4\_%`}},
+		{expression: "traits.phone>+49", generated: "(identities.traits_phone > ? AND ", args: []any{"+49"}},
+		{expression: "metadata_admin.customer_id>=10"},
+		{expression: "traits.phone=" + strings.Repeat("1", mysqlGeneratedColumnLength)},
+		{expression: "traits.email=ada@example.org"},
+		{expression: "metadata_public.customer_id=42"},
+	} {
+		t.Run("filter="+tc.expression, func(t *testing.T) {
+			f, err := identity.ParseFilter(tc.expression)
+			require.NoError(t, err)
+
+			condition, args, err := filterCondition("mysql", *f)
+			require.NoError(t, err)
+			if tc.generated == "" {
+				assert.NotContains(t, condition, "identities.traits_phone")
+				assert.NotContains(t, condition, "identities.metadata_admin_customer_id")
+				return
+			}
+			assert.True(t, strings.HasPrefix(condition, tc.generated), condition)
+			assert.Equal(t, tc.args, args[:len(tc.args)])
+		})
+	}
+}
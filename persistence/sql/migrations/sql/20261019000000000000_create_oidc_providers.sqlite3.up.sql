@@ -0,0 +1,11 @@
+CREATE TABLE selfservice_oidc_providers (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "nid" char(36) NOT NULL,
+    "provider_id" VARCHAR(255) NOT NULL,
+    "config" TEXT NOT NULL,
+    "created_at" DATETIME NOT NULL,
+    "updated_at" DATETIME NOT NULL,
+    CONSTRAINT selfservice_oidc_providers_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX selfservice_oidc_providers_nid_provider_id_uq_idx ON selfservice_oidc_providers (nid, provider_id);
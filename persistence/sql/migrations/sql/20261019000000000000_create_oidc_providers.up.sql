@@ -0,0 +1,11 @@
+CREATE TABLE selfservice_oidc_providers (
+    "id" UUID NOT NULL PRIMARY KEY,
+    "nid" UUID NOT NULL,
+    "provider_id" VARCHAR(255) NOT NULL,
+    "config" jsonb NOT NULL,
+    "created_at" timestamp NOT NULL,
+    "updated_at" timestamp NOT NULL,
+    CONSTRAINT selfservice_oidc_providers_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX selfservice_oidc_providers_nid_provider_id_uq_idx ON selfservice_oidc_providers (nid, provider_id);
@@ -0,0 +1,15 @@
+CREATE TABLE scim_users (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    nid CHAR(36) NOT NULL,
+    organization_id CHAR(36) NOT NULL,
+    user_name VARCHAR(255) NOT NULL,
+    external_id VARCHAR(255) NOT NULL DEFAULT '',
+    attributes JSON NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT scim_users_identities_id_fk FOREIGN KEY (id) REFERENCES identities (id) ON DELETE CASCADE,
+    CONSTRAINT scim_users_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+CREATE UNIQUE INDEX scim_users_nid_organization_id_user_name_uq_idx ON scim_users (nid, organization_id, user_name);
+CREATE INDEX scim_users_nid_organization_id_external_id_idx ON scim_users (nid, organization_id, external_id);
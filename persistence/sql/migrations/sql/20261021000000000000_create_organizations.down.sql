@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS organization_domains;
+DROP TABLE IF EXISTS organizations;
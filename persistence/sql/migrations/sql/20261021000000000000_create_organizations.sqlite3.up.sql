@@ -0,0 +1,26 @@
+CREATE TABLE organizations (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "nid" char(36) NOT NULL,
+    "label" VARCHAR(255) NOT NULL DEFAULT '',
+    "created_at" DATETIME NOT NULL,
+    "updated_at" DATETIME NOT NULL,
+    CONSTRAINT organizations_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE INDEX organizations_nid_created_at_idx ON organizations (nid, created_at);
+
+CREATE TABLE organization_domains (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "nid" char(36) NOT NULL,
+    "organization_id" char(36) NOT NULL,
+    "domain" VARCHAR(255) NOT NULL,
+    "verification_token" VARCHAR(64) NOT NULL,
+    "verified_at" DATETIME NULL,
+    "created_at" DATETIME NOT NULL,
+    "updated_at" DATETIME NOT NULL,
+    CONSTRAINT organization_domains_organizations_id_fk FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE,
+    CONSTRAINT organization_domains_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX organization_domains_nid_organization_id_domain_uq_idx ON organization_domains (nid, organization_id, domain);
+CREATE INDEX organization_domains_nid_domain_idx ON organization_domains (nid, domain);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS identities_nid_state_idx;
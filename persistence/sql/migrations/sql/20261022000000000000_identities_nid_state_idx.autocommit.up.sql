@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS identities_nid_state_idx ON identities (nid, state, id);
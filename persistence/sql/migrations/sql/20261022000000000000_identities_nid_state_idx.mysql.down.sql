@@ -0,0 +1 @@
+DROP INDEX identities_nid_state_idx ON identities;
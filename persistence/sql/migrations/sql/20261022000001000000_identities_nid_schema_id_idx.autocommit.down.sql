@@ -0,0 +1 @@
+DROP INDEX IF EXISTS identities_nid_schema_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS identities_nid_schema_id_idx ON identities (nid, schema_id, id);
@@ -0,0 +1 @@
+DROP INDEX identities_nid_schema_id_idx ON identities;
@@ -0,0 +1 @@
+CREATE INDEX identities_nid_schema_id_idx ON identities (nid, schema_id(128), id);
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS identities_nid_schema_id_idx;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS identities_nid_created_at_idx;
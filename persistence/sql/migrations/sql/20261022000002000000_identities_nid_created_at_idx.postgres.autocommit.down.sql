@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS identities_nid_created_at_idx;
@@ -0,0 +1 @@
+CREATE INDEX CONCURRENTLY IF NOT EXISTS identities_nid_created_at_idx ON identities (nid, created_at);
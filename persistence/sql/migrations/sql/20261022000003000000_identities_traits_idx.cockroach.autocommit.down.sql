@@ -0,0 +1 @@
+DROP INDEX IF EXISTS identities_traits_idx;
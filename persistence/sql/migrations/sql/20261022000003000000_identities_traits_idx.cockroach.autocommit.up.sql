@@ -0,0 +1 @@
+CREATE INVERTED INDEX IF NOT EXISTS identities_traits_idx ON identities (nid, traits);
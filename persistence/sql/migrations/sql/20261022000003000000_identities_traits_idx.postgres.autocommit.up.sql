@@ -0,0 +1 @@
+CREATE INDEX CONCURRENTLY IF NOT EXISTS identities_traits_idx ON identities USING GIN (traits jsonb_path_ops);
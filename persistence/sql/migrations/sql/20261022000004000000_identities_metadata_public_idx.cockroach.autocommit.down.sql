@@ -0,0 +1 @@
+DROP INDEX IF EXISTS identities_metadata_public_idx;
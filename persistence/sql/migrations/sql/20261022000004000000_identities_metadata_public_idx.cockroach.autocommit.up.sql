@@ -0,0 +1 @@
+CREATE INVERTED INDEX IF NOT EXISTS identities_metadata_public_idx ON identities (nid, metadata_public);
@@ -0,0 +1 @@
+CREATE INDEX CONCURRENTLY IF NOT EXISTS identities_metadata_public_idx ON identities USING GIN (metadata_public jsonb_path_ops);
@@ -0,0 +1 @@
+CREATE INVERTED INDEX IF NOT EXISTS identities_metadata_admin_idx ON identities (nid, metadata_admin);
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS identities_metadata_admin_idx;
@@ -0,0 +1 @@
+CREATE INDEX CONCURRENTLY IF NOT EXISTS identities_metadata_admin_idx ON identities USING GIN (metadata_admin jsonb_path_ops);
@@ -0,0 +1,5 @@
+ALTER TABLE identities
+  DROP INDEX identities_nid_metadata_admin_customer_id_idx,
+  DROP INDEX identities_nid_traits_phone_idx,
+  DROP COLUMN metadata_admin_customer_id,
+  DROP COLUMN traits_phone;
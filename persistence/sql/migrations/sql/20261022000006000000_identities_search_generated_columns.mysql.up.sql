@@ -0,0 +1,5 @@
+ALTER TABLE identities
+  ADD COLUMN traits_phone VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin AS (LEFT(JSON_UNQUOTE(JSON_EXTRACT(traits, '$."phone"')), 255)) VIRTUAL,
+  ADD COLUMN metadata_admin_customer_id VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin AS (LEFT(JSON_UNQUOTE(JSON_EXTRACT(metadata_admin, '$."customer_id"')), 255)) VIRTUAL,
+  ADD INDEX identities_nid_traits_phone_idx (nid, traits_phone),
+  ADD INDEX identities_nid_metadata_admin_customer_id_idx (nid, metadata_admin_customer_id);
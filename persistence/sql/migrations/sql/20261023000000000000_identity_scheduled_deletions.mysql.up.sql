@@ -0,0 +1,13 @@
+CREATE TABLE identity_scheduled_deletions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    identity_id CHAR(36) NOT NULL,
+    nid CHAR(36) NOT NULL,
+    delete_after timestamp NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT identity_scheduled_deletions_identities_id_fk FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE CASCADE,
+    CONSTRAINT identity_scheduled_deletions_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+CREATE UNIQUE INDEX identity_scheduled_deletions_nid_identity_id_idx ON identity_scheduled_deletions (nid, identity_id);
+CREATE INDEX identity_scheduled_deletions_nid_delete_after_idx ON identity_scheduled_deletions (nid, delete_after);
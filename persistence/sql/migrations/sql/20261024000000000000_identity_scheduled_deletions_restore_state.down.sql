@@ -0,0 +1 @@
+ALTER TABLE "identity_scheduled_deletions" DROP COLUMN IF EXISTS "restore_state";
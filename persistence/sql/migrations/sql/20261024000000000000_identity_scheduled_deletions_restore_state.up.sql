@@ -0,0 +1 @@
+ALTER TABLE "identity_scheduled_deletions" ADD COLUMN "restore_state" VARCHAR(255);
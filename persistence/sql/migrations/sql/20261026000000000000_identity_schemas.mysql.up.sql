@@ -0,0 +1,11 @@
+CREATE TABLE identity_schemas (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    nid CHAR(36) NOT NULL,
+    schema_id VARCHAR(255) NOT NULL,
+    json_schema MEDIUMTEXT NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT identity_schemas_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+CREATE UNIQUE INDEX identity_schemas_nid_schema_id_idx ON identity_schemas (nid, schema_id);
@@ -0,0 +1,11 @@
+CREATE TABLE identity_schemas (
+    "id" TEXT NOT NULL PRIMARY KEY,
+    "nid" char(36) NOT NULL,
+    "schema_id" TEXT NOT NULL,
+    "json_schema" TEXT NOT NULL,
+    "created_at" DATETIME NOT NULL,
+    "updated_at" DATETIME NOT NULL,
+    CONSTRAINT identity_schemas_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX identity_schemas_nid_schema_id_idx ON identity_schemas (nid, schema_id);
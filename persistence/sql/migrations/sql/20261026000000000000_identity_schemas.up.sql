@@ -0,0 +1,11 @@
+CREATE TABLE identity_schemas (
+    "id" UUID NOT NULL PRIMARY KEY,
+    "nid" UUID NOT NULL,
+    "schema_id" VARCHAR(255) NOT NULL,
+    "json_schema" TEXT NOT NULL,
+    "created_at" timestamp NOT NULL,
+    "updated_at" timestamp NOT NULL,
+    CONSTRAINT identity_schemas_networks_id_fk FOREIGN KEY (nid) REFERENCES networks (id) ON UPDATE RESTRICT ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX identity_schemas_nid_schema_id_idx ON identity_schemas (nid, schema_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS organization_domains_nid_verified_domain_uq_idx;
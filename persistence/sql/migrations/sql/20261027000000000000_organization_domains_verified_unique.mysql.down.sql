@@ -0,0 +1,2 @@
+DROP INDEX organization_domains_nid_verified_domain_uq_idx ON organization_domains;
+ALTER TABLE `organization_domains` DROP COLUMN `verified_domain`;
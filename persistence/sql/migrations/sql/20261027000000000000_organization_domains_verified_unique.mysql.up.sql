@@ -0,0 +1,2 @@
+ALTER TABLE `organization_domains` ADD COLUMN `verified_domain` VARCHAR(255) GENERATED ALWAYS AS (CASE WHEN `verified_at` IS NOT NULL THEN `domain` END) VIRTUAL;
+CREATE UNIQUE INDEX organization_domains_nid_verified_domain_uq_idx ON organization_domains (nid, verified_domain);
@@ -0,0 +1 @@
+CREATE UNIQUE INDEX organization_domains_nid_verified_domain_uq_idx ON organization_domains (nid, domain) WHERE verified_at IS NOT NULL;
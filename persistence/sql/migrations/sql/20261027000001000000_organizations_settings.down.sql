@@ -0,0 +1,2 @@
+ALTER TABLE "organizations" DROP COLUMN IF EXISTS "scim";
+ALTER TABLE "organizations" DROP COLUMN IF EXISTS "session_lifespan";
@@ -0,0 +1,2 @@
+ALTER TABLE `organizations` DROP COLUMN `scim`;
+ALTER TABLE `organizations` DROP COLUMN `session_lifespan`;
@@ -0,0 +1,2 @@
+ALTER TABLE "organizations" ADD COLUMN "session_lifespan" INTEGER NULL;
+ALTER TABLE "organizations" ADD COLUMN "scim" TEXT NULL;
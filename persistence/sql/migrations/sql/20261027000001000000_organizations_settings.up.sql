@@ -0,0 +1,2 @@
+ALTER TABLE "organizations" ADD COLUMN "session_lifespan" BIGINT NULL;
+ALTER TABLE "organizations" ADD COLUMN "scim" jsonb NULL;
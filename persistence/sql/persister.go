@@ -11,11 +11,14 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/hashicorp/golang-lru/v2/expirable"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/persistence"
 	"github.com/ory/kratos/persistence/sql/devices"
 	idpersistence "github.com/ory/kratos/persistence/sql/identity"
@@ -46,6 +49,7 @@ type (
 		otelx.Provider
 		schema.IdentitySchemaProvider
 		identity.ValidationProvider
+		organization.ManagementProvider
 	}
 	Persister struct {
 		nid uuid.UUID
@@ -54,6 +58,10 @@ type (
 		mbs popx.MigrationStatuses
 		r   persisterDependencies
 
+		// identitySchemas caches the identity schemas stored in the database
+		// per network.
+		identitySchemas *expirable.LRU[uuid.UUID, []schema.StoredSchema]
+
 		identity.PrivilegedPool
 		session.DevicePersister
 	}
@@ -107,6 +115,7 @@ func NewPersister(r persisterDependencies, c *pop.Connection, opts ...Option) (*
 		c:               c,
 		mb:              m,
 		r:               r,
+		identitySchemas: expirable.NewLRU[uuid.UUID, []schema.StoredSchema](identitySchemasCacheSize, nil, identitySchemasCacheTTL),
 		PrivilegedPool:  idpersistence.NewPersister(r, c),
 		DevicePersister: devices.NewPersister(r, c),
 	}, nil
@@ -231,6 +240,27 @@ func (p *Persister) CleanupDatabase(ctx context.Context, wait time.Duration, old
 	}
 	time.Sleep(wait)
 
+	for _, status := range []courier.MessageStatus{courier.MessageStatusSent, courier.MessageStatusAbandoned} {
+		retention := p.r.Config().CourierMessageRetention(ctx, status.String())
+		if retention <= 0 {
+			continue
+		}
+
+		p.r.Logger().Printf("Cleaning up %s courier messages older than %s\n", status, retention)
+		if err := p.DeleteExpiredMessages(ctx, status, time.Now().Add(-retention), batchSize); err != nil {
+			return err
+		}
+		time.Sleep(wait)
+	}
+
+	// Scheduled deletions are due once their grace period has passed,
+	// independent of the age of the records.
+	p.r.Logger().Println("Deleting identities whose scheduled deletion is due")
+	if _, err := p.DeleteScheduledIdentities(ctx, time.Now(), batchSize); err != nil {
+		return err
+	}
+	time.Sleep(wait)
+
 	p.r.Logger().Println("Successfully cleaned up the latest batch of the SQL database! " +
 		"This should be re-run periodically, to be sure that all expired data is purged.")
 	return nil
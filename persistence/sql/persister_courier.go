@@ -227,3 +227,82 @@ func (p *Persister) RecordDispatch(ctx context.Context, msgID uuid.UUID, status
 
 	return nil
 }
+
+func (p *Persister) RedactMessage(ctx context.Context, m *courier.Message) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RedactMessage")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"UPDATE courier_messages SET subject = ?, body = ?, template_data = ? WHERE id = ? AND nid = ?",
+		m.Subject,
+		m.Body,
+		m.TemplateData,
+		m.ID,
+		p.NetworkID(ctx),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows())
+	}
+
+	return nil
+}
+
+func (p *Persister) RequeueMessage(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RequeueMessage")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"UPDATE courier_messages SET status = ?, send_count = 0 WHERE id = ? AND nid = ? AND status = ?",
+		courier.MessageStatusQueued,
+		id,
+		p.NetworkID(ctx),
+		courier.MessageStatusAbandoned,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+
+	if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows())
+	}
+
+	return nil
+}
+
+func (p *Persister) RequeueMessages(ctx context.Context, status courier.MessageStatus, since time.Time) (_ int, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RequeueMessages")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"UPDATE courier_messages SET status = ?, send_count = 0 WHERE nid = ? AND status = ? AND created_at >= ?",
+		courier.MessageStatusQueued,
+		p.NetworkID(ctx),
+		status,
+		since,
+	).ExecWithCount()
+	if err != nil {
+		return 0, sqlcon.HandleError(err)
+	}
+
+	return count, nil
+}
+
+func (p *Persister) DeleteExpiredMessages(ctx context.Context, status courier.MessageStatus, before time.Time, limit int) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteExpiredMessages")
+	defer otelx.End(span, &err)
+
+	// Dispatch records are removed through the ON DELETE CASCADE foreign key.
+	err = p.GetConnection(ctx).RawQuery(
+		"DELETE FROM courier_messages WHERE id in (SELECT id FROM (SELECT id FROM courier_messages c WHERE nid = ? AND status = ? AND created_at <= ? ORDER BY created_at ASC LIMIT ?) AS s)",
+		p.NetworkID(ctx),
+		status,
+		before,
+		limit,
+	).Exec()
+
+	return sqlcon.HandleError(err)
+}
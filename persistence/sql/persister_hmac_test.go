@@ -13,6 +13,7 @@ import (
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/embedx"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/pop/v6"
 	"github.com/ory/x/clock"
@@ -64,6 +65,10 @@ func (l *logRegistryOnly) IdentityValidator() *identity.Validator {
 	panic("implement me")
 }
 
+func (l *logRegistryOnly) OrganizationManager() *organization.Manager {
+	panic("implement me")
+}
+
 var _ persisterDependencies = &logRegistryOnly{}
 
 func TestPersisterHMAC(t *testing.T) {
@@ -0,0 +1,133 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/pop/v6"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+)
+
+var _ schema.Persister = new(Persister)
+
+const (
+	identitySchemasCacheSize = 1024
+	// identitySchemasCacheTTL bounds how long changes made on other instances
+	// take to be picked up. Changes on this instance invalidate the cache
+	// immediately.
+	identitySchemasCacheTTL = 10 * time.Second
+)
+
+func (p *Persister) CreateIdentitySchema(ctx context.Context, s *schema.StoredSchema) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateIdentitySchema")
+	defer otelx.End(span, &err)
+
+	s.ID = uuid.Must(uuid.NewV4())
+	s.NID = p.NetworkID(ctx)
+	s.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	s.UpdatedAt = s.CreatedAt
+	defer p.invalidateIdentitySchemas(ctx)
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(s))
+}
+
+func (p *Persister) GetIdentitySchema(ctx context.Context, id string) (_ *schema.StoredSchema, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetIdentitySchema")
+	defer otelx.End(span, &err)
+
+	var s schema.StoredSchema
+	if err := p.GetConnection(ctx).Where("nid = ? AND schema_id = ?", p.NetworkID(ctx), id).First(&s); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &s, nil
+}
+
+func (p *Persister) ListIdentitySchemas(ctx context.Context) (_ []schema.StoredSchema, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListIdentitySchemas")
+	defer otelx.End(span, &err)
+
+	nid := p.NetworkID(ctx)
+	if ss, ok := p.identitySchemas.Get(nid); ok {
+		return slices.Clone(ss), nil
+	}
+
+	ss := make([]schema.StoredSchema, 0)
+	if err := p.GetConnection(ctx).Where("nid = ?", nid).Order("schema_id ASC").All(&ss); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	p.identitySchemas.Add(nid, ss)
+	return slices.Clone(ss), nil
+}
+
+func (p *Persister) UpdateIdentitySchema(ctx context.Context, s *schema.StoredSchema) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateIdentitySchema")
+	defer otelx.End(span, &err)
+
+	s.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	defer p.invalidateIdentitySchemas(ctx)
+	return p.Transaction(ctx, func(ctx context.Context, c *pop.Connection) error {
+		if err := p.checkIdentitySchemaUnused(ctx, c, s.SchemaID, "Create a new schema and migrate them to it instead."); err != nil {
+			return err
+		}
+
+		count, err := c.RawQuery(
+			"UPDATE identity_schemas SET json_schema = ?, updated_at = ? WHERE nid = ? AND schema_id = ?",
+			s.Schema, s.UpdatedAt, p.NetworkID(ctx), s.SchemaID,
+		).ExecWithCount()
+		if err != nil {
+			return sqlcon.HandleError(err)
+		} else if count == 0 {
+			return errors.WithStack(sqlcon.ErrNoRows())
+		}
+		return nil
+	})
+}
+
+func (p *Persister) DeleteIdentitySchema(ctx context.Context, id string) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteIdentitySchema")
+	defer otelx.End(span, &err)
+
+	defer p.invalidateIdentitySchemas(ctx)
+	return p.Transaction(ctx, func(ctx context.Context, c *pop.Connection) error {
+		if err := p.checkIdentitySchemaUnused(ctx, c, id, "Migrate them to another schema first."); err != nil {
+			return err
+		}
+
+		count, err := c.RawQuery("DELETE FROM identity_schemas WHERE nid = ? AND schema_id = ?", p.NetworkID(ctx), id).ExecWithCount()
+		if err != nil {
+			return sqlcon.HandleError(err)
+		} else if count == 0 {
+			return errors.WithStack(sqlcon.ErrNoRows())
+		}
+		return nil
+	})
+}
+
+// checkIdentitySchemaUnused fails with a conflict if identities use the
+// identity schema with the given ID.
+func (p *Persister) checkIdentitySchemaUnused(ctx context.Context, c *pop.Connection, id, hint string) error {
+	used, err := c.Where("nid = ? AND schema_id = ?", p.NetworkID(ctx), id).Exists(new(identity.Identity))
+	if err != nil {
+		return sqlcon.HandleError(err)
+	} else if used {
+		return errors.WithStack(herodot.ErrConflict().WithReasonf("The identity schema %q is still used by identities. %s", id, hint))
+	}
+	return nil
+}
+
+// invalidateIdentitySchemas drops the cached identity schemas of the current
+// network after they were changed.
+func (p *Persister) invalidateIdentitySchemas(ctx context.Context) {
+	p.identitySchemas.Remove(p.NetworkID(ctx))
+}
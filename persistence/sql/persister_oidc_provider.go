@@ -0,0 +1,85 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/persistence/sql/update"
+	"github.com/ory/kratos/selfservice/strategy/oidc"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+)
+
+var _ oidc.ProviderPersister = new(Persister)
+
+func (p *Persister) CreateOIDCProvider(ctx context.Context, sp *oidc.StoredProvider) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateOIDCProvider")
+	defer otelx.End(span, &err)
+
+	sp.ID = uuid.Must(uuid.NewV4())
+	sp.NID = p.NetworkID(ctx)
+	sp.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	sp.UpdatedAt = sp.CreatedAt
+
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(sp))
+}
+
+func (p *Persister) GetOIDCProvider(ctx context.Context, providerID string) (_ *oidc.StoredProvider, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetOIDCProvider")
+	defer otelx.End(span, &err)
+
+	var sp oidc.StoredProvider
+	if err := p.GetConnection(ctx).
+		Where("nid = ? AND provider_id = ?", p.NetworkID(ctx), providerID).
+		First(&sp); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &sp, nil
+}
+
+func (p *Persister) ListOIDCProviders(ctx context.Context) (_ []oidc.StoredProvider, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListOIDCProviders")
+	defer otelx.End(span, &err)
+
+	providers := make([]oidc.StoredProvider, 0)
+	if err := p.GetConnection(ctx).
+		Where("nid = ?", p.NetworkID(ctx)).
+		Order("provider_id ASC").
+		All(&providers); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return providers, nil
+}
+
+func (p *Persister) UpdateOIDCProvider(ctx context.Context, sp *oidc.StoredProvider) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateOIDCProvider")
+	defer otelx.End(span, &err)
+
+	sp.NID = p.NetworkID(ctx)
+	sp.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	return update.Generic(ctx, p.GetConnection(ctx), p.r.Tracer(ctx).Tracer(), sp, "config", "updated_at")
+}
+
+func (p *Persister) DeleteOIDCProvider(ctx context.Context, providerID string) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteOIDCProvider")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"DELETE FROM selfservice_oidc_providers WHERE nid = ? AND provider_id = ?",
+		p.NetworkID(ctx),
+		providerID,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+	if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows())
+	}
+	return nil
+}
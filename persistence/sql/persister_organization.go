@@ -0,0 +1,168 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/persistence/sql/update"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+)
+
+var _ organization.Persister = new(Persister)
+
+func (p *Persister) CreateOrganization(ctx context.Context, o *organization.Organization) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateOrganization")
+	defer otelx.End(span, &err)
+
+	if o.ID == uuid.Nil {
+		o.ID = uuid.Must(uuid.NewV4())
+	}
+	o.NID = p.NetworkID(ctx)
+	o.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	o.UpdatedAt = o.CreatedAt
+	o.Domains = []organization.Domain{}
+
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(o))
+}
+
+func (p *Persister) GetOrganization(ctx context.Context, id uuid.UUID) (_ *organization.Organization, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetOrganization")
+	defer otelx.End(span, &err)
+
+	var o organization.Organization
+	if err := p.GetConnection(ctx).
+		Where("id = ? AND nid = ?", id, p.NetworkID(ctx)).
+		First(&o); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	o.Domains = make([]organization.Domain, 0)
+	if err := p.GetConnection(ctx).
+		Where("nid = ? AND organization_id = ?", p.NetworkID(ctx), id).
+		Order("domain ASC").
+		All(&o.Domains); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &o, nil
+}
+
+func (p *Persister) ListOrganizations(ctx context.Context) (_ []organization.Organization, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListOrganizations")
+	defer otelx.End(span, &err)
+
+	orgs := make([]organization.Organization, 0)
+	if err := p.GetConnection(ctx).
+		Where("nid = ?", p.NetworkID(ctx)).
+		Order("created_at ASC, id ASC").
+		All(&orgs); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	domains := make([]organization.Domain, 0)
+	if err := p.GetConnection(ctx).
+		Where("nid = ?", p.NetworkID(ctx)).
+		Order("domain ASC").
+		All(&domains); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+
+	byOrganization := make(map[uuid.UUID][]organization.Domain, len(orgs))
+	for _, d := range domains {
+		byOrganization[d.OrganizationID] = append(byOrganization[d.OrganizationID], d)
+	}
+	for k := range orgs {
+		orgs[k].Domains = byOrganization[orgs[k].ID]
+		if orgs[k].Domains == nil {
+			orgs[k].Domains = []organization.Domain{}
+		}
+	}
+	return orgs, nil
+}
+
+func (p *Persister) UpdateOrganization(ctx context.Context, o *organization.Organization) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateOrganization")
+	defer otelx.End(span, &err)
+
+	o.NID = p.NetworkID(ctx)
+	o.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	return update.Generic(ctx, p.GetConnection(ctx), p.r.Tracer(ctx).Tracer(), o, "label", "session_lifespan", "scim", "updated_at")
+}
+
+func (p *Persister) DeleteOrganization(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteOrganization")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"DELETE FROM organizations WHERE id = ? AND nid = ?",
+		id,
+		p.NetworkID(ctx),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+	if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows())
+	}
+	return nil
+}
+
+func (p *Persister) CreateOrganizationDomain(ctx context.Context, d *organization.Domain) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateOrganizationDomain")
+	defer otelx.End(span, &err)
+
+	d.ID = uuid.Must(uuid.NewV4())
+	d.NID = p.NetworkID(ctx)
+	d.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	d.UpdatedAt = d.CreatedAt
+
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(d))
+}
+
+func (p *Persister) UpdateOrganizationDomain(ctx context.Context, d *organization.Domain) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateOrganizationDomain")
+	defer otelx.End(span, &err)
+
+	d.NID = p.NetworkID(ctx)
+	d.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	return update.Generic(ctx, p.GetConnection(ctx), p.r.Tracer(ctx).Tracer(), d, "verified_at", "updated_at")
+}
+
+func (p *Persister) DeleteOrganizationDomain(ctx context.Context, organizationID uuid.UUID, domain string) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteOrganizationDomain")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"DELETE FROM organization_domains WHERE nid = ? AND organization_id = ? AND domain = ?",
+		p.NetworkID(ctx),
+		organizationID,
+		domain,
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+	if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows())
+	}
+	return nil
+}
+
+func (p *Persister) FindVerifiedOrganizationDomain(ctx context.Context, domain string) (_ *organization.Domain, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.FindVerifiedOrganizationDomain")
+	defer otelx.End(span, &err)
+
+	var d organization.Domain
+	if err := p.GetConnection(ctx).
+		Where("nid = ? AND domain = ? AND verified_at IS NOT NULL", p.NetworkID(ctx), domain).
+		First(&d); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &d, nil
+}
@@ -0,0 +1,182 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/pop/v6"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/sqlxx"
+)
+
+var _ identity.ScheduledDeletionPersister = new(Persister)
+
+func (p *Persister) ScheduleIdentityDeletion(ctx context.Context, d *identity.ScheduledDeletion) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ScheduleIdentityDeletion")
+	defer otelx.End(span, &err)
+
+	return p.Transaction(ctx, func(ctx context.Context, _ *pop.Connection) error {
+		if err := p.CancelScheduledIdentityDeletion(ctx, d.IdentityID); err != nil {
+			return err
+		}
+
+		d.ID = uuid.Must(uuid.NewV4())
+		d.NID = p.NetworkID(ctx)
+		d.DeleteAfter = d.DeleteAfter.UTC().Truncate(time.Microsecond)
+		d.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		d.UpdatedAt = d.CreatedAt
+		return sqlcon.HandleError(p.GetConnection(ctx).Create(d))
+	})
+}
+
+func (p *Persister) GetScheduledIdentityDeletion(ctx context.Context, identityID uuid.UUID) (_ *identity.ScheduledDeletion, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetScheduledIdentityDeletion")
+	defer otelx.End(span, &err)
+
+	var d identity.ScheduledDeletion
+	if err := p.GetConnection(ctx).Where("nid = ? AND identity_id = ?", p.NetworkID(ctx), identityID).First(&d); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &d, nil
+}
+
+func (p *Persister) CancelScheduledIdentityDeletion(ctx context.Context, identityID uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CancelScheduledIdentityDeletion")
+	defer otelx.End(span, &err)
+
+	return sqlcon.HandleError(
+		p.GetConnection(ctx).RawQuery(
+			"DELETE FROM identity_scheduled_deletions WHERE nid = ? AND identity_id = ?",
+			p.NetworkID(ctx),
+			identityID,
+		).Exec(),
+	)
+}
+
+func (p *Persister) SoftDeleteIdentity(ctx context.Context, identityID uuid.UUID, deleteAfter time.Time) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.SoftDeleteIdentity")
+	defer otelx.End(span, &err)
+
+	return p.Transaction(ctx, func(ctx context.Context, _ *pop.Connection) error {
+		i, err := p.GetIdentity(ctx, identityID, identity.ExpandNothing)
+		if err != nil {
+			return err
+		}
+
+		if i.State == identity.StatePendingDeletion {
+			return nil
+		}
+
+		restoreState := i.State
+		stateChangedAt := sqlxx.NullTime(time.Now().UTC())
+		i.State = identity.StatePendingDeletion
+		i.StateChangedAt = &stateChangedAt
+		if err := p.UpdateIdentityColumns(ctx, i, "state", "state_changed_at"); err != nil {
+			return err
+		}
+
+		if err := p.ScheduleIdentityDeletion(ctx, &identity.ScheduledDeletion{
+			IdentityID:   i.ID,
+			DeleteAfter:  deleteAfter,
+			RestoreState: sqlxx.NullString(restoreState),
+		}); err != nil {
+			return err
+		}
+
+		_, err = p.RevokeSessionsByIdentities(ctx, []uuid.UUID{i.ID})
+		return err
+	})
+}
+
+func (p *Persister) RestoreIdentity(ctx context.Context, identityID uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.RestoreIdentity")
+	defer otelx.End(span, &err)
+
+	return p.Transaction(ctx, func(ctx context.Context, _ *pop.Connection) error {
+		d, err := p.GetScheduledIdentityDeletion(ctx, identityID)
+		if errors.Is(err, sqlcon.ErrNoRows()) {
+			return errors.WithStack(herodot.ErrNotFound().WithReasonf("The identity is not scheduled for deletion."))
+		} else if err != nil {
+			return err
+		}
+
+		if d.RestoreState != "" {
+			i, err := p.GetIdentity(ctx, identityID, identity.ExpandNothing)
+			if err != nil {
+				return err
+			}
+
+			stateChangedAt := sqlxx.NullTime(time.Now().UTC())
+			i.State = identity.State(d.RestoreState)
+			i.StateChangedAt = &stateChangedAt
+			if err := p.UpdateIdentityColumns(ctx, i, "state", "state_changed_at"); err != nil {
+				return err
+			}
+		}
+
+		return p.CancelScheduledIdentityDeletion(ctx, identityID)
+	})
+}
+
+func (p *Persister) DeleteScheduledIdentities(ctx context.Context, now time.Time, limit int) (deleted int, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteScheduledIdentities")
+	defer otelx.End(span, &err)
+
+	var due []identity.ScheduledDeletion
+	if err := p.GetConnection(ctx).
+		Where("nid = ? AND delete_after <= ?", p.NetworkID(ctx), now.UTC()).
+		Order("delete_after ASC").
+		Limit(limit).
+		All(&due); err != nil {
+		return 0, sqlcon.HandleError(err)
+	}
+
+	for _, d := range due {
+		// Deleting the identity also removes the scheduled deletion, its
+		// sessions and its flows.
+		if err := p.PurgeIdentity(ctx, d.IdentityID); errors.Is(err, sqlcon.ErrNoRows()) {
+			continue
+		} else if err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func (p *Persister) PurgeIdentity(ctx context.Context, identityID uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.PurgeIdentity")
+	defer otelx.End(span, &err)
+
+	return p.Transaction(ctx, func(ctx context.Context, _ *pop.Connection) error {
+		if err := p.deleteIdentityCourierMessages(ctx, identityID); err != nil {
+			return err
+		}
+		return p.DeleteIdentity(ctx, identityID)
+	})
+}
+
+// deleteIdentityCourierMessages deletes the courier messages sent to any of
+// the identity's verifiable or recovery addresses.
+func (p *Persister) deleteIdentityCourierMessages(ctx context.Context, identityID uuid.UUID) error {
+	nid := p.NetworkID(ctx)
+	return sqlcon.HandleError(
+		p.GetConnection(ctx).RawQuery(
+			`DELETE FROM courier_messages WHERE nid = ? AND (
+				recipient IN (SELECT value FROM identity_verifiable_addresses WHERE nid = ? AND identity_id = ?) OR
+				recipient IN (SELECT value FROM identity_recovery_addresses WHERE nid = ? AND identity_id = ?)
+			)`,
+			nid, nid, identityID, nid, identityID,
+		).Exec(),
+	)
+}
@@ -0,0 +1,108 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/persistence/sql/update"
+	"github.com/ory/kratos/scim"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+)
+
+var _ scim.Persister = new(Persister)
+
+func (p *Persister) CreateSCIMUser(ctx context.Context, u *scim.StoredUser) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.CreateSCIMUser")
+	defer otelx.End(span, &err)
+
+	u.NID = p.NetworkID(ctx)
+	u.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	u.UpdatedAt = u.CreatedAt
+
+	return sqlcon.HandleError(p.GetConnection(ctx).Create(u))
+}
+
+func (p *Persister) GetSCIMUser(ctx context.Context, organizationID, id uuid.UUID) (_ *scim.StoredUser, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.GetSCIMUser")
+	defer otelx.End(span, &err)
+
+	var u scim.StoredUser
+	if err := p.GetConnection(ctx).
+		Where("id = ? AND nid = ? AND organization_id = ?", id, p.NetworkID(ctx), organizationID).
+		First(&u); err != nil {
+		return nil, sqlcon.HandleError(err)
+	}
+	return &u, nil
+}
+
+func (p *Persister) ListSCIMUsers(ctx context.Context, organizationID uuid.UUID, filter scim.UserFilter, offset, limit int) (_ []scim.StoredUser, _ int, err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.ListSCIMUsers")
+	defer otelx.End(span, &err)
+
+	where := []string{"nid = ?", "organization_id = ?"}
+	args := []any{p.NetworkID(ctx), organizationID}
+	if filter.ID != uuid.Nil {
+		where = append(where, "id = ?")
+		args = append(args, filter.ID)
+	}
+	if filter.UserName != "" {
+		where = append(where, "user_name = ?")
+		args = append(args, filter.UserName)
+	}
+	if filter.ExternalID != "" {
+		where = append(where, "external_id = ?")
+		args = append(args, filter.ExternalID)
+	}
+	clause := strings.Join(where, " AND ")
+
+	conn := p.GetConnection(ctx)
+	total, err := conn.Where(clause, args...).Count(new(scim.StoredUser))
+	if err != nil {
+		return nil, 0, sqlcon.HandleError(err)
+	}
+
+	users := make([]scim.StoredUser, 0)
+	if err := conn.RawQuery(
+		"SELECT * FROM scim_users WHERE "+clause+" ORDER BY created_at ASC, id ASC LIMIT ? OFFSET ?",
+		append(args, limit, offset)...,
+	).All(&users); err != nil {
+		return nil, 0, sqlcon.HandleError(err)
+	}
+	return users, total, nil
+}
+
+func (p *Persister) DeleteSCIMUser(ctx context.Context, organizationID, id uuid.UUID) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.DeleteSCIMUser")
+	defer otelx.End(span, &err)
+
+	count, err := p.GetConnection(ctx).RawQuery(
+		"DELETE FROM scim_users WHERE id = ? AND organization_id = ? AND nid = ?",
+		id,
+		organizationID,
+		p.NetworkID(ctx),
+	).ExecWithCount()
+	if err != nil {
+		return sqlcon.HandleError(err)
+	}
+	if count == 0 {
+		return errors.WithStack(sqlcon.ErrNoRows())
+	}
+	return nil
+}
+
+func (p *Persister) UpdateSCIMUser(ctx context.Context, u *scim.StoredUser) (err error) {
+	ctx, span := p.r.Tracer(ctx).Tracer().Start(ctx, "persistence.sql.UpdateSCIMUser")
+	defer otelx.End(span, &err)
+
+	u.NID = p.NetworkID(ctx)
+	u.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	return update.Generic(ctx, p.GetConnection(ctx), p.r.Tracer(ctx).Tracer(), u, "user_name", "external_id", "attributes", "updated_at")
+}
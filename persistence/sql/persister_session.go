@@ -111,14 +111,15 @@ func (p *Persister) ListSessions(ctx context.Context, active *bool, paginatorOpt
 		return nil, nil, err
 	}
 
+	identities := make([]*identity.Identity, 0, len(s))
 	for k := range s {
-		if s[k].Identity == nil {
-			continue
-		}
-		if err := p.InjectTraitsSchemaURL(ctx, s[k].Identity); err != nil {
-			return nil, nil, err
+		if s[k].Identity != nil {
+			identities = append(identities, s[k].Identity)
 		}
 	}
+	if err := p.InjectTraitsSchemaURLs(ctx, identities...); err != nil {
+		return nil, nil, err
+	}
 
 	s, nextPage := keysetpagination.Result(s, paginator)
 	return s, nextPage, nil
@@ -215,7 +216,7 @@ func (p *Persister) ExtendSession(ctx context.Context, sessionID uuid.UUID) (err
 		}
 
 		didRefresh = true
-		s = s.Refresh(ctx, p.r.Config())
+		s = s.Refresh(ctx, p.r.OrganizationManager())
 
 		if _, err := tx.Where("id = ? AND nid = ?", sessionID, nid).UpdateQuery(s, "expires_at"); err != nil {
 			return sqlcon.HandleError(err)
@@ -422,3 +422,25 @@ func NewUnknownAddressError() error {
 	},
 	)
 }
+
+func NewDeletionNotConfirmedError(instancePtr, phrase string) error {
+	t := text.NewErrorValidationSettingsDeletionNotConfirmed(phrase)
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: instancePtr,
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
+
+func NewTraitNotWritableError(instancePtr, property string) error {
+	t := text.NewErrorValidationTraitNotWritable(property)
+	return errors.WithStack(&ValidationError{
+		ValidationError: &jsonschema.ValidationError{
+			Message:     t.Text,
+			InstancePtr: instancePtr,
+		},
+		Messages: new(text.Messages).Add(t),
+	})
+}
@@ -48,6 +48,18 @@ type (
 		Organization struct {
 			Matcher string `json:"matcher"`
 		} `json:"organizations"`
+		// ReadOnlyAfterRegistration marks a trait which can be set during
+		// registration, but only be changed through the admin API afterwards.
+		ReadOnlyAfterRegistration bool `json:"readonly_after_registration"`
+		// AdminOnly marks a trait which can only be set through the admin API.
+		AdminOnly bool `json:"admin_only"`
+		// Normalize lists the normalizations applied to a string trait
+		// before it is validated and stored, in the given order.
+		Normalize []string `json:"normalize,omitempty"`
+		// Computed is a Jsonnet snippet which computes the trait's value
+		// from the other traits, available as `std.extVar('traits')`.
+		Computed string `json:"computed,omitempty"`
+
 		RawSchema map[string]interface{} `json:"-"`
 	}
 
@@ -40,6 +40,7 @@ type (
 		config.Provider
 		otelx.Provider
 		httpx.ClientProvider
+		PersistenceProvider
 	}
 	Handler struct {
 		r handlerDependencies
@@ -64,14 +65,20 @@ func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
 		httprouterx.AdminPrefix+"/"+SchemasPath+"/*",
 	)
 	public.GET(fmt.Sprintf("/%s/{id}", SchemasPath), h.getIdentitySchema)
+	public.GET(fmt.Sprintf("/%s/{id}/versions", SchemasPath), h.listVersions)
 	public.GET(fmt.Sprintf("/%s", SchemasPath), h.getAll)
 	public.GET(fmt.Sprintf("%s/%s/{id}", httprouterx.AdminPrefix, SchemasPath), h.getIdentitySchema)
+	public.GET(fmt.Sprintf("%s/%s/{id}/versions", httprouterx.AdminPrefix, SchemasPath), h.listVersions)
 	public.GET(fmt.Sprintf("%s/%s", httprouterx.AdminPrefix, SchemasPath), h.getAll)
 }
 
 func (h *Handler) RegisterAdminRoutes(admin *httprouterx.RouterAdmin) {
 	admin.GET(fmt.Sprintf("/%s/{id}", SchemasPath), redir.RedirectToPublicRoute(h.r))
+	admin.GET(fmt.Sprintf("/%s/{id}/versions", SchemasPath), redir.RedirectToPublicRoute(h.r))
 	admin.GET(fmt.Sprintf("/%s", SchemasPath), redir.RedirectToPublicRoute(h.r))
+	admin.POST(fmt.Sprintf("/%s", SchemasPath), h.createIdentitySchema)
+	admin.PUT(fmt.Sprintf("/%s/{id}", SchemasPath), h.updateIdentitySchema)
+	admin.DELETE(fmt.Sprintf("/%s/{id}", SchemasPath), h.deleteIdentitySchema)
 }
 
 // Raw JSON Schema
@@ -88,13 +95,18 @@ type _ struct {
 	// required: true
 	// in: path
 	ID string `json:"id"`
+
+	// Version of the schema to get. Defaults to the schema's current version.
+	//
+	// in: query
+	Version string `json:"version"`
 }
 
 // swagger:route GET /schemas/{id} identity getIdentitySchema
 //
 // # Get Identity JSON Schema
 //
-// Return a specific identity schema.
+// Return a specific identity schema. Set the `version` query parameter to get a previous version of the schema.
 //
 //	Produces:
 //	- application/json
@@ -125,6 +137,14 @@ func (h *Handler) getIdentitySchema(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if version := r.URL.Query().Get("version"); version != "" {
+		s, err = s.AtVersion(version)
+		if err != nil {
+			h.r.Writer().WriteError(w, r, err)
+			return
+		}
+	}
+
 	raw, err := h.ReadSchema(ctx, s.URL)
 	if err != nil {
 		code, ok := errorsx.GetCodeFromHerodotError(err)
@@ -225,6 +245,98 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	h.r.Writer().Write(w, r, ss)
 }
 
+// List of Identity JSON Schema Versions
+//
+// swagger:model identitySchemaVersions
+type IdentitySchemaVersions []identitySchemaVersion
+
+// An Identity JSON Schema Version
+//
+// swagger:model identitySchemaVersion
+type identitySchemaVersion struct {
+	// The version of the Identity JSON Schema
+	// required: true
+	Version string `json:"version"`
+	// Current is true for the version new identities are pinned to
+	// required: true
+	Current bool `json:"current"`
+	// The actual Identity JSON Schema
+	// required: true
+	Schema json.RawMessage `json:"schema"`
+}
+
+// List Identity JSON Schema Versions Parameters
+//
+// swagger:parameters listIdentitySchemaVersions
+type _ struct {
+	// ID must be set to the ID of schema you want to list the versions of
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// List Identity JSON Schema Versions Response
+//
+// swagger:response identitySchemaVersions
+type _ struct {
+	// in: body
+	Body IdentitySchemaVersions
+}
+
+// swagger:route GET /schemas/{id}/versions identity listIdentitySchemaVersions
+//
+// # List Identity Schema Versions
+//
+// Returns the current and all previous versions of an identity schema. Identities are pinned to the version
+// they were validated against, see the identity's `schema_version`.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Responses:
+//	  200: identitySchemaVersions
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-medium
+func (h *Handler) listVersions(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.r.Tracer(r.Context()).Tracer().Start(r.Context(), "schema.Handler.listVersions")
+	defer span.End()
+
+	ss, err := h.r.IdentityTraitsSchemas(ctx)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	id := r.PathValue("id")
+	s, err := ss.GetByID(id)
+	if err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrNotFound().WithReasonf("Identity schema `%s` could not be found.", id)))
+		return
+	}
+
+	versions := make(IdentitySchemaVersions, 0, len(s.Versions)+1)
+	for _, v := range append([]Version{{Version: s.Version, URL: s.URL, RawURL: s.RawURL}}, s.Versions...) {
+		raw, err := h.ReadSchema(ctx, v.URL)
+		if err != nil {
+			h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("The file for a JSON Schema version could not be found or opened. This is a configuration issue.").WithWrap(err)))
+			return
+		}
+		versions = append(versions, identitySchemaVersion{
+			Version: v.Version,
+			Current: v.Version == s.Version,
+			Schema:  raw,
+		})
+	}
+
+	h.r.Writer().Write(w, r, versions)
+}
+
 func (h *Handler) ReadSchema(ctx context.Context, uri *url.URL) (data []byte, err error) {
 	ctx, span := h.r.Tracer(ctx).Tracer().Start(ctx, "schema.Handler.ReadSchema")
 	defer otelx.End(span, &err)
@@ -0,0 +1,304 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/jsonx"
+	"github.com/ory/x/sqlcon"
+)
+
+// Create Identity Schema Body
+//
+// swagger:model createIdentitySchemaBody
+type CreateIdentitySchemaBody struct {
+	// ID is the ID identities use to reference the schema. It must not be
+	// used by a schema configured in `identity.schemas`.
+	//
+	// required: true
+	ID string `json:"id"`
+
+	// Schema is the identity's JSON Schema.
+	//
+	// required: true
+	Schema json.RawMessage `json:"schema"`
+}
+
+// Create Identity Schema Parameters
+//
+// swagger:parameters createIdentitySchema
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type createIdentitySchema struct {
+	// in: body
+	Body CreateIdentitySchemaBody
+}
+
+// Identity Schema Response
+//
+// swagger:response identitySchemaContainer
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type identitySchemaContainerResponse struct {
+	// in: body
+	Body identitySchemaContainer
+}
+
+// swagger:route POST /admin/schemas identity createIdentitySchema
+//
+// # Create an Identity Schema
+//
+// Stores an identity schema in the database. Stored schemas are served through the `/schemas` endpoints and can be
+// used by identities like the schemas configured in `identity.schemas`, without redeploying Ory Kratos.
+//
+// The schema is validated before it is stored. Its `ory.sh/kratos` extension must be valid, and `$ref`s are
+// restricted if `security.disallow_ref_in_identity_schemas` is enabled.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  201: identitySchemaContainer
+//	  400: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) createIdentitySchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body CreateIdentitySchemaBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithError(err.Error())))
+		return
+	}
+
+	if body.ID == "" {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReason("The identity schema ID must not be empty.")))
+		return
+	}
+
+	if configured, err := h.isConfiguredSchema(ctx, body.ID); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	} else if configured {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrConflict().WithReasonf("The identity schema %q is already configured in `identity.schemas`.", body.ID)))
+		return
+	}
+
+	if err := ValidateIdentitySchema(ctx, body.Schema, h.r.Config().SecurityDisallowRefInIdentitySchemas(ctx)); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	s := &StoredSchema{SchemaID: body.ID, Schema: []byte(body.Schema)}
+	if err := h.r.IdentitySchemaPersister().CreateIdentitySchema(ctx, s); errors.Is(err, sqlcon.ErrUniqueViolation()) {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrConflict().WithReasonf("The identity schema %q already exists.", body.ID)))
+		return
+	} else if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().WriteCreated(w, r, IDToURL(h.r.Config().SelfPublicURL(ctx), s.SchemaID).String(), &identitySchemaContainer{
+		ID:     s.SchemaID,
+		Schema: json.RawMessage(s.Schema),
+	})
+}
+
+// Update Identity Schema Body
+//
+// swagger:model updateIdentitySchemaBody
+type UpdateIdentitySchemaBody struct {
+	// Schema is the identity's JSON Schema.
+	//
+	// required: true
+	Schema json.RawMessage `json:"schema"`
+}
+
+// Update Identity Schema Parameters
+//
+// swagger:parameters updateIdentitySchema
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type updateIdentitySchema struct {
+	// ID must be set to the ID of the stored schema you want to update
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// in: body
+	Body UpdateIdentitySchemaBody
+}
+
+// swagger:route PUT /admin/schemas/{id} identity updateIdentitySchema
+//
+// # Update an Identity Schema
+//
+// Replaces the JSON Schema of an identity schema stored in the database. Schemas configured in `identity.schemas`
+// can not be updated.
+//
+// The new schema is validated like in `createIdentitySchema`. Schemas which are used by identities can not be
+// updated, as that would silently change how the identities are validated. Create a new schema and migrate the
+// identities to it instead.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identitySchemaContainer
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) updateIdentitySchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body UpdateIdentitySchemaBody
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&body); err != nil {
+		h.r.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithError(err.Error())))
+		return
+	}
+
+	s, err := h.getStoredSchema(ctx, r.PathValue("id"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := ValidateIdentitySchema(ctx, body.Schema, h.r.Config().SecurityDisallowRefInIdentitySchemas(ctx)); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	s.Schema = []byte(body.Schema)
+	if err := h.r.IdentitySchemaPersister().UpdateIdentitySchema(ctx, s); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	h.r.Writer().Write(w, r, &identitySchemaContainer{
+		ID:     s.SchemaID,
+		Schema: json.RawMessage(s.Schema),
+	})
+}
+
+// Delete Identity Schema Parameters
+//
+// swagger:parameters deleteIdentitySchema
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type deleteIdentitySchema struct {
+	// ID must be set to the ID of the stored schema you want to delete
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route DELETE /admin/schemas/{id} identity deleteIdentitySchema
+//
+// # Delete an Identity Schema
+//
+// Deletes an identity schema stored in the database. Schemas which are still used by identities can not be
+// deleted, migrate the identities to another schema first. Schemas configured in `identity.schemas` can not be
+// deleted.
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  404: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (h *Handler) deleteIdentitySchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	s, err := h.getStoredSchema(ctx, r.PathValue("id"))
+	if err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if err := h.r.IdentitySchemaPersister().DeleteIdentitySchema(ctx, s.SchemaID); err != nil {
+		h.r.Writer().WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getStoredSchema returns the stored schema with the given raw or base64
+// encoded ID.
+func (h *Handler) getStoredSchema(ctx context.Context, id string) (*StoredSchema, error) {
+	if configured, err := h.isConfiguredSchema(ctx, id); err != nil {
+		return nil, err
+	} else if configured {
+		return nil, errors.WithStack(herodot.ErrConflict().WithReasonf("The identity schema %q is configured in `identity.schemas` and can not be changed through the API.", id))
+	}
+
+	s, err := h.r.IdentitySchemaPersister().GetIdentitySchema(ctx, id)
+	if errors.Is(err, sqlcon.ErrNoRows()) {
+		if decoded, ok := TryDecodeID(id); ok {
+			s, err = h.r.IdentitySchemaPersister().GetIdentitySchema(ctx, decoded)
+		}
+	}
+	if errors.Is(err, sqlcon.ErrNoRows()) {
+		return nil, errors.WithStack(herodot.ErrNotFound().WithReasonf("Identity schema `%s` could not be found.", id))
+	} else if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// isConfiguredSchema returns true if the identity schema provider knows a
+// schema with the given ID which is not stored in the database.
+func (h *Handler) isConfiguredSchema(ctx context.Context, id string) (bool, error) {
+	ss, err := h.r.IdentityTraitsSchemas(ctx)
+	if err != nil {
+		return false, err
+	}
+	s, err := ss.GetByID(id)
+	if err != nil {
+		return false, nil
+	}
+	return !s.Stored, nil
+}
@@ -13,15 +13,18 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
 
 	"github.com/ory/client-go"
 	_ "github.com/ory/jsonschema/v3/fileloader"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
 	"github.com/ory/x/configx"
 	"github.com/ory/x/contextx"
 	"github.com/ory/x/httprouterx"
@@ -230,3 +233,177 @@ func TestHandler(t *testing.T) {
 		}
 	})
 }
+
+func TestHandlerVersions(t *testing.T) {
+	router := httprouterx.NewRouterPublic()
+	ts := contextx.NewConfigurableTestServer(router)
+	t.Cleanup(ts.Close)
+
+	_, reg := pkg.NewFastRegistryWithMocks(t, configx.WithValues(map[string]any{
+		config.ViperKeyPublicBaseURL:           ts.URL,
+		config.ViperKeyDefaultIdentitySchemaID: "default",
+		config.ViperKeyIdentitySchemas: config.Schemas{{
+			ID:       "default",
+			URL:      "file://./stub/identity-2.schema.json",
+			Version:  "v2",
+			Versions: []config.SchemaVersion{{Version: "v1", URL: "file://./stub/identity.schema.json"}},
+		}},
+	}))
+	reg.SchemaHandler().RegisterPublicRoutes(router)
+
+	get := func(t *testing.T, path string, expectCode int) []byte {
+		res, err := ts.Client(t.Context()).Get(ts.URL + path)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.EqualValuesf(t, expectCode, res.StatusCode, "%s", body)
+		return body
+	}
+
+	v1, err := os.ReadFile("./stub/identity.schema.json")
+	require.NoError(t, err)
+	v2, err := os.ReadFile("./stub/identity-2.schema.json")
+	require.NoError(t, err)
+
+	t.Run("case=get the current version", func(t *testing.T) {
+		assert.JSONEq(t, string(v2), string(get(t, "/schemas/default", http.StatusOK)))
+		assert.JSONEq(t, string(v2), string(get(t, "/schemas/default?version=v2", http.StatusOK)))
+	})
+
+	t.Run("case=get a previous version", func(t *testing.T) {
+		assert.JSONEq(t, string(v1), string(get(t, "/schemas/default?version=v1", http.StatusOK)))
+	})
+
+	t.Run("case=get an unknown version", func(t *testing.T) {
+		get(t, "/schemas/default?version=v3", http.StatusNotFound)
+	})
+
+	t.Run("case=list versions", func(t *testing.T) {
+		var actual []struct {
+			Version string          `json:"version"`
+			Current bool            `json:"current"`
+			Schema  json.RawMessage `json:"schema"`
+		}
+		require.NoError(t, json.Unmarshal(get(t, "/schemas/default/versions", http.StatusOK), &actual))
+
+		require.Len(t, actual, 2)
+		assert.Equal(t, "v2", actual[0].Version)
+		assert.True(t, actual[0].Current)
+		assert.JSONEq(t, string(v2), string(actual[0].Schema))
+		assert.Equal(t, "v1", actual[1].Version)
+		assert.False(t, actual[1].Current)
+		assert.JSONEq(t, string(v1), string(actual[1].Schema))
+
+		get(t, "/schemas/unknown/versions", http.StatusNotFound)
+	})
+}
+
+func TestHandlerStoredSchemas(t *testing.T) {
+	conf, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+		configx.WithValue(config.ViperKeySecurityDisallowRefInIdentitySchemas, true),
+	)
+	ctx := t.Context()
+	publicTS, adminTS := testhelpers.NewKratosServer(t, reg)
+
+	do := func(t *testing.T, method, href, body string, expectCode int) gjson.Result {
+		t.Helper()
+		req, err := http.NewRequest(method, href, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := adminTS.Client().Do(req)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		actual, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.EqualValuesf(t, expectCode, res.StatusCode, "%s", actual)
+		return gjson.ParseBytes(actual)
+	}
+
+	schemaWithTitle := func(title string) string {
+		return `{"$schema":"http://json-schema.org/draft-07/schema#","title":"` + title + `","type":"object","properties":{"traits":{"type":"object","properties":{"email":{"type":"string","format":"email","ory.sh/kratos":{"credentials":{"password":{"identifier":true}}}}}}}}`
+	}
+	create := func(t *testing.T, id, schema string, expectCode int) gjson.Result {
+		return do(t, "POST", adminTS.URL+"/admin/schemas", `{"id":"`+id+`","schema":`+schema+`}`, expectCode)
+	}
+
+	t.Run("case=creates a schema and serves it", func(t *testing.T) {
+		res := create(t, "customer", schemaWithTitle("v1"), http.StatusCreated)
+		assert.Equal(t, "customer", res.Get("id").String(), res.Raw)
+
+		res = do(t, "GET", publicTS.URL+"/schemas/customer", "", http.StatusOK)
+		assert.Equal(t, "v1", res.Get("title").String(), res.Raw)
+
+		res = do(t, "GET", publicTS.URL+"/schemas", "", http.StatusOK)
+		assert.ElementsMatch(t, []string{config.DefaultIdentityTraitsSchemaID, "customer"}, res.Get("#.id").Value(), res.Raw)
+	})
+
+	t.Run("case=rejects conflicting IDs", func(t *testing.T) {
+		create(t, "customer", schemaWithTitle("v1"), http.StatusConflict)
+		create(t, config.DefaultIdentityTraitsSchemaID, schemaWithTitle("v1"), http.StatusConflict)
+	})
+
+	t.Run("case=rejects invalid schemas", func(t *testing.T) {
+		for name, schema := range map[string]string{
+			"no traits":         `{"type":"object","properties":{}}`,
+			"invalid extension": `{"type":"object","properties":{"traits":{"type":"object","properties":{"email":{"type":"string","ory.sh/kratos":{"credentials":{"password":{"identifier":0}}}}}}}}`,
+			"file ref":          `{"type":"object","properties":{"traits":{"$ref":"file:///etc/passwd"}}}`,
+			"invalid pattern":   `{"type":"object","properties":{"traits":{"type":"string","pattern":"("}}}`,
+		} {
+			t.Run("schema="+name, func(t *testing.T) {
+				create(t, "invalid", schema, http.StatusBadRequest)
+			})
+		}
+		do(t, "GET", publicTS.URL+"/schemas/invalid", "", http.StatusNotFound)
+	})
+
+	t.Run("case=updates a schema", func(t *testing.T) {
+		res := do(t, "PUT", adminTS.URL+"/admin/schemas/customer", `{"schema":`+schemaWithTitle("v2")+`}`, http.StatusOK)
+		assert.Equal(t, "v2", res.Get("schema.title").String(), res.Raw)
+
+		res = do(t, "GET", publicTS.URL+"/schemas/customer", "", http.StatusOK)
+		assert.Equal(t, "v2", res.Get("title").String(), res.Raw)
+
+		do(t, "PUT", adminTS.URL+"/admin/schemas/customer", `{"schema":{"type":"object"}}`, http.StatusBadRequest)
+		do(t, "PUT", adminTS.URL+"/admin/schemas/unknown", `{"schema":`+schemaWithTitle("v2")+`}`, http.StatusNotFound)
+		do(t, "PUT", adminTS.URL+"/admin/schemas/"+config.DefaultIdentityTraitsSchemaID, `{"schema":`+schemaWithTitle("v2")+`}`, http.StatusConflict)
+	})
+
+	t.Run("case=validates identities against stored schemas", func(t *testing.T) {
+		do(t, "POST", adminTS.URL+"/admin/identities", `{"schema_id":"customer","traits":{"email":"not-an-email"}}`, http.StatusBadRequest)
+		do(t, "POST", adminTS.URL+"/admin/identities", `{"schema_id":"customer","traits":{"email":"`+testhelpers.RandomEmail()+`"}}`, http.StatusCreated)
+	})
+
+	t.Run("case=rejects updates while identities use the schema", func(t *testing.T) {
+		do(t, "PUT", adminTS.URL+"/admin/schemas/customer", `{"schema":`+schemaWithTitle("v3")+`}`, http.StatusConflict)
+
+		res := do(t, "GET", publicTS.URL+"/schemas/customer", "", http.StatusOK)
+		assert.Equal(t, "v2", res.Get("title").String(), res.Raw)
+	})
+
+	t.Run("case=deletes a schema", func(t *testing.T) {
+		do(t, "DELETE", adminTS.URL+"/admin/schemas/customer", "", http.StatusConflict)
+		do(t, "DELETE", adminTS.URL+"/admin/schemas/"+config.DefaultIdentityTraitsSchemaID, "", http.StatusConflict)
+
+		create(t, "preset://unused", schemaWithTitle("v1"), http.StatusCreated)
+		encoded := base64.RawURLEncoding.EncodeToString([]byte("preset://unused"))
+		do(t, "GET", publicTS.URL+"/schemas/"+encoded, "", http.StatusOK)
+		do(t, "DELETE", adminTS.URL+"/admin/schemas/"+encoded, "", http.StatusNoContent)
+		do(t, "GET", publicTS.URL+"/schemas/"+encoded, "", http.StatusNotFound)
+		do(t, "DELETE", adminTS.URL+"/admin/schemas/"+encoded, "", http.StatusNotFound)
+	})
+
+	t.Run("case=configured schemas take precedence", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyIdentitySchemas, config.Schemas{
+			{ID: config.DefaultIdentityTraitsSchemaID, URL: "file://./stub/identity.schema.json"},
+			{ID: "customer", URL: "file://./stub/identity-2.schema.json"},
+		})
+
+		ss, err := reg.IdentityTraitsSchemas(ctx)
+		require.NoError(t, err)
+		s, err := ss.GetByID("customer")
+		require.NoError(t, err)
+		assert.Equal(t, "file://./stub/identity-2.schema.json", s.RawURL)
+	})
+}
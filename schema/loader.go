@@ -6,6 +6,7 @@ package schema
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,7 +17,9 @@ import (
 	"github.com/ory/jsonschema/v3/httploader"
 
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
 
+	"github.com/ory/herodot"
 	"github.com/ory/jsonschema/v3"
 	"github.com/ory/x/httpx"
 )
@@ -96,6 +99,40 @@ func NewCompilerWithURL(ctx context.Context, schemaURL string, disallowRefs bool
 	return c, nil
 }
 
+// ValidateIdentitySchema checks that raw is an identity schema which can be
+// used to validate identities. The schema is prevalidated like every loaded
+// schema, must define the `traits` property, and is compiled together with
+// the `ory.sh/kratos` extension so that invalid extension configurations are
+// rejected. See NewCompiler for the semantics of disallowRefs.
+func ValidateIdentitySchema(ctx context.Context, raw []byte, disallowRefs bool) error {
+	if len(raw) > MaxSchemaBodyBytes {
+		return errors.WithStack(herodot.ErrBadRequest().WithReasonf("The identity schema must not be larger than %d bytes.", MaxSchemaBodyBytes))
+	}
+	if !gjson.ValidBytes(raw) {
+		return errors.WithStack(herodot.ErrBadRequest().WithReason("The identity schema is not valid JSON."))
+	}
+	if !gjson.GetBytes(raw, "properties.traits").IsObject() {
+		return errors.WithStack(herodot.ErrBadRequest().WithReason("The identity schema must define the `traits` property."))
+	}
+
+	schemaURL := "base64://" + base64.StdEncoding.EncodeToString(raw)
+	compiler, err := NewCompilerWithURL(ctx, schemaURL, disallowRefs)
+	if err != nil {
+		return errors.WithStack(herodot.ErrBadRequest().WithReason("The identity schema is invalid.").WithError(err.Error()))
+	}
+
+	runner, err := NewExtensionRunner(ctx)
+	if err != nil {
+		return err
+	}
+	runner.Register(compiler)
+
+	if _, err := compiler.Compile(ctx, schemaURL); err != nil {
+		return errors.WithStack(herodot.ErrBadRequest().WithReason("The identity schema is invalid.").WithError(err.Error()))
+	}
+	return nil
+}
+
 var defaultHTTPClient = httpx.NewResilientClient(httpx.ResilientClientDisallowInternalIPs())
 
 // ensureGuardedHTTPClient guarantees that the jsonschema httploader will
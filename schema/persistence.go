@@ -0,0 +1,72 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/x/sqlxx"
+)
+
+// StoredSchema is an identity schema which is stored in the database and
+// managed through the admin API instead of being configured in
+// `identity.schemas`.
+//
+// swagger:ignore
+type StoredSchema struct {
+	ID uuid.UUID `json:"-" db:"id"`
+
+	// NID is the network ID (multi-tenant discriminator).
+	NID uuid.UUID `json:"-" db:"nid"`
+
+	// SchemaID is the ID identities use to reference the schema.
+	SchemaID string `json:"id" db:"schema_id"`
+
+	// Schema is the raw JSON Schema.
+	Schema sqlxx.JSONRawMessage `json:"schema" db:"json_schema"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (StoredSchema) TableName() string {
+	return "identity_schemas"
+}
+
+// RawURL returns the schema as a base64 URL, which is understood by the
+// schema loaders and by Handler.ReadSchema.
+func (s *StoredSchema) RawURL() string {
+	return "base64://" + base64.StdEncoding.EncodeToString(s.Schema)
+}
+
+// Persister stores identity schemas in the database.
+type Persister interface {
+	// CreateIdentitySchema stores a new identity schema. It fails with a
+	// conflict if a schema with the same ID is already stored.
+	CreateIdentitySchema(ctx context.Context, s *StoredSchema) error
+
+	// GetIdentitySchema returns the stored identity schema with the given ID.
+	GetIdentitySchema(ctx context.Context, id string) (*StoredSchema, error)
+
+	// ListIdentitySchemas returns all stored identity schemas ordered by
+	// their ID.
+	ListIdentitySchemas(ctx context.Context) ([]StoredSchema, error)
+
+	// UpdateIdentitySchema replaces the JSON Schema of a stored identity
+	// schema. It fails with a conflict if identities use the schema.
+	UpdateIdentitySchema(ctx context.Context, s *StoredSchema) error
+
+	// DeleteIdentitySchema deletes a stored identity schema. It fails with
+	// a conflict if identities still use the schema.
+	DeleteIdentitySchema(ctx context.Context, id string) error
+}
+
+// PersistenceProvider provides access to the persister.
+type PersistenceProvider interface {
+	IdentitySchemaPersister() Persister
+}
@@ -32,6 +32,7 @@ type IdentitySchemaProvider interface {
 
 type deps interface {
 	config.Provider
+	PersistenceProvider
 }
 
 type DefaultIdentitySchemaProvider struct {
@@ -55,13 +56,39 @@ func (d *DefaultIdentitySchemaProvider) IdentityTraitsSchemas(ctx context.Contex
 			return nil, errors.WithStack(err)
 		}
 
+		var versions []Version
+		for _, v := range s.Versions {
+			vurl, err := url.Parse(v.URL)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			versions = append(versions, Version{Version: v.Version, URL: vurl, RawURL: v.URL})
+		}
+
 		ss = append(ss, Schema{
-			ID:     s.ID,
-			URL:    surl,
-			RawURL: s.URL,
+			ID:       s.ID,
+			URL:      surl,
+			RawURL:   s.URL,
+			Version:  s.Version,
+			Versions: versions,
 		})
 	}
 
+	// Schemas stored in the database are listed after the configured ones.
+	// If both use the same ID, the configured schema takes precedence.
+	stored, err := d.d.IdentitySchemaPersister().ListIdentitySchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stored {
+		raw := s.RawURL()
+		surl, err := url.Parse(raw)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		ss = append(ss, Schema{ID: s.SchemaID, URL: surl, RawURL: raw, Stored: true})
+	}
+
 	return ss, nil
 }
 
@@ -159,6 +186,50 @@ type Schema struct {
 	URL *url.URL `json:"-"`
 	// RawURL contains the raw URL value as it was passed in the configuration. URL parsing can break base64 encoded URLs.
 	RawURL string `json:"url"`
+	// Version is the version of the schema at URL. It is empty for unversioned schemas.
+	Version string `json:"version,omitempty"`
+	// Versions are the previous versions of the schema.
+	Versions []Version `json:"-"`
+	// Stored is true if the schema is stored in the database instead of being
+	// configured.
+	Stored bool `json:"-"`
+}
+
+// Version is a previous version of an identity schema.
+type Version struct {
+	Version string
+	URL     *url.URL
+	// RawURL contains the raw URL value as it was passed in the configuration.
+	RawURL string
+}
+
+// AtVersion returns the schema at the given version. An empty version is the
+// version of identities which were created before the schema was versioned,
+// which is the oldest declared version.
+func (s *Schema) AtVersion(version string) (*Schema, error) {
+	if version == "" && len(s.Versions) > 0 {
+		version = s.Versions[0].Version
+	}
+	if version == "" || version == s.Version {
+		return s, nil
+	}
+
+	for _, v := range s.Versions {
+		if v.Version == version {
+			return &Schema{ID: s.ID, URL: v.URL, RawURL: v.RawURL, Version: v.Version, Versions: s.Versions}, nil
+		}
+	}
+
+	return nil, errors.WithStack(herodot.ErrNotFound().WithReasonf("Identity schema %q has no version %q.", s.ID, version))
+}
+
+// IsCurrentVersion returns true if version is the schema's current version.
+// An empty version is only current if the schema has no previous versions.
+func (s *Schema) IsCurrentVersion(version string) bool {
+	if version == "" {
+		return len(s.Versions) == 0
+	}
+	return version == s.Version
 }
 
 func (s *Schema) SchemaURL(host *url.URL) *url.URL {
@@ -0,0 +1,160 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/ory/x/jsonschemax"
+)
+
+// TraitAccess lists the traits of an identity schema which self-service flows
+// may not write, as configured by the `readonly_after_registration` and
+// `admin_only` annotations of the `ory.sh/kratos` extension. Paths are
+// relative to the identity's traits, e.g. `employee_id`.
+type TraitAccess struct {
+	// ReadOnlyAfterRegistration contains the traits which can only be set
+	// during registration.
+	ReadOnlyAfterRegistration []string
+
+	// AdminOnly contains the traits which can only be set through the admin
+	// API.
+	AdminOnly []string
+}
+
+type traitAccessCacheKey struct {
+	schemaURL    string
+	disallowRefs bool
+}
+
+// traitAccesses caches the trait access per schema, as self-service flows
+// need it on every request. The TTL bounds how long changes to schemas
+// behind the same URL take to be picked up.
+var traitAccesses = expirable.NewLRU[traitAccessCacheKey, *TraitAccess](1024, nil, time.Minute)
+
+// NewTraitAccess lists the access-controlled traits of the identity schema at
+// schemaURL. See NewCompiler for the semantics of disallowRefs. The result is
+// cached and must not be modified.
+func NewTraitAccess(ctx context.Context, schemaURL string, disallowRefs bool) (*TraitAccess, error) {
+	key := traitAccessCacheKey{schemaURL: schemaURL, disallowRefs: disallowRefs}
+	if a, ok := traitAccesses.Get(key); ok {
+		return a, nil
+	}
+
+	a, err := newTraitAccess(ctx, schemaURL, disallowRefs)
+	if err != nil {
+		return nil, err
+	}
+	traitAccesses.Add(key, a)
+	return a, nil
+}
+
+func newTraitAccess(ctx context.Context, schemaURL string, disallowRefs bool) (*TraitAccess, error) {
+	c, err := NewCompilerWithURL(ctx, schemaURL, disallowRefs)
+	if err != nil {
+		return nil, err
+	}
+	runner, err := NewExtensionRunner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runner.Register(c)
+
+	paths, err := jsonschemax.ListPaths(ctx, schemaURL, c)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var a TraitAccess
+	for _, p := range paths {
+		name, ok := strings.CutPrefix(p.Name, "traits.")
+		if !ok || strings.Contains(name, "#") {
+			continue
+		}
+		ext, ok := p.CustomProperties[ExtensionName].(*ExtensionConfig)
+		if !ok {
+			continue
+		}
+		switch {
+		case ext.AdminOnly:
+			a.AdminOnly = append(a.AdminOnly, name)
+		case ext.ReadOnlyAfterRegistration:
+			a.ReadOnlyAfterRegistration = append(a.ReadOnlyAfterRegistration, name)
+		}
+	}
+	return &a, nil
+}
+
+// IsWritableInRegistration returns false if the trait at path can not be set
+// in self-service registration flows.
+func (a *TraitAccess) IsWritableInRegistration(path string) bool {
+	return !hasTraitPath(a.AdminOnly, path)
+}
+
+// IsWritableInSettings returns false if the trait at path can not be changed
+// in self-service settings flows.
+func (a *TraitAccess) IsWritableInSettings(path string) bool {
+	return !hasTraitPath(a.AdminOnly, path) && !hasTraitPath(a.ReadOnlyAfterRegistration, path)
+}
+
+// CheckRegistration returns a validation error if traits sets an admin-only
+// trait.
+func (a *TraitAccess) CheckRegistration(traits json.RawMessage) error {
+	for _, path := range a.AdminOnly {
+		if gjson.GetBytes(traits, path).Exists() {
+			return NewTraitNotWritableError(traitPointer(path), path)
+		}
+	}
+	return nil
+}
+
+// CheckSettings returns a validation error if updated changes a trait which
+// can not be changed in self-service settings flows. Protected traits missing
+// from updated, for example because the form field was disabled, keep their
+// original value.
+func (a *TraitAccess) CheckSettings(original, updated json.RawMessage) (json.RawMessage, error) {
+	for _, path := range slices.Concat(a.AdminOnly, a.ReadOnlyAfterRegistration) {
+		before, after := gjson.GetBytes(original, path), gjson.GetBytes(updated, path)
+		if !after.Exists() {
+			if !before.Exists() {
+				continue
+			}
+			var err error
+			updated, err = sjson.SetRawBytes(updated, path, []byte(before.Raw))
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			continue
+		}
+		if !before.Exists() || !reflect.DeepEqual(before.Value(), after.Value()) {
+			return nil, NewTraitNotWritableError(traitPointer(path), path)
+		}
+	}
+	return updated, nil
+}
+
+// hasTraitPath returns true if path is one of paths or nested below one of
+// them.
+func hasTraitPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if path == p || strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func traitPointer(path string) string {
+	return "#/traits/" + strings.ReplaceAll(path, ".", "/")
+}
@@ -0,0 +1,81 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package schema_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/schema"
+)
+
+func TestTraitAccess(t *testing.T) {
+	raw := `{
+  "type": "object",
+  "properties": {
+    "traits": {
+      "type": "object",
+      "properties": {
+        "email": {"type": "string"},
+        "employee_id": {"type": "string", "ory.sh/kratos": {"readonly_after_registration": true}},
+        "billing": {
+          "type": "object",
+          "ory.sh/kratos": {"admin_only": true},
+          "properties": {"plan": {"type": "string"}}
+        }
+      }
+    }
+  }
+}`
+	access, err := schema.NewTraitAccess(t.Context(), "base64://"+base64.StdEncoding.EncodeToString([]byte(raw)), true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"employee_id"}, access.ReadOnlyAfterRegistration)
+	assert.Equal(t, []string{"billing"}, access.AdminOnly)
+
+	t.Run("method=IsWritable", func(t *testing.T) {
+		assert.True(t, access.IsWritableInRegistration("email"))
+		assert.True(t, access.IsWritableInRegistration("employee_id"))
+		assert.False(t, access.IsWritableInRegistration("billing.plan"))
+
+		assert.True(t, access.IsWritableInSettings("email"))
+		assert.False(t, access.IsWritableInSettings("employee_id"))
+		assert.False(t, access.IsWritableInSettings("billing.plan"))
+	})
+
+	t.Run("method=CheckRegistration", func(t *testing.T) {
+		require.NoError(t, access.CheckRegistration(json.RawMessage(`{"email":"foo@ory.sh","employee_id":"E-1"}`)))
+
+		var ve *schema.ValidationError
+		require.ErrorAs(t, access.CheckRegistration(json.RawMessage(`{"billing":{"plan":"free"}}`)), &ve)
+		assert.Equal(t, "#/traits/billing", ve.InstancePtr)
+	})
+
+	t.Run("method=CheckSettings", func(t *testing.T) {
+		original := json.RawMessage(`{"email":"foo@ory.sh","employee_id":"E-1","billing":{"plan":"free"}}`)
+
+		actual, err := access.CheckSettings(original, json.RawMessage(`{"email":"bar@ory.sh"}`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"email":"bar@ory.sh","employee_id":"E-1","billing":{"plan":"free"}}`, string(actual))
+
+		actual, err = access.CheckSettings(original, json.RawMessage(`{"email":"bar@ory.sh","employee_id":"E-1","billing":{"plan":"free"}}`))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"email":"bar@ory.sh","employee_id":"E-1","billing":{"plan":"free"}}`, string(actual))
+
+		for _, updated := range []string{
+			`{"employee_id":"E-2"}`,
+			`{"billing":{"plan":"enterprise"}}`,
+		} {
+			_, err := access.CheckSettings(original, json.RawMessage(updated))
+			var ve *schema.ValidationError
+			require.ErrorAs(t, err, &ve, updated)
+		}
+
+		_, err = access.CheckSettings(json.RawMessage(`{"email":"foo@ory.sh"}`), json.RawMessage(`{"employee_id":"E-1"}`))
+		require.Error(t, err)
+	})
+}
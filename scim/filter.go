@@ -0,0 +1,120 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/herodot"
+)
+
+// parseFilter parses the subset of SCIM filters (RFC 7644, Section 3.4.2.2)
+// used by identity providers to look up users before provisioning them:
+// equality comparisons of `id`, `userName`, and `externalId`, optionally
+// combined with `and`.
+//
+// It returns false if the filter can not match any user.
+func parseFilter(filter string) (_ UserFilter, matchable bool, err error) {
+	var f UserFilter
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return f, false, err
+	}
+
+	matchable = true
+	for len(tokens) > 0 {
+		if len(tokens) < 3 || !strings.EqualFold(tokens[1], "eq") {
+			return f, false, unsupportedFilterError(filter)
+		}
+
+		var value string
+		if err := json.Unmarshal([]byte(tokens[2]), &value); err != nil {
+			return f, false, unsupportedFilterError(filter)
+		}
+
+		attr := strings.TrimPrefix(strings.ToLower(tokens[0]), strings.ToLower(SchemaUser)+":")
+		switch attr {
+		case "id":
+			id, err := uuid.FromString(value)
+			if err != nil || (f.ID != uuid.Nil && f.ID != id) {
+				matchable = false
+			}
+			f.ID = id
+		case "username":
+			value = normalizeUserName(value)
+			if f.UserName != "" && f.UserName != value {
+				matchable = false
+			}
+			f.UserName = value
+		case "externalid":
+			if f.ExternalID != "" && f.ExternalID != value {
+				matchable = false
+			}
+			f.ExternalID = value
+		default:
+			return f, false, unsupportedFilterError(filter)
+		}
+
+		tokens = tokens[3:]
+		if len(tokens) > 0 {
+			if !strings.EqualFold(tokens[0], "and") {
+				return f, false, unsupportedFilterError(filter)
+			}
+			tokens = tokens[1:]
+			if len(tokens) == 0 {
+				return f, false, unsupportedFilterError(filter)
+			}
+		}
+	}
+	return f, matchable, nil
+}
+
+// tokenizeFilter splits the filter at whitespace outside of quoted strings.
+// Quoted strings keep their quotes.
+func tokenizeFilter(filter string) ([]string, error) {
+	var (
+		tokens  []string
+		current strings.Builder
+		quoted  bool
+		escaped bool
+	)
+	for _, c := range filter {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ' ' || c == '\t'):
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteRune(c)
+	}
+	if quoted {
+		return nil, newError(herodot.ErrBadRequest(), errorTypeInvalidFilter, "The filter contains an unterminated string.")
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+func unsupportedFilterError(filter string) error {
+	return newError(herodot.ErrBadRequest(), errorTypeInvalidFilter,
+		"The filter "+filter+" is not supported. Only equality comparisons of id, userName, and externalId combined with \"and\" are supported.")
+}
+
+// normalizeUserName returns the key userName is compared by. SCIM compares
+// userName case-insensitively.
+func normalizeUserName(userName string) string {
+	return strings.ToLower(userName)
+}
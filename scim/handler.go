@@ -0,0 +1,484 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+// Package scim implements a SCIM 2.0 (RFC 7643, RFC 7644) service provider
+// which lets an organization's identity provider provision the
+// organization's identities.
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x/nosurfx"
+	"github.com/ory/kratos/x/transaction"
+	"github.com/ory/pop/v6"
+	"github.com/ory/x/httprouterx"
+	"github.com/ory/x/httpx"
+	"github.com/ory/x/jsonnetsecure"
+	"github.com/ory/x/logrusx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/urlx"
+)
+
+const (
+	RouteBase                  = "/scim/{organization}/v2"
+	RouteServiceProviderConfig = RouteBase + "/ServiceProviderConfig"
+	RouteUsers                 = RouteBase + "/Users"
+	RouteUser                  = RouteUsers + "/{id}"
+)
+
+const (
+	// defaultPageSize is the number of users returned if the client does not
+	// set `count`.
+	defaultPageSize = 100
+	// maxPageSize is the maximum number of users returned at once.
+	maxPageSize = 1000
+	// maxRequestSize is the maximum size of request bodies.
+	maxRequestSize = 1 << 20
+)
+
+type (
+	handlerDependencies interface {
+		httpx.WriterProvider
+		httpx.ClientProvider
+		logrusx.Provider
+		nosurfx.CSRFProvider
+		config.Provider
+		jsonnetsecure.VMProvider
+		identity.ManagementProvider
+		identity.PrivilegedPoolProvider
+		identity.ScheduledDeletionPersistenceProvider
+		organization.ManagementProvider
+		session.PersistenceProvider
+		transaction.PersistenceProvider
+		PersistenceProvider
+	}
+	Handler struct {
+		r handlerDependencies
+	}
+	HandlerProvider interface {
+		SCIMHandler() *Handler
+	}
+)
+
+func NewHandler(r handlerDependencies) *Handler {
+	return &Handler{r: r}
+}
+
+func (h *Handler) RegisterPublicRoutes(public *httprouterx.RouterPublic) {
+	// SCIM clients authenticate with bearer tokens instead of cookies.
+	h.r.CSRFHandler().IgnoreGlobs("/scim/*/v2/Users", "/scim/*/v2/Users/*")
+
+	public.GET(RouteServiceProviderConfig, h.withOrganization(h.getServiceProviderConfig))
+	public.GET(RouteUsers, h.withOrganization(h.listUsers))
+	public.POST(RouteUsers, h.withOrganization(h.createUser))
+	public.GET(RouteUser, h.withOrganization(h.getUser))
+	public.PUT(RouteUser, h.withOrganization(h.replaceUser))
+	public.PATCH(RouteUser, h.withOrganization(h.patchUser))
+	public.DELETE(RouteUser, h.withOrganization(h.deleteUser))
+}
+
+type organizationHandler func(w http.ResponseWriter, r *http.Request, org *config.Organization)
+
+// withOrganization authenticates the SCIM client with one of the bearer
+// tokens of the organization in the URL.
+func (h *Handler) withOrganization(next organizationHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		unauthorized := errors.WithStack(herodot.ErrUnauthorized().WithReason("The bearer token is invalid or SCIM provisioning is not enabled for the organization."))
+
+		id, err := uuid.FromString(r.PathValue("organization"))
+		if err != nil {
+			h.writeError(w, r, unauthorized)
+			return
+		}
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "bearer") || token == "" {
+			h.writeError(w, r, unauthorized)
+			return
+		}
+		org, ok, err := h.r.OrganizationManager().AuthenticateSCIM(r.Context(), id, token)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		} else if !ok {
+			h.writeError(w, r, unauthorized)
+			return
+		}
+		next(w, r, org)
+	}
+}
+
+func (h *Handler) getServiceProviderConfig(w http.ResponseWriter, r *http.Request, org *config.Organization) {
+	h.write(w, http.StatusOK, map[string]any{
+		"schemas":          []string{SchemaServiceProviderConfig},
+		"documentationUri": "https://www.ory.com/docs/kratos",
+		"patch":            map[string]any{"supported": true},
+		"bulk":             map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":           map[string]any{"supported": true, "maxResults": maxPageSize},
+		"changePassword":   map[string]any{"supported": false},
+		"sort":             map[string]any{"supported": false},
+		"etag":             map[string]any{"supported": false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with a bearer token configured for the organization.",
+			"primary":     true,
+		}},
+		"meta": map[string]any{
+			"resourceType": "ServiceProviderConfig",
+			"location":     h.location(r.Context(), org, "ServiceProviderConfig"),
+		},
+	})
+}
+
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request, org *config.Organization) {
+	query := r.URL.Query()
+	startIndex, count := 1, defaultPageSize
+	if v := query.Get("startIndex"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			h.writeError(w, r, newError(herodot.ErrBadRequest(), errorTypeInvalidValue, "The parameter startIndex must be an integer."))
+			return
+		}
+		startIndex = max(n, 1)
+	}
+	if v := query.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			h.writeError(w, r, newError(herodot.ErrBadRequest(), errorTypeInvalidValue, "The parameter count must be an integer."))
+			return
+		}
+		count = min(max(n, 0), maxPageSize)
+	}
+
+	var filter UserFilter
+	matchable := true
+	if v := query.Get("filter"); v != "" {
+		var err error
+		if filter, matchable, err = parseFilter(v); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+	}
+
+	users, total := []StoredUser{}, 0
+	if matchable {
+		var err error
+		if users, total, err = h.r.SCIMPersister().ListSCIMUsers(r.Context(), org.ID, filter, startIndex-1, count); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+	}
+
+	resources := make([]resource, 0, len(users))
+	for k := range users {
+		res, err := users[k].render(h.location(r.Context(), org, "Users", users[k].ID.String()))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		resources = append(resources, res)
+	}
+
+	h.write(w, http.StatusOK, map[string]any{
+		"schemas":      []string{SchemaListResponse},
+		"totalResults": total,
+		"startIndex":   startIndex,
+		"itemsPerPage": len(resources),
+		"Resources":    resources,
+	})
+}
+
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request, org *config.Organization) {
+	raw, err := readBody(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	res, err := newUserResource(raw)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	ctx := r.Context()
+	if err := h.checkUserNameAvailable(ctx, org, res.userName()); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	i := identity.NewIdentity(org.SCIM.SchemaID)
+	i.OrganizationID = uuid.NullUUID{UUID: org.ID, Valid: true}
+	i.State = identityState(res)
+	if err := h.mapUser(ctx, org, res, i); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	attributes, err := json.Marshal(res)
+	if err != nil {
+		h.writeError(w, r, errors.WithStack(err))
+		return
+	}
+	u := &StoredUser{
+		OrganizationID: org.ID,
+		UserName:       normalizeUserName(res.userName()),
+		ExternalID:     res.externalID(),
+		Attributes:     attributes,
+	}
+	if err := h.r.TransactionalPersisterProvider().Transaction(ctx, func(ctx context.Context, _ *pop.Connection) error {
+		if err := h.r.IdentityManager().Create(ctx, i); err != nil {
+			return err
+		}
+		u.ID = i.ID
+		return h.r.SCIMPersister().CreateSCIMUser(ctx, u)
+	}); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeUser(w, r, org, http.StatusCreated, u)
+}
+
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request, org *config.Organization) {
+	u, err := h.r.SCIMPersister().GetSCIMUser(r.Context(), org.ID, uuid.FromStringOrNil(r.PathValue("id")))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.writeUser(w, r, org, http.StatusOK, u)
+}
+
+func (h *Handler) replaceUser(w http.ResponseWriter, r *http.Request, org *config.Organization) {
+	u, err := h.r.SCIMPersister().GetSCIMUser(r.Context(), org.ID, uuid.FromStringOrNil(r.PathValue("id")))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	raw, err := readBody(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	res, err := newUserResource(raw)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	if err := h.updateUser(r.Context(), org, u, res); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.writeUser(w, r, org, http.StatusOK, u)
+}
+
+func (h *Handler) patchUser(w http.ResponseWriter, r *http.Request, org *config.Organization) {
+	u, err := h.r.SCIMPersister().GetSCIMUser(r.Context(), org.ID, uuid.FromStringOrNil(r.PathValue("id")))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	raw, err := readBody(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	var req patchRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		h.writeError(w, r, newError(herodot.ErrBadRequest(), errorTypeInvalidSyntax, "The request body is not a valid PATCH request."))
+		return
+	}
+
+	var res resource
+	if err := json.Unmarshal(u.Attributes, &res); err != nil {
+		h.writeError(w, r, errors.WithStack(err))
+		return
+	}
+	if res == nil {
+		res = resource{}
+	}
+	if err := res.applyPatch(&req); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	res.delete("id")
+	res.delete("meta")
+
+	if err := h.updateUser(r.Context(), org, u, res); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.writeUser(w, r, org, http.StatusOK, u)
+}
+
+func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request, org *config.Organization) {
+	u, err := h.r.SCIMPersister().GetSCIMUser(r.Context(), org.ID, uuid.FromStringOrNil(r.PathValue("id")))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	ctx := r.Context()
+
+	grace := h.r.Config().IdentityDeletionGracePeriod(ctx)
+	if grace <= 0 {
+		// The SCIM user is deleted together with the identity.
+		if err := h.r.PrivilegedIdentityPool().DeleteIdentity(ctx, u.ID); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// The identity is kept until the grace period has passed and can be
+	// restored until then. The SCIM user is removed right away, so the
+	// client no longer sees it and can reuse its user name.
+	if err := h.r.TransactionalPersisterProvider().Transaction(ctx, func(ctx context.Context, _ *pop.Connection) error {
+		if err := h.r.ScheduledDeletionPersister().SoftDeleteIdentity(ctx, u.ID, time.Now().Add(grace)); err != nil {
+			return err
+		}
+		return h.r.SCIMPersister().DeleteSCIMUser(ctx, org.ID, u.ID)
+	}); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// updateUser updates the identity and the stored SCIM user from the User
+// resource. Deactivating the user revokes the identity's sessions.
+func (h *Handler) updateUser(ctx context.Context, org *config.Organization, u *StoredUser, res resource) error {
+	userName := normalizeUserName(res.userName())
+	if userName != u.UserName {
+		if err := h.checkUserNameAvailable(ctx, org, userName); err != nil {
+			return err
+		}
+	}
+
+	i, err := h.r.PrivilegedIdentityPool().GetIdentityConfidential(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if err := h.mapUser(ctx, org, res, i); err != nil {
+		return err
+	}
+	deactivated := i.State == identity.StateActive && !res.active()
+	i.State = identityState(res)
+
+	attributes, err := json.Marshal(res)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	u.UserName, u.ExternalID, u.Attributes = userName, res.externalID(), attributes
+
+	return h.r.TransactionalPersisterProvider().Transaction(ctx, func(ctx context.Context, _ *pop.Connection) error {
+		if err := h.r.IdentityManager().Update(ctx, i, identity.ManagerAllowWriteProtectedTraits); err != nil {
+			return err
+		}
+		if err := h.r.SCIMPersister().UpdateSCIMUser(ctx, u); err != nil {
+			return err
+		}
+		if deactivated {
+			if _, err := h.r.SessionPersister().RevokeSessionsIdentityExcept(ctx, i.ID, uuid.Nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (h *Handler) checkUserNameAvailable(ctx context.Context, org *config.Organization, userName string) error {
+	_, total, err := h.r.SCIMPersister().ListSCIMUsers(ctx, org.ID, UserFilter{UserName: normalizeUserName(userName)}, 0, 0)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return newError(herodot.ErrConflict(), errorTypeUniqueness, "A user with this userName already exists.")
+	}
+	return nil
+}
+
+func identityState(res resource) identity.State {
+	if res.active() {
+		return identity.StateActive
+	}
+	return identity.StateInactive
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	raw, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize+1))
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("Unable to read the request body: %s", err))
+	}
+	if len(raw) > maxRequestSize {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReason("The request body is too large."))
+	}
+	return raw, nil
+}
+
+func (h *Handler) location(ctx context.Context, org *config.Organization, paths ...string) string {
+	return urlx.AppendPaths(h.r.Config().SelfPublicURL(ctx), append([]string{"scim", org.ID.String(), "v2"}, paths...)...).String()
+}
+
+func (h *Handler) writeUser(w http.ResponseWriter, r *http.Request, org *config.Organization, status int, u *StoredUser) {
+	location := h.location(r.Context(), org, "Users", u.ID.String())
+	res, err := u.render(location)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	if status == http.StatusCreated {
+		w.Header().Set("Location", location)
+	}
+	h.write(w, status, res)
+}
+
+func (h *Handler) write(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes the error in the format defined by RFC 7644, Section
+// 3.12.
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, sqlcon.ErrUniqueViolation()) {
+		err = newError(herodot.ErrConflict(), errorTypeUniqueness, "The user conflicts with an identity that already exists.")
+	}
+
+	de := herodot.ToDefaultError(err, "")
+	status := de.StatusCode()
+	detail := de.Reason()
+	if status >= http.StatusInternalServerError {
+		h.r.Logger().WithRequest(r).WithError(err).Error("Unable to handle SCIM request.")
+		detail = http.StatusText(status)
+	} else if detail == "" {
+		detail = de.Error()
+	}
+
+	body := map[string]any{
+		"schemas": []string{SchemaError},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	}
+	if scimType, ok := de.Details()["scimType"]; ok {
+		body["scimType"] = scimType
+	}
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+	}
+	h.write(w, status, body)
+}
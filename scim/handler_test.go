@@ -0,0 +1,253 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/kratos/scim"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/sqlcon"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	const (
+		tokenA = "organization-a-token-0123456789abcdef"
+		tokenB = "organization-b-token-0123456789abcdef"
+	)
+	orgA, orgB, orgDisabled := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+
+	_, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+		configx.WithValues(map[string]any{
+			config.ViperKeyOrganizations: []map[string]any{
+				{"id": orgA.String(), "scim": map[string]any{"bearer_tokens": []string{tokenA}, "mapper_url": "file://./stub/scim.jsonnet"}},
+				{"id": orgB.String(), "scim": map[string]any{"bearer_tokens": []string{tokenB}, "mapper_url": "file://./stub/scim.jsonnet"}},
+				{"id": orgDisabled.String()},
+			},
+		}),
+	)
+	public, _ := testhelpers.NewKratosServer(t, reg)
+
+	do := func(t *testing.T, org uuid.UUID, token, method, path string, body any) (*http.Response, []byte) {
+		var payload io.Reader
+		if body != nil {
+			raw, err := json.Marshal(body)
+			require.NoError(t, err)
+			payload = bytes.NewReader(raw)
+		}
+		req, err := http.NewRequestWithContext(t.Context(), method, public.URL+"/scim/"+org.String()+"/v2"+path, payload)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", scim.ContentType)
+		req.Header.Set("Authorization", "Bearer "+token)
+		res, err := public.Client().Do(req)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		raw, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, raw
+	}
+
+	newUser := func(userName string) map[string]any {
+		return map[string]any{
+			"schemas":    []string{scim.SchemaUser},
+			"userName":   userName,
+			"externalId": "external-" + userName,
+			"name":       map[string]any{"givenName": "Jane", "familyName": "Doe"},
+			"emails":     []map[string]any{{"type": "work", "value": userName, "primary": true}},
+			"active":     true,
+		}
+	}
+
+	t.Run("case=rejects unauthenticated requests", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			org   uuid.UUID
+			token string
+		}{
+			"wrong token":          {org: orgA, token: tokenB},
+			"missing token":        {org: orgA},
+			"unknown organization": {org: uuid.Must(uuid.NewV4()), token: tokenA},
+			"scim not enabled":     {org: orgDisabled, token: tokenA},
+		} {
+			t.Run("case="+name, func(t *testing.T) {
+				res, body := do(t, tc.org, tc.token, http.MethodGet, "/Users", nil)
+				assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "%s", body)
+				assert.Equal(t, scim.SchemaError, gjson.GetBytes(body, "schemas.0").String())
+				assert.Equal(t, "401", gjson.GetBytes(body, "status").String())
+			})
+		}
+	})
+
+	t.Run("case=returns the service provider config", func(t *testing.T) {
+		res, body := do(t, orgA, tokenA, http.MethodGet, "/ServiceProviderConfig", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, scim.ContentType, res.Header.Get("Content-Type"))
+		assert.True(t, gjson.GetBytes(body, "patch.supported").Bool())
+		assert.True(t, gjson.GetBytes(body, "filter.supported").Bool())
+	})
+
+	var userID string
+	t.Run("case=provisions a user", func(t *testing.T) {
+		res, body := do(t, orgA, tokenA, http.MethodPost, "/Users", newUser("jane@example.com"))
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", body)
+		userID = gjson.GetBytes(body, "id").String()
+		assert.Equal(t, public.URL+"/scim/"+orgA.String()+"/v2/Users/"+userID, res.Header.Get("Location"))
+		assert.Equal(t, "jane@example.com", gjson.GetBytes(body, "userName").String())
+		assert.Equal(t, "User", gjson.GetBytes(body, "meta.resourceType").String())
+
+		i, err := reg.PrivilegedIdentityPool().GetIdentity(t.Context(), uuid.FromStringOrNil(userID), identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"email":"jane@example.com","first_name":"Jane","last_name":"Doe"}`, string(i.Traits))
+		assert.Equal(t, orgA, i.OrganizationID.UUID)
+		assert.Equal(t, identity.StateActive, i.State)
+	})
+
+	t.Run("case=rejects duplicate user names", func(t *testing.T) {
+		res, body := do(t, orgA, tokenA, http.MethodPost, "/Users", newUser("JANE@example.com"))
+		assert.Equal(t, http.StatusConflict, res.StatusCode, "%s", body)
+		assert.Equal(t, "uniqueness", gjson.GetBytes(body, "scimType").String())
+	})
+
+	t.Run("case=filters users", func(t *testing.T) {
+		for filter, expected := range map[string]int64{
+			`userName eq "Jane@Example.com"`:                                            1,
+			`externalId eq "external-jane@example.com"`:                                 1,
+			`userName eq "jane@example.com" and externalId eq "external-other"`:         0,
+			`userName eq "nobody@example.com"`:                                          0,
+			`id eq "` + userID + `"`:                                                    1,
+			`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "jane@example.com"`: 1,
+		} {
+			t.Run("filter="+filter, func(t *testing.T) {
+				res, body := do(t, orgA, tokenA, http.MethodGet, "/Users?filter="+url.QueryEscape(filter), nil)
+				require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+				assert.Equal(t, expected, gjson.GetBytes(body, "totalResults").Int())
+				assert.Equal(t, expected, gjson.GetBytes(body, "Resources.#").Int())
+			})
+		}
+
+		res, body := do(t, orgA, tokenA, http.MethodGet, "/Users?filter="+url.QueryEscape(`emails.value co "example"`), nil)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+		assert.Equal(t, "invalidFilter", gjson.GetBytes(body, "scimType").String())
+	})
+
+	t.Run("case=users are scoped to the organization", func(t *testing.T) {
+		res, body := do(t, orgB, tokenB, http.MethodGet, "/Users/"+userID, nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+
+		res, body = do(t, orgB, tokenB, http.MethodGet, "/Users", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.EqualValues(t, 0, gjson.GetBytes(body, "totalResults").Int())
+
+		res, body = do(t, orgB, tokenB, http.MethodDelete, "/Users/"+userID, nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+	})
+
+	t.Run("case=patches a user and revokes the sessions of deactivated users", func(t *testing.T) {
+		i, err := reg.PrivilegedIdentityPool().GetIdentity(t.Context(), uuid.FromStringOrNil(userID), identity.ExpandNothing)
+		require.NoError(t, err)
+		req := testhelpers.NewTestHTTPRequest(t, "GET", "/sessions/whoami", nil)
+		sess, err := testhelpers.NewActiveSession(req, reg, i, time.Now().UTC(), identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+		require.NoError(t, err)
+		require.NoError(t, reg.SessionPersister().UpsertSession(t.Context(), sess))
+
+		// The operations are formatted as sent by Microsoft Entra ID.
+		res, body := do(t, orgA, tokenA, http.MethodPatch, "/Users/"+userID, map[string]any{
+			"schemas": []string{scim.SchemaPatchOp},
+			"Operations": []map[string]any{
+				{"op": "Replace", "path": "active", "value": "False"},
+				{"op": "Replace", "path": "name.familyName", "value": "Smith"},
+				{"op": "Replace", "path": `emails[type eq "work"].value`, "value": "jane@example.com"},
+				{"op": "Add", "path": "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", "value": "Engineering"},
+			},
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.False(t, gjson.GetBytes(body, "active").Bool())
+		assert.Equal(t, "Smith", gjson.GetBytes(body, "name.familyName").String())
+		assert.EqualValues(t, 1, gjson.GetBytes(body, "emails.#").Int())
+
+		i, err = reg.PrivilegedIdentityPool().GetIdentity(t.Context(), i.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.Equal(t, identity.StateInactive, i.State)
+		assert.Equal(t, "Smith", gjson.GetBytes(i.Traits, "last_name").String())
+		assert.Equal(t, "Engineering", gjson.GetBytes(i.MetadataAdmin, "department").String())
+
+		sess, err = reg.SessionPersister().GetSession(t.Context(), sess.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.False(t, sess.Active)
+
+		res, body = do(t, orgA, tokenA, http.MethodPatch, "/Users/"+userID, map[string]any{
+			"schemas":    []string{scim.SchemaPatchOp},
+			"Operations": []map[string]any{{"op": "move", "path": "active"}},
+		})
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, "%s", body)
+	})
+
+	t.Run("case=replaces a user", func(t *testing.T) {
+		user := newUser("jane.doe@example.com")
+		delete(user, "name")
+		res, body := do(t, orgA, tokenA, http.MethodPut, "/Users/"+userID, user)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, "jane.doe@example.com", gjson.GetBytes(body, "userName").String())
+		assert.True(t, gjson.GetBytes(body, "active").Bool())
+		assert.False(t, gjson.GetBytes(body, "name").Exists())
+
+		i, err := reg.PrivilegedIdentityPool().GetIdentity(t.Context(), uuid.FromStringOrNil(userID), identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"email":"jane.doe@example.com"}`, string(i.Traits))
+		assert.Equal(t, identity.StateActive, i.State)
+	})
+
+	t.Run("case=deletes a user", func(t *testing.T) {
+		res, body := do(t, orgA, tokenA, http.MethodDelete, "/Users/"+userID, nil)
+		require.Equal(t, http.StatusNoContent, res.StatusCode, "%s", body)
+
+		_, err := reg.PrivilegedIdentityPool().GetIdentity(t.Context(), uuid.FromStringOrNil(userID), identity.ExpandNothing)
+		require.ErrorIs(t, err, sqlcon.ErrNoRows())
+
+		res, body = do(t, orgA, tokenA, http.MethodGet, "/Users/"+userID, nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+	})
+
+	t.Run("case=soft-deletes a user during the deletion grace period", func(t *testing.T) {
+		reg.Config().MustSet(t.Context(), config.ViperKeyIdentityDeletionGracePeriod, "24h")
+		t.Cleanup(func() {
+			reg.Config().MustSet(t.Context(), config.ViperKeyIdentityDeletionGracePeriod, "0s")
+		})
+
+		res, body := do(t, orgA, tokenA, http.MethodPost, "/Users", newUser("john@example.com"))
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", body)
+		id := gjson.GetBytes(body, "id").String()
+
+		res, body = do(t, orgA, tokenA, http.MethodDelete, "/Users/"+id, nil)
+		require.Equal(t, http.StatusNoContent, res.StatusCode, "%s", body)
+
+		i, err := reg.PrivilegedIdentityPool().GetIdentity(t.Context(), uuid.FromStringOrNil(id), identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.Equal(t, identity.StatePendingDeletion, i.State)
+
+		res, body = do(t, orgA, tokenA, http.MethodGet, "/Users/"+id, nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+
+		res, body = do(t, orgA, tokenA, http.MethodGet, "/Users?filter="+url.QueryEscape(`userName eq "john@example.com"`), nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Zero(t, gjson.GetBytes(body, "totalResults").Int())
+	})
+}
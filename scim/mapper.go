@@ -0,0 +1,78 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/fetcher"
+)
+
+var jsonnetCache, _ = ristretto.NewCache(&ristretto.Config[[]byte, []byte]{
+	MaxCost:     100 << 20, // 100MB,
+	NumCounters: 1_000_000, // 1kB per snippet -> 100k snippets -> 1M counters
+	BufferItems: 64,
+})
+
+// mapUser sets the identity's traits and, if returned by the organization's
+// Jsonnet mapper, its metadata from the User resource.
+func (h *Handler) mapUser(ctx context.Context, org *config.Organization, res resource, i *identity.Identity) error {
+	fetch := fetcher.NewFetcher(fetcher.WithClient(h.r.HTTPClient(ctx)), fetcher.WithCache(jsonnetCache, 60*time.Minute))
+	snippet, err := fetch.FetchContext(ctx, org.SCIM.MapperURL)
+	if err != nil {
+		return err
+	}
+
+	user, err := json.Marshal(res)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	vm, err := h.r.JsonnetVM(ctx)
+	if err != nil {
+		return err
+	}
+	vm.ExtCode("user", string(user))
+
+	evaluated, err := vm.EvaluateAnonymousSnippet(org.SCIM.MapperURL, snippet.String())
+	if err != nil {
+		return newError(herodot.ErrBadRequest(), errorTypeInvalidValue, "Unable to map the User resource to an identity: "+err.Error())
+	}
+
+	traits := gjson.Get(evaluated, "identity.traits")
+	if !traits.IsObject() {
+		return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("SCIM Jsonnet mapper did not return an object for key identity.traits. Please check your Jsonnet code!"))
+	}
+	i.Traits = identity.Traits(traits.Raw)
+
+	if metadata := gjson.Get(evaluated, "identity.metadata_public"); metadata.Exists() {
+		if !metadata.IsObject() {
+			return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("SCIM Jsonnet mapper did not return an object for key identity.metadata_public. Please check your Jsonnet code!"))
+		}
+		i.MetadataPublic = []byte(metadata.Raw)
+	}
+	if metadata := gjson.Get(evaluated, "identity.metadata_admin"); metadata.Exists() {
+		if !metadata.IsObject() {
+			return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("SCIM Jsonnet mapper did not return an object for key identity.metadata_admin. Please check your Jsonnet code!"))
+		}
+		i.MetadataAdmin = []byte(metadata.Raw)
+	}
+
+	h.r.Logger().
+		WithField("organization_id", org.ID).
+		WithSensitiveField("scim_user", res).
+		WithSensitiveField("mapper_jsonnet_output", evaluated).
+		WithField("mapper_jsonnet_url", org.SCIM.MapperURL).
+		Debug("SCIM Jsonnet mapper completed.")
+	return nil
+}
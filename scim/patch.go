@@ -0,0 +1,304 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ory/herodot"
+)
+
+type (
+	patchRequest struct {
+		Schemas    []string         `json:"schemas"`
+		Operations []patchOperation `json:"Operations"`
+	}
+	patchOperation struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}
+
+	// patchPath is a parsed PATCH path of the form
+	// `[urn:]attribute[[valueFilter]][.subAttribute]`.
+	patchPath struct {
+		// urn is the schema URN of an extension attribute.
+		urn    string
+		attr   string
+		filter *valueFilter
+		sub    string
+	}
+	// valueFilter selects the values of a multi-valued attribute whose
+	// sub-attribute equals the given value, for example `type eq "work"`.
+	valueFilter struct {
+		attr  string
+		value any
+	}
+)
+
+// applyPatch applies the operations of a SCIM PATCH request (RFC 7644,
+// Section 3.5.2) to the resource.
+func (r resource) applyPatch(req *patchRequest) error {
+	if len(req.Operations) == 0 {
+		return newError(herodot.ErrBadRequest(), errorTypeInvalidSyntax, "The PATCH request does not contain any operations.")
+	}
+	for _, op := range req.Operations {
+		if err := r.applyOperation(op); err != nil {
+			return err
+		}
+	}
+	return r.validateUser()
+}
+
+func (r resource) applyOperation(op patchOperation) error {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+		replace := strings.EqualFold(op.Op, "replace")
+		if op.Path == "" {
+			values, ok := op.Value.(map[string]any)
+			if !ok {
+				return newError(herodot.ErrBadRequest(), errorTypeInvalidValue, "Operations without a path require an object value.")
+			}
+			for k, v := range values {
+				if ext, ok := v.(map[string]any); ok && isURN(k) {
+					c := r.container(&patchPath{urn: k}, true)
+					for ek, ev := range ext {
+						c.setValue(ek, ev, replace)
+					}
+					continue
+				}
+				if err := r.applyOperation(patchOperation{Op: op.Op, Path: k, Value: v}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		pp, err := parsePatchPath(op.Path)
+		if err != nil {
+			return err
+		}
+		c := r.container(pp, true)
+		if pp.filter != nil {
+			return c.setFiltered(pp, op.Value)
+		}
+		if pp.sub != "" {
+			v, _ := c.get(pp.attr)
+			m, ok := v.(map[string]any)
+			if !ok {
+				m = map[string]any{}
+				c.set(pp.attr, m)
+			}
+			resource(m).setValue(pp.sub, op.Value, replace)
+			return nil
+		}
+		c.setValue(pp.attr, op.Value, replace)
+		return nil
+	case "remove":
+		if op.Path == "" {
+			return newError(herodot.ErrBadRequest(), errorTypeNoTarget, "Remove operations require a path.")
+		}
+		pp, err := parsePatchPath(op.Path)
+		if err != nil {
+			return err
+		}
+		c := r.container(pp, false)
+		if c == nil {
+			return nil
+		}
+		if pp.filter != nil {
+			c.removeFiltered(pp)
+			return nil
+		}
+		if pp.sub != "" {
+			if v, ok := c.get(pp.attr); ok {
+				if m, ok := v.(map[string]any); ok {
+					resource(m).delete(pp.sub)
+				}
+			}
+			return nil
+		}
+		c.delete(pp.attr)
+		return nil
+	default:
+		return newError(herodot.ErrBadRequest(), errorTypeInvalidSyntax, "The PATCH operation "+op.Op+" is not supported.")
+	}
+}
+
+// container returns the object holding the attributes of the path's schema.
+func (r resource) container(pp *patchPath, create bool) resource {
+	if pp.urn == "" {
+		return r
+	}
+	v, _ := r.get(pp.urn)
+	m, ok := v.(map[string]any)
+	if !ok {
+		if !create {
+			return nil
+		}
+		m = map[string]any{}
+		r.set(pp.urn, m)
+	}
+	return m
+}
+
+// setValue sets the attribute. Complex values are merged into existing ones
+// and, unless replace is set, values are appended to multi-valued attributes.
+func (r resource) setValue(name string, value any, replace bool) {
+	existing, _ := r.get(name)
+	switch e := existing.(type) {
+	case map[string]any:
+		if v, ok := value.(map[string]any); ok {
+			for k, sv := range v {
+				resource(e).set(k, sv)
+			}
+			return
+		}
+	case []any:
+		if !replace {
+			if v, ok := value.([]any); ok {
+				r.set(name, append(e, v...))
+			} else {
+				r.set(name, append(e, value))
+			}
+			return
+		}
+	}
+	r.set(name, value)
+}
+
+// setFiltered sets the value of the multi-valued attribute's values matching
+// the path's filter, adding a value if none matches.
+func (r resource) setFiltered(pp *patchPath, value any) error {
+	merge := func(m map[string]any) error {
+		if pp.sub != "" {
+			resource(m).set(pp.sub, value)
+			return nil
+		}
+		v, ok := value.(map[string]any)
+		if !ok {
+			return newError(herodot.ErrBadRequest(), errorTypeInvalidValue, "Operations on values of multi-valued attributes require an object value.")
+		}
+		for k, sv := range v {
+			resource(m).set(k, sv)
+		}
+		return nil
+	}
+
+	v, _ := r.get(pp.attr)
+	list, _ := v.([]any)
+	var matched bool
+	for _, e := range list {
+		if m, ok := e.(map[string]any); ok && pp.filter.matches(m) {
+			matched = true
+			if err := merge(m); err != nil {
+				return err
+			}
+		}
+	}
+	if !matched {
+		m := map[string]any{pp.filter.attr: pp.filter.value}
+		if err := merge(m); err != nil {
+			return err
+		}
+		r.set(pp.attr, append(list, m))
+	}
+	return nil
+}
+
+// removeFiltered removes the multi-valued attribute's values matching the
+// path's filter, or their sub-attribute if the path has one.
+func (r resource) removeFiltered(pp *patchPath) {
+	v, _ := r.get(pp.attr)
+	list, ok := v.([]any)
+	if !ok {
+		return
+	}
+	kept := make([]any, 0, len(list))
+	for _, e := range list {
+		m, ok := e.(map[string]any)
+		if !ok || !pp.filter.matches(m) {
+			kept = append(kept, e)
+			continue
+		}
+		if pp.sub != "" {
+			resource(m).delete(pp.sub)
+			kept = append(kept, m)
+		}
+	}
+	r.set(pp.attr, kept)
+}
+
+func (f *valueFilter) matches(m map[string]any) bool {
+	v, ok := resource(m).get(f.attr)
+	if !ok {
+		return false
+	}
+	if a, ok := v.(string); ok {
+		b, ok := f.value.(string)
+		return ok && strings.EqualFold(a, b)
+	}
+	return v == f.value
+}
+
+func parsePatchPath(raw string) (*patchPath, error) {
+	invalid := newError(herodot.ErrBadRequest(), errorTypeInvalidPath, "The PATCH path "+raw+" is invalid or not supported.")
+
+	path, pp := raw, new(patchPath)
+	if isURN(path) {
+		if strings.HasPrefix(strings.ToLower(path), strings.ToLower(SchemaUser)+":") {
+			path = path[len(SchemaUser)+1:]
+		} else {
+			// The attribute follows the last colon of the extension's URN.
+			end := len(path)
+			if i := strings.Index(path, "["); i >= 0 {
+				end = i
+			}
+			i := strings.LastIndex(path[:end], ":")
+			pp.urn, path = path[:i], path[i+1:]
+		}
+	}
+
+	if i := strings.Index(path, "["); i >= 0 {
+		j := strings.Index(path, "]")
+		if j < i {
+			return nil, invalid
+		}
+		tokens, err := tokenizeFilter(path[i+1 : j])
+		if err != nil || len(tokens) != 3 || !strings.EqualFold(tokens[1], "eq") {
+			return nil, invalid
+		}
+		f := &valueFilter{attr: tokens[0]}
+		if err := json.Unmarshal([]byte(tokens[2]), &f.value); err != nil {
+			return nil, invalid
+		}
+		switch f.value.(type) {
+		case string, bool, float64:
+		default:
+			return nil, invalid
+		}
+
+		pp.attr, pp.filter = path[:i], f
+		if rest := path[j+1:]; rest != "" {
+			if !strings.HasPrefix(rest, ".") {
+				return nil, invalid
+			}
+			pp.sub = rest[1:]
+		}
+	} else if i := strings.Index(path, "."); i >= 0 {
+		pp.attr, pp.sub = path[:i], path[i+1:]
+	} else {
+		pp.attr = path
+	}
+
+	if pp.attr == "" || (pp.filter != nil && pp.filter.attr == "") || strings.ContainsAny(pp.sub, ".[]") {
+		return nil, invalid
+	}
+	return pp, nil
+}
+
+func isURN(path string) bool {
+	return strings.HasPrefix(strings.ToLower(path), "urn:")
+}
@@ -0,0 +1,58 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/x/sqlxx"
+)
+
+type (
+	// StoredUser is the SCIM User resource of an identity provisioned by an
+	// organization's SCIM client.
+	//
+	// swagger:ignore
+	StoredUser struct {
+		// ID is the ID of the provisioned identity.
+		ID             uuid.UUID `db:"id"`
+		NID            uuid.UUID `db:"nid"`
+		OrganizationID uuid.UUID `db:"organization_id"`
+
+		UserName   string `db:"user_name"`
+		ExternalID string `db:"external_id"`
+
+		// Attributes is the User resource as last sent by the SCIM client.
+		Attributes sqlxx.JSONRawMessage `db:"attributes"`
+
+		CreatedAt time.Time `db:"created_at"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+
+	// UserFilter restricts the users returned by ListSCIMUsers. Empty fields
+	// are ignored.
+	UserFilter struct {
+		ID         uuid.UUID
+		UserName   string
+		ExternalID string
+	}
+
+	Persister interface {
+		CreateSCIMUser(ctx context.Context, u *StoredUser) error
+		GetSCIMUser(ctx context.Context, organizationID, id uuid.UUID) (*StoredUser, error)
+		ListSCIMUsers(ctx context.Context, organizationID uuid.UUID, filter UserFilter, offset, limit int) ([]StoredUser, int, error)
+		UpdateSCIMUser(ctx context.Context, u *StoredUser) error
+		DeleteSCIMUser(ctx context.Context, organizationID, id uuid.UUID) error
+	}
+	PersistenceProvider interface {
+		SCIMPersister() Persister
+	}
+)
+
+func (StoredUser) TableName() string {
+	return "scim_users"
+}
@@ -0,0 +1,175 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+)
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	// ContentType is the media type of SCIM requests and responses.
+	ContentType = "application/scim+json"
+)
+
+// SCIM error types returned in the `scimType` field of errors (RFC 7644,
+// Section 3.12).
+const (
+	errorTypeInvalidFilter = "invalidFilter"
+	errorTypeInvalidPath   = "invalidPath"
+	errorTypeInvalidSyntax = "invalidSyntax"
+	errorTypeInvalidValue  = "invalidValue"
+	errorTypeUniqueness    = "uniqueness"
+	errorTypeNoTarget      = "noTarget"
+)
+
+// resource is a SCIM resource. Attribute names are case-insensitive.
+type resource map[string]any
+
+// newUserResource decodes the User resource sent by a SCIM client and drops
+// the attributes which are assigned by the service provider.
+func newUserResource(raw []byte) (resource, error) {
+	var res resource
+	if err := json.Unmarshal(raw, &res); err != nil || res == nil {
+		return nil, newError(herodot.ErrBadRequest(), errorTypeInvalidSyntax, "The request body is not a valid SCIM resource.")
+	}
+	res.delete("id")
+	res.delete("meta")
+	return res, res.validateUser()
+}
+
+func (r resource) validateUser() error {
+	if r.userName() == "" {
+		return newError(herodot.ErrBadRequest(), errorTypeInvalidValue, "The attribute userName is required.")
+	}
+	if v, ok := r.get("active"); ok {
+		active, ok := parseBool(v)
+		if !ok {
+			return newError(herodot.ErrBadRequest(), errorTypeInvalidValue, "The attribute active must be a boolean.")
+		}
+		r.set("active", active)
+	}
+	return nil
+}
+
+// key returns the key of the attribute with the given case-insensitive name.
+func (r resource) key(name string) (string, bool) {
+	if _, ok := r[name]; ok {
+		return name, true
+	}
+	for k := range r {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+	return name, false
+}
+
+func (r resource) get(name string) (any, bool) {
+	k, ok := r.key(name)
+	if !ok {
+		return nil, false
+	}
+	return r[k], true
+}
+
+func (r resource) set(name string, value any) {
+	k, _ := r.key(name)
+	r[k] = value
+}
+
+func (r resource) delete(name string) {
+	if k, ok := r.key(name); ok {
+		delete(r, k)
+	}
+}
+
+func (r resource) string(name string) string {
+	v, _ := r.get(name)
+	s, _ := v.(string)
+	return s
+}
+
+func (r resource) userName() string { return r.string("userName") }
+
+func (r resource) externalID() string { return r.string("externalId") }
+
+// active returns the value of the `active` attribute, which defaults to true.
+func (r resource) active() bool {
+	v, ok := r.get("active")
+	if !ok {
+		return true
+	}
+	active, ok := parseBool(v)
+	return !ok || active
+}
+
+// parseBool parses a boolean attribute. Some clients, for example Microsoft
+// Entra ID, send booleans as strings.
+func parseBool(v any) (bool, bool) {
+	switch v := v.(type) {
+	case bool:
+		return v, true
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// render returns the User resource of the stored user as returned to SCIM
+// clients.
+func (u *StoredUser) render(location string) (resource, error) {
+	var res resource
+	if err := json.Unmarshal(u.Attributes, &res); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if res == nil {
+		res = resource{}
+	}
+
+	res.set("schemas", userSchemas(res))
+	res.set("id", u.ID.String())
+	res.set("active", res.active())
+	res.set("meta", map[string]any{
+		"resourceType": "User",
+		"created":      u.CreatedAt.UTC().Format(time.RFC3339),
+		"lastModified": u.UpdatedAt.UTC().Format(time.RFC3339),
+		"location":     location,
+	})
+	return res, nil
+}
+
+// userSchemas returns the schemas of the User resource, which always include
+// the core User schema.
+func userSchemas(res resource) []any {
+	schemas, _ := res.get("schemas")
+	list, _ := schemas.([]any)
+	for _, s := range list {
+		if s, ok := s.(string); ok && strings.EqualFold(s, SchemaUser) {
+			return list
+		}
+	}
+	return append([]any{SchemaUser}, list...)
+}
+
+// newError returns a herodot error carrying the SCIM error type.
+func newError(err *herodot.DefaultError, scimType, detail string) error {
+	return errors.WithStack(err.WithReason(detail).WithDetail("scimType", scimType))
+}
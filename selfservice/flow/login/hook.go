@@ -30,6 +30,7 @@ import (
 	"github.com/ory/x/httpx"
 	"github.com/ory/x/logrusx"
 	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
 )
 
 type (
@@ -53,6 +54,7 @@ type (
 		hydra.Provider
 		identity.PrivilegedPoolProvider
 		identity.ManagementProvider
+		identity.ScheduledDeletionPersistenceProvider
 		session.ManagementProvider
 		session.PersistenceProvider
 		nosurfx.CSRFTokenGeneratorProvider
@@ -125,6 +127,18 @@ func (e *HookExecutor) handleLoginError(_ http.ResponseWriter, r *http.Request,
 	return flowError
 }
 
+// cancelScheduledDeletion cancels the scheduled deletion of the identity, if
+// any, because signing in during the grace period of a scheduled account
+// deletion cancels the deletion.
+func (e *HookExecutor) cancelScheduledDeletion(ctx context.Context, i *identity.Identity) error {
+	if _, err := e.d.ScheduledDeletionPersister().GetScheduledIdentityDeletion(ctx, i.ID); errors.Is(err, sqlcon.ErrNoRows()) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return e.d.ScheduledDeletionPersister().CancelScheduledIdentityDeletion(ctx, i.ID)
+}
+
 func (e *HookExecutor) PostLoginHook(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -215,6 +229,10 @@ func (e *HookExecutor) PostLoginHook(
 			Debug("ExecuteLoginPostHook completed successfully.")
 	}
 
+	if err := e.cancelScheduledDeletion(ctx, i); err != nil {
+		return err
+	}
+
 	if f.Type == flow.TypeAPI {
 		span.SetAttributes(attribute.String("flow_type", string(flow.TypeAPI)))
 		if err := e.d.SessionPersister().UpsertSession(ctx, s); err != nil {
@@ -0,0 +1,171 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package login
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/jsonschemax"
+	"github.com/ory/x/sqlcon"
+)
+
+// OrganizationMatcherEmailDomain matches identifiers to organizations by the
+// domain of their email address.
+const OrganizationMatcherEmailDomain = "email_domain"
+
+type (
+	// OrganizationSSOStarter starts a login flow at a single sign-on provider.
+	OrganizationSSOStarter interface {
+		// StartOrganizationSSO redirects to the provider's authorization
+		// endpoint and returns flow.ErrCompletedByStrategy. It returns
+		// flow.ErrStrategyNotResponsible if it does not know the provider.
+		StartOrganizationSSO(w http.ResponseWriter, r *http.Request, f *Flow, providerID string) error
+	}
+
+	OrganizationSSOStarterProvider interface {
+		OrganizationSSOStarters() []OrganizationSSOStarter
+	}
+
+	organizationSSODependencies interface {
+		config.Provider
+		organization.ManagementProvider
+		OrganizationSSOStarterProvider
+	}
+)
+
+// EnforceOrganizationSSO redirects first factor logins with an identifier
+// belonging to an organization with single sign-on providers to one of them,
+// if enforcing single sign-on is enabled. It returns
+// flow.ErrCompletedByStrategy if the login was redirected, and nil if the
+// identifier may sign in with other methods.
+func EnforceOrganizationSSO(w http.ResponseWriter, r *http.Request, d organizationSSODependencies, f *Flow, identifier string) error {
+	return enforceOrganizationSSO(w, r, d, f, []string{identifier})
+}
+
+// EnforceOrganizationSSOForIdentity is like EnforceOrganizationSSO for
+// methods which identify the identity without an identifier, such as
+// passkeys. It considers the email addresses among the identity's
+// credential identifiers and verifiable addresses.
+func EnforceOrganizationSSOForIdentity(w http.ResponseWriter, r *http.Request, d organizationSSODependencies, f *Flow, i *identity.Identity) error {
+	var identifiers []string
+	for _, c := range i.Credentials {
+		identifiers = append(identifiers, c.Identifiers...)
+	}
+	for _, a := range i.VerifiableAddresses {
+		if a.Via == identity.AddressTypeEmail {
+			identifiers = append(identifiers, a.Value)
+		}
+	}
+	return enforceOrganizationSSO(w, r, d, f, identifiers)
+}
+
+func enforceOrganizationSSO(w http.ResponseWriter, r *http.Request, d organizationSSODependencies, f *Flow, identifiers []string) error {
+	ctx := r.Context()
+	if !d.Config().OrganizationsEnforceSSO(ctx) || f.RequestedAAL != identity.AuthenticatorAssuranceLevel1 {
+		return nil
+	}
+
+	var domains []string
+	for _, identifier := range identifiers {
+		if _, domain, ok := strings.Cut(x.NormalizeEmailIdentifier(identifier), "@"); ok && domain != "" && !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+	if len(domains) == 0 {
+		return nil
+	}
+
+	ds, err := f.IdentitySchema.URL(ctx, d.Config())
+	if err != nil {
+		return err
+	}
+	matches, err := hasOrganizationMatcher(ctx, ds.String(), d.Config().SecurityDisallowRefInIdentitySchemas(ctx))
+	if err != nil {
+		return err
+	}
+	if !matches {
+		return nil
+	}
+
+	for _, domain := range domains {
+		org, err := d.OrganizationManager().FindByDomain(ctx, domain)
+		if errors.Is(err, sqlcon.ErrNoRows()) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if len(org.SSOProviders) == 0 {
+			continue
+		}
+
+		f.OrganizationID.UUID, f.OrganizationID.Valid = org.ID, true
+		for _, starter := range d.OrganizationSSOStarters() {
+			err := starter.StartOrganizationSSO(w, r, f, org.SSOProviders[0])
+			if errors.Is(err, flow.ErrStrategyNotResponsible) {
+				continue
+			}
+			return err
+		}
+
+		return errors.WithStack(herodot.ErrMisconfiguration().
+			WithReasonf("The organization %s requires single sign-on, but its provider %q is not available.", org.ID, org.SSOProviders[0]))
+	}
+	return nil
+}
+
+type organizationMatcherCacheKey struct {
+	schemaURL    string
+	disallowRefs bool
+}
+
+// organizationMatchers caches whether identity schemas match identifiers to
+// organizations. Entries expire because the schemas behind some URLs can be
+// changed at runtime.
+var organizationMatchers = expirable.NewLRU[organizationMatcherCacheKey, bool](1024, nil, time.Minute)
+
+// hasOrganizationMatcher reports whether the identity schema matches
+// identifiers to organizations by their email domain.
+func hasOrganizationMatcher(ctx context.Context, schemaURL string, disallowRefs bool) (bool, error) {
+	key := organizationMatcherCacheKey{schemaURL: schemaURL, disallowRefs: disallowRefs}
+	if matches, ok := organizationMatchers.Get(key); ok {
+		return matches, nil
+	}
+
+	runner, err := schema.NewExtensionRunner(ctx)
+	if err != nil {
+		return false, err
+	}
+	c, err := schema.NewCompilerWithURL(ctx, schemaURL, disallowRefs)
+	if err != nil {
+		return false, err
+	}
+	c.ExtractAnnotations = true
+	runner.Register(c)
+
+	paths, err := jsonschemax.ListPaths(ctx, schemaURL, c)
+	if err != nil {
+		return false, err
+	}
+
+	matches := slices.ContainsFunc(paths, func(path jsonschemax.Path) bool {
+		config, ok := path.CustomProperties[schema.ExtensionName].(*schema.ExtensionConfig)
+		return ok && config.Organization.Matcher == OrganizationMatcherEmailDomain
+	})
+	organizationMatchers.Add(key, matches)
+	return matches, nil
+}
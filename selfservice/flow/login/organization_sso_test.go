@@ -0,0 +1,81 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package login_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/x/configx"
+)
+
+func TestEnforceOrganizationSSOForIdentity(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.Must(uuid.NewV4())
+	_, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://../../strategy/password/stub/email.schema.json")),
+		configx.WithValues(testhelpers.MethodEnableConfig(identity.CredentialsTypeOIDC, true)),
+		configx.WithValue(config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypeOIDC)+".config.providers",
+			[]map[string]any{{
+				"provider":        "github",
+				"id":              "acme-sso",
+				"client_id":       "client",
+				"client_secret":   "secret",
+				"mapper_url":      "file://../../strategy/oidc/stub/oidc.facebook.jsonnet",
+				"organization_id": orgID.String(),
+			}},
+		),
+		configx.WithValue(config.ViperKeyOrganizations, []map[string]any{{
+			"id":      orgID.String(),
+			"label":   "Acme",
+			"domains": []string{"acme.example"},
+		}}),
+		configx.WithValue(config.ViperKeyOrganizationsEnforceSSO, true),
+	)
+
+	enforce := func(t *testing.T, i *identity.Identity) (*httptest.ResponseRecorder, *login.Flow, error) {
+		r := httptest.NewRequest(http.MethodPost, "/self-service/login", nil)
+		f, err := login.NewFlow(reg, r, flow.TypeBrowser)
+		require.NoError(t, err)
+		require.NoError(t, reg.LoginFlowPersister().CreateLoginFlow(t.Context(), f))
+
+		w := httptest.NewRecorder()
+		return w, f, login.EnforceOrganizationSSOForIdentity(w, r, reg, f, i)
+	}
+
+	t.Run("case=redirects identities with an organization email address", func(t *testing.T) {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.SetCredentials(identity.CredentialsTypePasskey, identity.Credentials{Identifiers: []string{"user-handle"}})
+		i.VerifiableAddresses = []identity.VerifiableAddress{{Via: identity.AddressTypeEmail, Value: "User@Acme.Example"}}
+
+		w, f, err := enforce(t, i)
+		require.ErrorIs(t, err, flow.ErrCompletedByStrategy)
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Contains(t, w.Header().Get("Location"), "https://github.com/login/oauth/authorize")
+		assert.Equal(t, uuid.NullUUID{UUID: orgID, Valid: true}, f.OrganizationID)
+	})
+
+	t.Run("case=ignores identities without an organization email address", func(t *testing.T) {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{Identifiers: []string{"user@other.example"}})
+		i.SetCredentials(identity.CredentialsTypePasskey, identity.Credentials{Identifiers: []string{"user-handle"}})
+
+		w, f, err := enforce(t, i)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, f.OrganizationID.Valid)
+	})
+}
@@ -4,6 +4,7 @@
 package logout
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/ory/kratos/x/redir"
 	"github.com/ory/x/httprouterx"
 	"github.com/ory/x/httpx"
+	"github.com/ory/x/logrusx"
 
 	"go.opentelemetry.io/otel/trace"
 
@@ -44,6 +46,20 @@ type (
 		session.PersistenceProvider
 		errorx.ManagementProvider
 		config.Provider
+		logrusx.Provider
+		UpstreamLogoutProvider
+	}
+	// UpstreamLogouter is implemented by strategies which can end the session
+	// at the upstream identity provider the session was authenticated with.
+	UpstreamLogouter interface {
+		// UpstreamLogoutURL returns the URL the browser is redirected to in order
+		// to log out at the upstream identity provider before returning to
+		// returnTo, or nil if the session does not need to be logged out
+		// upstream.
+		UpstreamLogoutURL(ctx context.Context, sess *session.Session, returnTo *url.URL) (*url.URL, error)
+	}
+	UpstreamLogoutProvider interface {
+		UpstreamLogouters() []UpstreamLogouter
 	}
 	HandlerProvider interface {
 		LogoutHandler() *Handler
@@ -342,10 +358,10 @@ func (h *Handler) updateLogoutFlow(w http.ResponseWriter, r *http.Request) {
 
 	trace.SpanFromContext(r.Context()).AddEvent(events.NewSessionRevoked(r.Context(), sess.ID, sess.IdentityID))
 
-	h.completeLogout(w, r)
+	h.completeLogout(w, r, sess)
 }
 
-func (h *Handler) completeLogout(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) completeLogout(w http.ResponseWriter, r *http.Request, sess *session.Session) {
 	_ = h.d.CSRFHandler().RegenerateToken(w, r)
 
 	ret, err := redir.SecureRedirectTo(r, h.d.Config().SelfServiceFlowLogoutRedirectURL(r.Context()),
@@ -371,5 +387,22 @@ func (h *Handler) completeLogout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	http.Redirect(w, r, ret.String(), http.StatusSeeOther)
+	http.Redirect(w, r, h.upstreamLogoutURL(r, sess, ret).String(), http.StatusSeeOther)
+}
+
+// upstreamLogoutURL returns the URL of the first upstream logout required by
+// the session, or returnTo if there is none. Failing to log out upstream does
+// not fail the logout as the session has already been revoked.
+func (h *Handler) upstreamLogoutURL(r *http.Request, sess *session.Session, returnTo *url.URL) *url.URL {
+	for _, l := range h.d.UpstreamLogouters() {
+		u, err := l.UpstreamLogoutURL(r.Context(), sess, returnTo)
+		if err != nil {
+			h.d.Logger().WithRequest(r).WithError(err).Warn("Unable to log out at the upstream identity provider.")
+			continue
+		}
+		if u != nil {
+			return u
+		}
+	}
+	return returnTo
 }
@@ -4,6 +4,7 @@
 package registration
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/url"
 
@@ -750,6 +751,12 @@ func (h *Handler) updateRegistrationFlow(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// The traits were submitted by the user and may not set admin-only traits.
+	if err := h.d.RegistrationExecutor().CheckAdminOnlyTraits(ctx, i.SchemaID, json.RawMessage(i.Traits)); err != nil {
+		h.d.RegistrationFlowErrorHandler().WriteFlowError(w, r, f, s.ID(), s.NodeGroup(), err)
+		return
+	}
+
 	if err := h.d.RegistrationExecutor().PostRegistrationHook(w, r, f, i, session.AuthenticationMethod{
 		Method: s.ID(),
 		AAL:    identity.AuthenticatorAssuranceLevel1,
@@ -19,6 +19,7 @@ import (
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hydra"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/sessiontokenexchange"
@@ -95,6 +96,7 @@ type (
 		httpx.WriterProvider
 		otelx.Provider
 		sessiontokenexchange.PersistenceProvider
+		schema.IdentitySchemaProvider
 	}
 	HookExecutor struct {
 		d executorDependencies
@@ -394,3 +396,24 @@ func (e *HookExecutor) PreRegistrationHook(w http.ResponseWriter, r *http.Reques
 
 	return nil
 }
+
+// CheckAdminOnlyTraits returns a validation error if traits submitted by the
+// user set a trait of the identity schema which can only be set through the
+// admin API. Traits set by trusted sources, such as the OpenID Connect Jsonnet
+// mapper or web hooks, are not checked.
+func (e *HookExecutor) CheckAdminOnlyTraits(ctx context.Context, schemaID string, traits json.RawMessage) error {
+	schemas, err := e.d.IdentityTraitsSchemas(ctx)
+	if err != nil {
+		return err
+	}
+	s, err := schemas.GetByID(schemaID)
+	if err != nil {
+		return err
+	}
+
+	access, err := schema.NewTraitAccess(ctx, s.URL.String(), e.d.Config().SecurityDisallowRefInIdentitySchemas(ctx))
+	if err != nil {
+		return err
+	}
+	return access.CheckRegistration(traits)
+}
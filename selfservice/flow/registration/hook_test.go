@@ -5,6 +5,7 @@ package registration_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	nethttptest "net/http/httptest"
@@ -23,10 +24,12 @@ import (
 	"github.com/ory/kratos/identity"
 	"github.com/ory/kratos/pkg"
 	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/flow/registration"
 	"github.com/ory/kratos/selfservice/hook"
 	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
 	"github.com/ory/kratos/ui/node"
 	"github.com/ory/kratos/x"
 	"github.com/ory/x/sqlcon"
@@ -402,3 +405,45 @@ func TestPostRegistrationHookAccountLinkingOnly(t *testing.T) {
 		require.ErrorIs(t, err, sqlcon.ErrUniqueViolation())
 	})
 }
+
+func TestPostRegistrationHookAdminOnlyTraits(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	conf, reg := pkg.NewFastRegistryWithMocks(t)
+	reg.SetHydra(hydra.NewFake())
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/trait-access.schema.json")
+	conf.MustSet(ctx, config.ViperKeySelfServiceBrowserDefaultReturnTo, returnToServer.URL)
+
+	t.Run("case=rejects admin-only traits submitted by the user", func(t *testing.T) {
+		err := reg.RegistrationHookExecutor().CheckAdminOnlyTraits(ctx, config.DefaultIdentityTraitsSchemaID, json.RawMessage(`{"email":"foo@ory.sh","role":"admin"}`))
+
+		var ve *schema.ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Equal(t, "#/traits/role", ve.InstancePtr)
+		assert.EqualValues(t, text.ErrorValidationTraitNotWritable, ve.Messages[0].ID)
+	})
+
+	t.Run("case=allows traits without admin-only traits", func(t *testing.T) {
+		require.NoError(t, reg.RegistrationHookExecutor().CheckAdminOnlyTraits(ctx, config.DefaultIdentityTraitsSchemaID, json.RawMessage(`{"email":"foo@ory.sh"}`)))
+	})
+
+	t.Run("case=allows admin-only traits set by trusted sources", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/registration/post", nil)
+		r = r.WithContext(ctx)
+		regFlow, err := registration.NewFlow(reg, r, flow.TypeAPI)
+		require.NoError(t, err)
+
+		email := testhelpers.RandomEmail()
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"email":"` + email + `","role":"admin"}`)
+		require.NoError(t, reg.RegistrationHookExecutor().PostRegistrationHook(httptest.NewRecorder(), r, regFlow, i, session.AuthenticationMethod{
+			Method: identity.CredentialsTypeOIDC,
+			AAL:    identity.AuthenticatorAssuranceLevel1,
+		}))
+
+		actual, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, i.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"email":"`+email+`","role":"admin"}`, string(actual.Traits))
+	})
+}
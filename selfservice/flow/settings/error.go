@@ -245,6 +245,11 @@ func (s *ErrorHandler) WriteFlowError(
 		s.forward(ctx, w, r, f, err)
 		return
 	}
+	schema, err = schema.AtVersion(id.SchemaVersion)
+	if err != nil {
+		s.forward(ctx, w, r, f, err)
+		return
+	}
 
 	if err := sortNodes(ctx, f.UI.Nodes, schema.RawURL); err != nil {
 		s.forward(ctx, w, r, f, err)
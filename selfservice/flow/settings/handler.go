@@ -14,6 +14,7 @@ import (
 	"github.com/ory/kratos/continuity"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow"
@@ -71,6 +72,7 @@ type (
 		identity.ValidationProvider
 		identity.ManagementProvider
 		identity.PrivilegedPoolProvider
+		organization.ManagementProvider
 
 		errorx.ManagementProvider
 
@@ -174,7 +176,11 @@ func (h *Handler) NewFlow(ctx context.Context, w http.ResponseWriter, r *http.Re
 		return nil, err
 	}
 
-	filters := PrepareOrganizations(r, f, i, h.d.Config().Organizations(ctx))
+	orgs, err := h.d.OrganizationManager().Organizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filters := PrepareOrganizations(r, f, i, orgs)
 
 	cookieStore := continuity.NewCookieReferenceStore(h.d.ContinuityCookieManager(ctx))
 	for _, strategy := range h.d.SettingsStrategies(ctx, filters...) {
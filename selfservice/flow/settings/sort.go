@@ -22,6 +22,7 @@ func sortNodes(ctx context.Context, n node.Nodes, schemaRef string) error {
 			node.WebAuthnGroup,
 			node.PasskeyGroup,
 			node.TOTPGroup,
+			node.DeletionGroup,
 		}),
 		node.SortStableGroups(node.DeviceAuthnGroup),
 		node.SortUseOrderAppend([]string{
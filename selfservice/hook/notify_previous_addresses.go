@@ -63,6 +63,12 @@ func (e *NotifyPreviousAddresses) ExecuteSettingsPostPersistHook(
 		targets = slices.DeleteFunc(targets, func(a identity.AddressRef) bool {
 			return a.Via != identity.AddressTypeEmail && a.Via != identity.AddressTypeSMS
 		})
+		if addressesChanged(params.Previous.VerifiableAddresses, params.Updated.VerifiableAddresses) {
+			// Also alert the devices and WhatsApp numbers registered for
+			// receiving codes.
+			targets = append(targets, params.Updated.PushAddresses()...)
+			targets = append(targets, params.Updated.WhatsAppAddresses()...)
+		}
 		if len(targets) == 0 {
 			return nil
 		}
@@ -135,7 +135,7 @@ func (s *Sender) SendCode(ctx context.Context, f flow.Flow, id *identity.Identit
 					OAuth2LoginRequest: oauth2LoginRequest,
 					UserRequestHeaders: hook.RemoveDisallowedHeaders(header, s.deps.Config().WebhookHeaderAllowlist(ctx)),
 				})
-			case identity.ChannelTypeSMS:
+			case identity.ChannelTypeSMS, identity.ChannelTypePush, identity.ChannelTypeWhatsApp:
 				t = sms.NewRegistrationCodeValid(s.deps, &sms.RegistrationCodeValidModel{
 					To:                 address.To,
 					RegistrationCode:   rawCode,
@@ -192,7 +192,7 @@ func (s *Sender) SendCode(ctx context.Context, f flow.Flow, id *identity.Identit
 					OAuth2LoginRequest: oauth2LoginRequest,
 					UserRequestHeaders: hook.RemoveDisallowedHeaders(header, s.deps.Config().WebhookHeaderAllowlist(ctx)),
 				})
-			case identity.ChannelTypeSMS:
+			case identity.ChannelTypeSMS, identity.ChannelTypePush, identity.ChannelTypeWhatsApp:
 				t = sms.NewLoginCodeValid(s.deps, &sms.LoginCodeValidModel{
 					To:                 address.To,
 					LoginCode:          rawCode,
@@ -509,6 +509,34 @@ func (s *Sender) send(ctx context.Context, via string, t courier.Template) error
 
 		_, err = c.QueueSMS(ctx, t)
 		return err
+	case f.AddCase(identity.ChannelTypePush):
+		c, err := s.deps.Courier(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Push notifications are rendered from the SMS templates.
+		t, ok := t.(courier.SMSTemplate)
+		if !ok {
+			return errors.WithStack(herodot.ErrInternalServerError().WithReasonf("Expected sms template but got %T", t))
+		}
+
+		_, err = c.QueuePush(ctx, t)
+		return err
+	case f.AddCase(identity.ChannelTypeWhatsApp):
+		c, err := s.deps.Courier(ctx)
+		if err != nil {
+			return err
+		}
+
+		// WhatsApp messages are rendered from the SMS templates.
+		t, ok := t.(courier.SMSTemplate)
+		if !ok {
+			return errors.WithStack(herodot.ErrInternalServerError().WithReasonf("Expected sms template but got %T", t))
+		}
+
+		_, err = c.QueueWhatsApp(ctx, t)
+		return err
 	default:
 		return f.ToUnknownCaseErr()
 	}
@@ -24,6 +24,7 @@ import (
 	"github.com/ory/kratos/courier"
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow"
@@ -101,6 +102,9 @@ type (
 
 		login.StrategyProvider
 		login.FlowPersistenceProvider
+		login.OrganizationSSOStarterProvider
+
+		organization.ManagementProvider
 
 		registration.StrategyProvider
 		registration.FlowPersistenceProvider
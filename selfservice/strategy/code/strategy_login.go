@@ -237,6 +237,12 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		identifier := maybeNormalizeEmail(
 			cmp.Or(p.Identifier, p.Address),
 		)
+		if err := login.EnforceOrganizationSSO(w, r, s.deps, f, identifier); err != nil {
+			if errors.Is(err, flow.ErrCompletedByStrategy) {
+				return nil, err
+			}
+			return nil, s.HandleLoginError(r, f, &p, err, false)
+		}
 		id, addresses, err := s.findIdentityForIdentifier(ctx, identifier, f.RequestedAAL, sess)
 		if err != nil {
 			return nil, s.HandleLoginError(r, f, &p, err, false)
@@ -256,6 +262,15 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		if err != nil {
 			return nil, s.HandleLoginError(r, f, &p, err, true)
 		}
+		// The identifier may be a username or phone number, so the
+		// identity's email addresses decide whether it has to sign in with
+		// single sign-on.
+		if err := login.EnforceOrganizationSSOForIdentity(w, r, s.deps, f, i); err != nil {
+			if errors.Is(err, flow.ErrCompletedByStrategy) {
+				return nil, err
+			}
+			return nil, s.HandleLoginError(r, f, &p, x.WrapWithIdentityIDError(err, i.ID), true)
+		}
 		return i, nil
 	case flow.StatePassedChallenge:
 		return nil, s.HandleLoginError(r, f, &p, errors.WithStack(schema.NewNoLoginStrategyResponsible()), false)
@@ -518,6 +533,12 @@ func (s *Strategy) loginSendCode(ctx context.Context, w http.ResponseWriter, r *
 	defer otelx.End(span, &err)
 
 	if address, found := lo.Find(addresses, func(item Address) bool {
+		if item.Via == identity.CodeChannelPush {
+			// Device tokens are case-sensitive, so only the platform prefix
+			// is normalized.
+			normalized, err := x.NormalizeIdentifier(identifier, string(identity.CodeChannelPush))
+			return err == nil && item.To == normalized
+		}
 		return item.To == x.GracefulNormalization(identifier)
 	}); found {
 		addresses = []Address{address}
@@ -0,0 +1,19 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package deletion
+
+import (
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+)
+
+func NewConfirmDeletionNode() *node.Node {
+	return node.NewInputField(node.DeletionConfirm, nil, node.DeletionGroup, node.InputAttributeTypeText, node.WithRequiredInputAttribute).
+		WithMetaLabel(text.NewInfoSelfServiceSettingsDeleteAccountConfirm(ConfirmationPhrase))
+}
+
+func NewDeleteAccountNode() *node.Node {
+	return node.NewInputField("method", StrategyID, node.DeletionGroup, node.InputAttributeTypeSubmit).
+		WithMetaLabel(text.NewInfoSelfServiceSettingsDeleteAccount())
+}
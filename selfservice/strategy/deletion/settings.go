@@ -0,0 +1,289 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package deletion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ory/kratos/continuity"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/schema"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/redir"
+	"github.com/ory/pop/v6"
+	"github.com/ory/x/decoderx"
+	"github.com/ory/x/otelx"
+	"github.com/ory/x/sqlcon"
+)
+
+// Update Settings Flow with Deletion Method
+//
+// swagger:model updateSettingsFlowWithDeletionMethod
+type updateSettingsFlowWithDeletionMethod struct {
+	// Confirmation
+	//
+	// Must be set to "DELETE" to confirm the deletion of the account.
+	//
+	// required: true
+	Confirm string `json:"deletion_confirm"`
+
+	// CSRFToken is the anti-CSRF token
+	CSRFToken string `json:"csrf_token"`
+
+	// Method
+	//
+	// Should be set to "deletion" when trying to delete the account.
+	//
+	// required: true
+	Method string `json:"method"`
+
+	// Flow is flow ID.
+	//
+	// swagger:ignore
+	Flow string `json:"flow"`
+
+	// Transient data to pass along to any webhooks
+	//
+	// required: false
+	TransientPayload json.RawMessage `json:"transient_payload,omitempty" form:"transient_payload"`
+}
+
+func (p *updateSettingsFlowWithDeletionMethod) GetFlowID() uuid.UUID {
+	return x.ParseUUID(p.Flow)
+}
+
+func (p *updateSettingsFlowWithDeletionMethod) SetFlowID(rid uuid.UUID) {
+	p.Flow = rid.String()
+}
+
+func (s *Strategy) Settings(ctx context.Context, w http.ResponseWriter, r *http.Request, f *settings.Flow, ss *session.Session) (_ *settings.UpdateContext, err error) {
+	ctx, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.deletion.Strategy.Settings")
+	defer otelx.End(span, &err)
+
+	var p updateSettingsFlowWithDeletionMethod
+	ctxUpdate, err := settings.PrepareUpdate(s.d, w, r, f, ss, settings.ContinuityKey(s.SettingsStrategyID()), &p)
+	if errors.Is(err, settings.ErrContinuePreviousAction) {
+		return ctxUpdate, s.continueSettingsFlow(ctx, w, r, ctxUpdate, p)
+	} else if err != nil {
+		return ctxUpdate, s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	if err := s.decodeSettingsFlow(r, &p); err != nil {
+		return ctxUpdate, s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	if err := flow.MethodEnabledAndAllowed(ctx, f.GetFlowName(), s.SettingsStrategyID(), p.Method, s.d); err != nil {
+		span.SetAttributes(attribute.String("not_responsible_reason", "method is not deletion"))
+		return nil, err
+	}
+
+	// This does not come from the payload!
+	p.Flow = ctxUpdate.Flow.ID.String()
+	if err := s.continueSettingsFlow(ctx, w, r, ctxUpdate, p); err != nil {
+		return ctxUpdate, s.handleSettingsError(w, r, ctxUpdate, p, err)
+	}
+
+	return ctxUpdate, nil
+}
+
+func (s *Strategy) decodeSettingsFlow(r *http.Request, dest interface{}) error {
+	compiler, err := decoderx.HTTPRawJSONSchemaCompiler(settingsSchema)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return decoderx.Decode(r, dest, compiler,
+		decoderx.HTTPDecoderSetValidatePayloads(true),
+		decoderx.HTTPDecoderJSONFollowsFormFormat(),
+	)
+}
+
+func (s *Strategy) continueSettingsFlow(ctx context.Context, w http.ResponseWriter, r *http.Request, ctxUpdate *settings.UpdateContext, p updateSettingsFlowWithDeletionMethod) error {
+	if err := flow.MethodEnabledAndAllowed(ctx, flow.SettingsFlow, s.SettingsStrategyID(), s.SettingsStrategyID(), s.d); err != nil {
+		return err
+	}
+
+	if err := flow.EnsureCSRF(s.d, r, ctxUpdate.Flow.Type, s.d.Config().DisableAPIFlowEnforcement(ctx), s.d.GenerateCSRFToken, p.CSRFToken); err != nil {
+		return err
+	}
+
+	if !s.d.SessionManager().IsPrivileged(ctx, ctxUpdate.Session) {
+		return errors.WithStack(settings.NewFlowNeedsReAuth())
+	}
+
+	if p.Confirm != ConfirmationPhrase {
+		return schema.NewDeletionNotConfirmedError("#/"+node.DeletionConfirm, ConfirmationPhrase)
+	}
+
+	i, err := s.d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, ctxUpdate.Session.IdentityID)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteIdentity(ctx, w, r, ctxUpdate, i)
+}
+
+// deleteIdentity runs the settings hooks of this method, deletes the
+// identity or schedules its deletion, and revokes all of its sessions.
+//
+// Web hooks which are able to interrupt the flow run before the identity is
+// deleted and can veto the deletion. All other hooks run afterwards.
+func (s *Strategy) deleteIdentity(ctx context.Context, w http.ResponseWriter, r *http.Request, ctxUpdate *settings.UpdateContext, i *identity.Identity) error {
+	c := s.d.Config()
+	returnTo, err := redir.SecureRedirectTo(r, c.SelfServiceBrowserDefaultReturnTo(ctx),
+		redir.SecureRedirectUseSourceURL(ctxUpdate.Flow.RequestURL),
+		redir.SecureRedirectAllowURLs(c.SelfServiceBrowserAllowedReturnToDomains(ctx)),
+		redir.SecureRedirectAllowSelfServiceURLs(c.SelfPublicURL(ctx)),
+		redir.SecureRedirectOverrideDefaultReturnTo(c.SelfServiceFlowSettingsReturnTo(ctx, s.SettingsStrategyID(), c.SelfServiceBrowserDefaultReturnTo(ctx))),
+	)
+	if err != nil {
+		return err
+	}
+
+	preHooks, err := s.d.PostSettingsPrePersistHooks(ctx, s.SettingsStrategyID())
+	if err != nil {
+		return err
+	}
+	for _, executor := range preHooks {
+		if err := executor.ExecuteSettingsPrePersistHook(w, r, settings.PostHookPrePersistExecutorParams{
+			Flow:     ctxUpdate.Flow,
+			Identity: i,
+			Session:  ctxUpdate.Session,
+		}); err != nil {
+			if errors.Is(err, settings.ErrHookAbortFlow) {
+				return errors.WithStack(flow.ErrCompletedByStrategy)
+			}
+			return flow.HandleHookError(w, r, ctxUpdate.Flow, nil, s.NodeGroup(), err, s.d, s.d)
+		}
+	}
+
+	gracePeriod := c.SelfServiceDeletionMethodGracePeriod(ctx)
+	deleteAfter := time.Now().UTC().Add(gracePeriod)
+	if err := s.d.TransactionalPersisterProvider().Transaction(ctx, func(ctx context.Context, _ *pop.Connection) error {
+		if _, err := s.d.SessionPersister().RevokeSessionsByIdentities(ctx, []uuid.UUID{i.ID}); err != nil {
+			return err
+		}
+		if gracePeriod > 0 {
+			return s.d.ScheduledDeletionPersister().ScheduleIdentityDeletion(ctx, &identity.ScheduledDeletion{
+				IdentityID:  i.ID,
+				DeleteAfter: deleteAfter,
+			})
+		}
+		return s.d.PrivilegedIdentityPool().DeleteIdentity(ctx, i.ID)
+	}); err != nil {
+		return err
+	}
+
+	// Signs out the current browser or API client once the deletion is
+	// committed. The session no longer exists if the identity was deleted
+	// immediately.
+	if err := s.d.SessionManager().PurgeFromRequest(ctx, w, r); err != nil && !errors.Is(err, sqlcon.ErrNoRows()) {
+		return err
+	}
+
+	s.d.Logger().
+		WithRequest(r).
+		WithField("identity_id", i.ID).
+		WithField("grace_period", gracePeriod).
+		Info("An identity requested the deletion of its account.")
+
+	ctxUpdate.Flow.State = flow.StateSuccess
+	ctxUpdate.Flow.UI.ResetMessages()
+	ctxUpdate.Flow.UI.Nodes = node.Nodes{}
+	if gracePeriod > 0 {
+		ctxUpdate.Flow.UI.AddMessage(node.DefaultGroup, text.NewInfoSelfServiceSettingsAccountDeletionScheduled(deleteAfter))
+		if err := s.d.SettingsFlowPersister().UpdateSettingsFlow(ctx, ctxUpdate.Flow); err != nil {
+			return err
+		}
+	} else {
+		// The flow was removed together with the identity.
+		ctxUpdate.Flow.UI.AddMessage(node.DefaultGroup, text.NewInfoSelfServiceSettingsAccountDeleted())
+	}
+
+	postHooks, err := s.d.PostSettingsPostPersistHooks(ctx, s.SettingsStrategyID())
+	if err != nil {
+		return err
+	}
+	for k, executor := range postHooks {
+		if err := executor.ExecuteSettingsPostPersistHook(w, r, settings.PostHookPostPersistExecutorParams{
+			Flow:     ctxUpdate.Flow,
+			Previous: i,
+			Updated:  i,
+			Session:  ctxUpdate.Session,
+		}); err != nil {
+			if errors.Is(err, settings.ErrHookAbortFlow) {
+				return errors.WithStack(flow.ErrCompletedByStrategy)
+			}
+			// The identity is already deleted, so we only log the error.
+			s.d.Logger().
+				WithRequest(r).
+				WithError(err).
+				WithField("executor", fmt.Sprintf("%T", executor)).
+				WithField("executor_position", k).
+				WithField("identity_id", i.ID).
+				Warn("A hook failed after the identity requested the deletion of its account.")
+		}
+	}
+
+	if ctxUpdate.Flow.Type == flow.TypeAPI {
+		s.d.Writer().Write(w, r, ctxUpdate.Flow)
+		return errors.WithStack(flow.ErrCompletedByStrategy)
+	}
+
+	if x.IsJSONRequest(r) {
+		ctxUpdate.Flow.AddContinueWith(flow.NewContinueWithRedirectBrowserTo(returnTo.String()))
+		s.d.Writer().Write(w, r, ctxUpdate.Flow)
+		return errors.WithStack(flow.ErrCompletedByStrategy)
+	}
+
+	http.Redirect(w, r, returnTo.String(), http.StatusSeeOther)
+	return errors.WithStack(flow.ErrCompletedByStrategy)
+}
+
+func (s *Strategy) PopulateSettingsMethod(ctx context.Context, r *http.Request, _ *identity.Identity, f *settings.Flow) (err error) {
+	_, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.deletion.Strategy.PopulateSettingsMethod")
+	defer otelx.End(span, &err)
+
+	f.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+	f.UI.Nodes.Upsert(NewConfirmDeletionNode())
+	f.UI.Nodes.Append(NewDeleteAccountNode())
+	return nil
+}
+
+func (s *Strategy) handleSettingsError(w http.ResponseWriter, r *http.Request, ctxUpdate *settings.UpdateContext, p updateSettingsFlowWithDeletionMethod, err error) error {
+	if errors.Is(err, flow.ErrCompletedByStrategy) {
+		return err
+	}
+
+	// Do not pause flow if the flow type is an API flow as we can't save cookies in those flows.
+	if e := new(settings.FlowNeedsReAuth); errors.As(err, &e) && ctxUpdate.Flow != nil && ctxUpdate.Flow.Type == flow.TypeBrowser {
+		key := settings.ContinuityKey(s.SettingsStrategyID())
+		cookieStore := continuity.NewCookieReferenceStore(s.d.ContinuityCookieManager(r.Context()))
+		opts := settings.ContinuityOptions(p, ctxUpdate.GetSessionIdentity())
+		if _, err := s.d.ContinuityManager().Pause(r.Context(), w, r, key, cookieStore, opts...); err != nil {
+			return err
+		}
+	}
+
+	if ctxUpdate.Flow != nil {
+		ctxUpdate.Flow.UI.ResetMessages()
+		ctxUpdate.Flow.UI.SetCSRF(s.d.GenerateCSRFToken(r))
+		ctxUpdate.Flow.UI.Nodes.SetValueAttribute(node.DeletionConfirm, p.Confirm)
+	}
+
+	return err
+}
@@ -0,0 +1,196 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package deletion_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/x/assertx"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/sqlcon"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/kratos/selfservice/flow"
+	"github.com/ory/kratos/selfservice/flow/login"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/selfservice/strategy/deletion"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+)
+
+func TestCompleteSettings(t *testing.T) {
+	ctx := context.Background()
+	conf, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.MethodEnableConfig(identity.CredentialsTypePassword, false)),
+		configx.WithValues(testhelpers.MethodEnableConfig("profile", false)),
+		configx.WithValues(testhelpers.MethodEnableConfig(deletion.StrategyID, true)),
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/identity.schema.json")),
+		configx.WithValues(map[string]any{
+			config.ViperKeySelfServiceSettingsRequiredAAL:                   "aal1",
+			config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter: "5m",
+		}),
+	)
+
+	publicTS, _ := testhelpers.NewKratosServer(t, reg)
+	_ = testhelpers.NewErrorTestServer(t, reg)
+	_ = testhelpers.NewSettingsUIFlowEchoServer(t, reg)
+	_ = testhelpers.NewLoginUIFlowEchoServer(t, reg)
+
+	createIdentity := func(t *testing.T) *identity.Identity {
+		i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+		i.Traits = identity.Traits(`{"subject":"` + x.NewUUID().String() + `@ory.sh"}`)
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		return i
+	}
+
+	doAPIFlow := func(t *testing.T, v func(url.Values), id *identity.Identity) (string, *http.Response) {
+		apiClient := testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, id)
+		f := testhelpers.InitializeSettingsFlowViaAPI(t, apiClient, publicTS)
+		values := testhelpers.SDKFormFieldsToURLValues(f.Ui.Nodes)
+		v(values)
+		return testhelpers.SettingsMakeRequest(t, true, false, f, apiClient, testhelpers.EncodeFormAsJSON(t, true, values))
+	}
+
+	confirm := func(v url.Values) {
+		v.Set("method", deletion.StrategyID)
+		v.Set(node.DeletionConfirm, deletion.ConfirmationPhrase)
+	}
+
+	assertIdentityExists := func(t *testing.T, id *identity.Identity) {
+		_, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, id.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+	}
+
+	t.Run("case=shows the deletion form", func(t *testing.T) {
+		apiClient := testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, createIdentity(t))
+		f := testhelpers.InitializeSettingsFlowViaAPI(t, apiClient, publicTS)
+
+		var names []string
+		for _, n := range f.Ui.Nodes {
+			if n.Group == node.DeletionGroup.String() {
+				names = append(names, n.Attributes.UiNodeInputAttributes.Name)
+			}
+		}
+		assert.Equal(t, []string{node.DeletionConfirm, "method"}, names)
+	})
+
+	t.Run("case=requires typed confirmation", func(t *testing.T) {
+		id := createIdentity(t)
+		actual, res := doAPIFlow(t, func(v url.Values) {
+			v.Set("method", deletion.StrategyID)
+			v.Set(node.DeletionConfirm, "delete")
+		}, id)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, actual)
+		assert.EqualValues(t, text.ErrorValidationSettingsDeletionNotConfirmed,
+			gjson.Get(actual, "ui.nodes.#(attributes.name==deletion_confirm).messages.0.id").Int(), actual)
+		assertIdentityExists(t, id)
+	})
+
+	t.Run("case=requires a privileged session", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "1ns")
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "5m")
+		})
+
+		id := createIdentity(t)
+		actual, res := doAPIFlow(t, confirm, id)
+
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, actual)
+		assertx.EqualAsJSONExcept(t, settings.NewFlowNeedsReAuth(), json.RawMessage(actual), []string{"redirect_browser_to"})
+		assertIdentityExists(t, id)
+	})
+
+	t.Run("case=deletes the identity immediately", func(t *testing.T) {
+		id := createIdentity(t)
+		actual, res := doAPIFlow(t, confirm, id)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode, actual)
+		assert.Equal(t, "success", gjson.Get(actual, "state").String(), actual)
+		assert.EqualValues(t, text.InfoSelfServiceSettingsAccountDeleted, gjson.Get(actual, "ui.messages.0.id").Int(), actual)
+
+		_, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, id.ID, identity.ExpandNothing)
+		require.ErrorIs(t, err, sqlcon.ErrNoRows())
+	})
+
+	t.Run("case=schedules the deletion after the grace period", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyDeletionGracePeriod, "24h")
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeyDeletionGracePeriod, "0s")
+		})
+
+		id := createIdentity(t)
+		actual, res := doAPIFlow(t, confirm, id)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode, actual)
+		assert.EqualValues(t, text.InfoSelfServiceSettingsAccountDeletionScheduled, gjson.Get(actual, "ui.messages.0.id").Int(), actual)
+		assertIdentityExists(t, id)
+
+		scheduled, err := reg.ScheduledDeletionPersister().GetScheduledIdentityDeletion(ctx, id.ID)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), scheduled.DeleteAfter, time.Minute)
+
+		sessions, _, err := reg.SessionPersister().ListSessionsByIdentity(ctx, id.ID, new(bool), 0, 10, uuid.Nil, identity.ExpandNothing)
+		require.NoError(t, err)
+		for _, s := range sessions {
+			assert.False(t, s.Active)
+		}
+
+		t.Run("case=is not deleted before the grace period passed", func(t *testing.T) {
+			deleted, err := reg.ScheduledDeletionPersister().DeleteScheduledIdentities(ctx, time.Now(), 10)
+			require.NoError(t, err)
+			assert.Zero(t, deleted)
+			assertIdentityExists(t, id)
+		})
+
+		t.Run("case=is deleted after the grace period passed", func(t *testing.T) {
+			_, err := reg.ScheduledDeletionPersister().DeleteScheduledIdentities(ctx, time.Now().Add(25*time.Hour), 10)
+			require.NoError(t, err)
+
+			_, err = reg.PrivilegedIdentityPool().GetIdentity(ctx, id.ID, identity.ExpandNothing)
+			require.ErrorIs(t, err, sqlcon.ErrNoRows())
+		})
+	})
+
+	t.Run("case=signing in cancels the scheduled deletion", func(t *testing.T) {
+		conf.MustSet(ctx, config.ViperKeyDeletionGracePeriod, "24h")
+		t.Cleanup(func() {
+			conf.MustSet(ctx, config.ViperKeyDeletionGracePeriod, "0s")
+		})
+
+		id := createIdentity(t)
+		_, res := doAPIFlow(t, confirm, id)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		r := testhelpers.NewTestHTTPRequest(t, "POST", publicTS.URL+login.RouteSubmitFlow, nil)
+		f, err := login.NewFlow(reg, r, flow.TypeAPI)
+		require.NoError(t, err)
+		f.Active = identity.CredentialsTypePassword
+
+		i, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, id.ID, identity.ExpandDefault)
+		require.NoError(t, err)
+		sess := session.NewInactiveSession()
+		sess.CompletedLoginFor(identity.CredentialsTypePassword, identity.AuthenticatorAssuranceLevel1)
+		require.NoError(t, reg.LoginHookExecutor().PostLoginHook(httptest.NewRecorder(), r, node.PasswordGroup, f, i, sess, ""))
+
+		_, err = reg.ScheduledDeletionPersister().GetScheduledIdentityDeletion(ctx, id.ID)
+		require.ErrorIs(t, err, sqlcon.ErrNoRows())
+	})
+}
@@ -0,0 +1,68 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+// Package deletion implements the settings method which lets users delete
+// their own account.
+package deletion
+
+import (
+	"github.com/ory/kratos/continuity"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/ui/node"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/nosurfx"
+	"github.com/ory/kratos/x/transaction"
+	"github.com/ory/x/httpx"
+	"github.com/ory/x/logrusx"
+	"github.com/ory/x/otelx"
+)
+
+const (
+	// StrategyID is the settings method which deletes the account.
+	StrategyID = "deletion"
+
+	// ConfirmationPhrase must be typed by the user to confirm the deletion
+	// of the account.
+	ConfirmationPhrase = "DELETE"
+)
+
+var _ settings.Strategy = (*Strategy)(nil)
+
+type dependencies interface {
+	logrusx.Provider
+	httpx.WriterProvider
+	nosurfx.CSRFTokenGeneratorProvider
+	nosurfx.CSRFProvider
+	transaction.PersistenceProvider
+	otelx.Provider
+
+	config.Provider
+
+	continuity.ManagementProvider
+
+	x.CookieProvider
+
+	settings.FlowPersistenceProvider
+	settings.HooksProvider
+
+	identity.PrivilegedPoolProvider
+	identity.ScheduledDeletionPersistenceProvider
+
+	session.ManagementProvider
+	session.PersistenceProvider
+}
+
+type Strategy struct{ d dependencies }
+
+func NewStrategy(d dependencies) *Strategy { return &Strategy{d: d} }
+
+func (s *Strategy) SettingsStrategyID() string {
+	return StrategyID
+}
+
+func (s *Strategy) NodeGroup() node.UiNodeGroup {
+	return node.DeletionGroup
+}
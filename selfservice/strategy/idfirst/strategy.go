@@ -8,6 +8,7 @@ import (
 
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/session"
 	"github.com/ory/kratos/ui/node"
@@ -29,6 +30,9 @@ type dependencies interface {
 	identity.PrivilegedPoolProvider
 	login.StrategyProvider
 	login.FlowPersistenceProvider
+	login.OrganizationSSOStarterProvider
+
+	organization.ManagementProvider
 }
 
 type Strategy struct{ d dependencies }
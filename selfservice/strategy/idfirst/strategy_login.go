@@ -69,6 +69,13 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		return nil, s.handleLoginError(r, f, p, err)
 	}
 
+	if err := login.EnforceOrganizationSSO(w, r, s.d, f, p.Identifier); err != nil {
+		if errors.Is(err, flow.ErrCompletedByStrategy) {
+			return nil, err
+		}
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+
 	expand := identity.ExpandCredentials
 	if s.d.Config().SecurityAccountEnumerationMitigate(ctx) {
 		expand = identity.ExpandNothing
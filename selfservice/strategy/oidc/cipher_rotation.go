@@ -0,0 +1,86 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/kratos/cipher"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/x/logrusx"
+)
+
+type (
+	providerSecretsCipherRotationDependencies interface {
+		ProviderPersistenceProvider
+		cipher.Provider
+		logrusx.Provider
+	}
+
+	providerSecretsCipherRotation struct {
+		d providerSecretsCipherRotationDependencies
+	}
+)
+
+// NewProviderSecretsCipherRotation returns the rotation target for the
+// secrets of the providers managed through the admin API.
+func NewProviderSecretsCipherRotation(d providerSecretsCipherRotationDependencies) identity.CipherRotationTarget {
+	return &providerSecretsCipherRotation{d: d}
+}
+
+func (r *providerSecretsCipherRotation) RotateCiphertexts(ctx context.Context, _ int, result *identity.CipherRotationProgress, progress func(identity.CipherRotationProgress)) error {
+	stored, err := r.d.OIDCProviderPersister().ListOIDCProviders(ctx)
+	if err != nil {
+		return err
+	}
+
+	for k := range stored {
+		rotated, err := r.rotate(ctx, &stored[k])
+		result.Scanned++
+		switch {
+		case err != nil:
+			result.Failed++
+			r.d.Logger().
+				WithError(err).
+				WithField("provider_id", stored[k].ProviderID).
+				Warn("Unable to re-encrypt the OpenID Connect provider's secrets with the primary cipher secret.")
+		case rotated:
+			result.Rotated++
+		}
+	}
+
+	if progress != nil {
+		progress(*result)
+	}
+	return nil
+}
+
+func (r *providerSecretsCipherRotation) rotate(ctx context.Context, sp *StoredProvider) (rotated bool, err error) {
+	var c Configuration
+	if err := json.Unmarshal(sp.Config, &c); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	for _, secret := range c.secrets() {
+		ciphertext, ok, err := cipher.Reencrypt(ctx, r.d.Cipher(ctx), *secret)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			*secret = ciphertext
+			rotated = true
+		}
+	}
+	if !rotated {
+		return false, nil
+	}
+
+	if sp.Config, err = json.Marshal(c); err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, r.d.OIDCProviderPersister().UpdateOIDCProvider(ctx, sp)
+}
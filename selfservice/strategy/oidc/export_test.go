@@ -5,6 +5,7 @@ package oidc
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 
 	"github.com/gofrs/uuid"
@@ -40,3 +41,9 @@ func (s *Strategy) ProcessTestLoginForTest(ctx context.Context, w http.ResponseW
 func (s *Strategy) FinishTestLoginForTest(ctx context.Context, w http.ResponseWriter, r *http.Request, f *login.Flow, dp *login.DebugPayload) error {
 	return s.finishTestLogin(ctx, w, r, f, dp)
 }
+
+// TLSClientConfigForTest exposes the TLS configuration presenting the client
+// certificate so tests can trust their own certificate authority.
+func (c *Configuration) TLSClientConfigForTest() (*tls.Config, error) {
+	return c.tlsClientConfig()
+}
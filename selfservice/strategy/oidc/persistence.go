@@ -0,0 +1,50 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/ory/x/sqlxx"
+)
+
+type (
+	// StoredProvider is an OpenID Connect provider managed through the admin
+	// API. Its secrets are encrypted in the stored configuration.
+	//
+	// swagger:ignore
+	StoredProvider struct {
+		ID  uuid.UUID `json:"-" db:"id"`
+		NID uuid.UUID `json:"-" db:"nid"`
+
+		// ProviderID is the ID of the provider, which is unique per network.
+		ProviderID string `json:"id" db:"provider_id"`
+
+		// Config is the provider's configuration with encrypted secrets.
+		Config sqlxx.JSONRawMessage `json:"config" db:"config"`
+
+		CreatedAt time.Time `json:"created_at" db:"created_at"`
+		UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	}
+
+	ProviderPersister interface {
+		CreateOIDCProvider(ctx context.Context, p *StoredProvider) error
+		GetOIDCProvider(ctx context.Context, providerID string) (*StoredProvider, error)
+		ListOIDCProviders(ctx context.Context) ([]StoredProvider, error)
+		UpdateOIDCProvider(ctx context.Context, p *StoredProvider) error
+		DeleteOIDCProvider(ctx context.Context, providerID string) error
+		NetworkID(ctx context.Context) uuid.UUID
+	}
+
+	ProviderPersistenceProvider interface {
+		OIDCProviderPersister() ProviderPersister
+	}
+)
+
+func (StoredProvider) TableName() string {
+	return "selfservice_oidc_providers"
+}
@@ -0,0 +1,362 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha1" //#nosec G505 -- required for the x5t header
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v4"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/contextx"
+	"github.com/ory/x/httpx"
+)
+
+const (
+	// TokenEndpointAuthMethodClientSecretBasic sends the client secret using
+	// HTTP basic authentication.
+	TokenEndpointAuthMethodClientSecretBasic = "client_secret_basic"
+	// TokenEndpointAuthMethodClientSecretPost sends the client secret in the
+	// request body.
+	TokenEndpointAuthMethodClientSecretPost = "client_secret_post"
+	// TokenEndpointAuthMethodPrivateKeyJWT authenticates using a client
+	// assertion signed with the configured key (RFC 7523).
+	TokenEndpointAuthMethodPrivateKeyJWT = "private_key_jwt"
+	// TokenEndpointAuthMethodTLSClientAuth authenticates using the configured
+	// client certificate (RFC 8705).
+	TokenEndpointAuthMethodTLSClientAuth = "tls_client_auth"
+
+	clientAssertionType     = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	clientAssertionLifespan = 5 * time.Minute
+)
+
+// tlsClients caches the registry clients presenting a client certificate so
+// that connections are reused across requests.
+var tlsClients, _ = lru.New[[32]byte, *http.Client](128)
+
+// authStyle returns how the client credentials are sent to the token endpoint.
+func (c *Configuration) authStyle() oauth2.AuthStyle {
+	switch c.TokenEndpointAuthMethod {
+	case TokenEndpointAuthMethodClientSecretBasic:
+		return oauth2.AuthStyleInHeader
+	case TokenEndpointAuthMethodClientSecretPost, TokenEndpointAuthMethodPrivateKeyJWT, TokenEndpointAuthMethodTLSClientAuth:
+		return oauth2.AuthStyleInParams
+	default:
+		return oauth2.AuthStyleAutoDetect
+	}
+}
+
+// usesClientCertificate returns true if a client certificate is configured.
+func (c *Configuration) usesClientCertificate() bool {
+	return c.TLSClientCertificate != "" || c.TLSClientKey != ""
+}
+
+// HTTPClient returns the client for requests to the provider. It presents the
+// configured client certificate and authenticates at the token endpoint using
+// the configured method.
+func (c *Configuration) HTTPClient(ctx context.Context, reg Dependencies) (*http.Client, error) {
+	switch c.TokenEndpointAuthMethod {
+	case "", TokenEndpointAuthMethodClientSecretBasic, TokenEndpointAuthMethodClientSecretPost:
+		if !c.usesClientCertificate() {
+			return reg.HTTPClient(ctx).HTTPClient, nil
+		}
+	case TokenEndpointAuthMethodPrivateKeyJWT:
+		if c.ClientAssertionSigningKey == "" {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("OpenID Connect provider %q uses private_key_jwt but no client assertion signing key is configured.", c.ID))
+		}
+	case TokenEndpointAuthMethodTLSClientAuth:
+		if !c.usesClientCertificate() {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("OpenID Connect provider %q uses tls_client_auth but no client certificate is configured.", c.ID))
+		}
+	default:
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("OpenID Connect provider %q uses the unsupported token endpoint authentication method %q.", c.ID, c.TokenEndpointAuthMethod))
+	}
+
+	base := reg.HTTPClient(ctx).HTTPClient
+	if c.usesClientCertificate() {
+		cl, err := c.tlsClient(ctx, reg)
+		if err != nil {
+			return nil, err
+		}
+		base = cl
+	}
+
+	transport := base.Transport
+
+	if c.TokenEndpointAuthMethod == TokenEndpointAuthMethodPrivateKeyJWT || c.TokenEndpointAuthMethod == TokenEndpointAuthMethodTLSClientAuth {
+		ct := &clientAuthTransport{base: transport, clientID: c.ClientID}
+		if c.TokenEndpointAuthMethod == TokenEndpointAuthMethodPrivateKeyJWT {
+			signer, err := newClientAssertionSigner(c)
+			if err != nil {
+				return nil, err
+			}
+			ct.assertion = signer.sign
+		}
+		transport = ct
+	}
+
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: base.CheckRedirect,
+		Jar:           base.Jar,
+		Timeout:       base.Timeout,
+	}, nil
+}
+
+// tlsClient returns the registry's client presenting the configured client
+// certificate, so that the network policy of all other provider requests
+// applies to it as well.
+func (c *Configuration) tlsClient(ctx context.Context, reg Dependencies) (*http.Client, error) {
+	// The registry reads these from the root context, see RegistryDefault.HTTPClient.
+	noInternalIPs := reg.Config().ClientHTTPNoPrivateIPRanges(contextx.RootContext)
+	exceptions := reg.Config().ClientHTTPPrivateIPExceptionURLs(contextx.RootContext)
+
+	h := sha256.New()
+	_, _ = io.WriteString(h, c.TLSClientCertificate+"\x00"+c.TLSClientKey+"\x00")
+	if noInternalIPs {
+		_, _ = io.WriteString(h, strings.Join(exceptions, "\x00"))
+		_, _ = h.Write([]byte{1})
+	}
+	var fingerprint [32]byte
+	copy(fingerprint[:], h.Sum(nil))
+	if cl, ok := tlsClients.Get(fingerprint); ok {
+		return cl, nil
+	}
+
+	conf, err := c.tlsClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	cl := reg.HTTPClient(ctx, httpx.ResilientClientWithTLSConfig(conf)).HTTPClient
+	tlsClients.Add(fingerprint, cl)
+	return cl, nil
+}
+
+// tlsClientConfig returns the TLS configuration presenting the configured
+// client certificate.
+func (c *Configuration) tlsClientConfig() (*tls.Config, error) {
+	cert, err := tls.X509KeyPair([]byte(c.TLSClientCertificate), []byte(c.TLSClientKey))
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithWrap(err).WithReasonf("Unable to load the client certificate of OpenID Connect provider %q: %s", c.ID, err))
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// clientAuthTransport rewrites token requests so that the client is
+// authenticated with a client assertion or its TLS client certificate instead
+// of a client secret.
+type clientAuthTransport struct {
+	base      http.RoundTripper
+	clientID  string
+	assertion func(audience string) (string, error)
+}
+
+func (t *clientAuthTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return t.base.RoundTrip(r)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/x-www-form-urlencoded" {
+		return t.base.RoundTrip(r)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil || form.Get("grant_type") == "" {
+		// Not a token request.
+		r = r.Clone(r.Context())
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		return t.base.RoundTrip(r)
+	}
+
+	form.Del("client_secret")
+	form.Set("client_id", t.clientID)
+	if t.assertion != nil {
+		audience := url.URL{Scheme: r.URL.Scheme, Host: r.URL.Host, Path: r.URL.Path}
+		assertion, err := t.assertion(audience.String())
+		if err != nil {
+			return nil, err
+		}
+		form.Set("client_assertion_type", clientAssertionType)
+		form.Set("client_assertion", assertion)
+	}
+
+	encoded := form.Encode()
+	r = r.Clone(r.Context())
+	r.Header.Del("Authorization")
+	r.Body = io.NopCloser(strings.NewReader(encoded))
+	r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(encoded)), nil }
+	r.ContentLength = int64(len(encoded))
+	return t.base.RoundTrip(r)
+}
+
+type clientAssertionSigner struct {
+	clientID string
+	method   jwt.SigningMethod
+	key      crypto.Signer
+	headers  map[string]string
+}
+
+// newClientAssertionSigner parses the client assertion signing key, which is
+// either a JWK or a PEM-encoded private key. PEM-encoded keys may be followed
+// by the key's certificate, whose thumbprint is then sent in the x5t header.
+func newClientAssertionSigner(c *Configuration) (*clientAssertionSigner, error) {
+	s := &clientAssertionSigner{clientID: c.ClientID, headers: map[string]string{}}
+	alg := c.ClientAssertionSigningAlg
+
+	raw := strings.TrimSpace(c.ClientAssertionSigningKey)
+	if strings.HasPrefix(raw, "{") {
+		key, err := jwk.ParseKey([]byte(raw))
+		if err != nil {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration().WithWrap(err).WithReasonf("Unable to parse the client assertion signing key of OpenID Connect provider %q: %s", c.ID, err))
+		}
+		var private any
+		if err := key.Raw(&private); err != nil {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration().WithWrap(err).WithReasonf("Unable to parse the client assertion signing key of OpenID Connect provider %q: %s", c.ID, err))
+		}
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("The client assertion signing key of OpenID Connect provider %q is not a private key.", c.ID))
+		}
+		s.key = signer
+		if kid := key.KeyID(); kid != "" {
+			s.headers["kid"] = kid
+		}
+		if x5t := key.X509CertThumbprint(); x5t != "" {
+			s.headers["x5t"] = x5t
+		}
+		if x5t := key.X509CertThumbprintS256(); x5t != "" {
+			s.headers["x5t#S256"] = x5t
+		}
+		if alg == "" && key.Algorithm().String() != "" {
+			alg = key.Algorithm().String()
+		}
+	} else {
+		for rest := []byte(raw); ; {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			switch block.Type {
+			case "CERTIFICATE":
+				sha1Sum := sha1.Sum(block.Bytes) //#nosec G401 -- required for the x5t header
+				sha256Sum := sha256.Sum256(block.Bytes)
+				s.headers["x5t"] = base64.RawURLEncoding.EncodeToString(sha1Sum[:])
+				s.headers["x5t#S256"] = base64.RawURLEncoding.EncodeToString(sha256Sum[:])
+			case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY":
+				key, err := parsePrivateKey(block)
+				if err != nil {
+					return nil, errors.WithStack(herodot.ErrMisconfiguration().WithWrap(err).WithReasonf("Unable to parse the client assertion signing key of OpenID Connect provider %q: %s", c.ID, err))
+				}
+				s.key = key
+			}
+		}
+		if s.key == nil {
+			return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("The client assertion signing key of OpenID Connect provider %q contains no private key.", c.ID))
+		}
+		if c.ClientAssertionSigningKeyID != "" {
+			s.headers["kid"] = c.ClientAssertionSigningKeyID
+		}
+	}
+
+	if alg == "" {
+		alg = defaultSigningAlg(s.key)
+	}
+	s.method = jwt.GetSigningMethod(alg)
+	if s.method == nil || alg == "none" {
+		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("The client assertion signing algorithm %q of OpenID Connect provider %q is not supported.", alg, c.ID))
+	}
+
+	return s, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+func defaultSigningAlg(key crypto.Signer) string {
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P384():
+			return jwt.SigningMethodES384.Alg()
+		case elliptic.P521():
+			return jwt.SigningMethodES512.Alg()
+		}
+		return jwt.SigningMethodES256.Alg()
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA.Alg()
+	case *rsa.PrivateKey:
+		return jwt.SigningMethodRS256.Alg()
+	}
+	return ""
+}
+
+// sign returns a client assertion for the given audience, which is the token
+// endpoint.
+func (s *clientAssertionSigner) sign(audience string) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(s.method, jwt.RegisteredClaims{
+		Issuer:    s.clientID,
+		Subject:   s.clientID,
+		Audience:  jwt.ClaimStrings{audience},
+		ID:        uuid.Must(uuid.NewV4()).String(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(clientAssertionLifespan)),
+	})
+	for k, v := range s.headers {
+		token.Header[k] = v
+	}
+
+	assertion, err := token.SignedString(s.key)
+	if err != nil {
+		return "", errors.WithStack(herodot.ErrInternalServerError().WithWrap(err).WithReasonf("Unable to sign the client assertion: %s", err))
+	}
+	return assertion, nil
+}
@@ -0,0 +1,232 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/selfservice/strategy/oidc"
+	"github.com/ory/x/httpx"
+)
+
+func newSelfSignedCertificate(t *testing.T, key crypto.Signer) (certPEM, keyPEM []byte) {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kratos"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})
+}
+
+func TestClientAuthentication(t *testing.T) {
+	t.Parallel()
+
+	_, reg := pkg.NewVeryFastRegistryWithoutDB(t)
+
+	// newTokenEndpoint returns a token endpoint which hands the token request
+	// to check and issues an access token if it passes.
+	newTokenEndpoint := func(t *testing.T, check func(r *http.Request, form url.Values)) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			check(r, r.PostForm)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "access-token", "token_type": "bearer", "expires_in": 3600})
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	exchange := func(t *testing.T, c *oidc.Configuration, tokenURL string) {
+		client, err := c.HTTPClient(t.Context(), reg)
+		require.NoError(t, err)
+
+		conf := &oauth2.Config{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			Endpoint:     oauth2.Endpoint{TokenURL: tokenURL},
+		}
+		token, err := conf.Exchange(context.WithValue(t.Context(), oauth2.HTTPClient, client), "code")
+		require.NoError(t, err)
+		assert.Equal(t, "access-token", token.AccessToken)
+	}
+
+	t.Run("method=private_key_jwt", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		certPEM, keyPEM := newSelfSignedCertificate(t, rsaKey)
+
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		ecJWK, err := jwk.FromRaw(ecKey)
+		require.NoError(t, err)
+		require.NoError(t, ecJWK.Set(jwk.KeyIDKey, "ec-key"))
+		rawJWK, err := json.Marshal(ecJWK)
+		require.NoError(t, err)
+
+		for _, tc := range []struct {
+			name    string
+			key     string
+			alg     string
+			public  crypto.PublicKey
+			headers map[string]bool
+		}{
+			{name: "PEM key with certificate", key: string(keyPEM) + string(certPEM), alg: "PS256", public: &rsaKey.PublicKey, headers: map[string]bool{"x5t": true, "x5t#S256": true, "kid": false}},
+			{name: "JWK", key: string(rawJWK), public: &ecKey.PublicKey, headers: map[string]bool{"kid": true, "x5t": false}},
+		} {
+			t.Run("case="+tc.name, func(t *testing.T) {
+				c := &oidc.Configuration{
+					ID:                        "fapi",
+					ClientID:                  "client",
+					ClientSecret:              "must-not-be-sent",
+					TokenEndpointAuthMethod:   oidc.TokenEndpointAuthMethodPrivateKeyJWT,
+					ClientAssertionSigningKey: tc.key,
+					ClientAssertionSigningAlg: tc.alg,
+				}
+
+				var srv *httptest.Server
+				srv = newTokenEndpoint(t, func(r *http.Request, form url.Values) {
+					assert.Empty(t, r.Header.Get("Authorization"))
+					assert.Empty(t, form.Get("client_secret"))
+					assert.Equal(t, "client", form.Get("client_id"))
+					assert.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", form.Get("client_assertion_type"))
+
+					var claims jwt.RegisteredClaims
+					token, err := jwt.ParseWithClaims(form.Get("client_assertion"), &claims, func(*jwt.Token) (any, error) { return tc.public, nil })
+					require.NoError(t, err)
+					if tc.alg != "" {
+						assert.Equal(t, tc.alg, token.Method.Alg())
+					}
+					for header, present := range tc.headers {
+						_, ok := token.Header[header]
+						assert.Equal(t, present, ok, header)
+					}
+					assert.Equal(t, "client", claims.Issuer)
+					assert.Equal(t, "client", claims.Subject)
+					assert.True(t, claims.VerifyAudience(srv.URL+"/token", true), "%v", claims.Audience)
+					assert.NotEmpty(t, claims.ID)
+				})
+
+				exchange(t, c, srv.URL+"/token")
+			})
+		}
+
+		t.Run("case=missing key", func(t *testing.T) {
+			_, err := (&oidc.Configuration{ID: "fapi", TokenEndpointAuthMethod: oidc.TokenEndpointAuthMethodPrivateKeyJWT}).HTTPClient(t.Context(), reg)
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("method=tls_client_auth", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		certPEM, keyPEM := newSelfSignedCertificate(t, key)
+
+		t.Run("case=sends the client ID instead of the secret", func(t *testing.T) {
+			srv := newTokenEndpoint(t, func(r *http.Request, form url.Values) {
+				assert.Empty(t, r.Header.Get("Authorization"))
+				assert.Empty(t, form.Get("client_secret"))
+				assert.Equal(t, "client", form.Get("client_id"))
+			})
+
+			exchange(t, &oidc.Configuration{
+				ID:                      "mtls",
+				ClientID:                "client",
+				ClientSecret:            "must-not-be-sent",
+				TokenEndpointAuthMethod: oidc.TokenEndpointAuthMethodTLSClientAuth,
+				TLSClientCertificate:    string(certPEM),
+				TLSClientKey:            string(keyPEM),
+			}, srv.URL+"/token")
+		})
+
+		t.Run("case=presents the client certificate", func(t *testing.T) {
+			cert, err := tls.X509KeyPair(certPEM, keyPEM)
+			require.NoError(t, err)
+
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Len(t, r.TLS.PeerCertificates, 1)
+				assert.Equal(t, "kratos", r.TLS.PeerCertificates[0].Subject.CommonName)
+			}))
+			srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+			srv.StartTLS()
+			t.Cleanup(srv.Close)
+
+			conf, err := (&oidc.Configuration{
+				ID:                   "mtls",
+				TLSClientCertificate: string(certPEM),
+				TLSClientKey:         string(keyPEM),
+			}).TLSClientConfigForTest()
+			require.NoError(t, err)
+			assert.Equal(t, [][]byte(cert.Certificate), [][]byte(conf.Certificates[0].Certificate))
+			conf.RootCAs = x509.NewCertPool()
+			conf.RootCAs.AddCert(srv.Certificate())
+
+			res, err := httpx.NewResilientClient(httpx.ResilientClientWithTLSConfig(conf)).HTTPClient.Get(srv.URL)
+			require.NoError(t, err)
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+		})
+
+		t.Run("case=applies the network policy of the registry", func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("the request should not have reached the test server")
+			}))
+			t.Cleanup(srv.Close)
+
+			_, reg := pkg.NewVeryFastRegistryWithoutDB(t)
+			reg.Config().MustSet(t.Context(), config.ViperKeyClientHTTPNoPrivateIPRanges, true)
+
+			client, err := (&oidc.Configuration{
+				ID:                      "mtls",
+				ClientID:                "client",
+				TokenEndpointAuthMethod: oidc.TokenEndpointAuthMethodTLSClientAuth,
+				TLSClientCertificate:    string(certPEM),
+				TLSClientKey:            string(keyPEM),
+			}).HTTPClient(t.Context(), reg)
+			require.NoError(t, err)
+
+			conf := &oauth2.Config{ClientID: "client", Endpoint: oauth2.Endpoint{TokenURL: srv.URL + "/token"}}
+			_, err = conf.Exchange(context.WithValue(t.Context(), oauth2.HTTPClient, client), "code")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "no route to host")
+		})
+
+		t.Run("case=missing certificate", func(t *testing.T) {
+			_, err := (&oidc.Configuration{ID: "mtls", TokenEndpointAuthMethod: oidc.TokenEndpointAuthMethodTLSClientAuth}).HTTPClient(t.Context(), reg)
+			require.Error(t, err)
+		})
+	})
+}
@@ -73,10 +73,40 @@ type Configuration struct {
 	// ClientSecret is the application's secret.
 	ClientSecret string `json:"client_secret"`
 
+	// TokenEndpointAuthMethod is the method used to authenticate at the token endpoint. Can be one of
+	// `client_secret_basic`, `client_secret_post`, `private_key_jwt`, or `tls_client_auth`. If empty, the client
+	// secret is sent using whichever of `client_secret_basic` and `client_secret_post` the provider accepts.
+	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method,omitempty"`
+
+	// ClientAssertionSigningKey is the private key used to sign client assertions if `token_endpoint_auth_method` is
+	// `private_key_jwt`. It can be a JWK or a PEM-encoded private key. A PEM-encoded key may be followed by its
+	// certificate, whose thumbprint is then sent in the `x5t` header as required by Microsoft Entra ID.
+	ClientAssertionSigningKey string `json:"client_assertion_signing_key,omitempty"`
+
+	// ClientAssertionSigningKeyID is sent in the `kid` header of client assertions signed with a PEM-encoded key.
+	// JWKs use their own key ID.
+	ClientAssertionSigningKeyID string `json:"client_assertion_signing_key_id,omitempty"`
+
+	// ClientAssertionSigningAlg is the algorithm used to sign client assertions, for example `PS256`. Defaults to the
+	// JWK's algorithm or, if not set, `RS256` for RSA keys, `ES256`, `ES384` or `ES512` for EC keys, and `EdDSA` for
+	// Ed25519 keys.
+	ClientAssertionSigningAlg string `json:"client_assertion_signing_alg,omitempty"`
+
+	// TLSClientCertificate is the PEM-encoded client certificate presented to the provider for mutual TLS.
+	TLSClientCertificate string `json:"tls_client_certificate,omitempty"`
+
+	// TLSClientKey is the PEM-encoded private key of the client certificate.
+	TLSClientKey string `json:"tls_client_key,omitempty"`
+
 	// IssuerURL is the OpenID Connect Server URL. You can leave this empty if `provider` is not set to `generic`.
 	// If set, neither `auth_url` nor `token_url` are required.
 	IssuerURL string `json:"issuer_url"`
 
+	// UpstreamLogout enables OpenID Connect RP-Initiated Logout. If set, browsers logging out of a session which was
+	// authenticated with this provider are redirected to the provider's `end_session_endpoint` before returning to the
+	// logout return URL. The provider must allow the logout return URL as a post logout redirect URI.
+	UpstreamLogout bool `json:"upstream_logout,omitempty"`
+
 	// AuthURL is the authorize url, typically something like: https://example.org/oauth2/auth
 	// Should only be used when the OAuth2 / OpenID Connect server is not supporting OpenID Connect Discovery and when
 	// `provider` is set to `generic`.
@@ -45,7 +45,13 @@ func (g *ProviderGenericOIDC) Config() *Configuration {
 }
 
 func (g *ProviderGenericOIDC) withHTTPClientContext(ctx context.Context) context.Context {
-	return gooidc.ClientContext(ctx, g.reg.HTTPClient(ctx).HTTPClient)
+	client, err := g.config.HTTPClient(ctx, g.reg)
+	if err != nil {
+		// The misconfiguration is reported by OAuth2, which is called before
+		// any request is sent to the provider.
+		client = g.reg.HTTPClient(ctx).HTTPClient
+	}
+	return gooidc.ClientContext(ctx, client)
 }
 
 func (g *ProviderGenericOIDC) provider(ctx context.Context) (*gooidc.Provider, error) {
@@ -65,6 +71,10 @@ func (g *ProviderGenericOIDC) oauth2ConfigFromEndpoint(ctx context.Context, endp
 		scope = append(scope, gooidc.ScopeOpenID)
 	}
 
+	if style := g.config.authStyle(); style != oauth2.AuthStyleAutoDetect {
+		endpoint.AuthStyle = style
+	}
+
 	return &oauth2.Config{
 		ClientID:     g.config.ClientID,
 		ClientSecret: g.config.ClientSecret,
@@ -75,15 +85,46 @@ func (g *ProviderGenericOIDC) oauth2ConfigFromEndpoint(ctx context.Context, endp
 }
 
 func (g *ProviderGenericOIDC) OAuth2(ctx context.Context) (*oauth2.Config, error) {
+	if _, err := g.config.HTTPClient(ctx, g.reg); err != nil {
+		return nil, err
+	}
+
 	p, err := g.provider(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	endpoint := p.Endpoint()
+	if g.config.usesClientCertificate() {
+		// Providers supporting mutual TLS may require it on dedicated
+		// endpoints (RFC 8705, Section 5).
+		var claims struct {
+			Aliases struct {
+				TokenEndpoint string `json:"token_endpoint"`
+			} `json:"mtls_endpoint_aliases"`
+		}
+		if err := p.Claims(&claims); err == nil && claims.Aliases.TokenEndpoint != "" {
+			endpoint.TokenURL = claims.Aliases.TokenEndpoint
+		}
+	}
 	return g.oauth2ConfigFromEndpoint(ctx, endpoint), nil
 }
 
+func (g *ProviderGenericOIDC) endSessionEndpoint(ctx context.Context) (string, error) {
+	p, err := g.provider(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var claims struct {
+		EndSessionEndpoint string `json:"end_session_endpoint"`
+	}
+	if err := p.Claims(&claims); err != nil {
+		return "", errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to decode the OpenID Connect discovery document: %s", err))
+	}
+	return claims.EndSessionEndpoint, nil
+}
+
 func (g *ProviderGenericOIDC) AuthCodeURLOptions(r ider) []oauth2.AuthCodeOption {
 	var options []oauth2.AuthCodeOption
 
@@ -18,11 +18,13 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/hashicorp/golang-lru/v2/expirable"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/ory/herodot"
 	"github.com/ory/kratos/cipher"
@@ -30,6 +32,7 @@ import (
 	"github.com/ory/kratos/driver/config"
 	oidcv1 "github.com/ory/kratos/gen/oidc/v1"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/schema"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow"
@@ -102,6 +105,9 @@ type Dependencies interface {
 	login.StrategyProvider
 	login.HandlerProvider
 	login.ErrorHandlerProvider
+	login.OrganizationSSOStarterProvider
+
+	organization.ManagementProvider
 
 	registration.HookExecutorProvider
 	registration.FlowPersistenceProvider
@@ -118,6 +124,8 @@ type Dependencies interface {
 
 	cipher.Provider
 
+	ProviderPersistenceProvider
+
 	jsonnetsecure.VMProvider
 }
 
@@ -155,6 +163,14 @@ type Strategy struct {
 	handleMethodNotAllowedError func(err error) error
 
 	conflictingIdentityPolicy ConflictingIdentityPolicy
+
+	// runtimeProviders caches the decrypted providers managed through the
+	// admin API per network.
+	runtimeProviders *expirable.LRU[uuid.UUID, []Configuration]
+
+	// upstreamTokenRefreshes deduplicates concurrent refreshes of the same
+	// upstream tokens.
+	upstreamTokenRefreshes singleflight.Group
 }
 type ConflictingIdentityPolicy func(ctx context.Context, existingIdentity, newIdentity *identity.Identity, provider Provider, claims *Claims) ConflictingIdentityVerdict
 
@@ -223,7 +239,17 @@ func (s *Strategy) RegisterPublicRoutes(r *httprouterx.RouterPublic) {
 	r.POST(RouteCallback, s.redirectToGET)
 }
 
-func (s *Strategy) RegisterAdminRoutes(*httprouterx.RouterAdmin) {}
+func (s *Strategy) RegisterAdminRoutes(r *httprouterx.RouterAdmin) {
+	if s.ID() == identity.CredentialsTypeOIDC {
+		r.GET(RouteAdminUpstreamToken, s.getUpstreamToken)
+
+		r.GET(RouteAdminProviders, s.listRuntimeProviders)
+		r.POST(RouteAdminProviders, s.createRuntimeProvider)
+		r.GET(RouteAdminProvider, s.getRuntimeProvider)
+		r.PUT(RouteAdminProvider, s.updateRuntimeProvider)
+		r.DELETE(RouteAdminProvider, s.deleteRuntimeProvider)
+	}
+}
 
 // Redirect POST request to GET rewriting form fields to query params.
 func (s *Strategy) redirectToGET(w http.ResponseWriter, r *http.Request) {
@@ -287,6 +313,7 @@ func NewStrategy(d Dependencies, opts ...NewStrategyOpt) *Strategy {
 		credType:                    identity.CredentialsTypeOIDC,
 		handleUnknownProviderError:  func(err error) error { return err },
 		handleMethodNotAllowedError: func(err error) error { return err },
+		runtimeProviders:            expirable.NewLRU[uuid.UUID, []Configuration](runtimeProvidersCacheSize, nil, runtimeProvidersCacheTTL),
 	}
 
 	for _, opt := range opts {
@@ -629,8 +656,11 @@ func (s *Strategy) exchangeCode(ctx context.Context, provider OAuth2Provider, co
 		}
 	}
 
-	client := s.d.HTTPClient(ctx)
-	ctx = context.WithValue(ctx, oauth2.HTTPClient, client.HTTPClient)
+	client, err := provider.Config().HTTPClient(ctx, s.d)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, client)
 	return te.Exchange(ctx, code, opts...)
 }
 
@@ -657,6 +687,22 @@ func (s *Strategy) Config(ctx context.Context) (*ConfigurationCollection, error)
 		return nil, errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to decode OpenID Connect Provider configuration: %s", err))
 	}
 
+	if s.ID() != identity.CredentialsTypeOIDC {
+		return &c, nil
+	}
+
+	// Providers managed through the admin API are added to the configured
+	// ones. Configured providers take precedence.
+	runtime, err := s.listRuntimeProviderConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range runtime {
+		if !slices.ContainsFunc(c.Providers, func(configured Configuration) bool { return configured.ID == p.ID }) {
+			c.Providers = append(c.Providers, p)
+		}
+	}
+
 	return &c, nil
 }
 
@@ -771,6 +817,11 @@ func (s *Strategy) HandleError(ctx context.Context, w http.ResponseWriter, r *ht
 			if err != nil {
 				return err
 			}
+			access, err := schema.NewTraitAccess(ctx, ds.String(), s.d.Config().SecurityDisallowRefInIdentitySchemas(ctx))
+			if err != nil {
+				return err
+			}
+			container.DisableTraitNodes(traitNodes, access.IsWritableInRegistration)
 
 			rf.UI.Nodes = append(rf.UI.Nodes, traitNodes...)
 			rf.UI.UpdateNodeValuesFromJSON(traits, "traits", group)
@@ -936,6 +987,8 @@ func (s *Strategy) linkCredentials(ctx context.Context, i *identity.Identity, to
 			InitialRefreshToken: tokens.GetRefreshToken(),
 			InitialIDToken:      tokens.GetIDToken(),
 			Organization:        organization,
+
+			AccessTokenExpiresAt: tokens.GetExpiresAt(),
 		})
 
 		creds.Config, err = json.Marshal(conf)
@@ -993,5 +1046,6 @@ func (s *Strategy) encryptOAuth2Tokens(ctx context.Context, token *oauth2.Token)
 		return nil, err
 	}
 
+	et.ExpiresAt = token.Expiry
 	return et, nil
 }
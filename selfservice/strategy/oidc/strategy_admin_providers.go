@@ -0,0 +1,488 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/x/jsonx"
+	"github.com/ory/x/sqlcon"
+	"github.com/ory/x/urlx"
+)
+
+const (
+	RouteAdminProviders = "/oidc/providers"
+	RouteAdminProvider  = RouteAdminProviders + "/{id}"
+)
+
+// runtimeProviderID restricts provider IDs to characters which can be used
+// in the callback URL without escaping.
+var runtimeProviderID = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,255}$`)
+
+// Runtime OpenID Connect Provider
+//
+// A provider managed through the admin API. Secrets are never returned.
+//
+// swagger:model runtimeOidcProvider
+type RuntimeProvider struct {
+	Configuration
+
+	// CreatedAt is the time the provider was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is the time the provider was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// secrets returns the configuration's secrets, which are stored encrypted and
+// never returned by the admin API.
+func (c *Configuration) secrets() []*string {
+	return []*string{&c.ClientSecret, &c.PrivateKey, &c.ClientAssertionSigningKey, &c.TLSClientKey}
+}
+
+func newRuntimeProvider(c *Configuration, sp *StoredProvider) *RuntimeProvider {
+	redacted := *c
+	for _, secret := range redacted.secrets() {
+		*secret = ""
+	}
+	return &RuntimeProvider{Configuration: redacted, CreatedAt: sp.CreatedAt, UpdatedAt: sp.UpdatedAt}
+}
+
+const (
+	runtimeProvidersCacheSize = 1024
+	// runtimeProvidersCacheTTL bounds how long changes made through the admin
+	// API on other instances take to be picked up. Changes on this instance
+	// invalidate the cache immediately.
+	runtimeProvidersCacheTTL = 10 * time.Second
+)
+
+// listRuntimeProviderConfigs returns the decrypted configurations of the
+// providers managed through the admin API. Providers which can not be decoded
+// or decrypted are logged and skipped, so that a single broken row does not
+// prevent signing in with all other providers.
+func (s *Strategy) listRuntimeProviderConfigs(ctx context.Context) ([]Configuration, error) {
+	nid := s.d.OIDCProviderPersister().NetworkID(ctx)
+	if providers, ok := s.runtimeProviders.Get(nid); ok {
+		return providers, nil
+	}
+
+	stored, err := s.d.OIDCProviderPersister().ListOIDCProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make([]Configuration, 0, len(stored))
+	for k := range stored {
+		c, err := s.decodeStoredProvider(ctx, &stored[k])
+		if err != nil {
+			s.d.Logger().
+				WithError(err).
+				WithField("provider_id", stored[k].ProviderID).
+				Error("Unable to decode the OpenID Connect provider managed through the admin API. The provider is skipped.")
+			continue
+		}
+		providers = append(providers, *c)
+	}
+
+	s.runtimeProviders.Add(nid, providers)
+	return providers, nil
+}
+
+// invalidateRuntimeProviders drops the cached providers of the current
+// network after they were changed through the admin API.
+func (s *Strategy) invalidateRuntimeProviders(ctx context.Context) {
+	s.runtimeProviders.Remove(s.d.OIDCProviderPersister().NetworkID(ctx))
+}
+
+func (s *Strategy) decodeStoredProvider(ctx context.Context, sp *StoredProvider) (*Configuration, error) {
+	var c Configuration
+	if err := json.Unmarshal(sp.Config, &c); err != nil {
+		return nil, errors.WithStack(herodot.ErrInternalServerError().WithWrap(err).WithReasonf("Unable to decode the stored OpenID Connect provider %q: %s", sp.ProviderID, err))
+	}
+
+	for _, secret := range c.secrets() {
+		plaintext, err := s.d.Cipher(ctx).Decrypt(ctx, *secret)
+		if err != nil {
+			return nil, err
+		}
+		*secret = string(plaintext)
+	}
+
+	c.ID = sp.ProviderID
+	return &c, nil
+}
+
+func (s *Strategy) encodeStoredProvider(ctx context.Context, c Configuration) ([]byte, error) {
+	var err error
+	for _, secret := range c.secrets() {
+		if *secret, err = s.d.Cipher(ctx).Encrypt(ctx, []byte(*secret)); err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return raw, nil
+}
+
+// validateRuntimeProvider checks that the provider can be used, which includes
+// fetching the issuer's discovery document.
+func (s *Strategy) validateRuntimeProvider(ctx context.Context, c *Configuration) error {
+	if !runtimeProviderID.MatchString(c.ID) {
+		return errors.WithStack(herodot.ErrBadRequest().WithReason("The provider ID must consist of 1 to 255 alphanumeric characters, dashes, or underscores."))
+	}
+	if _, ok := supportedProviders[c.Provider]; !ok {
+		return errors.WithStack(herodot.ErrBadRequest().WithReasonf("The provider type %q is not supported.", c.Provider))
+	}
+	if c.ClientID == "" {
+		return errors.WithStack(herodot.ErrBadRequest().WithReason("The client ID must be set."))
+	}
+	if c.Mapper == "" {
+		return errors.WithStack(herodot.ErrBadRequest().WithReason("The mapper URL must be set."))
+	}
+	if c.Provider == "generic" && c.IssuerURL == "" {
+		return errors.WithStack(herodot.ErrBadRequest().WithReason("The issuer URL must be set for generic providers."))
+	}
+
+	if _, err := c.HTTPClient(ctx, s.d); err != nil {
+		return errors.WithStack(herodot.ErrBadRequest().WithWrap(err).WithReason(herodot.ToDefaultError(err, "").Reason()))
+	}
+
+	var configured ConfigurationCollection
+	if err := json.Unmarshal(s.d.Config().SelfServiceStrategy(ctx, string(s.ID())).Config, &configured); err != nil {
+		return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to decode OpenID Connect Provider configuration: %s", err))
+	}
+	if slices.ContainsFunc(configured.Providers, func(p Configuration) bool { return p.ID == c.ID }) {
+		return errors.WithStack(herodot.ErrConflict().WithReasonf("The provider %q is already defined in the configuration file.", c.ID))
+	}
+
+	if c.IssuerURL != "" {
+		if _, err := gooidc.NewProvider(gooidc.ClientContext(ctx, s.d.HTTPClient(ctx).HTTPClient), c.IssuerURL); err != nil {
+			return errors.WithStack(herodot.ErrBadRequest().WithWrap(err).WithReasonf("Unable to fetch the OpenID Connect discovery document of issuer %q: %s", c.IssuerURL, err))
+		}
+	}
+
+	return nil
+}
+
+// List Runtime OpenID Connect Providers Parameters
+//
+// swagger:parameters listRuntimeOidcProviders
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listRuntimeOidcProviders struct{}
+
+// List Runtime OpenID Connect Providers Response
+//
+// swagger:response listRuntimeOidcProviders
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type listRuntimeOidcProvidersResponse struct {
+	// in: body
+	Body []RuntimeProvider
+}
+
+// swagger:route GET /admin/oidc/providers identity listRuntimeOidcProviders
+//
+// # List OpenID Connect providers managed through the admin API
+//
+// Lists the OpenID Connect providers which were created through the admin API. Providers defined in the
+// configuration file are not included.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: listRuntimeOidcProviders
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (s *Strategy) listRuntimeProviders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	stored, err := s.d.OIDCProviderPersister().ListOIDCProviders(ctx)
+	if err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	providers := make([]RuntimeProvider, 0, len(stored))
+	for k := range stored {
+		c, err := s.decodeStoredProvider(ctx, &stored[k])
+		if err != nil {
+			s.d.Writer().WriteError(w, r, err)
+			return
+		}
+		providers = append(providers, *newRuntimeProvider(c, &stored[k]))
+	}
+
+	s.d.Writer().Write(w, r, providers)
+}
+
+// Create Runtime OpenID Connect Provider Parameters
+//
+// swagger:parameters createRuntimeOidcProvider
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type createRuntimeOidcProvider struct {
+	// in: body
+	// required: true
+	Body Configuration
+}
+
+// swagger:route POST /admin/oidc/providers identity createRuntimeOidcProvider
+//
+// # Create an OpenID Connect provider
+//
+// Creates an OpenID Connect provider which can be used for sign in without reloading the configuration. Secrets like
+// the client secret and private keys are stored encrypted. If an issuer URL is set, its discovery document must be reachable.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  201: runtimeOidcProvider
+//	  400: errorGeneric
+//	  409: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (s *Strategy) createRuntimeProvider(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var c Configuration
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&c); err != nil {
+		s.d.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithError(err.Error())))
+		return
+	}
+
+	if err := s.validateRuntimeProvider(ctx, &c); err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	raw, err := s.encodeStoredProvider(ctx, c)
+	if err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	sp := &StoredProvider{ProviderID: c.ID, Config: raw}
+	if err := s.d.OIDCProviderPersister().CreateOIDCProvider(ctx, sp); err != nil {
+		if errors.Is(err, sqlcon.ErrUniqueViolation()) {
+			err = errors.WithStack(herodot.ErrConflict().WithReasonf("The provider %q already exists.", c.ID))
+		}
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+	s.invalidateRuntimeProviders(ctx)
+
+	s.d.Writer().WriteCreated(w, r,
+		urlx.AppendPaths(s.d.Config().SelfAdminURL(ctx), "oidc", "providers", c.ID).String(),
+		newRuntimeProvider(&c, sp),
+	)
+}
+
+// Get Runtime OpenID Connect Provider Parameters
+//
+// swagger:parameters getRuntimeOidcProvider deleteRuntimeOidcProvider
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getRuntimeOidcProvider struct {
+	// ID is the provider's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+}
+
+// swagger:route GET /admin/oidc/providers/{id} identity getRuntimeOidcProvider
+//
+// # Get an OpenID Connect provider managed through the admin API
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: runtimeOidcProvider
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (s *Strategy) getRuntimeProvider(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sp, err := s.d.OIDCProviderPersister().GetOIDCProvider(ctx, r.PathValue("id"))
+	if err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	c, err := s.decodeStoredProvider(ctx, sp)
+	if err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	s.d.Writer().Write(w, r, newRuntimeProvider(c, sp))
+}
+
+// Update Runtime OpenID Connect Provider Parameters
+//
+// swagger:parameters updateRuntimeOidcProvider
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type updateRuntimeOidcProvider struct {
+	// ID is the provider's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// in: body
+	// required: true
+	Body Configuration
+}
+
+// swagger:route PUT /admin/oidc/providers/{id} identity updateRuntimeOidcProvider
+//
+// # Update an OpenID Connect provider managed through the admin API
+//
+// Replaces the provider's configuration. If secrets like the client secret or private keys are omitted, the stored
+// values are kept.
+//
+//	Consumes:
+//	- application/json
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: runtimeOidcProvider
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (s *Strategy) updateRuntimeProvider(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+
+	var c Configuration
+	if err := jsonx.NewStrictDecoder(r.Body).Decode(&c); err != nil {
+		s.d.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithError(err.Error())))
+		return
+	}
+	if c.ID != "" && c.ID != id {
+		s.d.Writer().WriteError(w, r, errors.WithStack(herodot.ErrBadRequest().WithReason("The provider ID can not be changed.")))
+		return
+	}
+	c.ID = id
+
+	sp, err := s.d.OIDCProviderPersister().GetOIDCProvider(ctx, id)
+	if err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+	previous, err := s.decodeStoredProvider(ctx, sp)
+	if err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+	for k, secret := range c.secrets() {
+		if *secret == "" {
+			*secret = *previous.secrets()[k]
+		}
+	}
+
+	if err := s.validateRuntimeProvider(ctx, &c); err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+
+	if sp.Config, err = s.encodeStoredProvider(ctx, c); err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+	if err := s.d.OIDCProviderPersister().UpdateOIDCProvider(ctx, sp); err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+	s.invalidateRuntimeProviders(ctx)
+
+	s.d.Writer().Write(w, r, newRuntimeProvider(&c, sp))
+}
+
+// swagger:route DELETE /admin/oidc/providers/{id} identity deleteRuntimeOidcProvider
+//
+// # Delete an OpenID Connect provider managed through the admin API
+//
+// Identities which signed in with the provider keep their credentials, but can no longer use them until a provider
+// with the same ID is created again.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  204: emptyResponse
+//	  404: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (s *Strategy) deleteRuntimeProvider(w http.ResponseWriter, r *http.Request) {
+	if err := s.d.OIDCProviderPersister().DeleteOIDCProvider(r.Context(), r.PathValue("id")); err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+	s.invalidateRuntimeProviders(r.Context())
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,202 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/kratos/selfservice/strategy/oidc"
+)
+
+func TestRuntimeProviders(t *testing.T) {
+	t.Parallel()
+
+	var issuer *httptest.Server
+	issuer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 issuer.URL,
+			"authorization_endpoint": issuer.URL + "/oauth2/auth",
+			"token_endpoint":         issuer.URL + "/oauth2/token",
+			"jwks_uri":               issuer.URL + "/.well-known/jwks.json",
+		})
+	}))
+	t.Cleanup(issuer.Close)
+
+	conf, reg := pkg.NewFastRegistryWithMocks(t)
+	setProviderConfig(t, conf, oidc.Configuration{
+		ID:       "configured",
+		Provider: "generic",
+		ClientID: "client",
+		Mapper:   "file://./stub/oidc.hydra.jsonnet",
+	})
+	_, admin := testhelpers.NewKratosServer(t, reg)
+
+	ss, err := reg.SettingsStrategies(t.Context()).Strategy(identity.CredentialsTypeOIDC.String())
+	require.NoError(t, err)
+	s := ss.(*oidc.Strategy)
+
+	do := func(t *testing.T, method, path string, body any) (*http.Response, []byte) {
+		var payload io.Reader
+		if body != nil {
+			raw, err := json.Marshal(body)
+			require.NoError(t, err)
+			payload = bytes.NewReader(raw)
+		}
+		req, err := http.NewRequestWithContext(t.Context(), method, admin.URL+"/admin/oidc/providers"+path, payload)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := admin.Client().Do(req)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		raw, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, raw
+	}
+
+	provider := map[string]any{
+		"id":            "customer-a",
+		"provider":      "generic",
+		"client_id":     "customer-a-client",
+		"client_secret": "customer-a-secret",
+		"issuer_url":    issuer.URL,
+		"mapper_url":    "file://./stub/oidc.hydra.jsonnet",
+	}
+
+	t.Run("case=creates a provider", func(t *testing.T) {
+		res, body := do(t, http.MethodPost, "", provider)
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", body)
+		assert.Equal(t, "customer-a", gjson.GetBytes(body, "id").String())
+		assert.Empty(t, gjson.GetBytes(body, "client_secret").String(), "secrets are not returned")
+
+		c, err := s.Config(t.Context())
+		require.NoError(t, err)
+		require.Len(t, c.Providers, 2)
+		assert.Equal(t, "configured", c.Providers[0].ID)
+		assert.Equal(t, "customer-a", c.Providers[1].ID)
+		assert.Equal(t, "customer-a-secret", c.Providers[1].ClientSecret)
+
+		p, err := s.Provider(t.Context(), "customer-a")
+		require.NoError(t, err)
+		assert.Equal(t, "customer-a-client", p.Config().ClientID)
+
+		sp, err := reg.OIDCProviderPersister().GetOIDCProvider(t.Context(), "customer-a")
+		require.NoError(t, err)
+		assert.NotContains(t, string(sp.Config), "customer-a-secret", "the client secret is stored encrypted")
+	})
+
+	t.Run("case=rejects invalid providers", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			override map[string]any
+			status   int
+		}{
+			"duplicate":          {override: map[string]any{}, status: http.StatusConflict},
+			"configured ID":      {override: map[string]any{"id": "configured"}, status: http.StatusConflict},
+			"invalid ID":         {override: map[string]any{"id": "not/valid"}, status: http.StatusBadRequest},
+			"unknown type":       {override: map[string]any{"id": "customer-b", "provider": "unknown"}, status: http.StatusBadRequest},
+			"unreachable issuer": {override: map[string]any{"id": "customer-b", "issuer_url": issuer.URL + "/unknown"}, status: http.StatusBadRequest},
+			"missing mapper":     {override: map[string]any{"id": "customer-b", "mapper_url": ""}, status: http.StatusBadRequest},
+			"unknown field":      {override: map[string]any{"id": "customer-b", "unknown": true}, status: http.StatusBadRequest},
+			"missing issuer URL": {override: map[string]any{"id": "customer-b", "issuer_url": ""}, status: http.StatusBadRequest},
+		} {
+			t.Run("case="+name, func(t *testing.T) {
+				body := map[string]any{}
+				for k, v := range provider {
+					body[k] = v
+				}
+				for k, v := range tc.override {
+					body[k] = v
+				}
+				res, raw := do(t, http.MethodPost, "", body)
+				assert.Equal(t, tc.status, res.StatusCode, "%s", raw)
+			})
+		}
+	})
+
+	t.Run("case=updates a provider and keeps omitted secrets", func(t *testing.T) {
+		res, body := do(t, http.MethodPut, "/customer-a", map[string]any{
+			"provider":   "generic",
+			"label":      "Customer A",
+			"client_id":  "customer-a-client",
+			"issuer_url": issuer.URL,
+			"mapper_url": "file://./stub/oidc.hydra.jsonnet",
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, "Customer A", gjson.GetBytes(body, "label").String())
+
+		p, err := s.Provider(t.Context(), "customer-a")
+		require.NoError(t, err)
+		assert.Equal(t, "Customer A", p.Config().Label)
+		assert.Equal(t, "customer-a-secret", p.Config().ClientSecret)
+
+		res, body = do(t, http.MethodGet, "/customer-a", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, "Customer A", gjson.GetBytes(body, "label").String())
+
+		res, body = do(t, http.MethodGet, "", nil)
+		require.Equal(t, http.StatusOK, res.StatusCode, "%s", body)
+		assert.Equal(t, []any{"customer-a"}, gjson.GetBytes(body, "#.id").Value())
+	})
+
+	t.Run("case=deletes a provider", func(t *testing.T) {
+		res, body := do(t, http.MethodDelete, "/customer-a", nil)
+		require.Equal(t, http.StatusNoContent, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodGet, "/customer-a", nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+
+		res, body = do(t, http.MethodDelete, "/customer-a", nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode, "%s", body)
+
+		_, err := s.Provider(t.Context(), "customer-a")
+		require.Error(t, err)
+	})
+
+	t.Run("case=skips providers which can not be decrypted", func(t *testing.T) {
+		require.NoError(t, reg.OIDCProviderPersister().CreateOIDCProvider(t.Context(), &oidc.StoredProvider{
+			ProviderID: "broken",
+			Config:     []byte(`{"id":"broken","provider":"generic","client_id":"broken","client_secret":"not-a-ciphertext","issuer_url":"` + issuer.URL + `","mapper_url":"file://./stub/oidc.hydra.jsonnet"}`),
+		}))
+
+		body := map[string]any{}
+		for k, v := range provider {
+			body[k] = v
+		}
+		body["id"] = "customer-c"
+		res, raw := do(t, http.MethodPost, "", body)
+		require.Equal(t, http.StatusCreated, res.StatusCode, "%s", raw)
+
+		c, err := s.Config(t.Context())
+		require.NoError(t, err)
+		var ids []string
+		for _, p := range c.Providers {
+			ids = append(ids, p.ID)
+		}
+		assert.Equal(t, []string{"configured", "customer-c"}, ids)
+	})
+
+	t.Run("case=rotates stored secrets", func(t *testing.T) {
+		result := new(identity.CipherRotationProgress)
+		require.NoError(t, oidc.NewProviderSecretsCipherRotation(reg).RotateCiphertexts(t.Context(), 100, result, nil))
+		// The secrets of customer-c are already encrypted with the primary
+		// secret, and those of the broken provider can not be decrypted.
+		assert.Equal(t, identity.CipherRotationProgress{Scanned: 2, Failed: 1}, *result)
+	})
+}
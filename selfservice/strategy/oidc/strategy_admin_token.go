@@ -0,0 +1,363 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/oauth2"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/otelx"
+)
+
+const RouteAdminUpstreamToken = "/identities/{id}/credentials/oidc/{provider}/token"
+
+// upstreamTokenExpiryDelta is how long before its expiry an access token is
+// refreshed, so that callers have time to use it.
+const upstreamTokenExpiryDelta = time.Minute
+
+const (
+	// upstreamTokenRefreshAttempts bounds how often a refresh is attempted
+	// if the stored tokens change concurrently.
+	upstreamTokenRefreshAttempts = 3
+	// upstreamTokenRetryWait is how long to wait before reading the stored
+	// tokens again after a failed refresh.
+	upstreamTokenRetryWait = 100 * time.Millisecond
+)
+
+// Upstream Access Token
+//
+// swagger:model identityUpstreamAccessToken
+type UpstreamAccessToken struct {
+	// Provider is the ID of the OpenID Connect provider.
+	//
+	// required: true
+	Provider string `json:"provider"`
+
+	// AccessToken is the access token issued by the provider.
+	//
+	// required: true
+	AccessToken string `json:"access_token"`
+
+	// IDToken is the latest ID token issued by the provider, if any.
+	IDToken string `json:"id_token,omitempty"`
+
+	// ExpiresAt is the expiry of the access token, if known.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Get Upstream Access Token Parameters
+//
+// swagger:parameters getIdentityUpstreamAccessToken
+//
+//nolint:deadcode,unused
+//lint:ignore U1000 Used to generate Swagger and OpenAPI definitions
+type getIdentityUpstreamAccessToken struct {
+	// ID is the identity's ID.
+	//
+	// required: true
+	// in: path
+	ID string `json:"id"`
+
+	// Provider is the ID of the OpenID Connect provider.
+	//
+	// required: true
+	// in: path
+	Provider string `json:"provider"`
+
+	// Refresh forces a refresh of the access token, for example because the
+	// provider rejected an access token without a known expiry.
+	//
+	// in: query
+	Refresh bool `json:"refresh"`
+}
+
+// swagger:route GET /admin/identities/{id}/credentials/oidc/{provider}/token identity getIdentityUpstreamAccessToken
+//
+// # Get a valid upstream access token for an identity
+//
+// Returns a valid access token issued by the OpenID Connect provider the identity signed in with. If the stored access
+// token expired, it is refreshed using the stored refresh token and the rotated tokens are persisted. Access tokens
+// without a known expiry are returned as is; set `refresh=true` once the provider rejects them.
+//
+//	Produces:
+//	- application/json
+//
+//	Schemes: http, https
+//
+//	Security:
+//	  oryAccessToken:
+//
+//	Responses:
+//	  200: identityUpstreamAccessToken
+//	  400: errorGeneric
+//	  404: errorGeneric
+//	  502: errorGeneric
+//	  default: errorGeneric
+//
+//	Extensions:
+//	  x-ory-ratelimit-bucket: kratos-admin-low
+func (s *Strategy) getUpstreamToken(w http.ResponseWriter, r *http.Request) {
+	token, err := s.UpstreamToken(r.Context(), x.ParseUUID(r.PathValue("id")), r.PathValue("provider"), r.URL.Query().Get("refresh") == "true")
+	if err != nil {
+		s.d.Writer().WriteError(w, r, err)
+		return
+	}
+	s.d.Writer().Write(w, r, token)
+}
+
+// UpstreamToken returns a valid access token of the identity's provider,
+// refreshing and persisting the tokens if the stored access token expired or
+// forceRefresh is set.
+//
+// Concurrent refreshes on this instance are deduplicated, so that the refresh
+// token is used once, which providers rotating refresh tokens require. The
+// upstream request runs outside of any transaction; the refreshed tokens are
+// only persisted if the stored tokens did not change in the meantime.
+// Otherwise, for example because another instance refreshed them, the stored
+// tokens are read again and, if still expired, refreshed again.
+func (s *Strategy) UpstreamToken(ctx context.Context, identityID uuid.UUID, providerID string, forceRefresh bool) (_ *UpstreamAccessToken, err error) {
+	ctx, span := s.d.Tracer(ctx).Tracer().Start(ctx, "strategy.oidc.UpstreamToken")
+	defer otelx.End(span, &err)
+	span.SetAttributes(attribute.String("provider", providerID))
+
+	stored, result, _, err := s.loadUpstreamToken(ctx, identityID, providerID, "")
+	if err != nil {
+		return nil, err
+	}
+	if !forceRefresh && !upstreamTokenNeedsRefresh(stored, result) {
+		return result, nil
+	}
+
+	token, err, _ := s.upstreamTokenRefreshes.Do(identityID.String()+"/"+providerID, func() (any, error) {
+		return s.refreshAndStoreUpstreamToken(ctx, identityID, providerID, stored.Subject, result.AccessToken)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token.(*UpstreamAccessToken), nil
+}
+
+// refreshAndStoreUpstreamToken refreshes the tokens of the provider unless the
+// stored access token differs from the seen one and is still valid, and
+// persists them if the stored tokens did not change during the refresh.
+func (s *Strategy) refreshAndStoreUpstreamToken(ctx context.Context, identityID uuid.UUID, providerID, subject, seen string) (*UpstreamAccessToken, error) {
+	var previous *identity.CredentialsOIDCProvider
+	var refreshErr error
+	for attempt := 1; ; attempt++ {
+		stored, current, refreshToken, err := s.loadUpstreamToken(ctx, identityID, providerID, subject)
+		if err != nil {
+			return nil, err
+		}
+		if refreshErr != nil && upstreamTokenUnchanged(previous, stored) {
+			return nil, refreshErr
+		}
+		if current.AccessToken != seen && !upstreamTokenNeedsRefresh(stored, current) {
+			// Another request refreshed the token.
+			return current, nil
+		}
+		if attempt > upstreamTokenRefreshAttempts {
+			return nil, errors.WithStack(herodot.ErrConflict().WithReasonf("The tokens of the OpenID Connect provider %q changed during each of %d refresh attempts.", providerID, upstreamTokenRefreshAttempts))
+		}
+		if refreshToken == "" {
+			return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("The access token of the OpenID Connect provider %q expired and no refresh token is available. The user needs to sign in again.", providerID))
+		}
+		previous, seen = stored, current.AccessToken
+
+		refreshed, err := s.refreshUpstreamToken(ctx, providerID, refreshToken)
+		if err != nil {
+			// The refresh token may have been used by another instance whose
+			// refreshed tokens are not persisted yet.
+			refreshErr = err
+			select {
+			case <-ctx.Done():
+				return nil, errors.WithStack(ctx.Err())
+			case <-time.After(upstreamTokenRetryWait):
+			}
+			continue
+		}
+		refreshErr = nil
+
+		if err := s.storeUpstreamToken(ctx, identityID, providerID, stored, refreshed); errors.Is(err, errUpstreamTokenChanged) {
+			continue
+		} else if err != nil {
+			// The refreshed access token is valid even though it could not be
+			// persisted, but a rotated refresh token is lost.
+			s.d.Logger().WithError(err).
+				WithField("identity_id", identityID).
+				WithField("provider", providerID).
+				Error("Unable to persist the refreshed upstream tokens. The rotated refresh token is lost and the user may need to sign in again.")
+		}
+		return upstreamAccessToken(current, refreshed), nil
+	}
+}
+
+// upstreamTokenUnchanged reports whether the stored tokens are the previously
+// read ones. The ciphertexts are compared, which differ for every encryption.
+func upstreamTokenUnchanged(previous, stored *identity.CredentialsOIDCProvider) bool {
+	return previous.InitialAccessToken == stored.InitialAccessToken && previous.InitialRefreshToken == stored.InitialRefreshToken
+}
+
+// errUpstreamTokenChanged aborts persisting refreshed tokens because the stored
+// tokens changed since they were read.
+var errUpstreamTokenChanged = errors.New("the stored upstream tokens changed")
+
+// storeUpstreamToken persists the refreshed tokens if the stored tokens are
+// still the given ones, and returns errUpstreamTokenChanged otherwise.
+func (s *Strategy) storeUpstreamToken(ctx context.Context, identityID uuid.UUID, providerID string, previous *identity.CredentialsOIDCProvider, refreshed *oauth2.Token) error {
+	et, err := s.encryptOAuth2Tokens(ctx, refreshed)
+	if err != nil {
+		return err
+	}
+
+	return s.d.PrivilegedIdentityPool().UpdateCredentialsConfig(ctx, identityID, s.ID(), identity.UpdateConfig(func(cfg *identity.CredentialsOIDC) error {
+		p := findUpstreamProvider(cfg, providerID, previous.Subject)
+		if p == nil {
+			return errors.WithStack(herodot.ErrNotFound().WithReasonf("The identity is no longer linked to the OpenID Connect provider %q.", providerID))
+		}
+		if !upstreamTokenUnchanged(previous, p) {
+			return errors.WithStack(errUpstreamTokenChanged)
+		}
+
+		p.InitialAccessToken = et.AccessToken
+		p.InitialRefreshToken = et.RefreshToken
+		if et.IDToken != "" {
+			p.InitialIDToken = et.IDToken
+		}
+		p.AccessTokenExpiresAt = et.ExpiresAt
+		return nil
+	}))
+}
+
+// loadUpstreamToken returns the stored tokens of the provider, optionally
+// restricted to the given subject, together with the decrypted access and ID
+// token and the decrypted refresh token.
+func (s *Strategy) loadUpstreamToken(ctx context.Context, identityID uuid.UUID, providerID, subject string) (*identity.CredentialsOIDCProvider, *UpstreamAccessToken, string, error) {
+	i, err := s.d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, identityID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var conf identity.CredentialsOIDC
+	if _, err := i.ParseCredentials(s.ID(), &conf); err != nil {
+		return nil, nil, "", err
+	}
+
+	stored := findUpstreamProvider(&conf, providerID, subject)
+	if stored == nil {
+		return nil, nil, "", errors.WithStack(herodot.ErrNotFound().WithReasonf("The identity is not linked to the OpenID Connect provider %q.", providerID))
+	}
+
+	result, refreshToken, err := s.decryptUpstreamToken(ctx, providerID, stored)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return stored, result, refreshToken, nil
+}
+
+// upstreamAccessToken returns the refreshed token, keeping the previous ID
+// token if the provider did not issue a new one.
+func upstreamAccessToken(previous *UpstreamAccessToken, refreshed *oauth2.Token) *UpstreamAccessToken {
+	result := &UpstreamAccessToken{
+		Provider:    previous.Provider,
+		AccessToken: refreshed.AccessToken,
+		IDToken:     previous.IDToken,
+	}
+	if !refreshed.Expiry.IsZero() {
+		result.ExpiresAt = new(refreshed.Expiry.UTC())
+	}
+	if rawIDToken, ok := refreshed.Extra("id_token").(string); ok && rawIDToken != "" {
+		result.IDToken = rawIDToken
+	}
+	return result
+}
+
+// findUpstreamProvider returns the stored tokens of the provider, optionally
+// restricted to the given subject.
+func findUpstreamProvider(conf *identity.CredentialsOIDC, providerID, subject string) *identity.CredentialsOIDCProvider {
+	for k := range conf.Providers {
+		if conf.Providers[k].Provider == providerID && (subject == "" || conf.Providers[k].Subject == subject) {
+			return &conf.Providers[k]
+		}
+	}
+	return nil
+}
+
+// decryptUpstreamToken returns the stored access and ID token, and the stored
+// refresh token.
+func (s *Strategy) decryptUpstreamToken(ctx context.Context, providerID string, stored *identity.CredentialsOIDCProvider) (*UpstreamAccessToken, string, error) {
+	c := s.d.Cipher(ctx)
+	accessToken, err := c.Decrypt(ctx, stored.InitialAccessToken)
+	if err != nil {
+		return nil, "", err
+	}
+	refreshToken, err := c.Decrypt(ctx, stored.InitialRefreshToken)
+	if err != nil {
+		return nil, "", err
+	}
+	idToken, err := c.Decrypt(ctx, stored.InitialIDToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := &UpstreamAccessToken{
+		Provider:    providerID,
+		AccessToken: string(accessToken),
+		IDToken:     string(idToken),
+	}
+	if !stored.AccessTokenExpiresAt.IsZero() {
+		result.ExpiresAt = new(stored.AccessTokenExpiresAt.UTC())
+	}
+	return result, string(refreshToken), nil
+}
+
+// upstreamTokenNeedsRefresh reports whether the access token is missing or
+// about to expire. Access tokens without a known expiry are used until the
+// provider rejects them; some providers, for example GitHub OAuth apps,
+// issue access tokens which do not expire.
+func upstreamTokenNeedsRefresh(stored *identity.CredentialsOIDCProvider, token *UpstreamAccessToken) bool {
+	if token.AccessToken == "" {
+		return true
+	}
+	if stored.AccessTokenExpiresAt.IsZero() {
+		return false
+	}
+	return time.Now().Add(upstreamTokenExpiryDelta).After(stored.AccessTokenExpiresAt)
+}
+
+func (s *Strategy) refreshUpstreamToken(ctx context.Context, providerID, refreshToken string) (*oauth2.Token, error) {
+	provider, err := s.Provider(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := provider.(OAuth2Provider)
+	if !ok {
+		return nil, errors.WithStack(herodot.ErrBadRequest().WithReasonf("The OpenID Connect provider %q does not support refreshing tokens.", providerID))
+	}
+
+	conf, err := p.OAuth2(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := provider.Config().HTTPClient(ctx, s.d)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, client)
+	token, err := conf.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, errors.WithStack(herodot.ErrUpstreamError().WithWrap(err).WithReasonf("Unable to refresh the access token of the OpenID Connect provider %q.", providerID).WithDebug(err.Error()))
+	}
+	return token, nil
+}
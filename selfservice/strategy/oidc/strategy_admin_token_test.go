@@ -0,0 +1,218 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/kratos/selfservice/strategy/oidc"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/contextx"
+)
+
+func TestUpstreamToken(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+	// concurrentRefresh, if set, runs during a refresh using the racing
+	// refresh token, as if another instance refreshed the tokens meanwhile.
+	var concurrentRefresh func()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"issuer":                 srv.URL,
+				"authorization_endpoint": srv.URL + "/oauth2/auth",
+				"token_endpoint":         srv.URL + "/oauth2/token",
+				"jwks_uri":               srv.URL + "/.well-known/jwks.json",
+			})
+		case "/oauth2/token":
+			require.NoError(t, r.ParseForm())
+			if r.PostForm.Get("refresh_token") == "racing-refresh-token" {
+				concurrentRefresh()
+				r.PostForm.Set("refresh_token", "valid-refresh-token")
+			}
+			if r.PostForm.Get("refresh_token") != "valid-refresh-token" {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": "invalid_grant"})
+				return
+			}
+			assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+			n := refreshes.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  fmt.Sprintf("refreshed-access-token-%d", n),
+				"refresh_token": "valid-refresh-token",
+				"token_type":    "bearer",
+				"expires_in":    3600,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	_, reg := pkg.NewFastRegistryWithMocks(t)
+	baseKey := fmt.Sprintf("%s.%s", config.ViperKeySelfServiceStrategyConfig, identity.CredentialsTypeOIDC)
+	ctx := testhelpers.WithDefaultIdentitySchema(t.Context(), "file://stub/registration.schema.json")
+	ctx = contextx.WithConfigValues(ctx, map[string]any{
+		baseKey + ".enabled": true,
+		baseKey + ".config": &oidc.ConfigurationCollection{Providers: []oidc.Configuration{{
+			ID:           "upstream",
+			Provider:     "generic",
+			ClientID:     "client",
+			ClientSecret: "secret",
+			IssuerURL:    srv.URL,
+			Mapper:       "file://./stub/oidc.hydra.jsonnet",
+		}}},
+	})
+	s := oidc.NewStrategy(reg)
+
+	createIdentity := func(t *testing.T, accessToken, refreshToken string, expiresAt time.Time) *identity.Identity {
+		c := reg.Cipher(ctx)
+		tokens := &identity.CredentialsOIDCEncryptedTokens{ExpiresAt: expiresAt}
+		var err error
+		tokens.AccessToken, err = c.Encrypt(ctx, []byte(accessToken))
+		require.NoError(t, err)
+		tokens.RefreshToken, err = c.Encrypt(ctx, []byte(refreshToken))
+		require.NoError(t, err)
+
+		creds, err := identity.NewCredentialsOIDC(tokens, "upstream", x.NewUUID().String(), "")
+		require.NoError(t, err)
+
+		i := identity.NewIdentity("")
+		i.Traits = identity.Traits(`{"subject":"` + x.NewUUID().String() + `@ory.sh"}`)
+		i.SetCredentials(identity.CredentialsTypeOIDC, *creds)
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+		return i
+	}
+
+	t.Run("case=returns a valid access token as is", func(t *testing.T) {
+		i := createIdentity(t, "stored-access-token", "valid-refresh-token", time.Now().Add(time.Hour))
+		before := refreshes.Load()
+
+		token, err := s.UpstreamToken(ctx, i.ID, "upstream", false)
+		require.NoError(t, err)
+		assert.Equal(t, "stored-access-token", token.AccessToken)
+		require.NotNil(t, token.ExpiresAt)
+		assert.Equal(t, before, refreshes.Load())
+	})
+
+	t.Run("case=refreshes and persists an expired access token", func(t *testing.T) {
+		i := createIdentity(t, "stored-access-token", "valid-refresh-token", time.Now().Add(-time.Minute))
+
+		token, err := s.UpstreamToken(ctx, i.ID, "upstream", false)
+		require.NoError(t, err)
+		assert.Contains(t, token.AccessToken, "refreshed-access-token-")
+		require.NotNil(t, token.ExpiresAt)
+		assert.True(t, token.ExpiresAt.After(time.Now().Add(30*time.Minute)))
+
+		// The refreshed token is persisted and returned without another refresh.
+		before := refreshes.Load()
+		again, err := s.UpstreamToken(ctx, i.ID, "upstream", false)
+		require.NoError(t, err)
+		assert.Equal(t, token.AccessToken, again.AccessToken)
+		assert.Equal(t, before, refreshes.Load())
+	})
+
+	t.Run("case=uses access tokens without a known expiry until a refresh is forced", func(t *testing.T) {
+		i := createIdentity(t, "stored-access-token", "valid-refresh-token", time.Time{})
+		before := refreshes.Load()
+
+		token, err := s.UpstreamToken(ctx, i.ID, "upstream", false)
+		require.NoError(t, err)
+		assert.Equal(t, "stored-access-token", token.AccessToken)
+		assert.Nil(t, token.ExpiresAt)
+		assert.Equal(t, before, refreshes.Load())
+
+		token, err = s.UpstreamToken(ctx, i.ID, "upstream", true)
+		require.NoError(t, err)
+		assert.Contains(t, token.AccessToken, "refreshed-access-token-")
+		assert.Equal(t, before+1, refreshes.Load())
+	})
+
+	t.Run("case=concurrent requests refresh the token once", func(t *testing.T) {
+		i := createIdentity(t, "stored-access-token", "valid-refresh-token", time.Now().Add(-time.Minute))
+		before := refreshes.Load()
+
+		var wg sync.WaitGroup
+		tokens := make([]string, 5)
+		for k := range tokens {
+			wg.Go(func() {
+				token, err := s.UpstreamToken(ctx, i.ID, "upstream", false)
+				if assert.NoError(t, err) {
+					tokens[k] = token.AccessToken
+				}
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, before+1, refreshes.Load())
+		for _, token := range tokens {
+			assert.Equal(t, tokens[0], token)
+		}
+	})
+
+	t.Run("case=does not overwrite tokens refreshed concurrently", func(t *testing.T) {
+		i := createIdentity(t, "stored-access-token", "racing-refresh-token", time.Now().Add(-time.Minute))
+		concurrentRefresh = func() {
+			c := reg.Cipher(ctx)
+			accessToken, err := c.Encrypt(ctx, []byte("concurrent-access-token"))
+			require.NoError(t, err)
+			refreshToken, err := c.Encrypt(ctx, []byte("valid-refresh-token"))
+			require.NoError(t, err)
+			require.NoError(t, reg.PrivilegedIdentityPool().UpdateCredentialsConfig(ctx, i.ID, identity.CredentialsTypeOIDC, identity.UpdateConfig(func(cfg *identity.CredentialsOIDC) error {
+				cfg.Providers[0].InitialAccessToken = accessToken
+				cfg.Providers[0].InitialRefreshToken = refreshToken
+				cfg.Providers[0].AccessTokenExpiresAt = time.Now().Add(time.Hour)
+				return nil
+			})))
+		}
+
+		token, err := s.UpstreamToken(ctx, i.ID, "upstream", false)
+		require.NoError(t, err)
+		assert.Equal(t, "concurrent-access-token", token.AccessToken)
+
+		again, err := s.UpstreamToken(ctx, i.ID, "upstream", false)
+		require.NoError(t, err)
+		assert.Equal(t, "concurrent-access-token", again.AccessToken)
+	})
+
+	t.Run("case=fails if the refresh token was revoked", func(t *testing.T) {
+		i := createIdentity(t, "stored-access-token", "revoked-refresh-token", time.Now().Add(-time.Minute))
+
+		_, err := s.UpstreamToken(ctx, i.ID, "upstream", false)
+		require.ErrorIs(t, err, herodot.ErrUpstreamError())
+	})
+
+	t.Run("case=fails if the token expired and there is no refresh token", func(t *testing.T) {
+		i := createIdentity(t, "stored-access-token", "", time.Now().Add(-time.Minute))
+
+		_, err := s.UpstreamToken(ctx, i.ID, "upstream", false)
+		require.ErrorIs(t, err, herodot.ErrBadRequest())
+	})
+
+	t.Run("case=fails if the identity is not linked to the provider", func(t *testing.T) {
+		i := createIdentity(t, "stored-access-token", "valid-refresh-token", time.Now().Add(time.Hour))
+
+		_, err := s.UpstreamToken(ctx, i.ID, "other", false)
+		require.ErrorIs(t, err, herodot.ErrNotFound())
+	})
+}
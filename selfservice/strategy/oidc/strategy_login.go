@@ -12,6 +12,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -335,6 +336,17 @@ func (s *Strategy) ProcessLogin(ctx context.Context, w http.ResponseWriter, r *h
 	ctx, span := s.d.Tracer(ctx).Tracer().Start(ctx, "selfservice.strategy.oidc.Strategy.processLogin")
 	defer otelx.End(span, &err)
 
+	// Providers which are not bound to an organization must not bypass the
+	// single sign-on of the organization the email address belongs to.
+	if provider.Config().OrganizationID == "" {
+		if err := login.EnforceOrganizationSSO(w, r, s.d, loginFlow, claims.Email); err != nil {
+			if errors.Is(err, flow.ErrCompletedByStrategy) {
+				return nil, err
+			}
+			return nil, s.HandleError(ctx, w, r, loginFlow, provider.Config().ID, nil, err)
+		}
+	}
+
 	i, c, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, s.ID(), identity.OIDCUniqueID(provider.Config().ID, claims.Subject))
 	if err != nil {
 		if errors.Is(err, sqlcon.ErrNoRows()) {
@@ -548,9 +560,17 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		return nil, errors.WithStack(flow.ErrCompletedByStrategy)
 	}
 
+	return nil, s.redirectToProvider(ctx, w, r, f, provider, p.Traits, p.UpstreamParameters)
+}
+
+// redirectToProvider starts the login at the provider's authorization
+// endpoint.
+func (s *Strategy) redirectToProvider(ctx context.Context, w http.ResponseWriter, r *http.Request, f *login.Flow, provider Provider, traits, upstreamParameters json.RawMessage) error {
+	pid := provider.Config().ID
+
 	state, pkce, err := s.GenerateState(ctx, provider, f, x.RequestBaseURL(r))
 	if err != nil {
-		return nil, s.HandleError(ctx, w, r, f, pid, nil, err)
+		return s.HandleError(ctx, w, r, f, pid, nil, err)
 	}
 	var refStore continuity.ContainerReferenceStore
 	if f.Type == flow.TypeAPI {
@@ -563,28 +583,28 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		continuity.WithPayload(&AuthCodeContainer{
 			State:            state,
 			FlowID:           f.ID.String(),
-			Traits:           p.Traits,
+			Traits:           traits,
 			TransientPayload: f.TransientPayload,
 			IdentitySchema:   f.IdentitySchema,
 		}),
 		continuity.WithLifespan(time.Minute*30),
 	); err != nil {
-		return nil, s.HandleError(ctx, w, r, f, pid, nil, err)
+		return s.HandleError(ctx, w, r, f, pid, nil, err)
 	}
 
 	f.Active = s.ID()
 	if err = s.d.LoginFlowPersister().UpdateLoginFlow(ctx, f); err != nil {
-		return nil, s.HandleError(ctx, w, r, f, pid, nil, errors.WithStack(herodot.ErrInternalServerError().WithReason("Could not update flow").WithWrap(err)))
+		return s.HandleError(ctx, w, r, f, pid, nil, errors.WithStack(herodot.ErrInternalServerError().WithReason("Could not update flow").WithWrap(err)))
 	}
 
 	var up map[string]string
-	if err := json.NewDecoder(bytes.NewBuffer(p.UpstreamParameters)).Decode(&up); err != nil {
-		return nil, err
+	if err := json.NewDecoder(bytes.NewBuffer(upstreamParameters)).Decode(&up); err != nil {
+		return err
 	}
 
 	codeURL, err := getAuthRedirectURL(ctx, provider, f, state, up, pkce)
 	if err != nil {
-		return nil, s.HandleError(ctx, w, r, f, pid, nil, err)
+		return s.HandleError(ctx, w, r, f, pid, nil, err)
 	}
 
 	if x.IsJSONRequest(r) {
@@ -593,7 +613,30 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 		http.Redirect(w, r, codeURL, http.StatusSeeOther)
 	}
 
-	return nil, errors.WithStack(flow.ErrCompletedByStrategy)
+	return errors.WithStack(flow.ErrCompletedByStrategy)
+}
+
+var _ login.OrganizationSSOStarter = new(Strategy)
+
+// StartOrganizationSSO redirects the login flow to a provider bound to the
+// organization the identifier belongs to.
+func (s *Strategy) StartOrganizationSSO(w http.ResponseWriter, r *http.Request, f *login.Flow, providerID string) error {
+	ctx := r.Context()
+
+	c, err := s.Config(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(c.Providers, func(p Configuration) bool { return p.ID == providerID }) {
+		return errors.WithStack(flow.ErrStrategyNotResponsible)
+	}
+
+	provider, err := s.Provider(ctx, providerID)
+	if err != nil {
+		return s.HandleError(ctx, w, r, f, providerID, nil, err)
+	}
+
+	return s.redirectToProvider(ctx, w, r, f, provider, nil, json.RawMessage("{}"))
 }
 
 func (s *Strategy) PopulateLoginMethodFirstFactorRefresh(r *http.Request, lf *login.Flow, _ *session.Session) error {
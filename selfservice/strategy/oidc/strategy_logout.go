@@ -0,0 +1,103 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/selfservice/flow/logout"
+	"github.com/ory/kratos/session"
+	"github.com/ory/x/otelx"
+)
+
+var _ logout.UpstreamLogouter = new(Strategy)
+
+// endSessionEndpointProvider is implemented by providers which support
+// OpenID Connect RP-Initiated Logout.
+type endSessionEndpointProvider interface {
+	endSessionEndpoint(ctx context.Context) (string, error)
+}
+
+// UpstreamLogoutURL returns the `end_session_endpoint` URL of the provider the
+// session was authenticated with, if the provider has upstream logout enabled.
+func (s *Strategy) UpstreamLogoutURL(ctx context.Context, sess *session.Session, returnTo *url.URL) (_ *url.URL, err error) {
+	var providerID string
+	for _, m := range sess.AMR {
+		if m.Method == s.ID() && m.Provider != "" {
+			providerID = m.Provider
+		}
+	}
+	if providerID == "" {
+		return nil, nil
+	}
+
+	ctx, span := s.d.Tracer(ctx).Tracer().Start(ctx, "strategy.oidc.UpstreamLogoutURL")
+	defer otelx.End(span, &err)
+	span.SetAttributes(attribute.String("provider", providerID))
+
+	provider, err := s.Provider(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	if !provider.Config().UpstreamLogout {
+		return nil, nil
+	}
+
+	ep, ok := provider.(endSessionEndpointProvider)
+	if !ok {
+		return nil, nil
+	}
+	endpoint, err := ep.endSessionEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	} else if endpoint == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("client_id", provider.Config().ClientID)
+	q.Set("post_logout_redirect_uri", returnTo.String())
+	if idToken, err := s.upstreamIDToken(ctx, sess, providerID); err != nil {
+		return nil, err
+	} else if idToken != "" {
+		q.Set("id_token_hint", idToken)
+	}
+	u.RawQuery = q.Encode()
+
+	return u, nil
+}
+
+// upstreamIDToken returns the decrypted ID token the provider issued when the
+// identity signed in, if one was stored.
+func (s *Strategy) upstreamIDToken(ctx context.Context, sess *session.Session, providerID string) (string, error) {
+	i, err := s.d.PrivilegedIdentityPool().GetIdentityConfidential(ctx, sess.IdentityID)
+	if err != nil {
+		return "", err
+	}
+
+	var conf identity.CredentialsOIDC
+	if _, err := i.ParseCredentials(s.ID(), &conf); err != nil {
+		return "", err
+	}
+
+	for _, p := range conf.Providers {
+		if p.Provider == providerID && p.InitialIDToken != "" {
+			idToken, err := s.d.Cipher(ctx).Decrypt(ctx, p.InitialIDToken)
+			if err != nil {
+				return "", err
+			}
+			return string(idToken), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,119 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/kratos/selfservice/strategy/oidc"
+	"github.com/ory/kratos/session"
+	"github.com/ory/kratos/x"
+	"github.com/ory/x/contextx"
+)
+
+func TestUpstreamLogoutURL(t *testing.T) {
+	t.Parallel()
+
+	newIssuer := func(t *testing.T, endSessionEndpoint bool) *httptest.Server {
+		var srv *httptest.Server
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/.well-known/openid-configuration" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			discovery := map[string]any{
+				"issuer":                 srv.URL,
+				"authorization_endpoint": srv.URL + "/oauth2/auth",
+				"token_endpoint":         srv.URL + "/oauth2/token",
+				"jwks_uri":               srv.URL + "/.well-known/jwks.json",
+			}
+			if endSessionEndpoint {
+				discovery["end_session_endpoint"] = srv.URL + "/oauth2/sessions/logout?tenant=a"
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(discovery)
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	withLogout, withoutLogout := newIssuer(t, true), newIssuer(t, false)
+
+	_, reg := pkg.NewFastRegistryWithMocks(t)
+	baseKey := fmt.Sprintf("%s.%s", config.ViperKeySelfServiceStrategyConfig, identity.CredentialsTypeOIDC)
+	ctx := testhelpers.WithDefaultIdentitySchema(t.Context(), "file://stub/registration.schema.json")
+	ctx = contextx.WithConfigValues(ctx, map[string]any{
+		baseKey + ".enabled": true,
+		baseKey + ".config": &oidc.ConfigurationCollection{Providers: []oidc.Configuration{
+			{ID: "enabled", Provider: "generic", ClientID: "client", ClientSecret: "secret", IssuerURL: withLogout.URL, Mapper: "file://./stub/oidc.hydra.jsonnet", UpstreamLogout: true},
+			{ID: "disabled", Provider: "generic", ClientID: "client", ClientSecret: "secret", IssuerURL: withLogout.URL, Mapper: "file://./stub/oidc.hydra.jsonnet"},
+			{ID: "unsupported", Provider: "generic", ClientID: "client", ClientSecret: "secret", IssuerURL: withoutLogout.URL, Mapper: "file://./stub/oidc.hydra.jsonnet", UpstreamLogout: true},
+		}},
+	})
+	s := oidc.NewStrategy(reg)
+	returnTo, err := url.Parse("https://www.ory.sh/logged-out")
+	require.NoError(t, err)
+
+	newSession := func(t *testing.T, provider, idToken string) *session.Session {
+		tokens := &identity.CredentialsOIDCEncryptedTokens{}
+		if idToken != "" {
+			encrypted, err := reg.Cipher(ctx).Encrypt(ctx, []byte(idToken))
+			require.NoError(t, err)
+			tokens.IDToken = encrypted
+		}
+		creds, err := identity.NewCredentialsOIDC(tokens, provider, x.NewUUID().String(), "")
+		require.NoError(t, err)
+
+		i := identity.NewIdentity("")
+		i.Traits = identity.Traits(`{"subject":"` + x.NewUUID().String() + `@ory.sh"}`)
+		i.SetCredentials(identity.CredentialsTypeOIDC, *creds)
+		require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(ctx, i))
+
+		sess := &session.Session{IdentityID: i.ID}
+		sess.CompletedLoginForWithProvider(identity.CredentialsTypeOIDC, identity.AuthenticatorAssuranceLevel1, provider, "")
+		return sess
+	}
+
+	t.Run("case=redirects through the end session endpoint", func(t *testing.T) {
+		u, err := s.UpstreamLogoutURL(ctx, newSession(t, "enabled", "id-token"), returnTo)
+		require.NoError(t, err)
+		require.NotNil(t, u)
+
+		assert.Equal(t, withLogout.URL+"/oauth2/sessions/logout", u.Scheme+"://"+u.Host+u.Path)
+		assert.Equal(t, "a", u.Query().Get("tenant"))
+		assert.Equal(t, "id-token", u.Query().Get("id_token_hint"))
+		assert.Equal(t, "client", u.Query().Get("client_id"))
+		assert.Equal(t, returnTo.String(), u.Query().Get("post_logout_redirect_uri"))
+	})
+
+	t.Run("case=omits the ID token hint if no ID token was stored", func(t *testing.T) {
+		u, err := s.UpstreamLogoutURL(ctx, newSession(t, "enabled", ""), returnTo)
+		require.NoError(t, err)
+		require.NotNil(t, u)
+		assert.False(t, u.Query().Has("id_token_hint"))
+	})
+
+	for name, sess := range map[string]*session.Session{
+		"upstream logout is disabled":           newSession(t, "disabled", "id-token"),
+		"provider has no logout endpoint":       newSession(t, "unsupported", "id-token"),
+		"session was not authenticated by oidc": {AMR: session.AuthenticationMethods{{Method: identity.CredentialsTypePassword}}},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			u, err := s.UpstreamLogoutURL(ctx, sess, returnTo)
+			require.NoError(t, err)
+			assert.Nil(t, u)
+		})
+	}
+}
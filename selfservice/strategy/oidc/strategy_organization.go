@@ -0,0 +1,80 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ory/herodot"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
+	"github.com/ory/x/sqlcon"
+)
+
+var _ organization.ProviderBinder = new(Strategy)
+
+// OrganizationProviders returns the IDs of the providers bound to the
+// organization, including the ones defined in the configuration file.
+func (s *Strategy) OrganizationProviders(ctx context.Context, organizationID uuid.UUID) ([]string, error) {
+	if s.ID() != identity.CredentialsTypeOIDC {
+		return nil, nil
+	}
+
+	c, err := s.Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []string
+	for _, p := range c.Providers {
+		if p.OrganizationID == organizationID.String() {
+			providers = append(providers, p.ID)
+		}
+	}
+	return providers, nil
+}
+
+// BindOrganizationProvider binds a provider managed through the admin API to
+// the organization. Providers defined in the configuration file can not be
+// bound.
+func (s *Strategy) BindOrganizationProvider(ctx context.Context, providerID string, organizationID uuid.NullUUID) error {
+	if s.ID() != identity.CredentialsTypeOIDC {
+		return errors.WithStack(sqlcon.ErrNoRows())
+	}
+
+	var configured ConfigurationCollection
+	if err := json.Unmarshal(s.d.Config().SelfServiceStrategy(ctx, string(s.ID())).Config, &configured); err != nil {
+		return errors.WithStack(herodot.ErrMisconfiguration().WithReasonf("Unable to decode OpenID Connect Provider configuration: %s", err))
+	}
+	if slices.ContainsFunc(configured.Providers, func(p Configuration) bool { return p.ID == providerID }) {
+		return errors.WithStack(herodot.ErrConflict().WithReasonf("The provider %q is defined in the configuration file and can not be bound to organizations through the admin API.", providerID))
+	}
+
+	sp, err := s.d.OIDCProviderPersister().GetOIDCProvider(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	c, err := s.decodeStoredProvider(ctx, sp)
+	if err != nil {
+		return err
+	}
+
+	c.OrganizationID = ""
+	if organizationID.Valid {
+		c.OrganizationID = organizationID.UUID.String()
+	}
+	if sp.Config, err = s.encodeStoredProvider(ctx, *c); err != nil {
+		return err
+	}
+	if err := s.d.OIDCProviderPersister().UpdateOIDCProvider(ctx, sp); err != nil {
+		return err
+	}
+	s.invalidateRuntimeProviders(ctx)
+	return nil
+}
@@ -357,6 +357,14 @@ func (s *Strategy) processRegistration(ctx context.Context, w http.ResponseWrite
 		return nil, s.HandleError(ctx, w, r, rf, provider.Config().ID, nil, err)
 	}
 
+	// Only the traits submitted by the user may not set admin-only traits,
+	// the Jsonnet mapper may set them.
+	if container != nil && len(container.Traits) > 0 {
+		if err := s.d.RegistrationExecutor().CheckAdminOnlyTraits(ctx, i.SchemaID, container.Traits); err != nil {
+			return nil, s.HandleError(ctx, w, r, rf, provider.Config().ID, i.Traits, err)
+		}
+	}
+
 	// Validate the identity itself
 	if err := s.d.IdentityValidator().Validate(ctx, i); err != nil {
 		return nil, s.HandleError(ctx, w, r, rf, provider.Config().ID, i.Traits, err)
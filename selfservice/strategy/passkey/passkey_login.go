@@ -215,10 +215,10 @@ func (s *Strategy) loginPasswordless(ctx context.Context, w http.ResponseWriter,
 		return nil, errors.WithStack(flow.ErrCompletedByStrategy)
 	}
 
-	return s.loginAuthenticate(ctx, r, f, p, identity.AuthenticatorAssuranceLevel1)
+	return s.loginAuthenticate(ctx, w, r, f, p, identity.AuthenticatorAssuranceLevel1)
 }
 
-func (s *Strategy) loginAuthenticate(ctx context.Context, r *http.Request, f *login.Flow, p *updateLoginFlowWithPasskeyMethod, _ identity.AuthenticatorAssuranceLevel) (*identity.Identity, error) {
+func (s *Strategy) loginAuthenticate(ctx context.Context, w http.ResponseWriter, r *http.Request, f *login.Flow, p *updateLoginFlowWithPasskeyMethod, _ identity.AuthenticatorAssuranceLevel) (*identity.Identity, error) {
 	web, err := webauthn.New(s.d.Config().PasskeyConfig(ctx))
 	if err != nil {
 		return nil, s.handleLoginError(r, f, errors.WithStack(herodot.ErrInternalServerError().WithReasonf("Unable to get webAuthn config.").WithDebug(err.Error())))
@@ -247,7 +247,7 @@ func (s *Strategy) loginAuthenticate(ctx context.Context, r *http.Request, f *lo
 		}
 		credentialType = identity.CredentialsTypeWebAuthn
 	}
-	err = s.d.PrivilegedIdentityPool().HydrateIdentityAssociations(ctx, i, identity.ExpandCredentials)
+	err = s.d.PrivilegedIdentityPool().HydrateIdentityAssociations(ctx, i, identity.Expandables{identity.ExpandFieldCredentials, identity.ExpandFieldVerifiableAddresses})
 	if err != nil {
 		return nil, s.handleLoginError(r, f, x.WrapWithIdentityIDError(errors.WithStack(herodot.ErrInternalServerError().
 			WithReason("Could not load identity credentials").
@@ -276,6 +276,15 @@ func (s *Strategy) loginAuthenticate(ctx context.Context, r *http.Request, f *lo
 		return nil, s.handleLoginError(r, f, x.WrapWithIdentityIDError(errors.WithStack(schema.NewWebAuthnVerifierWrongError("#/")), i.ID))
 	}
 
+	// Passkeys identify the identity without an identifier, so its email
+	// addresses decide whether it has to sign in with single sign-on.
+	if err := login.EnforceOrganizationSSOForIdentity(w, r, s.d, f, i); err != nil {
+		if errors.Is(err, flow.ErrCompletedByStrategy) {
+			return nil, err
+		}
+		return nil, s.handleLoginError(r, f, x.WrapWithIdentityIDError(err, i.ID))
+	}
+
 	// Persist the updated signature counter and clone warning for W3C WebAuthn
 	// clone detection. This must not block the login: the user already proved
 	// possession of the authenticator. Use credentialType, which may be webauthn
@@ -75,6 +75,13 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 	}
 
 	identifier := cmp.Or(p.Identifier, p.LegacyIdentifier)
+	if err := login.EnforceOrganizationSSO(w, r, s.d, f, identifier); err != nil {
+		if errors.Is(err, flow.ErrCompletedByStrategy) {
+			return nil, err
+		}
+		return nil, s.handleLoginError(r, f, p, err)
+	}
+
 	i, c, err := s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, s.ID(), identifier)
 	if err != nil {
 		time.Sleep(x.RandomDelay(s.d.Config().HasherArgon2(ctx).ExpectedDuration, s.d.Config().HasherArgon2(ctx).ExpectedDeviation))
@@ -107,17 +114,26 @@ func (s *Strategy) Login(w http.ResponseWriter, r *http.Request, f *login.Flow,
 			return nil, s.handleLoginError(r, f, p, x.WrapWithIdentityIDError(err, i.ID))
 		}
 	} else {
-		if err := hash.Compare(ctx, []byte(p.Password), []byte(o.HashedPassword)); err != nil {
+		if err := hash.CompareWithPepper(ctx, s.d, []byte(p.Password), []byte(o.HashedPassword)); err != nil {
 			return nil, s.handleLoginError(r, f, p, errors.WithStack(x.WrapWithIdentityIDError(schema.NewInvalidCredentialsError(), i.ID)))
 		}
 
-		if !s.d.Hasher(ctx).Understands([]byte(o.HashedPassword)) {
+		if !s.d.Hasher(ctx).Understands([]byte(o.HashedPassword)) || hash.NeedsRepeppering(ctx, s.d, []byte(o.HashedPassword)) {
 			if err := s.migratePasswordHash(ctx, i.ID, []byte(p.Password)); err != nil {
 				s.d.Logger().Warnf("Unable to migrate password hash for identity %s: %s Keeping existing password hash and continuing.", i.ID, x.WrapWithIdentityIDError(err, i.ID))
 			}
 		}
 	}
 
+	// The identifier may be a username or phone number, so the identity's
+	// email addresses decide whether it has to sign in with single sign-on.
+	if err := login.EnforceOrganizationSSOForIdentity(w, r, s.d, f, i); err != nil {
+		if errors.Is(err, flow.ErrCompletedByStrategy) {
+			return nil, err
+		}
+		return nil, s.handleLoginError(r, f, p, x.WrapWithIdentityIDError(err, i.ID))
+	}
+
 	f.Active = s.ID()
 	if err = s.d.LoginFlowPersister().UpdateLoginFlow(ctx, f); err != nil {
 		return nil, s.handleLoginError(r, f, p, errors.WithStack(x.WrapWithIdentityIDError(herodot.ErrInternalServerError().WithReason("Could not update flow").WithDebug(err.Error()), i.ID)))
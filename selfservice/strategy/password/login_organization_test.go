@@ -0,0 +1,104 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package password_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/kratos/text"
+	"github.com/ory/x/configx"
+	"github.com/ory/x/httprouterx"
+	"github.com/ory/x/sqlxx"
+)
+
+func TestLoginEnforcesOrganizationSSO(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.Must(uuid.NewV4())
+	conf, reg := pkg.NewFastRegistryWithMocks(t,
+		configx.WithValues(testhelpers.DefaultIdentitySchemaConfig("file://./stub/username.schema.json")),
+		configx.WithValues(testhelpers.MethodEnableConfig(identity.CredentialsTypePassword, true)),
+		configx.WithValues(testhelpers.MethodEnableConfig(identity.CredentialsTypeOIDC, true)),
+		configx.WithValue(config.ViperKeySelfServiceStrategyConfig+"."+string(identity.CredentialsTypeOIDC)+".config.providers",
+			[]map[string]any{{
+				"provider":        "github",
+				"id":              "acme-sso",
+				"client_id":       "client",
+				"client_secret":   "secret",
+				"mapper_url":      "file://../oidc/stub/oidc.facebook.jsonnet",
+				"organization_id": orgID.String(),
+			}},
+		),
+		configx.WithValue(config.ViperKeyOrganizations, []map[string]any{{
+			"id":      orgID.String(),
+			"label":   "Acme",
+			"domains": []string{"acme.example"},
+		}}),
+	)
+	publicTS, _ := testhelpers.NewKratosServerWithRouters(t, reg, httprouterx.NewRouterPublic(), httprouterx.NewRouterAdminWithPrefix())
+	apiClient := testhelpers.NewDebugClient(t)
+
+	login := func(t *testing.T, identifier string) (string, *http.Response) {
+		f := testhelpers.InitializeLoginFlowViaAPICtx(t.Context(), t, apiClient, publicTS, false)
+		return testhelpers.LoginMakeRequest(t, true, false, f, apiClient,
+			`{"method":"password","identifier":"`+identifier+`","password":"not-the-password"}`)
+	}
+
+	t.Run("case=allows password login if enforcement is disabled", func(t *testing.T) {
+		body, res := login(t, "user@acme.example")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+		assert.Equal(t, text.NewErrorValidationInvalidCredentials().Text, gjson.Get(body, "ui.messages.0.text").String(), body)
+	})
+
+	t.Run("case=redirects to the organization provider", func(t *testing.T) {
+		conf.MustSet(t.Context(), config.ViperKeyOrganizationsEnforceSSO, true)
+		t.Cleanup(func() { conf.MustSet(t.Context(), config.ViperKeyOrganizationsEnforceSSO, false) })
+
+		body, res := login(t, "User@Acme.Example")
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode, body)
+		assert.Equal(t, "browser_location_change_required", gjson.Get(body, "error.id").String(), body)
+		assert.Contains(t, gjson.Get(body, "redirect_browser_to").String(), "https://github.com/login/oauth/authorize", body)
+
+		t.Run("case=redirects usernames of organization identities", func(t *testing.T) {
+			hashed, err := reg.Hasher(t.Context()).Generate(t.Context(), []byte("the-password"))
+			require.NoError(t, err)
+			i := identity.NewIdentity(config.DefaultIdentityTraitsSchemaID)
+			i.Traits = identity.Traits(`{"username":"acme-user","email":"user@acme.example"}`)
+			i.SetCredentials(identity.CredentialsTypePassword, identity.Credentials{
+				Identifiers: []string{"acme-user"},
+				Config:      sqlxx.JSONRawMessage(`{"hashed_password":"` + string(hashed) + `"}`),
+			})
+			i.VerifiableAddresses = []identity.VerifiableAddress{{Via: identity.AddressTypeEmail, Value: "user@acme.example", Status: identity.VerifiableAddressStatusPending}}
+			require.NoError(t, reg.PrivilegedIdentityPool().CreateIdentity(t.Context(), i))
+
+			// A wrong password does not reveal that the account is managed.
+			body, res := login(t, "acme-user")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+			assert.Equal(t, text.NewErrorValidationInvalidCredentials().Text, gjson.Get(body, "ui.messages.0.text").String(), body)
+
+			f := testhelpers.InitializeLoginFlowViaAPICtx(t.Context(), t, apiClient, publicTS, false)
+			body, res = testhelpers.LoginMakeRequest(t, true, false, f, apiClient,
+				`{"method":"password","identifier":"acme-user","password":"the-password"}`)
+			assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode, body)
+			assert.Equal(t, "browser_location_change_required", gjson.Get(body, "error.id").String(), body)
+			assert.Contains(t, gjson.Get(body, "redirect_browser_to").String(), "https://github.com/login/oauth/authorize", body)
+		})
+
+		t.Run("case=ignores identifiers of other domains", func(t *testing.T) {
+			body, res := login(t, "user@other.example")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+			assert.Equal(t, text.NewErrorValidationInvalidCredentials().Text, gjson.Get(body, "ui.messages.0.text").String(), body)
+		})
+	})
+}
@@ -220,6 +220,11 @@ func (s *Strategy) PopulateRegistrationMethod(r *http.Request, f *registration.F
 		if err != nil {
 			return err
 		}
+		access, err := schema.NewTraitAccess(r.Context(), ds.String(), s.d.Config().SecurityDisallowRefInIdentitySchemas(r.Context()))
+		if err != nil {
+			return err
+		}
+		container.DisableTraitNodes(nodes, access.IsWritableInRegistration)
 
 		for _, n := range nodes {
 			f.UI.SetNode(n)
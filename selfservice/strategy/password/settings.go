@@ -140,13 +140,13 @@ func getPasswordHashFromCredential(creds map[identity.CredentialsType]identity.C
 // This is helpful to a user, e.g. in the case of a password leak: they want to change their password,
 // and unknowingly set the new password to be the same as the old one (that leaked). We force them to
 // set a different password in that case.
-func isNewPasswordSameAsOld(ctx context.Context, oldHashedPassword string, newPassword string) bool {
+func isNewPasswordSameAsOld(ctx context.Context, c hash.PepperConfiguration, oldHashedPassword string, newPassword string) bool {
 	if oldHashedPassword == "" {
 		return false
 	}
 
-	// `hash.Compare` returns `nil` on 'success' i.e. old and new are the same.
-	return hash.Compare(ctx, []byte(newPassword), []byte(oldHashedPassword)) == nil
+	// `hash.CompareWithPepper` returns `nil` on 'success' i.e. old and new are the same.
+	return hash.CompareWithPepper(ctx, c, []byte(newPassword), []byte(oldHashedPassword)) == nil
 }
 
 func (s *Strategy) continueSettingsFlow(ctx context.Context, r *http.Request, ctxUpdate *settings.UpdateContext, p updateSettingsFlowWithPasswordMethod) error {
@@ -187,7 +187,7 @@ func (s *Strategy) continueSettingsFlow(ctx context.Context, r *http.Request, ct
 		return err
 	})
 	g.Go(func() error {
-		if isNewPasswordSameAsOld(ctx, oldHashedPassword, p.Password) {
+		if isNewPasswordSameAsOld(ctx, s.d, oldHashedPassword, p.Password) {
 			return schema.NewPasswordPolicyViolationError("#/password", text.NewErrorValidationPasswordNewSameAsOld())
 		}
 		return nil
@@ -22,6 +22,7 @@ import (
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hash"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/registration"
@@ -64,6 +65,7 @@ type dependencies interface {
 	login.HookExecutorProvider
 	login.FlowPersistenceProvider
 	login.HandlerProvider
+	login.OrganizationSSOStarterProvider
 
 	settings.FlowPersistenceProvider
 	settings.HookExecutorProvider
@@ -76,6 +78,8 @@ type dependencies interface {
 
 	session.HandlerProvider
 	session.ManagementProvider
+
+	organization.ManagementProvider
 }
 
 type Strategy struct{ d dependencies }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"slices"
 
 	"github.com/ory/kratos/x/nosurfx"
 	"github.com/ory/x/httpx"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 
 	"github.com/ory/herodot"
@@ -94,6 +96,10 @@ func (s *Strategy) PopulateSettingsMethod(ctx context.Context, r *http.Request,
 	if err != nil {
 		return err
 	}
+	traitsSchema, err = traitsSchema.AtVersion(id.SchemaVersion)
+	if err != nil {
+		return err
+	}
 
 	// Use a schema compiler that disables identifiers. NewCompilerWithURL
 	// pre-registers the top-level schema via the trusted global loader; the
@@ -109,6 +115,12 @@ func (s *Strategy) PopulateSettingsMethod(ctx context.Context, r *http.Request,
 		return err
 	}
 
+	access, err := schema.NewTraitAccess(ctx, traitsSchema.URL.String(), disallowRefs)
+	if err != nil {
+		return err
+	}
+	container.DisableTraitNodes(nodes, access.IsWritableInSettings)
+
 	for _, n := range nodes {
 		f.UI.SetNode(n)
 	}
@@ -141,6 +153,12 @@ func (s *Strategy) Settings(ctx context.Context, w http.ResponseWriter, r *http.
 		return ctxUpdate, s.handleSettingsError(ctx, w, r, ctxUpdate, nil, p, err)
 	}
 
+	access, err := s.traitAccess(ctx, ctxUpdate.GetSessionIdentity())
+	if err != nil {
+		return ctxUpdate, s.handleSettingsError(ctx, w, r, ctxUpdate, nil, p, err)
+	}
+	fillProtectedTraitFormValues(r, access, ctxUpdate.GetSessionIdentity().Traits)
+
 	if err := decoderx.Decode(r, &p, option,
 		decoderx.HTTPDecoderAllowedMethods("POST", "GET"),
 		decoderx.HTTPDecoderSetValidatePayloads(true),
@@ -176,12 +194,21 @@ func (s *Strategy) continueFlow(ctx context.Context, r *http.Request, ctxUpdate
 		return err
 	}
 
+	access, err := s.traitAccess(ctx, ctxUpdate.GetSessionIdentity())
+	if err != nil {
+		return err
+	}
+	traits, err := access.CheckSettings(json.RawMessage(ctxUpdate.GetSessionIdentity().Traits), p.Traits)
+	if err != nil {
+		return err
+	}
+
 	options := []identity.ManagerOption{identity.ManagerExposeValidationErrorsForInternalTypeAssertion}
 	if s.d.SessionManager().IsPrivileged(ctx, ctxUpdate.Session) {
 		options = append(options, identity.ManagerAllowWriteProtectedTraits)
 	}
 
-	update, err := s.d.IdentityManager().SetTraits(ctx, ctxUpdate.GetSessionIdentity().ID, identity.Traits(p.Traits), options...)
+	update, err := s.d.IdentityManager().SetTraits(ctx, ctxUpdate.GetSessionIdentity().ID, identity.Traits(traits), options...)
 	if err != nil {
 		if errors.Is(err, identity.ErrProtectedFieldModified()) {
 			return settings.NewFlowNeedsReAuth()
@@ -193,6 +220,45 @@ func (s *Strategy) continueFlow(ctx context.Context, r *http.Request, ctxUpdate
 	return nil
 }
 
+// traitAccess returns the traits of the identity's schema which can not be
+// changed in the settings flow.
+func (s *Strategy) traitAccess(ctx context.Context, i *identity.Identity) (*schema.TraitAccess, error) {
+	schemas, err := s.d.IdentityTraitsSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := schemas.GetByID(i.SchemaID)
+	if err != nil {
+		return nil, err
+	}
+	ss, err = ss.AtVersion(i.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.NewTraitAccess(ctx, ss.URL.String(), s.d.Config().SecurityDisallowRefInIdentitySchemas(ctx))
+}
+
+// fillProtectedTraitFormValues adds the current values of traits which can not
+// be changed to form submissions. Browsers do not submit disabled form fields,
+// which would otherwise fail the validation of required traits.
+func fillProtectedTraitFormValues(r *http.Request, access *schema.TraitAccess, traits identity.Traits) {
+	if !httpx.HasContentType(r, "application/x-www-form-urlencoded") || r.ParseForm() != nil {
+		return
+	}
+
+	for _, path := range slices.Concat(access.AdminOnly, access.ReadOnlyAfterRegistration) {
+		key := "traits." + path
+		if r.PostForm.Has(key) {
+			continue
+		}
+		switch v := gjson.GetBytes(traits, path); v.Type {
+		case gjson.String, gjson.Number, gjson.True, gjson.False:
+			r.PostForm.Set(key, v.String())
+		}
+	}
+}
+
 // Update Settings Flow with Profile Method
 //
 // swagger:model updateSettingsFlowWithProfileMethod
@@ -288,6 +354,10 @@ func (s *Strategy) newSettingsProfileDecoder(ctx context.Context, i *identity.Id
 	if err != nil {
 		return nil, err
 	}
+	ss, err = ss.AtVersion(i.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
 	raw, err := sjson.SetBytes(settingsSchema,
 		"properties.traits.$ref", ss.URL.String()+"#/properties/traits")
 	if err != nil {
@@ -0,0 +1,100 @@
+// Copyright © 2026 Ory Corp
+// SPDX-License-Identifier: Apache-2.0
+
+package profile_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/kratos/driver/config"
+	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/pkg"
+	"github.com/ory/kratos/pkg/testhelpers"
+	"github.com/ory/kratos/selfservice/flow/settings"
+	"github.com/ory/kratos/text"
+	"github.com/ory/kratos/x"
+	"github.com/ory/kratos/x/nosurfx"
+)
+
+func TestStrategyTraitAccess(t *testing.T) {
+	ctx := t.Context()
+	conf, reg := pkg.NewFastRegistryWithMocks(t)
+	testhelpers.SetDefaultIdentitySchema(conf, "file://./stub/trait-access.schema.json")
+	conf.MustSet(ctx, config.ViperKeySelfServiceBrowserDefaultReturnTo, "https://www.ory.com/")
+	conf.MustSet(ctx, config.ViperKeySelfServiceSettingsPrivilegedAuthenticationAfter, "10m")
+	testhelpers.StrategyEnable(t, conf, settings.StrategyProfile, true)
+
+	_ = testhelpers.NewSettingsUIEchoServer(t, reg)
+	_ = testhelpers.NewErrorTestServer(t, reg)
+	publicTS, _ := testhelpers.NewKratosServer(t, reg)
+
+	email := testhelpers.RandomEmail()
+	i := &identity.Identity{
+		ID:       x.NewUUID(),
+		SchemaID: config.DefaultIdentityTraitsSchemaID,
+		State:    identity.StateActive,
+		Traits:   identity.Traits(`{"email":"` + email + `","employee_id":"E-1","role":"admin","nickname":"jd"}`),
+		Credentials: map[identity.CredentialsType]identity.Credentials{
+			identity.CredentialsTypePassword: {Type: "password", Identifiers: []string{email}, Config: []byte(`{"hashed_password":"$2a$04$zvZz1zV"}`)},
+		},
+	}
+	browserUser := testhelpers.NewHTTPClientWithIdentitySessionCookie(ctx, t, reg, i)
+	browserUser.Jar.SetCookies(nosurfx.WithFakeCSRFCookie(t, reg, publicTS.URL))
+	apiUser := testhelpers.NewHTTPClientWithIdentitySessionToken(ctx, t, reg, i)
+
+	getTraits := func(t *testing.T) gjson.Result {
+		actual, err := reg.PrivilegedIdentityPool().GetIdentity(ctx, i.ID, identity.ExpandNothing)
+		require.NoError(t, err)
+		return gjson.ParseBytes(actual.Traits)
+	}
+
+	t.Run("case=renders protected traits as disabled", func(t *testing.T) {
+		f := testhelpers.InitializeSettingsFlowViaBrowser(t, browserUser, true, publicTS)
+		raw, err := json.Marshal(f)
+		require.NoError(t, err)
+		for name, disabled := range map[string]bool{
+			"traits.email":       false,
+			"traits.nickname":    false,
+			"traits.employee_id": true,
+			"traits.role":        true,
+		} {
+			actual := gjson.GetBytes(raw, "ui.nodes.#(attributes.name=="+name+").attributes.disabled")
+			assert.Equal(t, disabled, actual.Bool(), "%s: %s", name, raw)
+		}
+	})
+
+	t.Run("case=keeps protected traits which were not submitted", func(t *testing.T) {
+		actual := testhelpers.SubmitSettingsForm(t, false, false, browserUser, publicTS, func(v url.Values) {
+			v.Set("method", settings.StrategyProfile)
+			v.Del("traits.employee_id")
+			v.Del("traits.role")
+			v.Set("traits.nickname", "johnny")
+		}, http.StatusOK, conf.SelfServiceFlowSettingsUI(ctx).String())
+		assert.Equal(t, "success", gjson.Get(actual, "state").String(), actual)
+
+		traits := getTraits(t)
+		assert.Equal(t, "johnny", traits.Get("nickname").String(), traits.Raw)
+		assert.Equal(t, "E-1", traits.Get("employee_id").String(), traits.Raw)
+		assert.Equal(t, "admin", traits.Get("role").String(), traits.Raw)
+	})
+
+	for _, trait := range []string{"employee_id", "role"} {
+		t.Run("case=rejects changes to "+trait, func(t *testing.T) {
+			actual := testhelpers.SubmitSettingsForm(t, true, false, apiUser, publicTS, func(v url.Values) {
+				v.Set("method", settings.StrategyProfile)
+			v.Set("traits."+trait, "changed")
+			}, http.StatusBadRequest, publicTS.URL+settings.RouteSubmitFlow)
+
+			messages := gjson.Get(actual, "ui.nodes.#(attributes.name==traits."+trait+").messages")
+			assert.EqualValues(t, text.ErrorValidationTraitNotWritable, messages.Get("0.id").Int(), actual)
+			assert.NotEqual(t, "changed", getTraits(t).Get(trait).String())
+		})
+	}
+}
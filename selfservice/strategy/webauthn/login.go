@@ -208,6 +208,13 @@ func (s *Strategy) loginPasswordless(ctx context.Context, w http.ResponseWriter,
 		return nil, s.handleLoginError(r, f, errors.WithStack(herodot.ErrBadRequest().WithReason("identifier is required")))
 	}
 
+	if err := login.EnforceOrganizationSSO(w, r, s.d, f, p.Identifier); err != nil {
+		if errors.Is(err, flow.ErrCompletedByStrategy) {
+			return nil, err
+		}
+		return nil, s.handleLoginError(r, f, err)
+	}
+
 	i, _, err = s.d.PrivilegedIdentityPool().FindByCredentialsIdentifier(ctx, s.ID(), p.Identifier)
 	if err != nil {
 		time.Sleep(x.RandomDelay(s.d.Config().HasherArgon2(ctx).ExpectedDuration, s.d.Config().HasherArgon2(ctx).ExpectedDeviation))
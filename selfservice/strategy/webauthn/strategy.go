@@ -16,6 +16,7 @@ import (
 	"github.com/ory/kratos/driver/config"
 	"github.com/ory/kratos/hash"
 	"github.com/ory/kratos/identity"
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/selfservice/errorx"
 	"github.com/ory/kratos/selfservice/flow/login"
 	"github.com/ory/kratos/selfservice/flow/registration"
@@ -61,6 +62,7 @@ type dependencies interface {
 	login.HookExecutorProvider
 	login.FlowPersistenceProvider
 	login.HandlerProvider
+	login.OrganizationSSOStarterProvider
 
 	settings.FlowPersistenceProvider
 	settings.HookExecutorProvider
@@ -74,6 +76,8 @@ type dependencies interface {
 
 	session.HandlerProvider
 	session.ManagementProvider
+
+	organization.ManagementProvider
 }
 
 type Strategy struct{ d dependencies }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/ory/kratos/x/events"
 
+	"github.com/ory/kratos/organization"
 	"github.com/ory/kratos/selfservice/flow"
 	"github.com/ory/kratos/selfservice/sessiontokenexchange"
 	"github.com/ory/kratos/ui/node"
@@ -56,6 +57,7 @@ type (
 		identity.PoolProvider
 		identity.PrivilegedPoolProvider
 		identity.ManagementProvider
+		organization.ManagementProvider
 		x.CookieProvider
 		logrusx.Provider
 		nosurfx.CSRFProvider
@@ -189,7 +191,7 @@ func (s *ManagerHTTP) IssueCookie(ctx context.Context, w http.ResponseWriter, r
 	cookie.Options.MaxAge = 0
 	if s.r.Config().SessionPersistentCookie(ctx) {
 		if session.ExpiresAt.IsZero() {
-			cookie.Options.MaxAge = int(s.r.Config().OrganizationSessionLifespan(ctx, session.OrganizationID()).Seconds())
+			cookie.Options.MaxAge = int(s.r.OrganizationManager().OrganizationSessionLifespan(ctx, session.OrganizationID()).Seconds())
 		} else {
 			cookie.Options.MaxAge = int(time.Until(session.ExpiresAt).Seconds())
 		}
@@ -513,7 +515,7 @@ func (s *ManagerHTTP) ActivateSession(r *http.Request, session *Session, i *iden
 
 	session.Active = true
 	session.IssuedAt = authenticatedAt
-	session.ExpiresAt = authenticatedAt.Add(s.r.Config().OrganizationSessionLifespan(ctx, session.OrganizationID()))
+	session.ExpiresAt = authenticatedAt.Add(s.r.OrganizationManager().OrganizationSessionLifespan(ctx, session.OrganizationID()))
 	session.AuthenticatedAt = authenticatedAt
 
 	session.SetSessionDeviceInformation(r.WithContext(ctx))
@@ -83,6 +83,10 @@ const (
 	InfoSelfServiceSettingsRemoveDeviceAuthnKey
 	InfoSelfServiceSettingsDeviceAuthnNonce
 	InfoSelfServiceSettingsManagedByOrganization
+	InfoSelfServiceSettingsDeleteAccount
+	InfoSelfServiceSettingsDeleteAccountConfirm
+	InfoSelfServiceSettingsAccountDeleted
+	InfoSelfServiceSettingsAccountDeletionScheduled
 )
 
 const (
@@ -175,6 +179,7 @@ const (
 	ErrorValidationDeviceAuthnVerifierWrong
 	ErrorValidationDeviceAuthnRelaxedAttestationNoLongerValid
 	ErrorValidationDeviceAuthnKeyReenrollmentRequired
+	ErrorValidationTraitNotWritable
 )
 
 const (
@@ -203,6 +208,7 @@ const (
 	ErrorValidationSettings                      ID = 4050000 + iota
 	ErrorValidationSettingsFlowExpired              // 4050001
 	ErrorValidationSettingsTooManyAddressChanges    // 4050002
+	ErrorValidationSettingsDeletionNotConfirmed     // 4050003
 )
 
 const (
@@ -31,6 +31,17 @@ func NewErrorValidationSettingsTooManyAddressChanges() *Message {
 	}
 }
 
+func NewErrorValidationSettingsDeletionNotConfirmed(phrase string) *Message {
+	return &Message{
+		ID:   ErrorValidationSettingsDeletionNotConfirmed,
+		Text: fmt.Sprintf("Please type \"%s\" to confirm the deletion of your account.", phrase),
+		Type: Error,
+		Context: context(map[string]any{
+			"phrase": phrase,
+		}),
+	}
+}
+
 func NewInfoSelfServiceSettingsTOTPQRCode() *Message {
 	return &Message{
 		ID:   InfoSelfServiceSettingsTOTPQRCode,
@@ -225,3 +236,42 @@ func NewInfoSelfServiceSettingsManagedByOrganization() *Message {
 		Type: Info,
 	}
 }
+
+func NewInfoSelfServiceSettingsDeleteAccount() *Message {
+	return &Message{
+		ID:   InfoSelfServiceSettingsDeleteAccount,
+		Text: "Delete account",
+		Type: Info,
+	}
+}
+
+func NewInfoSelfServiceSettingsDeleteAccountConfirm(phrase string) *Message {
+	return &Message{
+		ID:   InfoSelfServiceSettingsDeleteAccountConfirm,
+		Text: fmt.Sprintf("Type \"%s\" to confirm", phrase),
+		Type: Info,
+		Context: context(map[string]any{
+			"phrase": phrase,
+		}),
+	}
+}
+
+func NewInfoSelfServiceSettingsAccountDeleted() *Message {
+	return &Message{
+		ID:   InfoSelfServiceSettingsAccountDeleted,
+		Text: "Your account has been deleted.",
+		Type: Success,
+	}
+}
+
+func NewInfoSelfServiceSettingsAccountDeletionScheduled(deleteAfter time.Time) *Message {
+	return &Message{
+		ID:   InfoSelfServiceSettingsAccountDeletionScheduled,
+		Text: fmt.Sprintf("Your account will be deleted after %s. Sign in again before then to cancel the deletion.", deleteAfter.UTC().Format(time.RFC1123)),
+		Type: Success,
+		Context: context(map[string]any{
+			"delete_after":      deleteAfter,
+			"delete_after_unix": deleteAfter.Unix(),
+		}),
+	}
+}
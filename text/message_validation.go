@@ -414,6 +414,17 @@ func NewErrorValidationDeviceAuthnKeyReenrollmentRequired() *Message {
 	}
 }
 
+func NewErrorValidationTraitNotWritable(property string) *Message {
+	return &Message{
+		ID:   ErrorValidationTraitNotWritable,
+		Text: fmt.Sprintf("Property %s can not be changed.", property),
+		Type: Error,
+		Context: context(map[string]any{
+			"property": property,
+		}),
+	}
+}
+
 func NewErrorValidationLookupAlreadyUsed() *Message {
 	return &Message{
 		ID:   ErrorValidationLookupAlreadyUsed,
@@ -147,6 +147,21 @@ func NodesFromJSONSchema(ctx context.Context, group node.UiNodeGroup, jsonSchema
 	return nodes, nil
 }
 
+// DisableTraitNodes disables the input nodes of all traits for which writable
+// returns false. The trait path passed to writable is relative to the
+// identity's traits, e.g. `employee_id`.
+func DisableTraitNodes(nodes node.Nodes, writable func(path string) bool) {
+	for _, n := range nodes {
+		path, ok := strings.CutPrefix(n.ID(), "traits.")
+		if !ok || writable(path) {
+			continue
+		}
+		if attr, ok := n.Attributes.(*node.InputAttributes); ok {
+			attr.Disabled = true
+		}
+	}
+}
+
 func (c *Container) GetNodes() *node.Nodes {
 	return &c.Nodes
 }
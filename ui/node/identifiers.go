@@ -19,6 +19,10 @@ const (
 	LookupCodeEnter  = "lookup_secret"
 )
 
+const (
+	DeletionConfirm = "deletion_confirm"
+)
+
 const (
 	ProfileChooseCredentials = "profile_choose_credentials"
 )
@@ -51,6 +51,7 @@ const (
 	WebAuthnGroup        UiNodeGroup = "webauthn"
 	PasskeyGroup         UiNodeGroup = "passkey"
 	IdentifierFirstGroup UiNodeGroup = "identifier_first"
+	DeletionGroup        UiNodeGroup = "deletion"
 	CaptchaGroup         UiNodeGroup = "captcha"     // Available in OEL
 	SAMLGroup            UiNodeGroup = "saml"        // Available in OEL
 	DeviceAuthnGroup     UiNodeGroup = "deviceauthn" // Available in OEL
@@ -423,6 +424,7 @@ func (n *Nodes) ClearTransientNodes() {
 		PasskeyGroup,
 		IdentifierFirstGroup,
 		SAMLGroup,
+		DeletionGroup,
 	)
 }
 
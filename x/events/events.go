@@ -24,6 +24,7 @@ const (
 	IdentityCreated          semconv.Event = "IdentityCreated"
 	IdentityDeleted          semconv.Event = "IdentityDeleted"
 	IdentityUpdated          semconv.Event = "IdentityUpdated"
+	IdentitiesMerged         semconv.Event = "IdentitiesMerged"
 	JsonnetMappingFailed     semconv.Event = "JsonnetMappingFailed"
 	LoginFailed              semconv.Event = "LoginFailed"
 	LoginInitiated           semconv.Event = "LoginInitiated"
@@ -76,6 +77,7 @@ const (
 	AttributeKeySessionAAL                 semconv.AttributeKey = "SessionAAL"
 	AttributeKeySessionExpiresAt           semconv.AttributeKey = "SessionExpiresAt"
 	AttributeKeySessionID                  semconv.AttributeKey = "SessionID"
+	AttributeKeySourceIdentityID           semconv.AttributeKey = "SourceIdentityID"
 	AttributeKeyTokenizedSessionTTL        semconv.AttributeKey = "TokenizedSessionTTL"
 	AttributeKeyWebhookAttemptNumber       semconv.AttributeKey = "WebhookAttemptNumber"
 	AttributeKeyWebhookID                  semconv.AttributeKey = "WebhookID"
@@ -94,6 +96,10 @@ func attrSessionID(val uuid.UUID) otelattr.KeyValue {
 	return otelattr.String(AttributeKeySessionID.String(), val.String())
 }
 
+func attrSourceIdentityID(val uuid.UUID) otelattr.KeyValue {
+	return otelattr.String(AttributeKeySourceIdentityID.String(), val.String())
+}
+
 func attrTokenizedSessionTTL(ttl time.Duration) otelattr.KeyValue {
 	return otelattr.String(AttributeKeyTokenizedSessionTTL.String(), ttl.String())
 }
@@ -415,6 +421,17 @@ func NewIdentityUpdated(ctx context.Context, identityID uuid.UUID) (string, trac
 		)
 }
 
+func NewIdentitiesMerged(ctx context.Context, targetIdentityID, sourceIdentityID uuid.UUID) (string, trace.EventOption) {
+	return IdentitiesMerged.String(),
+		trace.WithAttributes(
+			append(
+				semconv.AttributesFromContext(ctx),
+				semconv.AttrIdentityID(targetIdentityID),
+				attrSourceIdentityID(sourceIdentityID),
+			)...,
+		)
+}
+
 func NewLoginFailed(ctx context.Context, flowID uuid.UUID, flowType, method, requestedAAL string, isRefresh bool, err error) (string, trace.EventOption) {
 	attrs := append(
 		semconv.AttributesFromContext(ctx),
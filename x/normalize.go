@@ -125,12 +125,35 @@ func GracefulNormalization(value string) string {
 	return NormalizeOtherIdentifier(value)
 }
 
+// Push platforms a device can be registered for. Push addresses are stored as
+// "<platform>:<device token>", so that the courier delivers them through the
+// channel of the device's platform.
+const (
+	PushPlatformFCM  = "fcm"
+	PushPlatformAPNs = "apns"
+)
+
+// ParsePushAddress splits a push address into its platform and device token.
+func ParsePushAddress(address string) (platform, token string, _ error) {
+	platform, token, _ = strings.Cut(address, ":")
+	platform = strings.ToLower(platform)
+	if platform != PushPlatformFCM && platform != PushPlatformAPNs {
+		return "", "", errors.Errorf("the push address must start with %q or %q", PushPlatformFCM+":", PushPlatformAPNs+":")
+	}
+	if token == "" {
+		return "", "", errors.New("the provided device token is empty")
+	}
+	return platform, token, nil
+}
+
 // NormalizeIdentifier normalizes an identifier based on the format.
 //
 // Supported formats are:
 //
 // - email
 // - phone
+// - push
+// - whatsapp
 // - username
 func NormalizeIdentifier(value, format string) (string, error) {
 	switch format {
@@ -159,6 +182,17 @@ func NormalizeIdentifier(value, format string) (string, error) {
 		}
 		return phonenumbers.Format(number, phonenumbers.E164), nil
 
+	case "push":
+		// Device tokens are opaque and case-sensitive.
+		platform, token, err := ParsePushAddress(strings.TrimSpace(value))
+		if err != nil {
+			return "", err
+		}
+		return platform + ":" + token, nil
+
+	case "whatsapp":
+		return NormalizeIdentifier(value, "sms")
+
 	case "username":
 		fallthrough
 	default:
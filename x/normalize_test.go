@@ -303,6 +303,13 @@ func TestNormalizeIdentifier(t *testing.T) {
 		{"+256 730 691 099", "sms", "+256730691099", false},
 		{"  username  ", "username", "username", false},
 		{"invalid-phone", "sms", "", true},
+		{"  fcm:dGVzdA:APA91bH-Token  ", "push", "fcm:dGVzdA:APA91bH-Token", false},
+		{"APNS:Abcdef0123", "push", "apns:Abcdef0123", false},
+		{"dGVzdA:APA91bH-Token", "push", "", true},
+		{"fcm:", "push", "", true},
+		{"   ", "push", "", true},
+		{"+1 650-253-0000", "whatsapp", "+16502530000", false},
+		{"invalid-phone", "whatsapp", "", true},
 	}
 
 	for _, test := range tests {